@@ -0,0 +1,23 @@
+package request
+
+// CreateAddress stores request data for POST /v1/users/:user_id/addresses endpoint.
+type CreateAddress struct {
+	Type       string `json:"type" description:"one of home, billing or shipping"`
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code" description:"optional"`
+	Region     string `json:"region" description:"optional, e.g. state or province"`
+	Country    string `json:"country" description:"ISO 3166-1 alpha-2 country code, e.g. PL"`
+	Primary    bool   `json:"primary" description:"makes the address primary, the first address of the user is always primary"`
+}
+
+// UpdateAddress stores request data for PUT /v1/users/:user_id/addresses/:address_id endpoint.
+type UpdateAddress struct {
+	Type       string `json:"type" description:"one of home, billing or shipping"`
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code" description:"optional"`
+	Region     string `json:"region" description:"optional, e.g. state or province"`
+	Country    string `json:"country" description:"ISO 3166-1 alpha-2 country code, e.g. PL"`
+	Primary    bool   `json:"primary" description:"makes the address primary, false does not unmark the primary address"`
+}
@@ -0,0 +1,18 @@
+package request
+
+// CreateAttributeDefinition stores request data for POST /v1/admin/attribute-definitions endpoint.
+type CreateAttributeDefinition struct {
+	Name     string   `json:"name" description:"lowercase letters, digits and underscores, e.g. department"`
+	Type     string   `json:"type" description:"one of string, number or boolean"`
+	Required bool     `json:"required" description:"users have to have the attribute when they are created or updated"`
+	Enum     []string `json:"enum" description:"allowed values of string attribute, any value is allowed when empty"`
+	Pattern  string   `json:"pattern" description:"regular expression which values of string attribute have to match"`
+}
+
+// UpdateAttributeDefinition stores request data for PUT /v1/admin/attribute-definitions/:definition_id endpoint.
+// Name and type of the attribute can't be changed.
+type UpdateAttributeDefinition struct {
+	Required bool     `json:"required" description:"users have to have the attribute when they are created or updated"`
+	Enum     []string `json:"enum" description:"allowed values of string attribute, any value is allowed when empty"`
+	Pattern  string   `json:"pattern" description:"regular expression which values of string attribute have to match"`
+}
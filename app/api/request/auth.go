@@ -0,0 +1,40 @@
+package request
+
+// Login stores request data for POST /v1/auth/login endpoint.
+type Login struct {
+	Email    string `json:"email" description:"email of the user, letter case is ignored"`
+	Password string `json:"password"`
+}
+
+// LoginMFA stores request data for POST /v1/auth/login/mfa endpoint.
+type LoginMFA struct {
+	MFAToken string `json:"mfa_token" description:"token returned by POST /v1/auth/login"`
+	Code     string `json:"code" description:"code from authenticator app or unused recovery code"`
+}
+
+// RefreshToken stores request data for POST /v1/auth/refresh endpoint.
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token" description:"refresh token of the session, it can be used once"`
+}
+
+// MFACode stores request data for POST /v1/users/:user_id/mfa/* endpoints.
+type MFACode struct {
+	Code string `json:"code" description:"code from authenticator app, unused recovery code is also accepted by enabled MFA"`
+}
+
+// RequestPasswordReset stores request data for POST /v1/auth/password-reset endpoint.
+type RequestPasswordReset struct {
+	Email string `json:"email" description:"email of the user, letter case is ignored"`
+}
+
+// ConfirmPasswordReset stores request data for POST /v1/auth/password-reset/confirm endpoint.
+type ConfirmPasswordReset struct {
+	Token       string `json:"token" description:"token delivered to the user"`
+	NewPassword string `json:"new_password" description:"10 to 128 characters with at least one letter and one digit"`
+}
+
+// ChangePassword stores request data for POST /v1/users/:user_id/password endpoint.
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" description:"10 to 128 characters with at least one letter and one digit"`
+}
@@ -0,0 +1,26 @@
+package request
+
+// CreateGroup stores request data for POST /v1/groups endpoint.
+type CreateGroup struct {
+	Name        string `json:"name" description:"unique name, it is also used as a tag of members, e.g. beta_testers"`
+	Description string `json:"description" description:"optional"`
+}
+
+// UpdateGroup stores request data for PUT /v1/groups/:group_id endpoint.
+type UpdateGroup struct {
+	Name        string `json:"name" description:"unique name, it is also used as a tag of members, e.g. beta_testers"`
+	Description string `json:"description" description:"optional"`
+}
+
+// GroupMembers stores request data for POST and DELETE /v1/groups/:group_id/members endpoints.
+type GroupMembers struct {
+	UserIDs []int `json:"user_ids" description:"IDs of users, at most 1000"`
+}
+
+// FindGroupMembers represents query params for GET /v1/groups/:group_id/members endpoint.
+type FindGroupMembers struct {
+	Limit    int    `form:"limit" description:"page size, default 30, at most 200"`
+	BeforeID int    `form:"before_id" description:"returns page before user with this ID, see pagination.prev_link"`
+	AfterID  int    `form:"after_id" description:"returns page after user with this ID, see pagination.next_link"`
+	Sort     string `form:"sort" description:"column and order, e.g. name:asc, default id:asc"`
+}
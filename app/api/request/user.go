@@ -1,33 +1,68 @@
 package request
 
+import "time"
+
 // CreateUser stores request data for POST /v1/users endpoint.
 type CreateUser struct {
-	Name    string `json:"name"`
-	Surname string `json:"surname"`
-	Gender  string `json:"gender"`
-	Age     int    `json:"age"`
-	Address string `json:"address"`
+	Name        string                 `json:"name"`
+	Surname     string                 `json:"surname"`
+	Gender      string                 `json:"gender"`
+	DateOfBirth string                 `json:"date_of_birth" description:"date in format YYYY-MM-DD"`
+	Age         int                    `json:"age" deprecated:"true" description:"deprecated, use date_of_birth, when date_of_birth is empty it is approximated as age years before the current day"`
+	Address     string                 `json:"address"`
+	Email       string                 `json:"email" description:"optional, unique regardless of letter case"`
+	Phone       string                 `json:"phone" description:"optional, international number normalised to E.164, e.g. +48123456789"`
+	Attributes  map[string]interface{} `json:"attributes" description:"custom attributes, see /v1/admin/attribute-definitions"`
 }
 
 // UpdateUser stores request data for PU /v1/users/:user_id endpoint.
 type UpdateUser struct {
-	Name    string `json:"name"`
-	Surname string `json:"surname"`
-	Gender  string `json:"gender"`
-	Age     int    `json:"age"`
-	Address string `json:"address"`
+	Name        string                 `json:"name"`
+	Surname     string                 `json:"surname"`
+	Gender      string                 `json:"gender"`
+	DateOfBirth string                 `json:"date_of_birth" description:"date in format YYYY-MM-DD"`
+	Age         int                    `json:"age" deprecated:"true" description:"deprecated, use date_of_birth, when date_of_birth is empty it is approximated as age years before the current day"`
+	Address     string                 `json:"address"`
+	Email       string                 `json:"email" description:"optional, unique regardless of letter case"`
+	Phone       string                 `json:"phone" description:"optional, international number normalised to E.164, e.g. +48123456789"`
+	Attributes  map[string]interface{} `json:"attributes" description:"custom attributes, see /v1/admin/attribute-definitions"`
+}
+
+// ConfirmEmailVerification stores request data for POST /v1/users/:user_id/email/verify/confirm endpoint.
+type ConfirmEmailVerification struct {
+	Token string `json:"token" description:"token delivered to the user by POST /v1/users/{user_id}/email/verify"`
 }
 
 // FindUsers represents query params for searching users
 type FindUsers struct {
-	Limit    int    `form:"limit"`
-	BeforeID int    `form:"before_id"`
-	AfterID  int    `form:"after_id"`
-	Sort     string `form:"sort"`
-	Name     string `form:"name"`
-	Surname  string `form:"surname"`
-	Gender   string `form:"gender"`
-	Address  string `form:"address"`
-	MinAge   int    `form:"min_age"`
-	MaxAge   int    `form:"max_age"`
+	Limit      int      `form:"limit" description:"page size, default 30, at most 200"`
+	BeforeID   int      `form:"before_id" description:"returns page before user with this ID, see pagination.prev_link"`
+	AfterID    int      `form:"after_id" description:"returns page after user with this ID, see pagination.next_link"`
+	Sort       string   `form:"sort" description:"column and order, e.g. name:asc, date_of_birth:desc or attr.department:asc, default id:asc, age sorts by date_of_birth in the opposite order, users without the attr sort attribute are last"`
+	Name       string   `form:"name" description:"case-insensitive substring of name"`
+	Surname    string   `form:"surname" description:"case-insensitive substring of surname"`
+	Gender     string   `form:"gender" description:"case-insensitive gender"`
+	Address    string   `form:"address" description:"case-insensitive substring of address"`
+	City       string   `form:"city" description:"case-insensitive city of any address of the user"`
+	Country    string   `form:"country" description:"ISO 3166-1 alpha-2 country code of any address of the user"`
+	MinAge     int      `form:"min_age" description:"minimum age in full years, calculated from date_of_birth"`
+	MaxAge     int      `form:"max_age" description:"maximum age in full years, calculated from date_of_birth"`
+	Status     string   `form:"status" description:"status of the account: pending, active, suspended, locked or closed"`
+	Groups     []int    `form:"group" description:"ID of group of the user, can be passed many times"`
+	Tags       []string `form:"tag" description:"name of group of the user, can be passed many times"`
+	GroupMatch string   `form:"group_match" description:"any (default) returns members of any of the groups and tags, all returns members of all of them"`
+	// Attributes are filters by custom attributes passed as attr.<name> query parameters.
+	Attributes map[string]string `form:"-"`
+}
+
+// FindUserChanges represents query params for GET /v1/users/changes endpoint
+type FindUserChanges struct {
+	Since string        `form:"since" description:"token of the last seen change, changes from the beginning are returned when empty"`
+	Limit int           `form:"limit"`
+	Wait  time.Duration `form:"wait" description:"how long to wait for changes when there are none, at most 60s"`
+}
+
+// ChangeUserStatus stores request data for POST /v1/users/:user_id/activate, suspend, lock and close endpoints.
+type ChangeUserStatus struct {
+	Reason string `json:"reason" description:"why the status is changed, it is recorded in status history, at most 500 characters"`
 }
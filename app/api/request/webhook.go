@@ -0,0 +1,23 @@
+package request
+
+// CreateWebhook stores request data for POST /v1/webhooks endpoint.
+type CreateWebhook struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+	Active     *bool    `json:"active"`
+}
+
+// UpdateWebhook stores request data for PUT /v1/webhooks/:webhook_id endpoint.
+type UpdateWebhook struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+	Active     *bool    `json:"active"`
+}
+
+// FindWebhookDeliveries represents query params for GET /v1/webhooks/:webhook_id/deliveries endpoint.
+type FindWebhookDeliveries struct {
+	Limit    int   `form:"limit"`
+	BeforeID int64 `form:"before_id"`
+}
@@ -0,0 +1,26 @@
+package response
+
+import "time"
+
+// Address stores response for GET /v1/users/:user_id/addresses/:address_id endpoint
+type Address struct {
+	ID         int       `json:"id"`
+	Type       string    `json:"type"`
+	Street     string    `json:"street"`
+	City       string    `json:"city"`
+	PostalCode string    `json:"postal_code"`
+	Region     string    `json:"region"`
+	Country    string    `json:"country"`
+	Primary    bool      `json:"primary"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateAddress stores response for POST /v1/users/:user_id/addresses endpoint
+type CreateAddress struct {
+	ID int `json:"id"`
+}
+
+// AddressList represents json response for GET /v1/users/:user_id/addresses route.
+type AddressList struct {
+	Result []Address `json:"result"`
+}
@@ -0,0 +1,24 @@
+package response
+
+import "time"
+
+// AttributeDefinition stores response for GET /v1/admin/attribute-definitions/:definition_id endpoint
+type AttributeDefinition struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Required  bool      `json:"required"`
+	Enum      []string  `json:"enum"`
+	Pattern   string    `json:"pattern"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateAttributeDefinition stores response for POST /v1/admin/attribute-definitions endpoint
+type CreateAttributeDefinition struct {
+	ID int `json:"id"`
+}
+
+// AttributeDefinitionList represents json response for GET /v1/admin/attribute-definitions route.
+type AttributeDefinitionList struct {
+	Result []AttributeDefinition `json:"result"`
+}
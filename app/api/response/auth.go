@@ -0,0 +1,46 @@
+package response
+
+import "time"
+
+// Token stores response for POST /v1/auth/login, POST /v1/auth/login/mfa and POST /v1/auth/refresh endpoints
+type Token struct {
+	AccessToken      string    `json:"access_token" description:"JSON Web Token signed with HS256, user ID is its subject and session ID is sid claim"`
+	TokenType        string    `json:"token_type" description:"always Bearer"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token" description:"single use token exchanged for new tokens by POST /v1/auth/refresh"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at" description:"session expires when refresh token isn't used until then"`
+}
+
+// MFAChallenge stores response for POST /v1/auth/login endpoint when MFA of the user is enabled
+type MFAChallenge struct {
+	MFAToken  string    `json:"mfa_token" description:"single use token confirmed by POST /v1/auth/login/mfa"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MFAEnrolment stores response for POST /v1/users/:user_id/mfa/enroll endpoint
+type MFAEnrolment struct {
+	Secret     string `json:"secret" description:"base32 encoded TOTP secret"`
+	OTPAuthURI string `json:"otpauth_uri" description:"key URI of authenticator apps"`
+	QRCode     string `json:"qr_code" description:"base64 encoded PNG image with QR code of otpauth_uri"`
+}
+
+// MFARecoveryCodes stores response for POST /v1/users/:user_id/mfa/activate
+// and POST /v1/users/:user_id/mfa/recovery-codes endpoints
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes" description:"single use codes, they are not shown again"`
+}
+
+// Session stores active session of the user
+type Session struct {
+	ID         int       `json:"id"`
+	UserAgent  string    `json:"user_agent" description:"User-Agent header of the last login or refresh"`
+	IP         string    `json:"ip" description:"client IP address of the last login or refresh"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// SessionList represents json response for GET /v1/users/:user_id/sessions route.
+type SessionList struct {
+	Result []Session `json:"result"`
+}
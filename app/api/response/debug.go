@@ -0,0 +1,12 @@
+package response
+
+// CacheStats stores counters of cache usage
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// DebugVars stores response for GET /debug/vars endpoint
+type DebugVars struct {
+	UserCache *CacheStats `json:"user_cache,omitempty" description:"missing when user cache is disabled"`
+}
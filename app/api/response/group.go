@@ -0,0 +1,27 @@
+package response
+
+import "time"
+
+// Group stores response for GET /v1/groups/:group_id endpoint
+type Group struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CreateGroup stores response for POST /v1/groups endpoint
+type CreateGroup struct {
+	ID int `json:"id"`
+}
+
+// GroupList represents json response for GET /v1/groups route.
+type GroupList struct {
+	Result []Group `json:"result"`
+}
+
+// GroupMembersChange stores response for POST and DELETE /v1/groups/:group_id/members endpoints
+type GroupMembersChange struct {
+	// Count is number of added or removed members, users which are not changed are not counted.
+	Count int `json:"count"`
+}
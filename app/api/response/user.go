@@ -4,13 +4,19 @@ import "time"
 
 // User stores response for GET /v1/users/:user_id endpoint
 type User struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Surname   string    `json:"surname"`
-	Gender    string    `json:"gender"`
-	Age       int       `json:"age"`
-	Address   string    `json:"address"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            int                    `json:"id"`
+	Name          string                 `json:"name"`
+	Surname       string                 `json:"surname"`
+	Gender        string                 `json:"gender"`
+	DateOfBirth   string                 `json:"date_of_birth" description:"date in format YYYY-MM-DD"`
+	Age           int                    `json:"age" description:"age in full years calculated from date_of_birth"`
+	Address       string                 `json:"address"`
+	Email         string                 `json:"email"`
+	Phone         string                 `json:"phone"`
+	EmailVerified bool                   `json:"email_verified"`
+	Attributes    map[string]interface{} `json:"attributes"`
+	Status        string                 `json:"status" description:"pending, active, suspended, locked or closed"`
+	CreatedAt     time.Time              `json:"created_at"`
 }
 
 // CreateUser stores response for POST /users endpoint
@@ -18,6 +24,25 @@ type CreateUser struct {
 	ID int `json:"id"`
 }
 
+// EmailVerification stores response for POST /v1/users/:user_id/email/verify endpoint
+type EmailVerification struct {
+	ExpiresAt time.Time `json:"expires_at" description:"time after which the delivered token can not be confirmed"`
+}
+
+// UserStatusChange stores transition of user status
+type UserStatusChange struct {
+	ID         int       `json:"id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// UserStatusHistory represents json response for GET /v1/users/:user_id/status-history route.
+type UserStatusHistory struct {
+	Result []UserStatusChange `json:"result"`
+}
+
 // UserListWithPagination represents json response for GET /users route.
 type UserListWithPagination struct {
 	Result     []User     `json:"result"`
@@ -26,8 +51,25 @@ type UserListWithPagination struct {
 
 // Pagination represents pagination data
 type Pagination struct {
-	PrevLink string `json:"prev_link"`
-	NextLink string `json:"next_link"`
-	BeforeID int    `json:"before_id"`
-	AfterID  int    `json:"after_id"`
+	PrevLink string `json:"prev_link" description:"URL of the previous page, empty on the first page"`
+	NextLink string `json:"next_link" description:"URL of the next page, empty on the last page"`
+	BeforeID int    `json:"before_id" description:"before_id of the previous page, 0 on the first page"`
+	AfterID  int    `json:"after_id" description:"after_id of the next page, 0 on the last page"`
+}
+
+// UserChange represents single change in GET /v1/users/changes response.
+// User is the current state of the user, it is omitted when user does not exist anymore.
+type UserChange struct {
+	Type      string    `json:"type"`
+	UserID    int       `json:"user_id"`
+	ChangedAt time.Time `json:"changed_at"`
+	User      *User     `json:"user,omitempty"`
+	Token     string    `json:"token"`
+}
+
+// UserChangeList represents json response for GET /v1/users/changes route.
+type UserChangeList struct {
+	Result    []UserChange `json:"result"`
+	NextToken string       `json:"next_token" description:"token to resume the feed from, passed as since"`
+	NextLink  string       `json:"next_link" description:"URL which resumes the feed from next_token"`
 }
@@ -0,0 +1,48 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook stores response for GET /v1/webhooks/:webhook_id endpoint
+type Webhook struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateWebhook stores response for POST /v1/webhooks endpoint.
+// Secret is returned only when webhook is created.
+type CreateWebhook struct {
+	ID     int    `json:"id"`
+	Secret string `json:"secret"`
+}
+
+// WebhookList represents json response for GET /v1/webhooks route.
+type WebhookList struct {
+	Result []Webhook `json:"result"`
+}
+
+// WebhookDelivery represents single delivery in GET /v1/webhooks/:webhook_id/deliveries response
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status"`
+	LastError      string          `json:"last_error"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// WebhookDeliveryList represents json response for GET /v1/webhooks/:webhook_id/deliveries route.
+type WebhookDeliveryList struct {
+	Result   []WebhookDelivery `json:"result"`
+	NextLink string            `json:"next_link" description:"URL of the next page, empty on the last page"`
+}
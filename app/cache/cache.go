@@ -0,0 +1,13 @@
+package cache
+
+// Cache provides an interface to store serialized values by key.
+// In-process LRU is the default implementation, shared caches (e.g. Redis) can be added
+// by implementing this interface.
+type Cache interface {
+	// Get returns value stored under key. Second value is FALSE when key is not found or expired.
+	Get(key string) ([]byte, bool, error)
+	// Set stores value under key.
+	Set(key string, value []byte) error
+	// Delete removes key from cache.
+	Delete(key string) error
+}
@@ -0,0 +1,54 @@
+// +build unit
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU(2, 0)
+	assert.Nil(t, c.Set("a", []byte("1")))
+	assert.Nil(t, c.Set("b", []byte("2")))
+
+	_, ok, _ := c.Get("a")
+	assert.True(t, ok)
+
+	assert.Nil(t, c.Set("c", []byte("3")))
+
+	_, ok, _ = c.Get("b")
+	assert.False(t, ok)
+	value, ok, _ := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	now := time.Date(2020, 5, 14, 16, 18, 40, 0, time.UTC)
+	c := NewLRU(10, time.Minute)
+	c.now = func() time.Time { return now }
+	assert.Nil(t, c.Set("a", []byte("1")))
+
+	now = now.Add(59 * time.Second)
+	_, ok, _ := c.Get("a")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok, _ = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRUDelete(t *testing.T) {
+	c := NewLRU(10, 0)
+	assert.Nil(t, c.Set("a", []byte("1")))
+	assert.Nil(t, c.Delete("a"))
+	assert.Nil(t, c.Delete("missing"))
+
+	_, ok, _ := c.Get("a")
+	assert.False(t, ok)
+}
@@ -1,27 +1,213 @@
 package config
 
 import (
-	"github.com/kelseyhightower/envconfig"
-	"github.com/pkg/errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/service/auth"
+	"github.com/mmgopher/user-service/app/service/outbox"
 )
 
+// authTokenSecretMinLength is minimal length of AUTH_TOKEN_SECRET, HS256 key should have at least 256 bits.
+const authTokenSecretMinLength = 32
+
 // Config represents aplication config object.
+// Every field is loaded from the setting named in config tag, see New for supported sources.
 type Config struct {
-	DBUser   string `envconfig:"DB_USER" required:"true"`
-	DBPass   string `envconfig:"DB_PASS" required:"true"`
-	DBType   string `envconfig:"DB_TYPE" required:"true"`
-	DBName   string `envconfig:"DB_NAME" required:"true"`
-	DBHost   string `envconfig:"DB_HOST" required:"true"`
-	LogLevel string `envconfig:"LOG_LEVEL" default:"error"`
+	DBType   string `config:"DB_TYPE" required:"true" oneof:"postgres mysql sqlite3"`
+	DBDSN    string `config:"DB_DSN" secret:"true"`
+	DBUser   string `config:"DB_USER"`
+	DBPass   string `config:"DB_PASS" secret:"true"`
+	DBName   string `config:"DB_NAME"`
+	DBHost   string `config:"DB_HOST"`
+	DBPort   int    `config:"DB_PORT" min:"0"`
+	LogLevel string `config:"LOG_LEVEL" default:"error" reload:"true"`
+
+	DBSSLMode             string        `config:"DB_SSL_MODE" default:"disable" oneof:"disable require verify-ca verify-full"`
+	DBSSLRootCert         string        `config:"DB_SSL_ROOT_CERT"`
+	DBSSLCert             string        `config:"DB_SSL_CERT"`
+	DBSSLKey              string        `config:"DB_SSL_KEY"`
+	DBApplicationName     string        `config:"DB_APPLICATION_NAME" default:"user-service"`
+	DBStatementTimeout    time.Duration `config:"DB_STATEMENT_TIMEOUT" default:"0s" min:"0s"`
+	DBMaxOpenConns        int           `config:"DB_MAX_OPEN_CONNS" default:"0" min:"0"`
+	DBMaxIdleConns        int           `config:"DB_MAX_IDLE_CONNS" default:"2" min:"0"`
+	DBConnMaxLifetime     time.Duration `config:"DB_CONN_MAX_LIFETIME" default:"0s" min:"0s"`
+	DBConnectRetries      int           `config:"DB_CONNECT_RETRIES" default:"10" min:"0"`
+	DBConnectRetryBackoff time.Duration `config:"DB_CONNECT_RETRY_BACKOFF" default:"500ms" min:"0s"`
+	DBConnectMaxBackoff   time.Duration `config:"DB_CONNECT_MAX_BACKOFF" default:"5s" min:"0s"`
+
+	DBReplicaDSNs                []string      `config:"DB_REPLICA_DSNS" secret:"true"`
+	DBReplicaHealthCheckInterval time.Duration `config:"DB_REPLICA_HEALTH_CHECK_INTERVAL" default:"5s" min:"1ms"`
+	DBReplicaMaxLag              time.Duration `config:"DB_REPLICA_MAX_LAG" default:"10s" min:"0s"`
+
+	RequestBodyMaxSize int `config:"REQUEST_BODY_MAX_SIZE" default:"1048576" min:"1"`
+
+	OutboxSink           string        `config:"OUTBOX_SINK" default:"stdout" oneof:"none stdout file webhook nats kafka"`
+	OutboxFilePath       string        `config:"OUTBOX_FILE_PATH" default:"events.log"`
+	OutboxWebhookURL     string        `config:"OUTBOX_WEBHOOK_URL"`
+	OutboxWebhookTimeout time.Duration `config:"OUTBOX_WEBHOOK_TIMEOUT" default:"5s" min:"1ms"`
+	OutboxNATSURL        string        `config:"OUTBOX_NATS_URL" default:"nats://localhost:4222"`
+	OutboxNATSSubject    string        `config:"OUTBOX_NATS_SUBJECT" default:"user-service"`
+	OutboxNATSTimeout    time.Duration `config:"OUTBOX_NATS_TIMEOUT" default:"5s" min:"1ms"`
+	OutboxKafkaURL       string        `config:"OUTBOX_KAFKA_URL"`
+	OutboxKafkaTopic     string        `config:"OUTBOX_KAFKA_TOPIC" default:"user-events"`
+	OutboxKafkaTimeout   time.Duration `config:"OUTBOX_KAFKA_TIMEOUT" default:"5s" min:"1ms"`
+	OutboxBatchSize      int           `config:"OUTBOX_BATCH_SIZE" default:"100" min:"1"`
+	OutboxPollInterval   time.Duration `config:"OUTBOX_POLL_INTERVAL" default:"1s" min:"1ms"`
+	OutboxRetryBackoff   time.Duration `config:"OUTBOX_RETRY_BACKOFF" default:"1s" min:"0s"`
+	OutboxMaxBackoff     time.Duration `config:"OUTBOX_MAX_BACKOFF" default:"5m" min:"0s"`
+
+	IdempotencyKeyTTL             time.Duration `config:"IDEMPOTENCY_KEY_TTL" default:"24h" min:"1s"`
+	IdempotencyKeyCleanupInterval time.Duration `config:"IDEMPOTENCY_KEY_CLEANUP_INTERVAL" default:"1h" min:"1ms"`
+
+	UserCacheEnabled bool          `config:"USER_CACHE_ENABLED" default:"false"`
+	UserCacheSize    int           `config:"USER_CACHE_SIZE" default:"10000" min:"1"`
+	UserCacheTTL     time.Duration `config:"USER_CACHE_TTL" default:"1m" min:"1ms"`
+
+	ChangeFeedPollInterval time.Duration `config:"CHANGE_FEED_POLL_INTERVAL" default:"500ms" min:"1ms"`
+
+	WebhookEnabled      bool          `config:"WEBHOOK_ENABLED" default:"true"`
+	WebhookTimeout      time.Duration `config:"WEBHOOK_TIMEOUT" default:"10s" min:"1ms"`
+	WebhookBatchSize    int           `config:"WEBHOOK_BATCH_SIZE" default:"100" min:"1"`
+	WebhookMaxAttempts  int           `config:"WEBHOOK_MAX_ATTEMPTS" default:"10" min:"1"`
+	WebhookPollInterval time.Duration `config:"WEBHOOK_POLL_INTERVAL" default:"1s" min:"1ms"`
+	WebhookRetryBackoff time.Duration `config:"WEBHOOK_RETRY_BACKOFF" default:"10s" min:"0s"`
+	WebhookMaxBackoff   time.Duration `config:"WEBHOOK_MAX_BACKOFF" default:"1h" min:"0s"`
+
+	Notifier                  string        `config:"NOTIFIER" default:"log" oneof:"log file"`
+	NotifierFilePath          string        `config:"NOTIFIER_FILE_PATH" default:"notifications.log"`
+	EmailVerificationTokenTTL time.Duration `config:"EMAIL_VERIFICATION_TOKEN_TTL" default:"24h" min:"1s"`
+	PasswordResetTokenTTL     time.Duration `config:"PASSWORD_RESET_TOKEN_TTL" default:"1h" min:"1s"`
+
+	PasswordArgon2Time    int           `config:"PASSWORD_ARGON2_TIME" default:"1" min:"1"`
+	PasswordArgon2Memory  int           `config:"PASSWORD_ARGON2_MEMORY" default:"65536" min:"8"`
+	PasswordArgon2Threads int           `config:"PASSWORD_ARGON2_THREADS" default:"2" min:"1"`
+	AuthMaxFailedAttempts int           `config:"AUTH_MAX_FAILED_ATTEMPTS" default:"5" min:"1"`
+	AuthTokenSecret       string        `config:"AUTH_TOKEN_SECRET" secret:"true"`
+	AuthTokenIssuer       string        `config:"AUTH_TOKEN_ISSUER" default:"user-service"`
+	AuthTokenTTL          time.Duration `config:"AUTH_TOKEN_TTL" default:"15m" min:"1s"`
+	SessionTTL            time.Duration `config:"SESSION_TTL" default:"720h" min:"1m"`
+
+	MFAIssuer        string        `config:"MFA_ISSUER" default:"user-service"`
+	MFATOTPSkew      int           `config:"MFA_TOTP_SKEW" default:"1" min:"0"`
+	MFARecoveryCodes int           `config:"MFA_RECOVERY_CODES" default:"10" min:"1"`
+	MFAChallengeTTL  time.Duration `config:"MFA_CHALLENGE_TTL" default:"5m" min:"1s"`
+
+	ErrorFormat             string `config:"ERROR_FORMAT" default:"legacy" oneof:"legacy problem"`
+	ErrorProblemTypeBaseURI string `config:"ERROR_PROBLEM_TYPE_BASE_URI" default:"urn:user-service:error:"`
+}
+
+// Supported values of ERROR_FORMAT setting.
+const (
+	ErrorFormatLegacy  = "legacy"
+	ErrorFormatProblem = "problem"
+)
+
+// New creates new instance of Config object. Settings are read from config file, environment variables
+// and command line flags, later sources override earlier ones.
+func New(args []string) (*Config, error) {
+	return load(args, newEnvSource())
+}
+
+// ProblemErrors reports whether all errors are rendered as RFC 7807 problem details.
+func (c Config) ProblemErrors() bool {
+	return c.ErrorFormat == ErrorFormatProblem
 }
 
-// New creates new instance of Config object.
-func New() (*Config, error) {
+// validate checks constraints which involve several settings.
+func (c Config) validate() ValidationErrors {
+	var errs ValidationErrors
 
-	var c Config
-	if err := envconfig.Process("", &c); err != nil {
-		return nil, errors.Wrap(err, "impossible to parse app configuration ")
+	if _, err := log.ParseLevel(c.LogLevel); err != nil {
+		errs = append(errs, "LOG_LEVEL: "+err.Error())
 	}
 
-	return &c, nil
+	if c.DBDSN == "" {
+		if c.DBName == "" {
+			errs = append(errs, "DB_NAME: is required when DB_DSN is not set")
+		}
+		if c.DBType != db.SQLiteType && c.DBUser == "" {
+			errs = append(errs, "DB_USER: is required when DB_DSN is not set")
+		}
+		if c.DBType != db.SQLiteType && c.DBHost == "" {
+			errs = append(errs, "DB_HOST: is required when DB_DSN is not set")
+		}
+	}
+
+	if c.OutboxSink == "webhook" && c.OutboxWebhookURL == "" {
+		errs = append(errs, "OUTBOX_WEBHOOK_URL: is required when OUTBOX_SINK is webhook")
+	}
+	if c.OutboxSink == "nats" && c.OutboxNATSSubject == "" {
+		errs = append(errs, "OUTBOX_NATS_SUBJECT: is required when OUTBOX_SINK is nats")
+	}
+	if c.OutboxSink == "kafka" && c.OutboxKafkaURL == "" {
+		errs = append(errs, "OUTBOX_KAFKA_URL: is required when OUTBOX_SINK is kafka")
+	}
+	if c.OutboxSink == "kafka" && c.OutboxKafkaTopic == "" {
+		errs = append(errs, "OUTBOX_KAFKA_TOPIC: is required when OUTBOX_SINK is kafka")
+	}
+
+	if c.PasswordArgon2Threads > 255 {
+		errs = append(errs, "PASSWORD_ARGON2_THREADS: must be at most 255")
+	}
+
+	if c.AuthTokenSecret != "" && len(c.AuthTokenSecret) < authTokenSecretMinLength {
+		errs = append(errs, "AUTH_TOKEN_SECRET: must be at least 32 characters long")
+	}
+
+	return errs
+}
+
+// DBSettings returns settings of connection to primary database.
+func (c Config) DBSettings() db.Settings {
+	return db.Settings{
+		Type:                c.DBType,
+		DSN:                 c.DBDSN,
+		User:                c.DBUser,
+		Password:            c.DBPass,
+		Name:                c.DBName,
+		Host:                c.DBHost,
+		Port:                c.DBPort,
+		SSLMode:             c.DBSSLMode,
+		SSLRootCert:         c.DBSSLRootCert,
+		SSLCert:             c.DBSSLCert,
+		SSLKey:              c.DBSSLKey,
+		ApplicationName:     c.DBApplicationName,
+		StatementTimeout:    c.DBStatementTimeout,
+		MaxOpenConns:        c.DBMaxOpenConns,
+		MaxIdleConns:        c.DBMaxIdleConns,
+		ConnMaxLifetime:     c.DBConnMaxLifetime,
+		ConnectRetries:      c.DBConnectRetries,
+		ConnectRetryBackoff: c.DBConnectRetryBackoff,
+		ConnectMaxBackoff:   c.DBConnectMaxBackoff,
+	}
+}
+
+// OutboxSinkSettings returns settings of the sink which receives outbox messages.
+func (c Config) OutboxSinkSettings() outbox.SinkSettings {
+	return outbox.SinkSettings{
+		Type:           c.OutboxSink,
+		FilePath:       c.OutboxFilePath,
+		WebhookURL:     c.OutboxWebhookURL,
+		WebhookTimeout: c.OutboxWebhookTimeout,
+		NATSURL:        c.OutboxNATSURL,
+		NATSSubject:    c.OutboxNATSSubject,
+		NATSTimeout:    c.OutboxNATSTimeout,
+		KafkaURL:       c.OutboxKafkaURL,
+		KafkaTopic:     c.OutboxKafkaTopic,
+		KafkaTimeout:   c.OutboxKafkaTimeout,
+	}
+}
+
+// PasswordHashParams returns argon2id parameters used to hash new passwords.
+func (c Config) PasswordHashParams() auth.Argon2Params {
+	return auth.Argon2Params{
+		Time:       uint32(c.PasswordArgon2Time),
+		Memory:     uint32(c.PasswordArgon2Memory),
+		Threads:    uint8(c.PasswordArgon2Threads),
+		SaltLength: 16,
+		KeyLength:  32,
+	}
 }
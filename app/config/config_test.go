@@ -0,0 +1,254 @@
+// +build unit
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.Nil(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "user-service-config")
+	require.Nil(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestLoadDefaults(t *testing.T) {
+	c, err := load(nil, mapSource(map[string]string{
+		"DB_TYPE": "sqlite3",
+		"DB_NAME": "users.db",
+	}))
+
+	require.Nil(t, err)
+	assert.Equal(t, "error", c.LogLevel)
+	assert.Equal(t, "disable", c.DBSSLMode)
+	assert.Equal(t, 10, c.DBConnectRetries)
+	assert.Equal(t, 500*time.Millisecond, c.ChangeFeedPollInterval)
+	assert.True(t, c.WebhookEnabled)
+	assert.Nil(t, c.DBReplicaDSNs)
+}
+
+func TestLoadLayers(t *testing.T) {
+	dir := tempDir(t)
+	file := writeFile(t, dir, "config.yaml", `
+db_type: postgres
+db_user: file
+db_host: file
+db_name: file
+log_level: debug
+db_port: 5433
+db_replica_dsns:
+  - first
+  - second
+`)
+
+	c, err := load(
+		[]string{"-config", file, "-db-host", "flag"},
+		mapSource(map[string]string{
+			"DB_USER": "env",
+			"DB_HOST": "env",
+		}),
+	)
+
+	require.Nil(t, err)
+	assert.Equal(t, "postgres", c.DBType)
+	assert.Equal(t, "file", c.DBName)
+	assert.Equal(t, "env", c.DBUser)
+	assert.Equal(t, "flag", c.DBHost)
+	assert.Equal(t, "debug", c.LogLevel)
+	assert.Equal(t, 5433, c.DBPort)
+	assert.Equal(t, []string{"first", "second"}, c.DBReplicaDSNs)
+}
+
+func TestLoadJSONFileFromEnvironment(t *testing.T) {
+	dir := tempDir(t)
+	file := writeFile(t, dir, "config.json", `{
+  "db_type": "sqlite3",
+  "db_name": "users.db",
+  "user_cache_enabled": true,
+  "user_cache_ttl": "5m",
+  "db_replica_dsns": ["first", "second#2"]
+}`)
+
+	c, err := load(nil, mapSource(map[string]string{"CONFIG_FILE": file}))
+
+	require.Nil(t, err)
+	assert.Equal(t, "users.db", c.DBName)
+	assert.True(t, c.UserCacheEnabled)
+	assert.Equal(t, 5*time.Minute, c.UserCacheTTL)
+	assert.Equal(t, []string{"first", "second#2"}, c.DBReplicaDSNs)
+}
+
+func TestLoadTOMLFileFromEnvironment(t *testing.T) {
+	dir := tempDir(t)
+	file := writeFile(t, dir, "config.toml", `
+# database
+db_type = "sqlite3"
+db_name = 'users.db' # path
+db_port = 5433
+user_cache_enabled = true
+user_cache_ttl = "5m"
+db_replica_dsns = [
+  "first",
+  "second#2",
+]
+`)
+
+	c, err := load(nil, mapSource(map[string]string{"CONFIG_FILE": file}))
+
+	require.Nil(t, err)
+	assert.Equal(t, "sqlite3", c.DBType)
+	assert.Equal(t, "users.db", c.DBName)
+	assert.Equal(t, 5433, c.DBPort)
+	assert.True(t, c.UserCacheEnabled)
+	assert.Equal(t, 5*time.Minute, c.UserCacheTTL)
+	assert.Equal(t, []string{"first", "second#2"}, c.DBReplicaDSNs)
+}
+
+func TestLoadTOMLFileWithTable(t *testing.T) {
+	dir := tempDir(t)
+	file := writeFile(t, dir, "config.toml", `
+[db]
+type = "sqlite3"
+`)
+
+	_, err := load(nil, mapSource(map[string]string{"CONFIG_FILE": file}))
+
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "nested setting db is not supported")
+}
+
+func TestLoadUnsupportedFile(t *testing.T) {
+	dir := tempDir(t)
+	file := writeFile(t, dir, "config.ini", `db_type=sqlite3`)
+
+	_, err := load(nil, mapSource(map[string]string{"CONFIG_FILE": file}))
+
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unsupported config file format .ini")
+}
+
+func TestLoadSecretFromFile(t *testing.T) {
+	dir := tempDir(t)
+	password := writeFile(t, dir, "password", "s3cret\n")
+	env := map[string]string{
+		"DB_TYPE":      "postgres",
+		"DB_USER":      "root",
+		"DB_HOST":      "localhost",
+		"DB_NAME":      "users",
+		"DB_PASS_FILE": password,
+	}
+
+	c, err := load(nil, mapSource(env))
+	require.Nil(t, err)
+	assert.Equal(t, "s3cret", c.DBPass)
+	assert.Equal(t, redacted, c.Dump()["DB_PASS"])
+	assert.Equal(t, "root", c.Dump()["DB_USER"])
+	assert.Equal(t, "10s", c.Dump()["DB_REPLICA_MAX_LAG"])
+
+	env["DB_PASS"] = "other"
+	_, err = load(nil, mapSource(env))
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "DB_PASS: is set together with DB_PASS_FILE")
+
+	c, err = load([]string{"-db-pass", "flag"}, mapSource(map[string]string{
+		"DB_TYPE":      "postgres",
+		"DB_USER":      "root",
+		"DB_HOST":      "localhost",
+		"DB_NAME":      "users",
+		"DB_PASS_FILE": password,
+	}))
+	require.Nil(t, err)
+	assert.Equal(t, "flag", c.DBPass)
+}
+
+func TestLoadAggregatesErrors(t *testing.T) {
+	_, err := load(nil, mapSource(map[string]string{
+		"DB_TYPE":           "oracle",
+		"DB_PORT":           "port",
+		"LOG_LEVEL":         "loud",
+		"OUTBOX_BATCH_SIZE": "0",
+		"USER_CACHE_TTL":    "forever",
+		"OUTBOX_SINK":       "webhook",
+	}))
+
+	require.NotNil(t, err)
+	errs, ok := err.(ValidationErrors)
+	require.True(t, ok)
+	assert.Equal(t, ValidationErrors{
+		`DB_TYPE: "oracle" is not one of postgres, mysql, sqlite3`,
+		`DB_PORT: "port" is not an integer`,
+		`OUTBOX_BATCH_SIZE: must be at least 1`,
+		`USER_CACHE_TTL: "forever" is not a duration`,
+		`LOG_LEVEL: not a valid logrus Level: "loud"`,
+		`DB_NAME: is required when DB_DSN is not set`,
+		`DB_USER: is required when DB_DSN is not set`,
+		`DB_HOST: is required when DB_DSN is not set`,
+		`OUTBOX_WEBHOOK_URL: is required when OUTBOX_SINK is webhook`,
+	}, errs)
+}
+
+func TestLoadValidatesAuthSettings(t *testing.T) {
+	_, err := load(nil, mapSource(map[string]string{
+		"DB_TYPE":                 "sqlite3",
+		"DB_NAME":                 "users.db",
+		"PASSWORD_ARGON2_THREADS": "256",
+		"AUTH_TOKEN_SECRET":       "short",
+	}))
+
+	require.NotNil(t, err)
+	assert.Equal(t, ValidationErrors{
+		`PASSWORD_ARGON2_THREADS: must be at most 255`,
+		`AUTH_TOKEN_SECRET: must be at least 32 characters long`,
+	}, err)
+}
+
+func TestLoadValidatesOutboxKafkaSettings(t *testing.T) {
+	_, err := load(nil, mapSource(map[string]string{
+		"DB_TYPE":            "sqlite3",
+		"DB_NAME":            "users.db",
+		"OUTBOX_SINK":        "kafka",
+		"OUTBOX_KAFKA_TOPIC": "",
+	}))
+
+	require.NotNil(t, err)
+	assert.Equal(t, ValidationErrors{
+		`OUTBOX_KAFKA_URL: is required when OUTBOX_SINK is kafka`,
+		`OUTBOX_KAFKA_TOPIC: is required when OUTBOX_SINK is kafka`,
+	}, err)
+}
+
+func TestLoadRejectsUnknownFileSetting(t *testing.T) {
+	dir := tempDir(t)
+	file := writeFile(t, dir, "config.yml", "db_type: sqlite3\ndb_name: users.db\ndb_passwd: secret\n")
+
+	_, err := load([]string{"-config", file}, mapSource(map[string]string{}))
+
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "CONFIG_FILE: unknown setting DB_PASSWD in config file")
+}
+
+func TestReloaded(t *testing.T) {
+	current := &Config{LogLevel: "error", DBHost: "first", UserCacheSize: 10}
+	next := &Config{LogLevel: "debug", DBHost: "second", UserCacheSize: 10}
+
+	reloaded, ignored := current.reloaded(next)
+
+	assert.Equal(t, "debug", reloaded.LogLevel)
+	assert.Equal(t, "first", reloaded.DBHost)
+	assert.Equal(t, []string{"DB_HOST"}, ignored)
+	assert.Equal(t, "error", current.LogLevel)
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// readFile reads flat YAML, JSON or TOML config file chosen by the file extension.
+// Keys are names of settings in any case, e.g. db_user or DB_USER, lists are allowed for list settings.
+func readFile(path string, all []setting) (source, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "impossible to read config file")
+	}
+
+	var values map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	// JSON is parsed as YAML, which is its superset
+	case ".yaml", ".yml", ".json":
+		values, err = parseYAML(content)
+	case ".toml":
+		values, err = parseTOML(content)
+	default:
+		return nil, errors.Errorf("unsupported config file format %s", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "impossible to parse config file %s", path)
+	}
+
+	known := map[string]bool{}
+	for _, s := range all {
+		known[s.name] = true
+		known[s.name+secretFileSuffix] = s.secret
+	}
+	for name := range values {
+		if !known[name] {
+			return nil, errors.Errorf("unknown setting %s in config file", name)
+		}
+	}
+
+	return mapSource(values), nil
+}
+
+func parseYAML(content []byte) (map[string]string, error) {
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	return flatValues(raw)
+}
+
+func parseTOML(content []byte) (map[string]string, error) {
+	raw := map[string]interface{}{}
+	if _, err := toml.Decode(string(content), &raw); err != nil {
+		return nil, err
+	}
+
+	return flatValues(raw)
+}
+
+// flatValues converts decoded top-level keys to settings, lists are joined with commas and tables are rejected.
+func flatValues(raw map[string]interface{}) (map[string]string, error) {
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		name := strings.ToUpper(key)
+		switch v := value.(type) {
+		case nil:
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			values[name] = strings.Join(items, ",")
+		case map[interface{}]interface{}, map[string]interface{}, []map[string]interface{}:
+			return nil, errors.Errorf("nested setting %s is not supported", key)
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,318 @@
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// configFileSetting is a setting with path of the config file, it is read from environment or -config flag.
+const configFileSetting = "CONFIG_FILE"
+
+// secretFileSuffix is added to the name of secret setting to read its value from a file, e.g. DB_PASS_FILE.
+const secretFileSuffix = "_FILE"
+
+// redacted replaces values of secret settings in the dump.
+const redacted = "******"
+
+// ValidationErrors contains all problems found in configuration.
+type ValidationErrors []string
+
+// Error returns all problems in one message.
+func (e ValidationErrors) Error() string {
+	return "invalid configuration: " + strings.Join(e, "; ")
+}
+
+// source returns raw value of the setting, ok is false when the setting is not set.
+type source func(name string) (value string, ok bool)
+
+func newEnvSource() source {
+	return os.LookupEnv
+}
+
+// setting describes a field of Config.
+type setting struct {
+	name       string
+	index      int
+	def        string
+	hasDefault bool
+	required   bool
+	oneOf      []string
+	min        string
+	secret     bool
+	reload     bool
+}
+
+func settings() []setting {
+	t := reflect.TypeOf(Config{})
+	result := make([]setting, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag
+		def, hasDefault := tag.Lookup("default")
+		s := setting{
+			name:       tag.Get("config"),
+			index:      i,
+			def:        def,
+			hasDefault: hasDefault,
+			required:   tag.Get("required") == "true",
+			min:        tag.Get("min"),
+			secret:     tag.Get("secret") == "true",
+			reload:     tag.Get("reload") == "true",
+		}
+		if oneOf := tag.Get("oneof"); oneOf != "" {
+			s.oneOf = strings.Fields(oneOf)
+		}
+		result = append(result, s)
+	}
+
+	return result
+}
+
+// load reads settings from sources ordered by priority: config file, environment and command line flags.
+// Secret settings can be read from a file named by setting with _FILE suffix.
+// All invalid settings are reported at once by ValidationErrors.
+func load(args []string, env source) (*Config, error) {
+	all := settings()
+
+	flags, err := parseFlags(args, all)
+	if err != nil {
+		return nil, errors.Wrap(err, "impossible to parse command line flags")
+	}
+
+	var errs ValidationErrors
+	layers := []source{}
+	path, ok := flags(configFileSetting)
+	if !ok {
+		path, ok = env(configFileSetting)
+	}
+	if ok {
+		file, err := readFile(path, all)
+		if err != nil {
+			errs = append(errs, configFileSetting+": "+err.Error())
+		} else {
+			layers = append(layers, file)
+		}
+	}
+	layers = append(layers, env, flags)
+
+	var c Config
+	value := reflect.ValueOf(&c).Elem()
+	for _, s := range all {
+		raw, ok, err := resolve(s, layers)
+		if err != nil {
+			errs = append(errs, s.name+": "+err.Error())
+			continue
+		}
+		if !ok {
+			if s.required {
+				errs = append(errs, s.name+": is required")
+				continue
+			}
+			if !s.hasDefault {
+				continue
+			}
+			raw = s.def
+		}
+
+		field := value.Field(s.index)
+		if err := setValue(field, raw); err != nil {
+			errs = append(errs, s.name+": "+err.Error())
+			continue
+		}
+		if err := s.check(field, raw); err != nil {
+			errs = append(errs, s.name+": "+err.Error())
+		}
+	}
+
+	if errs = append(errs, c.validate()...); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return &c, nil
+}
+
+// resolve returns value of the setting from the source with the highest priority.
+func resolve(s setting, layers []source) (string, bool, error) {
+	var value string
+	var found bool
+	for _, layer := range layers {
+		v, ok := layer(s.name)
+		if s.secret {
+			if path, fromFile := layer(s.name + secretFileSuffix); fromFile {
+				if ok {
+					return "", false, errors.Errorf("is set together with %s%s", s.name, secretFileSuffix)
+				}
+				content, err := ioutil.ReadFile(path)
+				if err != nil {
+					return "", false, errors.Wrap(err, "impossible to read secret file")
+				}
+				v, ok = strings.TrimRight(string(content), "\r\n"), true
+			}
+		}
+		if ok {
+			value, found = v, true
+		}
+	}
+
+	return value, found, nil
+}
+
+// parseFlags defines a flag for every setting, e.g. -db-user for DB_USER, and -config for the config file.
+// Returned source contains only flags set in arguments.
+func parseFlags(args []string, all []setting) (source, error) {
+	fs := flag.NewFlagSet("user-service", flag.ContinueOnError)
+	names := map[string]string{"config": configFileSetting}
+	fs.String("config", "", "path of YAML, JSON or TOML config file")
+	for _, s := range all {
+		names[flagName(s.name)] = s.name
+		fs.String(flagName(s.name), "", s.name)
+		if s.secret {
+			names[flagName(s.name+secretFileSuffix)] = s.name + secretFileSuffix
+			fs.String(flagName(s.name+secretFileSuffix), "", "file with "+s.name)
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		values[names[f.Name]] = f.Value.String()
+	})
+
+	return mapSource(values), nil
+}
+
+func flagName(name string) string {
+	return strings.ToLower(strings.Replace(name, "_", "-", -1))
+}
+
+func mapSource(values map[string]string) source {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+func setValue(field reflect.Value, raw string) error {
+	switch field.Interface().(type) {
+	case string:
+		field.SetString(raw)
+	case int:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return errors.Errorf("%q is not an integer", raw)
+		}
+		field.SetInt(int64(v))
+	case bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.Errorf("%q is not a boolean", raw)
+		}
+		field.SetBool(v)
+	case time.Duration:
+		v, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.Errorf("%q is not a duration", raw)
+		}
+		field.SetInt(int64(v))
+	case []string:
+		values := []string{}
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	default:
+		return errors.Errorf("unsupported type %s", field.Type())
+	}
+
+	return nil
+}
+
+// check validates value of the field against oneof and min constraints.
+func (s setting) check(field reflect.Value, raw string) error {
+	if len(s.oneOf) > 0 {
+		allowed := false
+		for _, v := range s.oneOf {
+			allowed = allowed || v == raw
+		}
+		if !allowed {
+			return errors.Errorf("%q is not one of %s", raw, strings.Join(s.oneOf, ", "))
+		}
+	}
+
+	if s.min == "" {
+		return nil
+	}
+
+	switch v := field.Interface().(type) {
+	case time.Duration:
+		if min, _ := time.ParseDuration(s.min); v < min {
+			return errors.Errorf("must be at least %s", min)
+		}
+	case int:
+		if min, _ := strconv.Atoi(s.min); v < min {
+			return errors.Errorf("must be at least %d", min)
+		}
+	}
+
+	return nil
+}
+
+// Dump returns all settings with values of secret settings redacted.
+func (c Config) Dump() map[string]interface{} {
+	value := reflect.ValueOf(c)
+	dump := make(map[string]interface{})
+	for _, s := range settings() {
+		field := value.Field(s.index)
+		switch v := field.Interface().(type) {
+		case []string:
+			if s.secret && len(v) > 0 {
+				dump[s.name] = redacted
+			} else {
+				dump[s.name] = strings.Join(v, ",")
+			}
+		case time.Duration:
+			dump[s.name] = v.String()
+		default:
+			if s.secret && !field.IsZero() {
+				dump[s.name] = redacted
+			} else {
+				dump[s.name] = v
+			}
+		}
+	}
+
+	return dump
+}
+
+// reloaded returns copy of the config with settings which can be changed at runtime taken from next config.
+// Names of other changed settings are also returned, they require restart.
+func (c Config) reloaded(next *Config) (*Config, []string) {
+	result := c
+	current := reflect.ValueOf(&result).Elem()
+	updated := reflect.ValueOf(next).Elem()
+
+	var ignored []string
+	for _, s := range settings() {
+		if reflect.DeepEqual(current.Field(s.index).Interface(), updated.Field(s.index).Interface()) {
+			continue
+		}
+		if !s.reload {
+			ignored = append(ignored, s.name)
+			continue
+		}
+		current.Field(s.index).Set(updated.Field(s.index))
+	}
+
+	return &result, ignored
+}
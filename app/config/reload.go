@@ -0,0 +1,46 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Watch reloads configuration with the same arguments on SIGHUP until the context is cancelled.
+// Only settings which can be changed at runtime (e.g. LOG_LEVEL) are passed to apply,
+// changes of other settings are logged and ignored until restart.
+func Watch(ctx context.Context, current *Config, args []string, apply func(c *Config)) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+		}
+
+		next, err := New(args)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"err": err,
+			}).Error("impossible to reload config, previous one is kept")
+			continue
+		}
+
+		var ignored []string
+		current, ignored = current.reloaded(next)
+		if len(ignored) > 0 {
+			log.WithFields(log.Fields{
+				"settings": ignored,
+			}).Warn("changed settings require restart and are ignored")
+		}
+
+		apply(current)
+		log.Info("config reloaded")
+	}
+}
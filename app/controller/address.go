@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/middleware"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// GetAddressList handles GET /v1/users/:user_id/addresses endpoint
+func (c Controller) GetAddressList(context *gin.Context) {
+	addresses, err := c.addressService.GetAddresses(context.GetInt(middleware.UserIDParamKey))
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	addressListResponse := make([]response.Address, 0, len(addresses))
+	for i := range addresses {
+		addressListResponse = append(addressListResponse, newAddressResponse(&addresses[i]))
+	}
+
+	context.JSON(http.StatusOK, response.AddressList{
+		Result: addressListResponse,
+	})
+}
+
+// GetAddress handles GET /v1/users/:user_id/addresses/:address_id endpoint
+func (c Controller) GetAddress(context *gin.Context) {
+	a, err := c.addressService.GetAddress(
+		context.GetInt(middleware.UserIDParamKey),
+		context.GetInt(middleware.AddressIDParamKey),
+	)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, newAddressResponse(a))
+}
+
+// CreateAddress handles POST /v1/users/:user_id/addresses endpoint
+func (c Controller) CreateAddress(context *gin.Context) {
+
+	var req request.CreateAddress
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	a, err := c.addressService.CreateAddress(context.GetInt(middleware.UserIDParamKey), &req)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, response.CreateAddress{
+		ID: a.ID,
+	})
+}
+
+// UpdateAddress handles PUT /v1/users/:user_id/addresses/:address_id endpoint
+func (c Controller) UpdateAddress(context *gin.Context) {
+
+	var req request.UpdateAddress
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	if err := c.addressService.UpdateAddress(
+		context.GetInt(middleware.UserIDParamKey),
+		context.GetInt(middleware.AddressIDParamKey),
+		&req,
+	); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+// DeleteAddress handles DELETE /v1/users/:user_id/addresses/:address_id endpoint
+func (c Controller) DeleteAddress(context *gin.Context) {
+	if err := c.addressService.DeleteAddress(
+		context.GetInt(middleware.UserIDParamKey),
+		context.GetInt(middleware.AddressIDParamKey),
+	); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+func newAddressResponse(a *model.Address) response.Address {
+	return response.Address{
+		ID:         a.ID,
+		Type:       a.Type,
+		Street:     a.Street,
+		City:       a.City,
+		PostalCode: a.PostalCode,
+		Region:     a.Region,
+		Country:    a.Country,
+		Primary:    a.Primary,
+		CreatedAt:  a.CreatedAt,
+	}
+}
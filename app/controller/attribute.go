@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/middleware"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// GetAttributeDefinitionList handles GET /v1/admin/attribute-definitions endpoint
+func (c Controller) GetAttributeDefinitionList(context *gin.Context) {
+	definitions, err := c.attributeService.GetAttributeDefinitions()
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	definitionListResponse := make([]response.AttributeDefinition, 0, len(definitions))
+	for i := range definitions {
+		definitionListResponse = append(definitionListResponse, newAttributeDefinitionResponse(&definitions[i]))
+	}
+
+	context.JSON(http.StatusOK, response.AttributeDefinitionList{
+		Result: definitionListResponse,
+	})
+}
+
+// GetAttributeDefinition handles GET /v1/admin/attribute-definitions/:definition_id endpoint
+func (c Controller) GetAttributeDefinition(context *gin.Context) {
+	d, err := c.attributeService.GetAttributeDefinition(context.GetInt(middleware.AttributeDefinitionIDParamKey))
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, newAttributeDefinitionResponse(d))
+}
+
+// CreateAttributeDefinition handles POST /v1/admin/attribute-definitions endpoint
+func (c Controller) CreateAttributeDefinition(context *gin.Context) {
+
+	var req request.CreateAttributeDefinition
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	definitionID, err := c.attributeService.CreateAttributeDefinition(&req)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, response.CreateAttributeDefinition{
+		ID: definitionID,
+	})
+}
+
+// UpdateAttributeDefinition handles PUT /v1/admin/attribute-definitions/:definition_id endpoint
+func (c Controller) UpdateAttributeDefinition(context *gin.Context) {
+
+	var req request.UpdateAttributeDefinition
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	if err := c.attributeService.UpdateAttributeDefinition(
+		context.GetInt(middleware.AttributeDefinitionIDParamKey),
+		&req,
+	); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+// DeleteAttributeDefinition handles DELETE /v1/admin/attribute-definitions/:definition_id endpoint
+func (c Controller) DeleteAttributeDefinition(context *gin.Context) {
+	if err := c.attributeService.DeleteAttributeDefinition(
+		context.GetInt(middleware.AttributeDefinitionIDParamKey),
+	); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+func newAttributeDefinitionResponse(d *model.AttributeDefinition) response.AttributeDefinition {
+	enum := d.EnumValues()
+	if enum == nil {
+		enum = []string{}
+	}
+
+	return response.AttributeDefinition{
+		ID:        d.ID,
+		Name:      d.Name,
+		Type:      d.Type,
+		Required:  d.Required,
+		Enum:      enum,
+		Pattern:   d.Pattern,
+		CreatedAt: d.CreatedAt,
+	}
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/middleware"
+	"github.com/mmgopher/user-service/app/service/user"
+)
+
+// tokenTypeBearer is type of issued access tokens
+const tokenTypeBearer = "Bearer"
+
+// Login handles POST /v1/auth/login endpoint
+func (c Controller) Login(context *gin.Context) {
+
+	var req request.Login
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	result, err := c.authService.Login(&req, newClient(context))
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	if result.MFAChallenge != nil {
+		context.JSON(http.StatusAccepted, response.MFAChallenge{
+			MFAToken:  result.MFAChallenge.Token,
+			ExpiresAt: result.MFAChallenge.ExpiresAt,
+		})
+		return
+	}
+
+	context.JSON(http.StatusOK, newTokenResponse(result.Tokens))
+}
+
+// LoginMFA handles POST /v1/auth/login/mfa endpoint
+func (c Controller) LoginMFA(context *gin.Context) {
+
+	var req request.LoginMFA
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	tokens, err := c.authService.LoginMFA(&req, newClient(context))
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, newTokenResponse(tokens))
+}
+
+// RequestPasswordReset handles POST /v1/auth/password-reset endpoint
+func (c Controller) RequestPasswordReset(context *gin.Context) {
+
+	var req request.RequestPasswordReset
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	if err := c.authService.RequestPasswordReset(&req); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusAccepted, gin.H{})
+}
+
+// ConfirmPasswordReset handles POST /v1/auth/password-reset/confirm endpoint
+func (c Controller) ConfirmPasswordReset(context *gin.Context) {
+
+	var req request.ConfirmPasswordReset
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	if err := c.authService.ConfirmPasswordReset(&req); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+// ChangePassword handles POST /v1/users/:user_id/password endpoint
+func (c Controller) ChangePassword(context *gin.Context) {
+
+	var req request.ChangePassword
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	if err := c.authService.ChangePassword(context.GetInt(middleware.UserIDParamKey), &req); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+func newTokenResponse(tokens *user.Tokens) response.Token {
+	return response.Token{
+		AccessToken:      tokens.Access.Value,
+		TokenType:        tokenTypeBearer,
+		ExpiresAt:        tokens.Access.ExpiresAt,
+		RefreshToken:     tokens.Refresh,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+	}
+}
+
+// newClient returns device which sent the request.
+func newClient(context *gin.Context) user.Client {
+	return user.Client{
+		IP:        context.ClientIP(),
+		UserAgent: context.Request.UserAgent(),
+	}
+}
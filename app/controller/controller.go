@@ -9,20 +9,49 @@ import (
 	"github.com/mmgopher/user-service/app/api/response"
 	"github.com/mmgopher/user-service/app/httperrors"
 	"github.com/mmgopher/user-service/app/middleware"
+	"github.com/mmgopher/user-service/app/service/changefeed"
 	"github.com/mmgopher/user-service/app/service/user"
+	"github.com/mmgopher/user-service/app/service/webhook"
 )
 
 // Controller represents Controller layer of application.
 type Controller struct {
-	userService user.Provider
+	userService              user.Provider
+	webhookService           webhook.Provider
+	changeFeedService        changefeed.Provider
+	emailVerificationService user.EmailVerificationProvider
+	addressService           user.AddressProvider
+	attributeService         user.AttributeDefinitionProvider
+	groupService             user.GroupProvider
+	authService              user.AuthProvider
+	mfaService               user.MFAProvider
+	sessionService           user.SessionProvider
 }
 
 // New creates new instance of Controller.
 func New(
 	userService user.Provider,
+	webhookService webhook.Provider,
+	changeFeedService changefeed.Provider,
+	emailVerificationService user.EmailVerificationProvider,
+	addressService user.AddressProvider,
+	attributeService user.AttributeDefinitionProvider,
+	groupService user.GroupProvider,
+	authService user.AuthProvider,
+	mfaService user.MFAProvider,
+	sessionService user.SessionProvider,
 ) *Controller {
 	return &Controller{
-		userService: userService,
+		userService:              userService,
+		webhookService:           webhookService,
+		changeFeedService:        changeFeedService,
+		emailVerificationService: emailVerificationService,
+		addressService:           addressService,
+		attributeService:         attributeService,
+		groupService:             groupService,
+		authService:              authService,
+		mfaService:               mfaService,
+		sessionService:           sessionService,
 	}
 }
 
@@ -33,15 +62,7 @@ func (c Controller) GetUser(context *gin.Context) {
 		httperrors.Emit(context, err)
 		return
 	}
-	context.JSON(http.StatusOK, response.User{
-		ID:        u.ID,
-		Name:      u.Name,
-		Surname:   u.Surname,
-		Gender:    u.Gender,
-		Age:       u.Age,
-		Address:   u.Address,
-		CreatedAt: u.CreatedAt,
-	})
+	context.JSON(http.StatusOK, newUserResponse(u))
 }
 
 // DeleteUser handles DELETE /v1/users/:user_id endpoint
@@ -100,6 +121,7 @@ func (c Controller) GetUserList(context *gin.Context) {
 		httperrors.Emit(context, httperrors.QueryParametersParsingError.WithCause(err))
 		return
 	}
+	req.Attributes = getAttributeFilters(context.Request.URL)
 
 	users, beforeID, afterID, err := c.userService.FindUsers(&req)
 	if err != nil {
@@ -110,17 +132,8 @@ func (c Controller) GetUserList(context *gin.Context) {
 	prevURL, nextURL := getPaginationURLs(context.Request.URL, beforeID, afterID)
 	userListResponse := make([]response.User, 0, len(users))
 
-	for _, u := range users {
-		userListResponse = append(userListResponse, response.User{
-			ID:        u.ID,
-			Name:      u.Name,
-			Surname:   u.Surname,
-			Age:       u.Age,
-			Gender:    u.Gender,
-			Address:   u.Address,
-			CreatedAt: u.CreatedAt,
-		})
-
+	for i := range users {
+		userListResponse = append(userListResponse, newUserResponse(&users[i]))
 	}
 
 	context.JSON(http.StatusOK, response.UserListWithPagination{
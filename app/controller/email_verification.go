@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/middleware"
+)
+
+// RequestEmailVerification handles POST /v1/users/:user_id/email/verify endpoint
+func (c Controller) RequestEmailVerification(context *gin.Context) {
+	verification, err := c.emailVerificationService.RequestEmailVerification(
+		context.GetInt(middleware.UserIDParamKey),
+	)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusAccepted, response.EmailVerification{
+		ExpiresAt: verification.ExpiresAt,
+	})
+}
+
+// ConfirmEmailVerification handles POST /v1/users/:user_id/email/verify/confirm endpoint
+func (c Controller) ConfirmEmailVerification(context *gin.Context) {
+
+	var req request.ConfirmEmailVerification
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	if err := c.emailVerificationService.ConfirmEmailVerification(
+		context.GetInt(middleware.UserIDParamKey),
+		&req,
+	); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
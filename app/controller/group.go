@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/middleware"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// GetGroupList handles GET /v1/groups endpoint
+func (c Controller) GetGroupList(context *gin.Context) {
+	groups, err := c.groupService.GetGroups()
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	groupListResponse := make([]response.Group, 0, len(groups))
+	for i := range groups {
+		groupListResponse = append(groupListResponse, newGroupResponse(&groups[i]))
+	}
+
+	context.JSON(http.StatusOK, response.GroupList{
+		Result: groupListResponse,
+	})
+}
+
+// GetGroup handles GET /v1/groups/:group_id endpoint
+func (c Controller) GetGroup(context *gin.Context) {
+	g, err := c.groupService.GetGroup(context.GetInt(middleware.GroupIDParamKey))
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, newGroupResponse(g))
+}
+
+// CreateGroup handles POST /v1/groups endpoint
+func (c Controller) CreateGroup(context *gin.Context) {
+
+	var req request.CreateGroup
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	groupID, err := c.groupService.CreateGroup(&req)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, response.CreateGroup{
+		ID: groupID,
+	})
+}
+
+// UpdateGroup handles PUT /v1/groups/:group_id endpoint
+func (c Controller) UpdateGroup(context *gin.Context) {
+
+	var req request.UpdateGroup
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	if err := c.groupService.UpdateGroup(context.GetInt(middleware.GroupIDParamKey), &req); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+// DeleteGroup handles DELETE /v1/groups/:group_id endpoint
+func (c Controller) DeleteGroup(context *gin.Context) {
+	if err := c.groupService.DeleteGroup(context.GetInt(middleware.GroupIDParamKey)); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+// GetGroupMemberList handles GET /v1/groups/:group_id/members endpoint
+func (c Controller) GetGroupMemberList(context *gin.Context) {
+	var req request.FindGroupMembers
+	if err := context.ShouldBindQuery(&req); err != nil {
+		httperrors.Emit(context, httperrors.QueryParametersParsingError.WithCause(err))
+		return
+	}
+
+	users, beforeID, afterID, err := c.groupService.GetGroupMembers(context.GetInt(middleware.GroupIDParamKey), &req)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	prevURL, nextURL := getPaginationURLs(context.Request.URL, beforeID, afterID)
+	userListResponse := make([]response.User, 0, len(users))
+
+	for i := range users {
+		userListResponse = append(userListResponse, newUserResponse(&users[i]))
+	}
+
+	context.JSON(http.StatusOK, response.UserListWithPagination{
+		Result: userListResponse,
+		Pagination: response.Pagination{
+			PrevLink: prevURL,
+			BeforeID: beforeID,
+			NextLink: nextURL,
+			AfterID:  afterID,
+		},
+	})
+}
+
+// AddGroupMembers handles POST /v1/groups/:group_id/members endpoint
+func (c Controller) AddGroupMembers(context *gin.Context) {
+
+	var req request.GroupMembers
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	count, err := c.groupService.AddGroupMembers(context.GetInt(middleware.GroupIDParamKey), &req)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, response.GroupMembersChange{
+		Count: count,
+	})
+}
+
+// RemoveGroupMembers handles DELETE /v1/groups/:group_id/members endpoint
+func (c Controller) RemoveGroupMembers(context *gin.Context) {
+
+	var req request.GroupMembers
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	count, err := c.groupService.RemoveGroupMembers(context.GetInt(middleware.GroupIDParamKey), &req)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, response.GroupMembersChange{
+		Count: count,
+	})
+}
+
+func newGroupResponse(g *model.Group) response.Group {
+	return response.Group{
+		ID:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		CreatedAt:   g.CreatedAt,
+	}
+}
@@ -3,8 +3,46 @@ package controller
 import (
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/model"
 )
 
+// attributeFilterPrefix marks query parameters which filter users by custom attributes, e.g. attr.department=sales.
+const attributeFilterPrefix = "attr."
+
+func newUserResponse(u *model.User) response.User {
+	return response.User{
+		ID:            u.ID,
+		Name:          u.Name,
+		Surname:       u.Surname,
+		Gender:        u.Gender,
+		DateOfBirth:   u.DateOfBirth.Format(model.DateLayout),
+		Age:           u.AgeAt(time.Now()),
+		Address:       u.Address,
+		Email:         u.Email,
+		Phone:         u.Phone,
+		EmailVerified: u.EmailVerifiedAt != nil,
+		Attributes:    u.AttributeValues(),
+		Status:        u.Status,
+		CreatedAt:     u.CreatedAt,
+	}
+}
+
+// getAttributeFilters returns filters by custom attributes passed as attr.<name> query parameters.
+func getAttributeFilters(reqURL *url.URL) map[string]string {
+	filters := make(map[string]string)
+	for key, values := range reqURL.Query() {
+		if strings.HasPrefix(key, attributeFilterPrefix) && len(values) > 0 {
+			filters[strings.TrimPrefix(key, attributeFilterPrefix)] = values[0]
+		}
+	}
+
+	return filters
+}
+
 func getPaginationURLs(reqURL *url.URL, beforeID int, afterID int) (prevURL, nextURL string) {
 
 	// keep all query params, except pagination related
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/middleware"
+)
+
+// EnrollMFA handles POST /v1/users/:user_id/mfa/enroll endpoint
+func (c Controller) EnrollMFA(context *gin.Context) {
+	enrolment, err := c.mfaService.Enroll(context.GetInt(middleware.UserIDParamKey))
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, response.MFAEnrolment{
+		Secret:     enrolment.Secret,
+		OTPAuthURI: enrolment.KeyURI,
+		QRCode:     base64.StdEncoding.EncodeToString(enrolment.QRCode),
+	})
+}
+
+// ActivateMFA handles POST /v1/users/:user_id/mfa/activate endpoint
+func (c Controller) ActivateMFA(context *gin.Context) {
+
+	var req request.MFACode
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	codes, err := c.mfaService.Activate(context.GetInt(middleware.UserIDParamKey), &req)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, response.MFARecoveryCodes{RecoveryCodes: codes})
+}
+
+// DisableMFA handles POST /v1/users/:user_id/mfa/disable endpoint
+func (c Controller) DisableMFA(context *gin.Context) {
+
+	var req request.MFACode
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	if err := c.mfaService.Disable(context.GetInt(middleware.UserIDParamKey), &req); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+// RegenerateMFARecoveryCodes handles POST /v1/users/:user_id/mfa/recovery-codes endpoint
+func (c Controller) RegenerateMFARecoveryCodes(context *gin.Context) {
+
+	var req request.MFACode
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	codes, err := c.mfaService.RegenerateRecoveryCodes(context.GetInt(middleware.UserIDParamKey), &req)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, response.MFARecoveryCodes{RecoveryCodes: codes})
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/middleware"
+)
+
+// RefreshToken handles POST /v1/auth/refresh endpoint
+func (c Controller) RefreshToken(context *gin.Context) {
+
+	var req request.RefreshToken
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	tokens, err := c.sessionService.Refresh(&req, newClient(context))
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, newTokenResponse(tokens))
+}
+
+// GetSessionList handles GET /v1/users/:user_id/sessions endpoint
+func (c Controller) GetSessionList(context *gin.Context) {
+	sessions, err := c.sessionService.GetSessions(context.GetInt(middleware.UserIDParamKey))
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	sessionListResponse := make([]response.Session, 0, len(sessions))
+	for _, session := range sessions {
+		sessionListResponse = append(sessionListResponse, response.Session{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+
+	context.JSON(http.StatusOK, response.SessionList{
+		Result: sessionListResponse,
+	})
+}
+
+// RevokeSession handles DELETE /v1/users/:user_id/sessions/:session_id endpoint
+func (c Controller) RevokeSession(context *gin.Context) {
+	if err := c.sessionService.RevokeSession(
+		context.GetInt(middleware.UserIDParamKey),
+		context.GetInt(middleware.SessionIDParamKey),
+	); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+// RevokeSessions handles DELETE /v1/users/:user_id/sessions endpoint
+func (c Controller) RevokeSessions(context *gin.Context) {
+	if err := c.sessionService.RevokeSessions(context.GetInt(middleware.UserIDParamKey)); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/middleware"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// ActivateUser handles POST /v1/users/:user_id/activate endpoint
+func (c Controller) ActivateUser(context *gin.Context) {
+	c.changeUserStatus(context, model.UserStatusActive)
+}
+
+// SuspendUser handles POST /v1/users/:user_id/suspend endpoint
+func (c Controller) SuspendUser(context *gin.Context) {
+	c.changeUserStatus(context, model.UserStatusSuspended)
+}
+
+// LockUser handles POST /v1/users/:user_id/lock endpoint
+func (c Controller) LockUser(context *gin.Context) {
+	c.changeUserStatus(context, model.UserStatusLocked)
+}
+
+// CloseUser handles POST /v1/users/:user_id/close endpoint
+func (c Controller) CloseUser(context *gin.Context) {
+	c.changeUserStatus(context, model.UserStatusClosed)
+}
+
+// GetUserStatusHistory handles GET /v1/users/:user_id/status-history endpoint
+func (c Controller) GetUserStatusHistory(context *gin.Context) {
+	changes, err := c.userService.GetUserStatusHistory(context.GetInt(middleware.UserIDParamKey))
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	historyResponse := make([]response.UserStatusChange, 0, len(changes))
+	for _, change := range changes {
+		historyResponse = append(historyResponse, response.UserStatusChange{
+			ID:         change.ID,
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			Reason:     change.Reason,
+			CreatedAt:  change.CreatedAt,
+		})
+	}
+
+	context.JSON(http.StatusOK, response.UserStatusHistory{
+		Result: historyResponse,
+	})
+}
+
+// changeUserStatus handles endpoints which change status of the user to the status.
+func (c Controller) changeUserStatus(context *gin.Context, status string) {
+
+	var req request.ChangeUserStatus
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	if err := c.userService.ChangeUserStatus(context.GetInt(middleware.UserIDParamKey), status, &req); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/changefeed"
+)
+
+const (
+	eventStreamContentType = "text/event-stream"
+	// eventStreamKeepAlive defines how long stream waits for changes before sending keep-alive comment.
+	eventStreamKeepAlive = 15 * time.Second
+)
+
+// GetUserChanges handles GET /v1/users/changes endpoint.
+// Changes are streamed as Server-Sent Events when client accepts `text/event-stream`.
+func (c Controller) GetUserChanges(context *gin.Context) {
+	var req request.FindUserChanges
+	if err := context.ShouldBindQuery(&req); err != nil {
+		httperrors.Emit(context, httperrors.QueryParametersParsingError.WithCause(err))
+		return
+	}
+
+	if strings.Contains(context.GetHeader("Accept"), eventStreamContentType) {
+		c.streamUserChanges(context, &req)
+		return
+	}
+
+	changes, nextToken, err := c.changeFeedService.FindChanges(context.Request.Context(), &req)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	changeListResponse := make([]response.UserChange, 0, len(changes))
+	for i := range changes {
+		changeListResponse = append(changeListResponse, newUserChangeResponse(&changes[i]))
+	}
+
+	reqURL := context.Request.URL
+	queryParams := reqURL.Query()
+	queryParams.Set("since", nextToken)
+	reqURL.RawQuery = queryParams.Encode()
+
+	context.JSON(http.StatusOK, response.UserChangeList{
+		Result:    changeListResponse,
+		NextToken: nextToken,
+		NextLink:  reqURL.String(),
+	})
+}
+
+// streamUserChanges streams changes as Server-Sent Events until client disconnects.
+// Token of the change is sent as event ID, so client can resume with `Last-Event-ID` header.
+func (c Controller) streamUserChanges(context *gin.Context, req *request.FindUserChanges) {
+	if lastEventID := context.GetHeader("Last-Event-ID"); lastEventID != "" {
+		req.Since = lastEventID
+	}
+	req.Wait = eventStreamKeepAlive
+
+	ctx := context.Request.Context()
+	changes, nextToken, err := c.changeFeedService.FindChanges(ctx, req)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.Header("Content-Type", eventStreamContentType)
+	context.Header("Cache-Control", "no-cache")
+	context.Header("Connection", "keep-alive")
+	context.Status(http.StatusOK)
+
+	for {
+		if len(changes) == 0 {
+			//nolint
+			context.Writer.WriteString(": keep-alive\n\n")
+		}
+
+		for i := range changes {
+			change := newUserChangeResponse(&changes[i])
+			context.Render(-1, sse.Event{
+				Id:    change.Token,
+				Event: change.Type,
+				Data:  change,
+			})
+		}
+		context.Writer.Flush()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		req.Since = nextToken
+		if changes, nextToken, err = c.changeFeedService.FindChanges(ctx, req); err != nil {
+			log.Errorf("%+v", err)
+			return
+		}
+	}
+}
+
+func newUserChangeResponse(c *model.UserChange) response.UserChange {
+	change := response.UserChange{
+		Type:      "user." + c.Operation,
+		UserID:    c.UserID,
+		ChangedAt: c.ChangedAt,
+		Token:     changefeed.EncodeToken(c.Seq),
+	}
+
+	if c.User != nil {
+		user := newUserResponse(c.User)
+		change.User = &user
+	}
+
+	return change
+}
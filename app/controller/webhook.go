@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/middleware"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/webhook"
+)
+
+// GetWebhook handles GET /v1/webhooks/:webhook_id endpoint
+func (c Controller) GetWebhook(context *gin.Context) {
+	w, err := c.webhookService.GetWebhook(context.GetInt(middleware.WebhookIDParamKey))
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, newWebhookResponse(w))
+}
+
+// GetWebhookList handles GET /v1/webhooks endpoint
+func (c Controller) GetWebhookList(context *gin.Context) {
+	webhooks, err := c.webhookService.GetWebhooks()
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	webhookListResponse := make([]response.Webhook, 0, len(webhooks))
+	for i := range webhooks {
+		webhookListResponse = append(webhookListResponse, newWebhookResponse(&webhooks[i]))
+	}
+
+	context.JSON(http.StatusOK, response.WebhookList{
+		Result: webhookListResponse,
+	})
+}
+
+// CreateWebhook handles POST /v1/webhooks endpoint
+func (c Controller) CreateWebhook(context *gin.Context) {
+
+	var req request.CreateWebhook
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	w, err := c.webhookService.CreateWebhook(&req)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, response.CreateWebhook{
+		ID:     w.ID,
+		Secret: w.Secret,
+	})
+}
+
+// UpdateWebhook handles PUT /v1/webhooks/:webhook_id endpoint
+func (c Controller) UpdateWebhook(context *gin.Context) {
+
+	var req request.UpdateWebhook
+	if err := context.ShouldBindJSON(&req); err != nil {
+		httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(err))
+		return
+	}
+
+	if err := c.webhookService.UpdateWebhook(
+		context.GetInt(middleware.WebhookIDParamKey),
+		&req,
+	); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+// DeleteWebhook handles DELETE /v1/webhooks/:webhook_id endpoint
+func (c Controller) DeleteWebhook(context *gin.Context) {
+	if err := c.webhookService.DeleteWebhook(context.GetInt(middleware.WebhookIDParamKey)); err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{})
+}
+
+// GetWebhookDeliveryList handles GET /v1/webhooks/:webhook_id/deliveries endpoint
+func (c Controller) GetWebhookDeliveryList(context *gin.Context) {
+	var req request.FindWebhookDeliveries
+	if err := context.ShouldBindQuery(&req); err != nil {
+		httperrors.Emit(context, httperrors.QueryParametersParsingError.WithCause(err))
+		return
+	}
+
+	deliveries, nextBeforeID, err := c.webhookService.FindDeliveries(
+		context.GetInt(middleware.WebhookIDParamKey),
+		&req,
+	)
+	if err != nil {
+		httperrors.Emit(context, err)
+		return
+	}
+
+	var nextURL string
+	if nextBeforeID > 0 {
+		reqURL := context.Request.URL
+		queryParams := reqURL.Query()
+		queryParams.Set("before_id", strconv.FormatInt(nextBeforeID, 10))
+		reqURL.RawQuery = queryParams.Encode()
+		nextURL = reqURL.String()
+	}
+
+	deliveryListResponse := make([]response.WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		deliveryListResponse = append(deliveryListResponse, response.WebhookDelivery{
+			ID:             d.ID,
+			EventID:        d.EventID,
+			EventType:      d.EventType,
+			Payload:        []byte(d.Payload),
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			NextAttemptAt:  d.NextAttemptAt,
+			DeliveredAt:    d.DeliveredAt,
+			CreatedAt:      d.CreatedAt,
+		})
+	}
+
+	context.JSON(http.StatusOK, response.WebhookDeliveryList{
+		Result:   deliveryListResponse,
+		NextLink: nextURL,
+	})
+}
+
+func newWebhookResponse(w *model.Webhook) response.Webhook {
+	return response.Webhook{
+		ID:         w.ID,
+		URL:        w.URL,
+		EventTypes: webhook.EventTypes(w),
+		Active:     w.Active,
+		CreatedAt:  w.CreatedAt,
+	}
+}
@@ -0,0 +1,201 @@
+package dao
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// AddressRepositoryProvider provides an interface to work with database Address entity
+type AddressRepositoryProvider interface {
+	// GetByID returns Address object by ID
+	GetByID(addressID int) (*model.Address, error)
+	// FindByUserID returns all addresses of the user ordered by ID
+	FindByUserID(userID int) ([]model.Address, error)
+	// Create creates new Address record
+	Create(address *model.Address) (int, error)
+	// Update updates address record
+	Update(address *model.Address) (bool, error)
+	// Delete deletes address record
+	Delete(addressID int) (bool, error)
+	// ClearPrimary unmarks primary address of the user
+	ClearPrimary(userID int) error
+}
+
+// AddressRepository represents object to work with database Address entity
+type AddressRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewAddressRepository creates new instance of AddressRepository.
+func NewAddressRepository(db *sqlx.DB) *AddressRepository {
+	return newAddressRepository(db)
+}
+
+func newAddressRepository(db executor) *AddressRepository {
+	return &AddressRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// GetByID returns Address object by ID
+func (r AddressRepository) GetByID(addressID int) (*model.Address, error) {
+	var address model.Address
+	if err := r.db.Get(&address, r.dialect.Query(`
+		SELECT id,
+		       user_id,
+		       type,
+		       street,
+		       city,
+		       postal_code,
+		       region,
+		       country,
+		       is_primary,
+		       created_at
+		FROM user_sch.address
+		WHERE id = ?`), addressID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "impossible to get address, addressID=%d", addressID)
+	}
+
+	return &address, nil
+}
+
+// FindByUserID returns all addresses of the user ordered by ID
+func (r AddressRepository) FindByUserID(userID int) ([]model.Address, error) {
+	addresses := []model.Address{}
+	if err := r.db.Select(&addresses, r.dialect.Query(`
+		SELECT id,
+		       user_id,
+		       type,
+		       street,
+		       city,
+		       postal_code,
+		       region,
+		       country,
+		       is_primary,
+		       created_at
+		FROM user_sch.address
+		WHERE user_id = ?
+		ORDER BY id`), userID,
+	); err != nil {
+		return nil, errors.Wrapf(err, "impossible to get addresses, userID=%d", userID)
+	}
+
+	return addresses, nil
+}
+
+// Create creates new Address record
+func (r AddressRepository) Create(address *model.Address) (int, error) {
+	err := r.dialect.InsertReturning(r.db, "user_sch.address", `
+	INSERT INTO user_sch.address(
+		user_id,
+		type,
+		street,
+		city,
+		postal_code,
+		region,
+		country,
+		is_primary
+	) VALUES (
+		 ?, ?, ?, ?, ?, ?, ?, ?
+	)`,
+		[]interface{}{
+			address.UserID,
+			address.Type,
+			address.Street,
+			address.City,
+			address.PostalCode,
+			address.Region,
+			address.Country,
+			address.Primary,
+		},
+		[]string{"id", "created_at"},
+		&address.ID, &address.CreatedAt,
+	)
+
+	if err != nil {
+		return 0, errors.Wrapf(err, "impossible to create address record, userID=%d", address.UserID)
+	}
+
+	return address.ID, nil
+}
+
+// Update updates address record
+func (r AddressRepository) Update(address *model.Address) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.address
+	SET  type = ?,
+		 street = ?,
+		 city = ?,
+		 postal_code = ?,
+		 region = ?,
+		 country = ?,
+		 is_primary = ?
+	WHERE id = ?`),
+		address.Type,
+		address.Street,
+		address.City,
+		address.PostalCode,
+		address.Region,
+		address.Country,
+		address.Primary,
+		address.ID,
+	)
+
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to update address record")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if address was updated")
+	}
+
+	return count == 1, nil
+}
+
+// Delete deletes address record
+func (r AddressRepository) Delete(addressID int) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	DELETE FROM user_sch.address
+	WHERE id = ?`),
+		addressID,
+	)
+
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to delete address record")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if address was deleted")
+	}
+
+	return count == 1, nil
+}
+
+// ClearPrimary unmarks primary address of the user
+func (r AddressRepository) ClearPrimary(userID int) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.address
+	SET is_primary = false
+	WHERE user_id = ?
+	AND is_primary = true`),
+		userID,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to clear primary address, userID=%d", userID)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package dao
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// AttributeDefinitionRepositoryProvider provides an interface to work with database AttributeDefinition entity
+type AttributeDefinitionRepositoryProvider interface {
+	// GetByID returns AttributeDefinition object by ID
+	GetByID(definitionID int) (*model.AttributeDefinition, error)
+	// GetByName returns AttributeDefinition object by name
+	GetByName(name string) (*model.AttributeDefinition, error)
+	// FindAll returns all attribute definitions ordered by name
+	FindAll() ([]model.AttributeDefinition, error)
+	// Create creates new AttributeDefinition record
+	Create(definition *model.AttributeDefinition) (int, error)
+	// Update updates attribute definition record
+	Update(definition *model.AttributeDefinition) (bool, error)
+	// Delete deletes attribute definition record
+	Delete(definitionID int) (bool, error)
+	// IsUsed checks if any user has attribute with the name
+	IsUsed(name string) (bool, error)
+}
+
+// AttributeDefinitionRepository represents object to work with database AttributeDefinition entity
+type AttributeDefinitionRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewAttributeDefinitionRepository creates new instance of AttributeDefinitionRepository.
+func NewAttributeDefinitionRepository(db *sqlx.DB) *AttributeDefinitionRepository {
+	return &AttributeDefinitionRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// GetByID returns AttributeDefinition object by ID
+func (r AttributeDefinitionRepository) GetByID(definitionID int) (*model.AttributeDefinition, error) {
+	var definition model.AttributeDefinition
+	if err := r.db.Get(&definition, r.dialect.Query(`
+		SELECT id,
+		       name,
+		       type,
+		       required,
+		       enum_values,
+		       pattern,
+		       created_at
+		FROM user_sch.attribute_definition
+		WHERE id = ?`), definitionID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "impossible to get attribute definition, definitionID=%d", definitionID)
+	}
+
+	return &definition, nil
+}
+
+// GetByName returns AttributeDefinition object by name
+func (r AttributeDefinitionRepository) GetByName(name string) (*model.AttributeDefinition, error) {
+	var definition model.AttributeDefinition
+	if err := r.db.Get(&definition, r.dialect.Query(`
+		SELECT id,
+		       name,
+		       type,
+		       required,
+		       enum_values,
+		       pattern,
+		       created_at
+		FROM user_sch.attribute_definition
+		WHERE name = ?`), name,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "impossible to get attribute definition, name=%s", name)
+	}
+
+	return &definition, nil
+}
+
+// FindAll returns all attribute definitions ordered by name
+func (r AttributeDefinitionRepository) FindAll() ([]model.AttributeDefinition, error) {
+	definitions := []model.AttributeDefinition{}
+	if err := r.db.Select(&definitions, r.dialect.Query(`
+		SELECT id,
+		       name,
+		       type,
+		       required,
+		       enum_values,
+		       pattern,
+		       created_at
+		FROM user_sch.attribute_definition
+		ORDER BY name`),
+	); err != nil {
+		return nil, errors.Wrap(err, "impossible to get attribute definitions")
+	}
+
+	return definitions, nil
+}
+
+// Create creates new AttributeDefinition record
+func (r AttributeDefinitionRepository) Create(definition *model.AttributeDefinition) (int, error) {
+	err := r.dialect.InsertReturning(r.db, "user_sch.attribute_definition", `
+	INSERT INTO user_sch.attribute_definition(
+		name,
+		type,
+		required,
+		enum_values,
+		pattern
+	) VALUES (
+		 ?, ?, ?, ?, ?
+	)`,
+		[]interface{}{
+			definition.Name,
+			definition.Type,
+			definition.Required,
+			enumJSON(definition),
+			definition.Pattern,
+		},
+		[]string{"id", "created_at"},
+		&definition.ID, &definition.CreatedAt,
+	)
+
+	if err != nil {
+		return 0, errors.Wrapf(err, "impossible to create attribute definition record, name=%s", definition.Name)
+	}
+
+	return definition.ID, nil
+}
+
+// Update updates attribute definition record
+func (r AttributeDefinitionRepository) Update(definition *model.AttributeDefinition) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.attribute_definition
+	SET  required = ?,
+		 enum_values = ?,
+		 pattern = ?
+	WHERE id = ?`),
+		definition.Required,
+		enumJSON(definition),
+		definition.Pattern,
+		definition.ID,
+	)
+
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to update attribute definition record")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if attribute definition was updated")
+	}
+
+	return count == 1, nil
+}
+
+// Delete deletes attribute definition record
+func (r AttributeDefinitionRepository) Delete(definitionID int) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	DELETE FROM user_sch.attribute_definition
+	WHERE id = ?`),
+		definitionID,
+	)
+
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to delete attribute definition record")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if attribute definition was deleted")
+	}
+
+	return count == 1, nil
+}
+
+// IsUsed checks if any user has attribute with the name
+func (r AttributeDefinitionRepository) IsUsed(name string) (bool, error) {
+	if !attributeNameRegex.MatchString(name) {
+		return false, errors.Errorf("attribute has incorrect name, name=%s", name)
+	}
+
+	var total int
+	if err := r.db.Get(&total,
+		// nolint
+		r.dialect.Query(fmt.Sprintf(`
+		SELECT count(*)
+		FROM user_sch.user
+		WHERE %s IS NOT NULL`,
+			r.dialect.JSONValue("attributes", name),
+		)),
+	); err != nil {
+		return false, errors.Wrapf(err, "impossible to get count of users with attribute, name=%s", name)
+	}
+
+	return total > 0, nil
+}
+
+// enumJSON returns allowed values of the attribute as JSON text, attribute without them has empty array.
+func enumJSON(definition *model.AttributeDefinition) string {
+	if len(definition.Enum) == 0 {
+		return "[]"
+	}
+
+	return string(definition.Enum)
+}
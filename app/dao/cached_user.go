@@ -0,0 +1,227 @@
+package dao
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/mmgopher/user-service/app/cache"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// CacheStats represents counters of cache usage.
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// CacheStatsProvider provides counters of cache usage.
+type CacheStatsProvider interface {
+	// Stats returns cache hit and miss counters.
+	Stats() CacheStats
+}
+
+// CachedUserRepository decorates UserRepositoryProvider with read-through cache of GetByID.
+// Cached users are invalidated by Update, MarkEmailVerified, UpdateStatus and Delete.
+type CachedUserRepository struct {
+	UserRepositoryProvider
+	cache  cache.Cache
+	group  singleflight.Group
+	hits   uint64
+	misses uint64
+	// generation is incremented by every invalidation
+	generation uint64
+}
+
+// NewCachedUserRepository creates new instance of CachedUserRepository.
+func NewCachedUserRepository(repository UserRepositoryProvider, cache cache.Cache) *CachedUserRepository {
+	return &CachedUserRepository{
+		UserRepositoryProvider: repository,
+		cache:                  cache,
+	}
+}
+
+// GetByID returns User object by ID.
+// Concurrent misses of the same user are coalesced into one database query.
+func (r *CachedUserRepository) GetByID(id int) (*model.User, error) {
+	key := userCacheKey(id)
+	value, ok, err := r.cache.Get(key)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"err": err,
+			"key": key,
+		}).Warn("impossible to get user from cache")
+	}
+
+	if ok {
+		var user model.User
+		if err := json.Unmarshal(value, &user); err == nil {
+			atomic.AddUint64(&r.hits, 1)
+			return &user, nil
+		}
+	}
+
+	atomic.AddUint64(&r.misses, 1)
+	result, err, _ := r.group.Do(key, func() (interface{}, error) {
+		generation := atomic.LoadUint64(&r.generation)
+		user, err := r.UserRepositoryProvider.GetByID(id)
+		if err != nil || user == nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(user)
+		if err != nil {
+			return nil, err
+		}
+
+		// user invalidated during the load may be stale, so it is returned but not cached
+		if generation != atomic.LoadUint64(&r.generation) {
+			return value, nil
+		}
+
+		if err := r.cache.Set(key, value); err != nil {
+			log.WithFields(log.Fields{
+				"err": err,
+				"key": key,
+			}).Warn("impossible to store user in cache")
+		}
+
+		return value, nil
+	})
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	// every caller gets own copy, so shared result can not be modified
+	var user model.User
+	if err := json.Unmarshal(result.([]byte), &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Update updates user record and removes it from cache.
+func (r *CachedUserRepository) Update(user *model.User) (bool, error) {
+	updated, err := r.UserRepositoryProvider.Update(user)
+	r.Invalidate(user.ID)
+	return updated, err
+}
+
+// MarkEmailVerified marks email of the user as verified and removes the user from cache.
+func (r *CachedUserRepository) MarkEmailVerified(userID int, email string, verifiedAt time.Time) (bool, error) {
+	updated, err := r.UserRepositoryProvider.MarkEmailVerified(userID, email, verifiedAt)
+	r.Invalidate(userID)
+	return updated, err
+}
+
+// UpdateStatus changes status of the user and removes the user from cache.
+func (r *CachedUserRepository) UpdateStatus(userID int, from, to string) (bool, error) {
+	updated, err := r.UserRepositoryProvider.UpdateStatus(userID, from, to)
+	r.Invalidate(userID)
+	return updated, err
+}
+
+// Delete deletes user record and removes it from cache.
+func (r *CachedUserRepository) Delete(userID int) (bool, error) {
+	deleted, err := r.UserRepositoryProvider.Delete(userID)
+	r.Invalidate(userID)
+	return deleted, err
+}
+
+// Invalidate removes users from cache.
+func (r *CachedUserRepository) Invalidate(userIDs ...int) {
+	for _, userID := range userIDs {
+		key := userCacheKey(userID)
+		atomic.AddUint64(&r.generation, 1)
+		r.group.Forget(key)
+		if err := r.cache.Delete(key); err != nil {
+			log.WithFields(log.Fields{
+				"err": err,
+				"key": key,
+			}).Warn("impossible to remove user from cache")
+		}
+	}
+}
+
+// Stats returns cache hit and miss counters.
+func (r *CachedUserRepository) Stats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadUint64(&r.hits),
+		Misses: atomic.LoadUint64(&r.misses),
+	}
+}
+
+// Transactional decorates TransactionProvider, so users updated or deleted in transaction
+// are removed from cache. Users are removed again after the transaction is finished,
+// because concurrent reads could cache the old state before commit.
+func (r *CachedUserRepository) Transactional(provider TransactionProvider) TransactionProvider {
+	return cachedTransactionProvider{
+		TransactionProvider: provider,
+		repository:          r,
+	}
+}
+
+type cachedTransactionProvider struct {
+	TransactionProvider
+	repository *CachedUserRepository
+}
+
+// RunInTransaction executes fn in database transaction.
+func (p cachedTransactionProvider) RunInTransaction(fn func(repositories Repositories) error) error {
+	tracked := &invalidatingUserRepository{
+		cached: p.repository,
+	}
+	defer func() {
+		p.repository.Invalidate(tracked.userIDs...)
+	}()
+
+	return p.TransactionProvider.RunInTransaction(func(repositories Repositories) error {
+		tracked.UserRepositoryProvider = repositories.Users
+		repositories.Users = tracked
+		return fn(repositories)
+	})
+}
+
+// invalidatingUserRepository reads directly from transaction and tracks modified users.
+type invalidatingUserRepository struct {
+	UserRepositoryProvider
+	cached  *CachedUserRepository
+	userIDs []int
+}
+
+// Update updates user record and removes it from cache.
+func (r *invalidatingUserRepository) Update(user *model.User) (bool, error) {
+	r.userIDs = append(r.userIDs, user.ID)
+	r.cached.Invalidate(user.ID)
+	return r.UserRepositoryProvider.Update(user)
+}
+
+// MarkEmailVerified marks email of the user as verified and removes the user from cache.
+func (r *invalidatingUserRepository) MarkEmailVerified(userID int, email string, verifiedAt time.Time) (bool, error) {
+	r.userIDs = append(r.userIDs, userID)
+	r.cached.Invalidate(userID)
+	return r.UserRepositoryProvider.MarkEmailVerified(userID, email, verifiedAt)
+}
+
+// UpdateStatus changes status of the user and removes the user from cache.
+func (r *invalidatingUserRepository) UpdateStatus(userID int, from, to string) (bool, error) {
+	r.userIDs = append(r.userIDs, userID)
+	r.cached.Invalidate(userID)
+	return r.UserRepositoryProvider.UpdateStatus(userID, from, to)
+}
+
+// Delete deletes user record and removes it from cache.
+func (r *invalidatingUserRepository) Delete(userID int) (bool, error) {
+	r.userIDs = append(r.userIDs, userID)
+	r.cached.Invalidate(userID)
+	return r.UserRepositoryProvider.Delete(userID)
+}
+
+func userCacheKey(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
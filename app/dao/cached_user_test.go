@@ -0,0 +1,134 @@
+// +build unit
+
+package dao
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/cache"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+func TestCachedUserRepositoryGetByID(t *testing.T) {
+	user := &model.User{ID: 5, Name: "name", Surname: "surname", Gender: "male", DateOfBirth: time.Date(1990, 5, 14, 0, 0, 0, 0, time.UTC), Address: "address",
+		Attributes: types.JSONText(`{"department":"sales"}`)}
+	mockRepository := MockUserRepositoryProvider{}
+	mockRepository.On("GetByID", 5).Return(user, nil).Once()
+
+	repository := NewCachedUserRepository(&mockRepository, cache.NewLRU(10, time.Minute))
+
+	first, err := repository.GetByID(5)
+	require.Nil(t, err)
+	second, err := repository.GetByID(5)
+	require.Nil(t, err)
+
+	assert.Equal(t, user, first)
+	assert.Equal(t, user, second)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1}, repository.Stats())
+	mockRepository.AssertExpectations(t)
+}
+
+func TestCachedUserRepositoryDoesNotCacheMissingUser(t *testing.T) {
+	mockRepository := MockUserRepositoryProvider{}
+	mockRepository.On("GetByID", 5).Return(nil, nil).Twice()
+
+	repository := NewCachedUserRepository(&mockRepository, cache.NewLRU(10, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		user, err := repository.GetByID(5)
+		require.Nil(t, err)
+		assert.Nil(t, user)
+	}
+	mockRepository.AssertExpectations(t)
+}
+
+func TestCachedUserRepositoryCoalescesMisses(t *testing.T) {
+	release := make(chan struct{})
+	mockRepository := MockUserRepositoryProvider{}
+	mockRepository.On("GetByID", 5).Return(func(int) *model.User {
+		<-release
+		return &model.User{ID: 5}
+	}, nil).Once()
+
+	repository := NewCachedUserRepository(&mockRepository, cache.NewLRU(10, time.Minute))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, err := repository.GetByID(5)
+			assert.Nil(t, err)
+			assert.Equal(t, 5, user.ID)
+		}()
+	}
+
+	// give goroutines time to join the same load
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	mockRepository.AssertExpectations(t)
+}
+
+func TestCachedUserRepositoryInvalidatesOnUpdateAndDelete(t *testing.T) {
+	user := &model.User{ID: 5, Name: "name"}
+	mockRepository := MockUserRepositoryProvider{}
+	mockRepository.On("GetByID", 5).Return(user, nil).Times(3)
+	mockRepository.On("Update", user).Return(true, nil)
+	mockRepository.On("Delete", 5).Return(true, nil)
+
+	repository := NewCachedUserRepository(&mockRepository, cache.NewLRU(10, time.Minute))
+
+	_, err := repository.GetByID(5)
+	require.Nil(t, err)
+	_, err = repository.Update(user)
+	require.Nil(t, err)
+	_, err = repository.GetByID(5)
+	require.Nil(t, err)
+	_, err = repository.Delete(5)
+	require.Nil(t, err)
+	_, err = repository.GetByID(5)
+	require.Nil(t, err)
+
+	assert.Equal(t, CacheStats{Hits: 0, Misses: 3}, repository.Stats())
+	mockRepository.AssertExpectations(t)
+}
+
+func TestCachedUserRepositoryInvalidatesInTransaction(t *testing.T) {
+	user := &model.User{ID: 5, Name: "name"}
+	mockRepository := MockUserRepositoryProvider{}
+	mockRepository.On("GetByID", 5).Return(user, nil).Twice()
+
+	txRepository := MockUserRepositoryProvider{}
+	txRepository.On("Update", user).Return(true, nil)
+
+	mockTransactionProvider := MockTransactionProvider{}
+	mockTransactionProvider.On("RunInTransaction", mock.Anything).
+		Return(func(fn func(Repositories) error) error {
+			return fn(Repositories{Users: &txRepository})
+		})
+
+	repository := NewCachedUserRepository(&mockRepository, cache.NewLRU(10, time.Minute))
+	transactionProvider := repository.Transactional(&mockTransactionProvider)
+
+	_, err := repository.GetByID(5)
+	require.Nil(t, err)
+	err = transactionProvider.RunInTransaction(func(repositories Repositories) error {
+		_, err := repositories.Users.Update(user)
+		return err
+	})
+	require.Nil(t, err)
+	_, err = repository.GetByID(5)
+	require.Nil(t, err)
+
+	assert.Equal(t, CacheStats{Hits: 0, Misses: 2}, repository.Stats())
+	mockRepository.AssertExpectations(t)
+	txRepository.AssertExpectations(t)
+}
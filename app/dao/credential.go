@@ -0,0 +1,170 @@
+package dao
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// CredentialRepositoryProvider provides an interface to work with database Credential entity
+type CredentialRepositoryProvider interface {
+	// GetByUserID returns Credential object of the user
+	GetByUserID(userID int) (*model.Credential, error)
+	// Save sets password hash of the user and resets failed login attempts
+	Save(userID int, passwordHash string) error
+	// Rehash replaces password hash of the user when it wasn't changed in the meantime
+	Rehash(userID int, from, to string) (bool, error)
+	// AddFailedAttempt increments failed login attempts of the user and returns their number
+	AddFailedAttempt(userID int) (int, error)
+	// ResetFailedAttempts resets failed login attempts of the user
+	ResetFailedAttempts(userID int) error
+}
+
+// CredentialRepository represents object to work with database Credential entity
+type CredentialRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewCredentialRepository creates new instance of CredentialRepository.
+func NewCredentialRepository(db *sqlx.DB) *CredentialRepository {
+	return newCredentialRepository(db)
+}
+
+func newCredentialRepository(db executor) *CredentialRepository {
+	return &CredentialRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// GetByUserID returns Credential object of the user
+func (r CredentialRepository) GetByUserID(userID int) (*model.Credential, error) {
+	var credential model.Credential
+	if err := r.db.Get(&credential, r.dialect.Query(`
+		SELECT user_id,
+		       password_hash,
+		       failed_attempts,
+		       updated_at
+		FROM user_sch.user_credential
+		WHERE user_id = ?`), userID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "impossible to get credential of user, userID=%d", userID)
+	}
+
+	return &credential, nil
+}
+
+// Save sets password hash of the user and resets failed login attempts.
+// Credential is created when the user has none.
+func (r CredentialRepository) Save(userID int, passwordHash string) error {
+	res, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.user_credential
+	SET password_hash = ?,
+	    failed_attempts = 0,
+	    updated_at = CURRENT_TIMESTAMP
+	WHERE user_id = ?`),
+		passwordHash,
+		userID,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "impossible to update credential of user, userID=%d", userID)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "impossible to check if credential of user was updated")
+	}
+
+	if count == 1 {
+		return nil
+	}
+
+	if _, err := r.db.Exec(r.dialect.Query(`
+	INSERT INTO user_sch.user_credential(
+		user_id,
+		password_hash
+	) VALUES (
+		?, ?
+	)`),
+		userID,
+		passwordHash,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to create credential of user, userID=%d", userID)
+	}
+
+	return nil
+}
+
+// Rehash replaces password hash of the user when it wasn't changed in the meantime
+func (r CredentialRepository) Rehash(userID int, from, to string) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.user_credential
+	SET password_hash = ?
+	WHERE user_id = ?
+	AND password_hash = ?`),
+		to,
+		userID,
+		from,
+	)
+	if err != nil {
+		return false, errors.Wrapf(err, "impossible to rehash password of user, userID=%d", userID)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if password of user was rehashed")
+	}
+
+	return count == 1, nil
+}
+
+// AddFailedAttempt increments failed login attempts of the user and returns their number.
+// It should run in transaction, so the number isn't changed by concurrent attempts before it is read.
+func (r CredentialRepository) AddFailedAttempt(userID int) (int, error) {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.user_credential
+	SET failed_attempts = failed_attempts + 1
+	WHERE user_id = ?`),
+		userID,
+	); err != nil {
+		return 0, errors.Wrapf(err, "impossible to add failed login attempt of user, userID=%d", userID)
+	}
+
+	var attempts int
+	if err := r.db.Get(&attempts, r.dialect.Query(`
+		SELECT failed_attempts
+		FROM user_sch.user_credential
+		WHERE user_id = ?`), userID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+
+		return 0, errors.Wrapf(err, "impossible to get failed login attempts of user, userID=%d", userID)
+	}
+
+	return attempts, nil
+}
+
+// ResetFailedAttempts resets failed login attempts of the user
+func (r CredentialRepository) ResetFailedAttempts(userID int) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.user_credential
+	SET failed_attempts = 0
+	WHERE user_id = ?`),
+		userID,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to reset failed login attempts of user, userID=%d", userID)
+	}
+
+	return nil
+}
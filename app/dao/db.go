@@ -0,0 +1,16 @@
+package dao
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// executor is implemented by both *sqlx.DB and *sqlx.Tx.
+// It allows repositories to work with and without transaction.
+type executor interface {
+	sqlx.Ext
+	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
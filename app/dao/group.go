@@ -0,0 +1,261 @@
+package dao
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// GroupRepositoryProvider provides an interface to work with database Group entity and its members
+type GroupRepositoryProvider interface {
+	// GetByID returns Group object by ID
+	GetByID(groupID int) (*model.Group, error)
+	// GetByName returns Group object by name
+	GetByName(name string) (*model.Group, error)
+	// FindAll returns all groups ordered by name
+	FindAll() ([]model.Group, error)
+	// FindByIDs returns groups with the IDs, missing groups are skipped
+	FindByIDs(groupIDs []int) ([]model.Group, error)
+	// FindByNames returns groups with the names, missing groups are skipped
+	FindByNames(names []string) ([]model.Group, error)
+	// Create creates new Group record
+	Create(group *model.Group) (int, error)
+	// Update updates group record
+	Update(group *model.Group) (bool, error)
+	// Delete deletes group record with its memberships
+	Delete(groupID int) (bool, error)
+	// AddMembers adds existing users to the group and returns number of new members
+	AddMembers(groupID int, userIDs []int) (int, error)
+	// RemoveMembers removes users from the group and returns number of removed members
+	RemoveMembers(groupID int, userIDs []int) (int, error)
+}
+
+// GroupRepository represents object to work with database Group entity
+type GroupRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewGroupRepository creates new instance of GroupRepository.
+func NewGroupRepository(db *sqlx.DB) *GroupRepository {
+	return &GroupRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// GetByID returns Group object by ID
+func (r GroupRepository) GetByID(groupID int) (*model.Group, error) {
+	var group model.Group
+	if err := r.db.Get(&group, r.dialect.Query(fmt.Sprintf(`
+		SELECT id,
+		       name,
+		       description,
+		       created_at
+		FROM user_sch.%s
+		WHERE id = ?`, r.dialect.Quote("groups"))), groupID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "impossible to get group, groupID=%d", groupID)
+	}
+
+	return &group, nil
+}
+
+// GetByName returns Group object by name
+func (r GroupRepository) GetByName(name string) (*model.Group, error) {
+	var group model.Group
+	if err := r.db.Get(&group, r.dialect.Query(fmt.Sprintf(`
+		SELECT id,
+		       name,
+		       description,
+		       created_at
+		FROM user_sch.%s
+		WHERE name = ?`, r.dialect.Quote("groups"))), name,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "impossible to get group, name=%s", name)
+	}
+
+	return &group, nil
+}
+
+// FindAll returns all groups ordered by name
+func (r GroupRepository) FindAll() ([]model.Group, error) {
+	groups := []model.Group{}
+	if err := r.db.Select(&groups, r.dialect.Query(fmt.Sprintf(`
+		SELECT id,
+		       name,
+		       description,
+		       created_at
+		FROM user_sch.%s
+		ORDER BY name`, r.dialect.Quote("groups"))),
+	); err != nil {
+		return nil, errors.Wrap(err, "impossible to get groups")
+	}
+
+	return groups, nil
+}
+
+// FindByIDs returns groups with the IDs, missing groups are skipped
+func (r GroupRepository) FindByIDs(groupIDs []int) ([]model.Group, error) {
+	return r.findBy("id", groupIDs)
+}
+
+// FindByNames returns groups with the names, missing groups are skipped
+func (r GroupRepository) FindByNames(names []string) ([]model.Group, error) {
+	return r.findBy("name", names)
+}
+
+// findBy returns groups which value of the column is one of values ordered by name.
+func (r GroupRepository) findBy(column string, values interface{}) ([]model.Group, error) {
+	groups := []model.Group{}
+	// nolint
+	query, args, err := sqlx.In(fmt.Sprintf(`
+		SELECT id,
+		       name,
+		       description,
+		       created_at
+		FROM user_sch.%s
+		WHERE %s IN (?)
+		ORDER BY name`, r.dialect.Quote("groups"), column), values)
+	if err != nil {
+		return nil, errors.Wrap(err, "impossible to build groups query")
+	}
+
+	if err := r.db.Select(&groups, r.dialect.Query(query), args...); err != nil {
+		return nil, errors.Wrapf(err, "impossible to get groups by %s", column)
+	}
+
+	return groups, nil
+}
+
+// Create creates new Group record
+func (r GroupRepository) Create(group *model.Group) (int, error) {
+	err := r.dialect.InsertReturning(r.db, "user_sch."+r.dialect.Quote("groups"), fmt.Sprintf(`
+	INSERT INTO user_sch.%s(
+		name,
+		description
+	) VALUES (
+		 ?, ?
+	)`, r.dialect.Quote("groups")),
+		[]interface{}{
+			group.Name,
+			group.Description,
+		},
+		[]string{"id", "created_at"},
+		&group.ID, &group.CreatedAt,
+	)
+
+	if err != nil {
+		return 0, errors.Wrapf(err, "impossible to create group record, name=%s", group.Name)
+	}
+
+	return group.ID, nil
+}
+
+// Update updates group record
+func (r GroupRepository) Update(group *model.Group) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(fmt.Sprintf(`
+	UPDATE user_sch.%s
+	SET  name = ?,
+		 description = ?
+	WHERE id = ?`, r.dialect.Quote("groups"))),
+		group.Name,
+		group.Description,
+		group.ID,
+	)
+
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to update group record")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if group was updated")
+	}
+
+	return count == 1, nil
+}
+
+// Delete deletes group record with its memberships
+func (r GroupRepository) Delete(groupID int) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(fmt.Sprintf(`
+	DELETE FROM user_sch.%s
+	WHERE id = ?`, r.dialect.Quote("groups"))),
+		groupID,
+	)
+
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to delete group record")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if group was deleted")
+	}
+
+	return count == 1, nil
+}
+
+// AddMembers adds existing users to the group and returns number of new members.
+// Users which do not exist or are already members are skipped.
+func (r GroupRepository) AddMembers(groupID int, userIDs []int) (int, error) {
+	query, args, err := sqlx.In(r.dialect.InsertIgnore(`
+	INSERT INTO user_sch.user_groups(
+		group_id,
+		user_id
+	)
+	SELECT ?, id
+	FROM user_sch.user
+	WHERE id IN (?)`), groupID, userIDs)
+	if err != nil {
+		return 0, errors.Wrap(err, "impossible to build add members query")
+	}
+
+	res, err := r.db.Exec(r.dialect.Query(query), args...)
+	if err != nil {
+		return 0, errors.Wrapf(err, "impossible to add members to group, groupID=%d", groupID)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "impossible to check how many members were added")
+	}
+
+	return int(count), nil
+}
+
+// RemoveMembers removes users from the group and returns number of removed members
+func (r GroupRepository) RemoveMembers(groupID int, userIDs []int) (int, error) {
+	query, args, err := sqlx.In(`
+	DELETE FROM user_sch.user_groups
+	WHERE group_id = ?
+	AND user_id IN (?)`, groupID, userIDs)
+	if err != nil {
+		return 0, errors.Wrap(err, "impossible to build remove members query")
+	}
+
+	res, err := r.db.Exec(r.dialect.Query(query), args...)
+	if err != nil {
+		return 0, errors.Wrapf(err, "impossible to remove members from group, groupID=%d", groupID)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "impossible to check how many members were removed")
+	}
+
+	return int(count), nil
+}
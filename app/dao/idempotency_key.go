@@ -0,0 +1,159 @@
+package dao
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// IdempotencyKeyRepositoryProvider provides an interface to work with database IdempotencyKey entity
+type IdempotencyKeyRepositoryProvider interface {
+	// Get returns IdempotencyKey object by scope and key
+	Get(scope, key string) (*model.IdempotencyKey, error)
+	// Create creates new IdempotencyKey record if it does not exist yet.
+	// Returns TRUE if record was created
+	Create(idempotencyKey *model.IdempotencyKey) (bool, error)
+	// Complete stores response of the request and marks key as completed
+	Complete(idempotencyKey *model.IdempotencyKey) error
+	// Delete deletes IdempotencyKey record
+	Delete(scope, key string) error
+	// DeleteExpired deletes records expired before given time and returns number of deleted records
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// IdempotencyKeyRepository represents object to work with database IdempotencyKey entity
+type IdempotencyKeyRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewIdempotencyKeyRepository creates new instance of IdempotencyKeyRepository.
+func NewIdempotencyKeyRepository(db *sqlx.DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// Get returns IdempotencyKey object by scope and key
+func (r IdempotencyKeyRepository) Get(scope, key string) (*model.IdempotencyKey, error) {
+	var idempotencyKey model.IdempotencyKey
+	if err := r.db.Get(&idempotencyKey, r.dialect.Query(fmt.Sprintf(`
+		SELECT scope,
+		       %[1]s,
+		       fingerprint,
+		       status,
+		       response_status,
+		       response_content_type,
+		       response_body,
+		       created_at,
+		       expires_at
+		FROM user_sch.idempotency_key
+		WHERE scope = ?
+		AND %[1]s = ?`, r.dialect.Quote("key"))), scope, key,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "impossible to get idempotency key, scope=%s", scope)
+	}
+
+	return &idempotencyKey, nil
+}
+
+// Create creates new IdempotencyKey record if it does not exist yet.
+// Returns TRUE if record was created
+func (r IdempotencyKeyRepository) Create(idempotencyKey *model.IdempotencyKey) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(r.dialect.InsertIgnore(fmt.Sprintf(`
+	INSERT INTO user_sch.idempotency_key(
+		scope,
+		%[1]s,
+		fingerprint,
+		status,
+		expires_at
+	) VALUES (
+		?, ?, ?, ?, ?
+	)`, r.dialect.Quote("key")))),
+		idempotencyKey.Scope,
+		idempotencyKey.Key,
+		idempotencyKey.Fingerprint,
+		idempotencyKey.Status,
+		idempotencyKey.ExpiresAt,
+	)
+
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to create idempotency key record")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if idempotency key was created")
+	}
+
+	return count == 1, nil
+}
+
+// Complete stores response of the request and marks key as completed
+func (r IdempotencyKeyRepository) Complete(idempotencyKey *model.IdempotencyKey) error {
+	if _, err := r.db.Exec(r.dialect.Query(fmt.Sprintf(`
+	UPDATE user_sch.idempotency_key
+	SET status = ?,
+	    response_status = ?,
+	    response_content_type = ?,
+	    response_body = ?
+	WHERE scope = ?
+	AND %[1]s = ?`, r.dialect.Quote("key"))),
+		model.IdempotencyKeyCompleted,
+		idempotencyKey.ResponseStatus,
+		idempotencyKey.ResponseContentType,
+		idempotencyKey.ResponseBody,
+		idempotencyKey.Scope,
+		idempotencyKey.Key,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to complete idempotency key, scope=%s", idempotencyKey.Scope)
+	}
+
+	return nil
+}
+
+// Delete deletes IdempotencyKey record
+func (r IdempotencyKeyRepository) Delete(scope, key string) error {
+	if _, err := r.db.Exec(r.dialect.Query(fmt.Sprintf(`
+	DELETE FROM user_sch.idempotency_key
+	WHERE scope = ?
+	AND %[1]s = ?`, r.dialect.Quote("key"))),
+		scope,
+		key,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to delete idempotency key, scope=%s", scope)
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes records expired before given time and returns number of deleted records
+func (r IdempotencyKeyRepository) DeleteExpired(now time.Time) (int64, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	DELETE FROM user_sch.idempotency_key
+	WHERE expires_at <= ?`),
+		now,
+	)
+
+	if err != nil {
+		return 0, errors.Wrap(err, "impossible to delete expired idempotency keys")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "impossible to check number of deleted idempotency keys")
+	}
+
+	return count, nil
+}
@@ -0,0 +1,209 @@
+package dao
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// MemoryAddressRepository is thread-safe in-memory implementation of AddressRepositoryProvider.
+// It keeps addresses of users stored in MemoryUserRepository, see MemoryUserRepository.Addresses.
+type MemoryAddressRepository struct {
+	mu        sync.RWMutex
+	users     *MemoryUserRepository
+	addresses map[int]model.Address
+	nextID    int
+	now       func() time.Time
+}
+
+// newMemoryAddressRepository creates new instance of MemoryAddressRepository for users of the repository.
+func newMemoryAddressRepository(users *MemoryUserRepository) *MemoryAddressRepository {
+	return &MemoryAddressRepository{
+		users:     users,
+		addresses: make(map[int]model.Address),
+		nextID:    1,
+		now:       time.Now,
+	}
+}
+
+// GetByID returns Address object by ID
+func (r *MemoryAddressRepository) GetByID(addressID int) (*model.Address, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	address, ok := r.addresses[addressID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &address, nil
+}
+
+// FindByUserID returns all addresses of the user ordered by ID
+func (r *MemoryAddressRepository) FindByUserID(userID int) ([]model.Address, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	addresses := []model.Address{}
+	for _, address := range r.addresses {
+		if address.UserID == userID {
+			addresses = append(addresses, address)
+		}
+	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].ID < addresses[j].ID
+	})
+
+	return addresses, nil
+}
+
+// Create creates new Address record, the user has to exist
+func (r *MemoryAddressRepository) Create(address *model.Address) (int, error) {
+	// the user is checked before the lock is taken, so locks of repositories are never held together
+	user, err := r.users.GetByID(address.UserID)
+	if err != nil {
+		return 0, err
+	}
+	if user == nil {
+		return 0, errors.Errorf("impossible to create address record of not existing user, userID=%d", address.UserID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	address.ID = r.nextID
+	// database keeps timestamps with microsecond precision
+	address.CreatedAt = r.now().UTC().Truncate(time.Microsecond)
+	r.nextID++
+	r.addresses[address.ID] = *address
+
+	return address.ID, nil
+}
+
+// Update updates address record
+func (r *MemoryAddressRepository) Update(address *model.Address) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.addresses[address.ID]
+	if !ok {
+		return false, nil
+	}
+
+	stored.Type = address.Type
+	stored.Street = address.Street
+	stored.City = address.City
+	stored.PostalCode = address.PostalCode
+	stored.Region = address.Region
+	stored.Country = address.Country
+	stored.Primary = address.Primary
+	r.addresses[address.ID] = stored
+
+	return true, nil
+}
+
+// Delete deletes address record
+func (r *MemoryAddressRepository) Delete(addressID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.addresses[addressID]; !ok {
+		return false, nil
+	}
+
+	delete(r.addresses, addressID)
+	return true, nil
+}
+
+// ClearPrimary unmarks primary address of the user
+func (r *MemoryAddressRepository) ClearPrimary(userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, address := range r.addresses {
+		if address.UserID == userID && address.Primary {
+			address.Primary = false
+			r.addresses[id] = address
+		}
+	}
+
+	return nil
+}
+
+// deleteByUserID deletes addresses of deleted user like foreign key with ON DELETE CASCADE does.
+func (r *MemoryAddressRepository) deleteByUserID(userID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, address := range r.addresses {
+		if address.UserID == userID {
+			delete(r.addresses, id)
+		}
+	}
+}
+
+// userIDs returns set of users with any address matching the filter in the same way as filter criteria
+// of the database query. Nil is returned when the filter does not filter by address.
+func (r *MemoryAddressRepository) userIDs(f userFilter) map[int]struct{} {
+	type condition func(address model.Address) bool
+	var conditions []condition
+
+	if strings.TrimSpace(f.city) != "" {
+		city := iLikePattern(f.city)
+		conditions = append(conditions, func(address model.Address) bool { return city.MatchString(address.City) })
+	}
+
+	if strings.TrimSpace(f.country) != "" {
+		country := strings.ToUpper(f.country)
+		conditions = append(conditions, func(address model.Address) bool { return address.Country == country })
+	}
+
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// every condition is a separate subquery, so city and country may match different addresses of the user
+	var userIDs map[int]struct{}
+	for _, c := range conditions {
+		matched := make(map[int]struct{})
+		for _, address := range r.addresses {
+			if _, ok := userIDs[address.UserID]; (userIDs == nil || ok) && c(address) {
+				matched[address.UserID] = struct{}{}
+			}
+		}
+		userIDs = matched
+	}
+
+	return userIDs
+}
+
+// snapshot returns copy of the repository state.
+func (r *MemoryAddressRepository) snapshot() (map[int]model.Address, int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	addresses := make(map[int]model.Address, len(r.addresses))
+	for id, address := range r.addresses {
+		addresses[id] = address
+	}
+
+	return addresses, r.nextID
+}
+
+// restore replaces the repository state with the snapshot.
+func (r *MemoryAddressRepository) restore(addresses map[int]model.Address, nextID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.addresses = addresses
+	r.nextID = nextID
+}
@@ -0,0 +1,140 @@
+package dao
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// MemoryCredentialRepository is thread-safe in-memory implementation of CredentialRepositoryProvider.
+// It keeps passwords of users stored in MemoryUserRepository, see MemoryUserRepository.Credentials.
+type MemoryCredentialRepository struct {
+	mu          sync.RWMutex
+	users       *MemoryUserRepository
+	credentials map[int]model.Credential
+	now         func() time.Time
+}
+
+// newMemoryCredentialRepository creates new instance of MemoryCredentialRepository for users of the repository.
+func newMemoryCredentialRepository(users *MemoryUserRepository) *MemoryCredentialRepository {
+	return &MemoryCredentialRepository{
+		users:       users,
+		credentials: make(map[int]model.Credential),
+		now:         time.Now,
+	}
+}
+
+// GetByUserID returns Credential object of the user
+func (r *MemoryCredentialRepository) GetByUserID(userID int) (*model.Credential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	credential, ok := r.credentials[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &credential, nil
+}
+
+// Save sets password hash of the user and resets failed login attempts.
+// Credential is created when the user has none, the user has to exist.
+func (r *MemoryCredentialRepository) Save(userID int, passwordHash string) error {
+	// the user is checked before the lock is taken, so locks of repositories are never held together
+	user, err := r.users.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.Errorf("impossible to create credential of not existing user, userID=%d", userID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.credentials[userID] = model.Credential{
+		UserID:       userID,
+		PasswordHash: passwordHash,
+		// database keeps timestamps with microsecond precision
+		UpdatedAt: r.now().UTC().Truncate(time.Microsecond),
+	}
+
+	return nil
+}
+
+// Rehash replaces password hash of the user when it wasn't changed in the meantime
+func (r *MemoryCredentialRepository) Rehash(userID int, from, to string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	credential, ok := r.credentials[userID]
+	if !ok || credential.PasswordHash != from {
+		return false, nil
+	}
+
+	credential.PasswordHash = to
+	r.credentials[userID] = credential
+
+	return true, nil
+}
+
+// AddFailedAttempt increments failed login attempts of the user and returns their number
+func (r *MemoryCredentialRepository) AddFailedAttempt(userID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	credential, ok := r.credentials[userID]
+	if !ok {
+		return 0, nil
+	}
+
+	credential.FailedAttempts++
+	r.credentials[userID] = credential
+
+	return credential.FailedAttempts, nil
+}
+
+// ResetFailedAttempts resets failed login attempts of the user
+func (r *MemoryCredentialRepository) ResetFailedAttempts(userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if credential, ok := r.credentials[userID]; ok {
+		credential.FailedAttempts = 0
+		r.credentials[userID] = credential
+	}
+
+	return nil
+}
+
+// deleteByUserID deletes credential of deleted user like foreign key with ON DELETE CASCADE does.
+func (r *MemoryCredentialRepository) deleteByUserID(userID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.credentials, userID)
+}
+
+// snapshot returns copy of the repository state.
+func (r *MemoryCredentialRepository) snapshot() map[int]model.Credential {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	credentials := make(map[int]model.Credential, len(r.credentials))
+	for userID, credential := range r.credentials {
+		credentials[userID] = credential
+	}
+
+	return credentials
+}
+
+// restore replaces the repository state with the snapshot.
+func (r *MemoryCredentialRepository) restore(credentials map[int]model.Credential) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.credentials = credentials
+}
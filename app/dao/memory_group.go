@@ -0,0 +1,297 @@
+package dao
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// MemoryGroupRepository is thread-safe in-memory implementation of GroupRepositoryProvider.
+// It keeps groups of users stored in MemoryUserRepository, see MemoryUserRepository.Groups.
+type MemoryGroupRepository struct {
+	mu     sync.RWMutex
+	users  *MemoryUserRepository
+	groups map[int]model.Group
+	// members are sets of IDs of members of the groups by group ID.
+	members map[int]map[int]struct{}
+	nextID  int
+	now     func() time.Time
+}
+
+// newMemoryGroupRepository creates new instance of MemoryGroupRepository for users of the repository.
+func newMemoryGroupRepository(users *MemoryUserRepository) *MemoryGroupRepository {
+	return &MemoryGroupRepository{
+		users:   users,
+		groups:  make(map[int]model.Group),
+		members: make(map[int]map[int]struct{}),
+		nextID:  1,
+		now:     time.Now,
+	}
+}
+
+// GetByID returns Group object by ID
+func (r *MemoryGroupRepository) GetByID(groupID int) (*model.Group, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	group, ok := r.groups[groupID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &group, nil
+}
+
+// GetByName returns Group object by name
+func (r *MemoryGroupRepository) GetByName(name string) (*model.Group, error) {
+	groups, err := r.FindByNames([]string{name})
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+
+	return &groups[0], nil
+}
+
+// FindAll returns all groups ordered by name
+func (r *MemoryGroupRepository) FindAll() ([]model.Group, error) {
+	return r.findBy(func(model.Group) bool { return true }), nil
+}
+
+// FindByIDs returns groups with the IDs, missing groups are skipped
+func (r *MemoryGroupRepository) FindByIDs(groupIDs []int) ([]model.Group, error) {
+	ids := make(map[int]struct{}, len(groupIDs))
+	for _, id := range groupIDs {
+		ids[id] = struct{}{}
+	}
+
+	return r.findBy(func(group model.Group) bool {
+		_, ok := ids[group.ID]
+		return ok
+	}), nil
+}
+
+// FindByNames returns groups with the names, missing groups are skipped
+func (r *MemoryGroupRepository) FindByNames(names []string) ([]model.Group, error) {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+
+	return r.findBy(func(group model.Group) bool {
+		_, ok := set[group.Name]
+		return ok
+	}), nil
+}
+
+// findBy returns groups matching the condition ordered by name.
+func (r *MemoryGroupRepository) findBy(matches func(group model.Group) bool) []model.Group {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groups := []model.Group{}
+	for _, group := range r.groups {
+		if matches(group) {
+			groups = append(groups, group)
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	return groups
+}
+
+// Create creates new Group record, names of groups are unique
+func (r *MemoryGroupRepository) Create(group *model.Group) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.nameTaken(group.Name, 0) {
+		return 0, errors.Errorf("impossible to create group record, name=%s", group.Name)
+	}
+
+	group.ID = r.nextID
+	// database keeps timestamps with microsecond precision
+	group.CreatedAt = r.now().UTC().Truncate(time.Microsecond)
+	r.nextID++
+	r.groups[group.ID] = *group
+	r.members[group.ID] = make(map[int]struct{})
+
+	return group.ID, nil
+}
+
+// Update updates group record
+func (r *MemoryGroupRepository) Update(group *model.Group) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.groups[group.ID]
+	if !ok {
+		return false, nil
+	}
+
+	if r.nameTaken(group.Name, group.ID) {
+		return false, errors.Errorf("impossible to update group record, name=%s", group.Name)
+	}
+
+	stored.Name = group.Name
+	stored.Description = group.Description
+	r.groups[group.ID] = stored
+
+	return true, nil
+}
+
+// Delete deletes group record with its memberships
+func (r *MemoryGroupRepository) Delete(groupID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.groups[groupID]; !ok {
+		return false, nil
+	}
+
+	delete(r.groups, groupID)
+	delete(r.members, groupID)
+	return true, nil
+}
+
+// AddMembers adds existing users to the group and returns number of new members.
+// Users which do not exist or are already members are skipped.
+func (r *MemoryGroupRepository) AddMembers(groupID int, userIDs []int) (int, error) {
+	// users are checked before the lock is taken, so locks of repositories are never held together
+	var existing []int
+	for _, userID := range userIDs {
+		user, err := r.users.GetByID(userID)
+		if err != nil {
+			return 0, err
+		}
+		if user != nil {
+			existing = append(existing, userID)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	members, ok := r.members[groupID]
+	if !ok {
+		return 0, errors.Errorf("impossible to add members to not existing group, groupID=%d", groupID)
+	}
+
+	var count int
+	for _, userID := range existing {
+		if _, ok := members[userID]; !ok {
+			members[userID] = struct{}{}
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// RemoveMembers removes users from the group and returns number of removed members
+func (r *MemoryGroupRepository) RemoveMembers(groupID int, userIDs []int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	members := r.members[groupID]
+	var count int
+	for _, userID := range userIDs {
+		if _, ok := members[userID]; ok {
+			delete(members, userID)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// nameTaken checks if the name is used by group other than the one with excludedID.
+func (r *MemoryGroupRepository) nameTaken(name string, excludedID int) bool {
+	for _, group := range r.groups {
+		if group.ID != excludedID && group.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeUser removes memberships of deleted user like foreign key with ON DELETE CASCADE does.
+func (r *MemoryGroupRepository) removeUser(userID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, members := range r.members {
+		delete(members, userID)
+	}
+}
+
+// userIDs returns set of users which are members of any of the groups of the filter or of all of them
+// in the same way as filter criteria of the database query. Nil is returned when the filter does not filter by groups.
+func (r *MemoryGroupRepository) userIDs(f userFilter) map[int]struct{} {
+	if len(f.groupIDs) == 0 {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groupIDs := make(map[int]struct{}, len(f.groupIDs))
+	for _, groupID := range f.groupIDs {
+		groupIDs[groupID] = struct{}{}
+	}
+
+	memberships := make(map[int]int)
+	for groupID := range groupIDs {
+		for userID := range r.members[groupID] {
+			memberships[userID]++
+		}
+	}
+
+	userIDs := make(map[int]struct{}, len(memberships))
+	for userID, count := range memberships {
+		// memberships are unique, so user is member of all groups when it has one membership per group
+		if !f.allGroups || count == len(f.groupIDs) {
+			userIDs[userID] = struct{}{}
+		}
+	}
+
+	return userIDs
+}
+
+// snapshot returns copy of the repository state.
+func (r *MemoryGroupRepository) snapshot() (map[int]model.Group, map[int]map[int]struct{}, int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groups := make(map[int]model.Group, len(r.groups))
+	for id, group := range r.groups {
+		groups[id] = group
+	}
+
+	members := make(map[int]map[int]struct{}, len(r.members))
+	for groupID, userIDs := range r.members {
+		members[groupID] = make(map[int]struct{}, len(userIDs))
+		for userID := range userIDs {
+			members[groupID][userID] = struct{}{}
+		}
+	}
+
+	return groups, members, r.nextID
+}
+
+// restore replaces the repository state with the snapshot.
+func (r *MemoryGroupRepository) restore(groups map[int]model.Group, members map[int]map[int]struct{}, nextID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.groups = groups
+	r.members = members
+	r.nextID = nextID
+}
@@ -0,0 +1,197 @@
+package dao
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// MemoryMFARepository is thread-safe in-memory implementation of MFARepositoryProvider.
+// It keeps second factors of users stored in MemoryUserRepository, see MemoryUserRepository.MFA.
+type MemoryMFARepository struct {
+	mu    sync.RWMutex
+	users *MemoryUserRepository
+	mfas  map[int]model.MFA
+	// recoveryCodes are sets of hashes of recovery codes by user ID.
+	recoveryCodes map[int]map[string]struct{}
+	now           func() time.Time
+}
+
+// newMemoryMFARepository creates new instance of MemoryMFARepository for users of the repository.
+func newMemoryMFARepository(users *MemoryUserRepository) *MemoryMFARepository {
+	return &MemoryMFARepository{
+		users:         users,
+		mfas:          make(map[int]model.MFA),
+		recoveryCodes: make(map[int]map[string]struct{}),
+		now:           time.Now,
+	}
+}
+
+// GetByUserID returns MFA object of the user
+func (r *MemoryMFARepository) GetByUserID(userID int) (*model.MFA, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	mfa, ok := r.mfas[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &mfa, nil
+}
+
+// Enroll sets secret of pending MFA of the user, enabled MFA is kept. The user has to exist.
+func (r *MemoryMFARepository) Enroll(userID int, secret string) (bool, error) {
+	if err := r.checkUser(userID); err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if mfa, ok := r.mfas[userID]; ok && mfa.Enabled() {
+		return false, nil
+	}
+
+	r.mfas[userID] = model.MFA{
+		UserID: userID,
+		Secret: secret,
+		// database keeps timestamps with microsecond precision
+		CreatedAt: r.now().UTC().Truncate(time.Microsecond),
+	}
+
+	return true, nil
+}
+
+// Enable enables pending MFA of the user, step of the confirmed code can't be used again
+func (r *MemoryMFARepository) Enable(userID int, step int64, enabledAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mfa, ok := r.mfas[userID]
+	if !ok || mfa.Enabled() {
+		return false, nil
+	}
+
+	// database keeps timestamps with microsecond precision
+	enabledAt = enabledAt.UTC().Truncate(time.Microsecond)
+	mfa.EnabledAt = &enabledAt
+	mfa.LastUsedStep = step
+	r.mfas[userID] = mfa
+
+	return true, nil
+}
+
+// UseStep records usage of code with the step, only steps newer than the last used one are accepted
+func (r *MemoryMFARepository) UseStep(userID int, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mfa, ok := r.mfas[userID]
+	if !ok || mfa.LastUsedStep >= step {
+		return false, nil
+	}
+
+	mfa.LastUsedStep = step
+	r.mfas[userID] = mfa
+
+	return true, nil
+}
+
+// Delete deletes MFA of the user together with recovery codes
+func (r *MemoryMFARepository) Delete(userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.mfas, userID)
+	delete(r.recoveryCodes, userID)
+
+	return nil
+}
+
+// ReplaceRecoveryCodes replaces all recovery codes of the user, the user has to exist
+func (r *MemoryMFARepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	if err := r.checkUser(userID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := make(map[string]struct{}, len(codeHashes))
+	for _, codeHash := range codeHashes {
+		if _, ok := codes[codeHash]; ok {
+			return errors.Errorf("impossible to create recovery code of user, userID=%d", userID)
+		}
+		codes[codeHash] = struct{}{}
+	}
+	r.recoveryCodes[userID] = codes
+
+	return nil
+}
+
+// UseRecoveryCode deletes recovery code of the user, only one of concurrent calls for the same code reports it was used
+func (r *MemoryMFARepository) UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.recoveryCodes[userID][codeHash]; !ok {
+		return false, nil
+	}
+
+	delete(r.recoveryCodes[userID], codeHash)
+	return true, nil
+}
+
+// checkUser returns error when the user does not exist like foreign key does.
+// The user is checked before the lock is taken, so locks of repositories are never held together.
+func (r *MemoryMFARepository) checkUser(userID int) error {
+	user, err := r.users.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.Errorf("impossible to create MFA of not existing user, userID=%d", userID)
+	}
+
+	return nil
+}
+
+// deleteByUserID deletes MFA and recovery codes of deleted user like foreign key with ON DELETE CASCADE does.
+func (r *MemoryMFARepository) deleteByUserID(userID int) {
+	// Delete never fails
+	_ = r.Delete(userID)
+}
+
+// snapshot returns copy of the repository state.
+func (r *MemoryMFARepository) snapshot() (map[int]model.MFA, map[int]map[string]struct{}) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	mfas := make(map[int]model.MFA, len(r.mfas))
+	for userID, mfa := range r.mfas {
+		mfas[userID] = mfa
+	}
+
+	recoveryCodes := make(map[int]map[string]struct{}, len(r.recoveryCodes))
+	for userID, codes := range r.recoveryCodes {
+		recoveryCodes[userID] = make(map[string]struct{}, len(codes))
+		for codeHash := range codes {
+			recoveryCodes[userID][codeHash] = struct{}{}
+		}
+	}
+
+	return mfas, recoveryCodes
+}
+
+// restore replaces the repository state with the snapshot.
+func (r *MemoryMFARepository) restore(mfas map[int]model.MFA, recoveryCodes map[int]map[string]struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.mfas = mfas
+	r.recoveryCodes = recoveryCodes
+}
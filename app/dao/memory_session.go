@@ -0,0 +1,159 @@
+package dao
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// MemorySessionRepository is thread-safe in-memory implementation of SessionRepositoryProvider.
+type MemorySessionRepository struct {
+	mu            sync.RWMutex
+	sessions      map[int]model.Session
+	refreshTokens map[string]int
+	nextID        int
+	now           func() time.Time
+}
+
+// NewMemorySessionRepository creates new instance of MemorySessionRepository.
+func NewMemorySessionRepository() *MemorySessionRepository {
+	return &MemorySessionRepository{
+		sessions:      make(map[int]model.Session),
+		refreshTokens: make(map[string]int),
+		nextID:        1,
+		now:           time.Now,
+	}
+}
+
+// Create creates new Session record with its first refresh token
+func (r *MemorySessionRepository) Create(session *model.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session.ID = r.nextID
+	session.CreatedAt = r.now().UTC().Truncate(time.Microsecond)
+	r.nextID++
+	r.sessions[session.ID] = *session
+	r.refreshTokens[session.RefreshTokenHash] = session.ID
+
+	return nil
+}
+
+// GetByRefreshToken returns Session object which the refresh token was issued for, including rotated tokens
+func (r *MemorySessionRepository) GetByRefreshToken(tokenHash string) (*model.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessionID, ok := r.refreshTokens[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+
+	session := r.sessions[sessionID]
+	return &session, nil
+}
+
+// Rotate replaces current refresh token of active session, only one of concurrent calls for the same token succeeds
+func (r *MemorySessionRepository) Rotate(session *model.Session, previousTokenHash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.sessions[session.ID]
+	if !ok || stored.RefreshTokenHash != previousTokenHash || stored.RevokedAt != nil {
+		return false, nil
+	}
+
+	stored.RefreshTokenHash = session.RefreshTokenHash
+	stored.UserAgent = session.UserAgent
+	stored.IP = session.IP
+	stored.LastSeenAt = session.LastSeenAt
+	stored.ExpiresAt = session.ExpiresAt
+	r.sessions[session.ID] = stored
+	r.refreshTokens[session.RefreshTokenHash] = session.ID
+
+	return true, nil
+}
+
+// FindActiveByUserID returns sessions of the user which are neither revoked nor expired at given time,
+// the most recently used first
+func (r *MemorySessionRepository) FindActiveByUserID(userID int, now time.Time) ([]model.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := []model.Session{}
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.Active(now) {
+			sessions = append(sessions, session)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].LastSeenAt.Equal(sessions[j].LastSeenAt) {
+			return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+		}
+		return sessions[i].ID > sessions[j].ID
+	})
+
+	return sessions, nil
+}
+
+// Revoke revokes the session of the user, false is returned when there is no such active session
+func (r *MemorySessionRepository) Revoke(userID, sessionID int, revokedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[sessionID]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return false, nil
+	}
+
+	session.RevokedAt = &revokedAt
+	r.sessions[sessionID] = session
+
+	return true, nil
+}
+
+// RevokeAll revokes all sessions of the user
+func (r *MemorySessionRepository) RevokeAll(userID int, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &revokedAt
+			r.sessions[id] = session
+		}
+	}
+
+	return nil
+}
+
+// snapshot returns copy of the repository state.
+func (r *MemorySessionRepository) snapshot() (map[int]model.Session, map[string]int, int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := make(map[int]model.Session, len(r.sessions))
+	for id, session := range r.sessions {
+		sessions[id] = session
+	}
+
+	refreshTokens := make(map[string]int, len(r.refreshTokens))
+	for tokenHash, sessionID := range r.refreshTokens {
+		refreshTokens[tokenHash] = sessionID
+	}
+
+	return sessions, refreshTokens, r.nextID
+}
+
+// restore replaces the repository state with the snapshot.
+func (r *MemorySessionRepository) restore(sessions map[int]model.Session, refreshTokens map[string]int, nextID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions = sessions
+	r.refreshTokens = refreshTokens
+	r.nextID = nextID
+}
@@ -0,0 +1,192 @@
+package dao
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// MemoryTransactor is in-memory implementation of TransactionProvider.
+// Transactions are executed one by one and changes are reverted when fn returns an error.
+// Changes are visible to readers outside of transaction before it is finished.
+type MemoryTransactor struct {
+	mu            sync.Mutex
+	users         *MemoryUserRepository
+	outbox        *MemoryOutboxRepository
+	statusHistory *MemoryUserStatusHistoryRepository
+	sessions      *MemorySessionRepository
+}
+
+// NewMemoryTransactor creates new instance of MemoryTransactor.
+func NewMemoryTransactor(
+	users *MemoryUserRepository,
+	outbox *MemoryOutboxRepository,
+	statusHistory *MemoryUserStatusHistoryRepository,
+	sessions *MemorySessionRepository,
+) *MemoryTransactor {
+	return &MemoryTransactor{
+		users:         users,
+		outbox:        outbox,
+		statusHistory: statusHistory,
+		sessions:      sessions,
+	}
+}
+
+// RunInTransaction executes fn in transaction.
+// Transaction is committed when fn returns nil, otherwise it is rolled back and fn error is returned.
+func (t *MemoryTransactor) RunInTransaction(fn func(repositories Repositories) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	users, nextUserID := t.users.snapshot()
+	addresses, nextAddressID := t.users.Addresses().snapshot()
+	groups, members, nextGroupID := t.users.Groups().snapshot()
+	credentials := t.users.Credentials().snapshot()
+	mfas, recoveryCodes := t.users.MFA().snapshot()
+	events, nextEventID := t.outbox.snapshot()
+	statusChanges := t.statusHistory.snapshot()
+	sessions, refreshTokens, nextSessionID := t.sessions.snapshot()
+
+	repositories := Repositories{
+		Users:         t.users,
+		Outbox:        t.outbox,
+		Addresses:     t.users.Addresses(),
+		StatusHistory: t.statusHistory,
+		Credentials:   t.users.Credentials(),
+		MFA:           t.users.MFA(),
+		Sessions:      t.sessions,
+	}
+	if err := fn(repositories); err != nil {
+		t.users.restore(users, nextUserID)
+		t.users.Addresses().restore(addresses, nextAddressID)
+		// groups are restored too, because memberships of deleted users are removed with them
+		t.users.Groups().restore(groups, members, nextGroupID)
+		t.users.Credentials().restore(credentials)
+		t.users.MFA().restore(mfas, recoveryCodes)
+		t.outbox.restore(events, nextEventID)
+		t.statusHistory.restore(statusChanges)
+		t.sessions.restore(sessions, refreshTokens, nextSessionID)
+		return err
+	}
+
+	return nil
+}
+
+// MemoryOutboxRepository is thread-safe in-memory implementation of OutboxRepositoryProvider.
+type MemoryOutboxRepository struct {
+	mu     sync.RWMutex
+	events map[int64]model.Event
+	nextID int64
+	now    func() time.Time
+}
+
+// NewMemoryOutboxRepository creates new instance of MemoryOutboxRepository.
+func NewMemoryOutboxRepository() *MemoryOutboxRepository {
+	return &MemoryOutboxRepository{
+		events: make(map[int64]model.Event),
+		nextID: 1,
+		now:    time.Now,
+	}
+}
+
+// Add stores new event in outbox
+func (r *MemoryOutboxRepository) Add(event *model.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now().UTC().Truncate(time.Microsecond)
+	event.ID = r.nextID
+	event.NextAttemptAt = now
+	event.CreatedAt = now
+	r.nextID++
+	r.events[event.ID] = *event
+
+	return nil
+}
+
+// FindPending returns the oldest unpublished event of every user which is due to be published at given time.
+func (r *MemoryOutboxRepository) FindPending(now time.Time, limit int) ([]model.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	oldest := make(map[int]model.Event)
+	for _, event := range r.events {
+		if event.PublishedAt != nil {
+			continue
+		}
+		if current, ok := oldest[event.AggregateID]; !ok || event.ID < current.ID {
+			oldest[event.AggregateID] = event
+		}
+	}
+
+	events := []model.Event{}
+	for _, event := range oldest {
+		if !event.NextAttemptAt.After(now) {
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events, nil
+}
+
+// MarkPublished marks event as published
+func (r *MemoryOutboxRepository) MarkPublished(eventID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event, ok := r.events[eventID]; ok {
+		publishedAt := r.now().UTC()
+		event.PublishedAt = &publishedAt
+		event.Attempts++
+		r.events[eventID] = event
+	}
+
+	return nil
+}
+
+// MarkFailed stores failed publishing attempt and schedules next one
+func (r *MemoryOutboxRepository) MarkFailed(eventID int64, nextAttemptAt time.Time, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event, ok := r.events[eventID]; ok {
+		event.Attempts++
+		event.LastError = reason
+		event.NextAttemptAt = nextAttemptAt
+		r.events[eventID] = event
+	}
+
+	return nil
+}
+
+// snapshot returns copy of the repository state.
+func (r *MemoryOutboxRepository) snapshot() (map[int64]model.Event, int64) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := make(map[int64]model.Event, len(r.events))
+	for id, event := range r.events {
+		events[id] = event
+	}
+
+	return events, r.nextID
+}
+
+// restore replaces the repository state with the snapshot.
+func (r *MemoryOutboxRepository) restore(events map[int64]model.Event, nextID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = events
+	r.nextID = nextID
+}
@@ -0,0 +1,205 @@
+// +build unit
+
+package dao
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+func TestMemoryTransactorRollback(t *testing.T) {
+	users := NewMemoryUserRepository()
+	outbox := NewMemoryOutboxRepository()
+	statusHistory := NewMemoryUserStatusHistoryRepository()
+	sessions := NewMemorySessionRepository()
+	transactor := NewMemoryTransactor(users, outbox, statusHistory, sessions)
+
+	user := conformanceTestUsers[0]
+	userID, err := users.Create(&user)
+	require.Nil(t, err)
+
+	_, err = users.Addresses().Create(&model.Address{UserID: userID, City: "Berlin", Country: "DE"})
+	require.Nil(t, err)
+	groupID, err := users.Groups().Create(&model.Group{Name: "admins"})
+	require.Nil(t, err)
+	_, err = users.Groups().AddMembers(groupID, []int{userID})
+	require.Nil(t, err)
+	require.Nil(t, users.Credentials().Save(userID, "hash"))
+	_, err = users.MFA().Enroll(userID, "secret")
+	require.Nil(t, err)
+	require.Nil(t, users.MFA().ReplaceRecoveryCodes(userID, []string{"code"}))
+
+	err = transactor.RunInTransaction(func(repositories Repositories) error {
+		if _, err := repositories.Addresses.Create(&model.Address{UserID: userID, City: "Paris"}); err != nil {
+			return err
+		}
+		if _, err := repositories.Credentials.AddFailedAttempt(userID); err != nil {
+			return err
+		}
+		if _, err := repositories.MFA.UseRecoveryCode(userID, "code"); err != nil {
+			return err
+		}
+		if _, err := repositories.Users.Delete(userID); err != nil {
+			return err
+		}
+		if err := repositories.Outbox.Add(&model.Event{AggregateID: userID, Type: model.EventUserDeleted}); err != nil {
+			return err
+		}
+		if err := repositories.StatusHistory.Add(&model.UserStatusChange{UserID: userID}); err != nil {
+			return err
+		}
+		if err := repositories.Sessions.Create(&model.Session{UserID: userID, RefreshTokenHash: "hash"}); err != nil {
+			return err
+		}
+		return errors.New("failure")
+	})
+	assert.EqualError(t, err, "failure")
+
+	stored, err := users.GetByID(userID)
+	require.Nil(t, err)
+	assert.NotNil(t, stored)
+
+	events, err := outbox.FindPending(stored.CreatedAt.AddDate(1, 0, 0), 10)
+	require.Nil(t, err)
+	assert.Len(t, events, 0)
+
+	changes, err := statusHistory.FindByUserID(userID)
+	require.Nil(t, err)
+	assert.Len(t, changes, 0)
+
+	session, err := sessions.GetByRefreshToken("hash")
+	require.Nil(t, err)
+	assert.Nil(t, session)
+
+	addresses, err := users.Addresses().FindByUserID(userID)
+	require.Nil(t, err)
+	require.Len(t, addresses, 1)
+	assert.Equal(t, "Berlin", addresses[0].City)
+
+	members, _, _, err := users.FindUsers(NewUserSearchBuilder(&request.FindUsers{}).WithGroups([]int{groupID}, false))
+	require.Nil(t, err)
+	assert.Len(t, members, 1)
+
+	credential, err := users.Credentials().GetByUserID(userID)
+	require.Nil(t, err)
+	require.NotNil(t, credential)
+	assert.Equal(t, 0, credential.FailedAttempts)
+
+	mfa, err := users.MFA().GetByUserID(userID)
+	require.Nil(t, err)
+	assert.NotNil(t, mfa)
+
+	used, err := users.MFA().UseRecoveryCode(userID, "code")
+	require.Nil(t, err)
+	assert.True(t, used)
+}
+
+func TestMemoryTransactorProvidesAllRepositories(t *testing.T) {
+	users := NewMemoryUserRepository()
+	transactor := NewMemoryTransactor(
+		users, NewMemoryOutboxRepository(), NewMemoryUserStatusHistoryRepository(), NewMemorySessionRepository())
+
+	err := transactor.RunInTransaction(func(repositories Repositories) error {
+		assert.NotNil(t, repositories.Users)
+		assert.NotNil(t, repositories.Outbox)
+		assert.NotNil(t, repositories.Addresses)
+		assert.NotNil(t, repositories.StatusHistory)
+		assert.NotNil(t, repositories.Credentials)
+		assert.NotNil(t, repositories.MFA)
+		assert.NotNil(t, repositories.Sessions)
+		return nil
+	})
+	assert.Nil(t, err)
+}
+
+func TestMemoryCredentialAndMFARepositories(t *testing.T) {
+	users := NewMemoryUserRepository()
+	credentials := users.Credentials()
+	mfas := users.MFA()
+
+	assert.Error(t, credentials.Save(1, "hash"))
+	_, err := mfas.Enroll(1, "secret")
+	assert.Error(t, err)
+
+	user := conformanceTestUsers[0]
+	userID, err := users.Create(&user)
+	require.Nil(t, err)
+
+	attempts, err := credentials.AddFailedAttempt(userID)
+	require.Nil(t, err)
+	assert.Equal(t, 0, attempts)
+
+	require.Nil(t, credentials.Save(userID, "hash"))
+	for i := 1; i <= 2; i++ {
+		attempts, err = credentials.AddFailedAttempt(userID)
+		require.Nil(t, err)
+		assert.Equal(t, i, attempts)
+	}
+
+	rehashed, err := credentials.Rehash(userID, "other", "new")
+	require.Nil(t, err)
+	assert.False(t, rehashed)
+	rehashed, err = credentials.Rehash(userID, "hash", "new")
+	require.Nil(t, err)
+	assert.True(t, rehashed)
+
+	require.Nil(t, credentials.ResetFailedAttempts(userID))
+	credential, err := credentials.GetByUserID(userID)
+	require.Nil(t, err)
+	assert.Equal(t, model.Credential{UserID: userID, PasswordHash: "new", UpdatedAt: credential.UpdatedAt}, *credential)
+
+	enrolled, err := mfas.Enroll(userID, "secret")
+	require.Nil(t, err)
+	assert.True(t, enrolled)
+
+	used, err := mfas.UseStep(userID, 10)
+	require.Nil(t, err)
+	assert.True(t, used)
+
+	enabled, err := mfas.Enable(userID, 20, time.Now())
+	require.Nil(t, err)
+	assert.True(t, enabled)
+	enabled, err = mfas.Enable(userID, 30, time.Now())
+	require.Nil(t, err)
+	assert.False(t, enabled)
+
+	enrolled, err = mfas.Enroll(userID, "other")
+	require.Nil(t, err)
+	assert.False(t, enrolled)
+
+	used, err = mfas.UseStep(userID, 20)
+	require.Nil(t, err)
+	assert.False(t, used)
+	used, err = mfas.UseStep(userID, 21)
+	require.Nil(t, err)
+	assert.True(t, used)
+
+	require.Nil(t, mfas.ReplaceRecoveryCodes(userID, []string{"a", "b"}))
+	used, err = mfas.UseRecoveryCode(userID, "a")
+	require.Nil(t, err)
+	assert.True(t, used)
+	used, err = mfas.UseRecoveryCode(userID, "a")
+	require.Nil(t, err)
+	assert.False(t, used)
+
+	deleted, err := users.Delete(userID)
+	require.Nil(t, err)
+	assert.True(t, deleted)
+
+	credential, err = credentials.GetByUserID(userID)
+	require.Nil(t, err)
+	assert.Nil(t, credential)
+	mfa, err := mfas.GetByUserID(userID)
+	require.Nil(t, err)
+	assert.Nil(t, mfa)
+	used, err = mfas.UseRecoveryCode(userID, "b")
+	require.Nil(t, err)
+	assert.False(t, used)
+}
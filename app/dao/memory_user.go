@@ -0,0 +1,564 @@
+package dao
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// memoryUserFirstID is the first generated user ID, the same as in database migrations.
+const memoryUserFirstID = 500
+
+// MemoryUserRepository is thread-safe in-memory implementation of UserRepositoryProvider.
+// It is intended for tests which should not depend on database.
+// Data related to users are kept in repositories returned by Addresses and Groups,
+// so users are filtered by them and they are deleted together with users.
+type MemoryUserRepository struct {
+	mu               sync.RWMutex
+	users            map[int]model.User
+	nextID           int
+	setOfUserColumns map[string]struct{}
+	now              func() time.Time
+	addresses        *MemoryAddressRepository
+	groups           *MemoryGroupRepository
+	credentials      *MemoryCredentialRepository
+	mfa              *MemoryMFARepository
+}
+
+// NewMemoryUserRepository creates new instance of MemoryUserRepository.
+func NewMemoryUserRepository() *MemoryUserRepository {
+	r := &MemoryUserRepository{
+		users:            make(map[int]model.User),
+		nextID:           memoryUserFirstID,
+		setOfUserColumns: userSortColumns(),
+		now:              time.Now,
+	}
+	r.addresses = newMemoryAddressRepository(r)
+	r.groups = newMemoryGroupRepository(r)
+	r.credentials = newMemoryCredentialRepository(r)
+	r.mfa = newMemoryMFARepository(r)
+
+	return r
+}
+
+// Addresses returns repository of addresses of the users.
+func (r *MemoryUserRepository) Addresses() *MemoryAddressRepository {
+	return r.addresses
+}
+
+// Groups returns repository of groups of the users.
+func (r *MemoryUserRepository) Groups() *MemoryGroupRepository {
+	return r.groups
+}
+
+// Credentials returns repository of credentials of the users.
+func (r *MemoryUserRepository) Credentials() *MemoryCredentialRepository {
+	return r.credentials
+}
+
+// MFA returns repository of second factors of the users.
+func (r *MemoryUserRepository) MFA() *MemoryMFARepository {
+	return r.mfa
+}
+
+// GetByID returns User object by ID
+func (r *MemoryUserRepository) GetByID(id int) (*model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &user, nil
+}
+
+// Create creates new User record
+func (r *MemoryUserRepository) Create(user *model.User) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.emailTaken(user.Email, 0) {
+		return 0, errors.Errorf("email is already used by another user, email=%s", user.Email)
+	}
+
+	user.ID = r.nextID
+	// database keeps timestamps with microsecond precision
+	user.CreatedAt = r.now().UTC().Truncate(time.Microsecond)
+	// database keeps only date of birth without time
+	user.DateOfBirth = model.Date(user.DateOfBirth)
+	user.Attributes = types.JSONText(attributesJSON(user))
+	user.Status = userStatus(user)
+	r.nextID++
+	r.users[user.ID] = *user
+
+	return user.ID, nil
+}
+
+// Update updates user record
+func (r *MemoryUserRepository) Update(user *model.User) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok {
+		return false, nil
+	}
+
+	if r.emailTaken(user.Email, user.ID) {
+		return false, errors.Errorf("email is already used by another user, email=%s", user.Email)
+	}
+
+	if !strings.EqualFold(stored.Email, user.Email) {
+		stored.EmailVerifiedAt = nil
+	}
+	stored.Name = user.Name
+	stored.Surname = user.Surname
+	stored.Gender = user.Gender
+	stored.DateOfBirth = model.Date(user.DateOfBirth)
+	stored.Address = user.Address
+	stored.Email = user.Email
+	stored.Phone = user.Phone
+	stored.Attributes = types.JSONText(attributesJSON(user))
+	r.users[user.ID] = stored
+
+	return true, nil
+}
+
+// MarkEmailVerified marks email of the user as verified if the user still has this email.
+// Returns TRUE if user was updated
+func (r *MemoryUserRepository) MarkEmailVerified(userID int, email string, verifiedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[userID]
+	if !ok || !strings.EqualFold(stored.Email, email) {
+		return false, nil
+	}
+
+	// database keeps timestamps with microsecond precision
+	verifiedAt = verifiedAt.UTC().Truncate(time.Microsecond)
+	stored.EmailVerifiedAt = &verifiedAt
+	r.users[userID] = stored
+
+	return true, nil
+}
+
+// UpdateStatus changes status of the user if the user still has status from.
+// Returns TRUE if user was updated
+func (r *MemoryUserRepository) UpdateStatus(userID int, from, to string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[userID]
+	if !ok || stored.Status != from {
+		return false, nil
+	}
+
+	stored.Status = to
+	r.users[userID] = stored
+
+	return true, nil
+}
+
+// Delete deletes user record together with addresses, memberships, credential and MFA of the user
+func (r *MemoryUserRepository) Delete(userID int) (bool, error) {
+	if !r.delete(userID) {
+		return false, nil
+	}
+
+	// related data are deleted after the lock of users is released, so locks of repositories are never held together
+	r.addresses.deleteByUserID(userID)
+	r.groups.removeUser(userID)
+	r.credentials.deleteByUserID(userID)
+	r.mfa.deleteByUserID(userID)
+	return true, nil
+}
+
+// delete deletes user and returns TRUE if the user existed.
+func (r *MemoryUserRepository) delete(userID int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[userID]; !ok {
+		return false
+	}
+
+	delete(r.users, userID)
+	return true
+}
+
+// CheckIfExistWithNameAndSurname checks if user with provided name and surname exists
+// Returns TRUE if user already exist
+func (r *MemoryUserRepository) CheckIfExistWithNameAndSurname(name, surname string) (bool, error) {
+	user, err := r.GetByNameAndSurname(name, surname)
+	return user != nil, err
+}
+
+// GetByNameAndSurname returns User object by name and surname.
+// The user with the lowest ID is returned when there are several ones.
+func (r *MemoryUserRepository) GetByNameAndSurname(name, surname string) (*model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found *model.User
+	for _, user := range r.users {
+		if user.Name == name && user.Surname == surname && (found == nil || user.ID < found.ID) {
+			u := user
+			found = &u
+		}
+	}
+
+	return found, nil
+}
+
+// GetByEmail returns User object by case-insensitive email
+func (r *MemoryUserRepository) GetByEmail(email string) (*model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if email == "" {
+		return nil, nil
+	}
+
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			return &user, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// emailTaken checks if the email is used by user other than the one with excludedID,
+// like unique index on lowercase email does.
+func (r *MemoryUserRepository) emailTaken(email string, excludedID int) bool {
+	if email == "" {
+		return false
+	}
+
+	for _, user := range r.users {
+		if user.ID != excludedID && strings.EqualFold(user.Email, email) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindUsers finds users using pagination, sorting and filtering with the same semantics as UserRepository.
+func (r *MemoryUserRepository) FindUsers(sb *UserSearchBuilder,
+) ([]model.User, int, int, error) {
+
+	if sb.sortAttribute != "" && !attributeNameRegex.MatchString(sb.sortAttribute) {
+		return nil, 0, 0, errors.Errorf("attribute used to sort has incorrect name, sortAttribute=%s", sb.sortAttribute)
+	}
+
+	if _, ok := r.setOfUserColumns[sb.SortColumn]; !ok && sb.sortAttribute == "" {
+		return nil, 0, 0, errors.Errorf("column used to sort does not exist in user table, sortColumn=%s",
+			sb.SortColumn)
+	}
+
+	// users with matching addresses and groups are found before the lock is taken,
+	// so locks of repositories are never held together
+	addressUserIDs := r.addresses.userIDs(sb.filter)
+	groupUserIDs := r.groups.userIDs(sb.filter)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var start model.User
+	if sb.NeedsStartValue() {
+		var ok bool
+		if start, ok = r.users[sb.StartID]; !ok {
+			return nil, 0, 0, errors.Errorf("row with start ID for next page not found, creator_profile_id=%d",
+				sb.StartID)
+		}
+	}
+	start.ID = sb.StartID
+
+	compare := userComparator(sb)
+	matches := sb.filter.matcher()
+	users := make([]model.User, 0, len(r.users))
+	for _, user := range r.users {
+		if !matches(user) {
+			continue
+		}
+
+		if _, ok := addressUserIDs[user.ID]; addressUserIDs != nil && !ok {
+			continue
+		}
+
+		if _, ok := groupUserIDs[user.ID]; groupUserIDs != nil && !ok {
+			continue
+		}
+
+		if sb.StartID > 0 && compare(start, user) >= 0 {
+			continue
+		}
+
+		users = append(users, user)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return compare(users[i], users[j]) < 0
+	})
+
+	if len(users) > sb.Limit+1 {
+		users = users[:sb.Limit+1]
+	}
+
+	// previous page is queried in reverse order and returned in requested one
+	if !sb.NextPage {
+		for i, j := 0, len(users)-1; i < j; i, j = i+1, j-1 {
+			users[i], users[j] = users[j], users[i]
+		}
+	}
+
+	usersToReturn, beforeID, afterID := sb.Page(users)
+	return usersToReturn, beforeID, afterID, nil
+}
+
+// snapshot returns copy of the repository state.
+func (r *MemoryUserRepository) snapshot() (map[int]model.User, int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make(map[int]model.User, len(r.users))
+	for id, user := range r.users {
+		users[id] = user
+	}
+
+	return users, r.nextID
+}
+
+// restore replaces the repository state with the snapshot.
+func (r *MemoryUserRepository) restore(users map[int]model.User, nextID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.users = users
+	r.nextID = nextID
+}
+
+// matcher returns function which checks if user matches the filter
+// in the same way as filter criteria of the database query.
+func (f userFilter) matcher() func(user model.User) bool {
+	type condition func(user model.User) bool
+	var conditions []condition
+
+	if strings.TrimSpace(f.name) != "" {
+		name := iLikePattern("%" + f.name + "%")
+		conditions = append(conditions, func(user model.User) bool { return name.MatchString(user.Name) })
+	}
+
+	if strings.TrimSpace(f.surname) != "" {
+		surname := iLikePattern("%" + f.surname + "%")
+		conditions = append(conditions, func(user model.User) bool { return surname.MatchString(user.Surname) })
+	}
+
+	if strings.TrimSpace(f.gender) != "" {
+		gender := iLikePattern(f.gender)
+		conditions = append(conditions, func(user model.User) bool { return gender.MatchString(user.Gender) })
+	}
+
+	if strings.TrimSpace(f.address) != "" {
+		address := iLikePattern("%" + f.address + "%")
+		conditions = append(conditions, func(user model.User) bool { return address.MatchString(user.Address) })
+	}
+
+	if f.status != "" {
+		conditions = append(conditions, func(user model.User) bool { return user.Status == f.status })
+	}
+
+	if len(f.attributes) > 0 {
+		// values are compared in the form in which they are decoded from JSON stored in the database
+		var expected map[string]interface{}
+		attributes, _ := json.Marshal(f.attributes)
+		_ = json.Unmarshal(attributes, &expected)
+		conditions = append(conditions, func(user model.User) bool {
+			values := user.AttributeValues()
+			for name, value := range expected {
+				if actual, ok := values[name]; !ok || !reflect.DeepEqual(actual, value) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	if f.minAge > 0 {
+		maxDateOfBirth := f.maxDateOfBirth()
+		conditions = append(conditions, func(user model.User) bool { return !user.DateOfBirth.After(maxDateOfBirth) })
+	}
+
+	if f.maxAge > 0 {
+		minDateOfBirth := f.minDateOfBirth()
+		conditions = append(conditions, func(user model.User) bool { return user.DateOfBirth.After(minDateOfBirth) })
+	}
+
+	return func(user model.User) bool {
+		for _, c := range conditions {
+			if !c(user) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// iLikePattern converts case-insensitive LIKE pattern to regular expression.
+// % matches any sequence and _ matches one character.
+func iLikePattern(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("(?is)^")
+	for _, c := range pattern {
+		switch c {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	return regexp.MustCompile(sb.String())
+}
+
+// userComparator returns function which compares users in the order in which the page is queried,
+// it returns -1 when a precedes b, 0 or 1. Users without the sort attribute are last in both sort orders,
+// so they precede other users when previous page is queried in reverse order.
+func userComparator(sb *UserSearchBuilder) func(a, b model.User) int {
+	sign := -orderSign(sb.getSortOrder() == Asc.orderNext)
+	if sb.sortAttribute == "" {
+		return func(a, b model.User) int {
+			return sign * compareUsers(a, b, sb.SortColumn)
+		}
+	}
+
+	missingSign := 1
+	if !sb.NextPage {
+		missingSign = -1
+	}
+
+	return func(a, b model.User) int {
+		aValue := a.AttributeValues()[sb.sortAttribute]
+		bValue := b.AttributeValues()[sb.sortAttribute]
+		switch {
+		case aValue == nil && bValue != nil:
+			return missingSign
+		case aValue != nil && bValue == nil:
+			return -missingSign
+		}
+
+		if result := compareJSONValues(aValue, bValue); result != 0 {
+			return sign * result
+		}
+		return sign * compareInts(a.ID, b.ID)
+	}
+}
+
+// compareJSONValues compares values decoded from JSON like PostgreSQL compares jsonb values.
+// Values of different types are ordered string < number < boolean, nil values are equal.
+// It returns -1, 0 or 1.
+func compareJSONValues(a, b interface{}) int {
+	rank := func(value interface{}) int {
+		switch value.(type) {
+		case string:
+			return 1
+		case float64:
+			return 2
+		case bool:
+			return 3
+		default:
+			return 0
+		}
+	}
+
+	if result := compareInts(rank(a), rank(b)); result != 0 {
+		return result
+	}
+
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case float64:
+		switch {
+		case a < b.(float64):
+			return -1
+		case a > b.(float64):
+			return 1
+		}
+	case bool:
+		if a != b.(bool) {
+			if a {
+				return 1
+			}
+			return -1
+		}
+	}
+
+	return 0
+}
+
+// compareUsers compares users by the column and by ID when column values are equal.
+// It returns -1, 0 or 1.
+func compareUsers(a, b model.User, column string) int {
+	var result int
+	switch column {
+	case "name":
+		result = strings.Compare(a.Name, b.Name)
+	case "surname":
+		result = strings.Compare(a.Surname, b.Surname)
+	case "gender":
+		result = strings.Compare(a.Gender, b.Gender)
+	case "address":
+		result = strings.Compare(a.Address, b.Address)
+	case "email":
+		result = strings.Compare(a.Email, b.Email)
+	case "phone":
+		result = strings.Compare(a.Phone, b.Phone)
+	case "status":
+		result = strings.Compare(a.Status, b.Status)
+	case "date_of_birth":
+		result = compareInts(int(a.DateOfBirth.Sub(b.DateOfBirth)), 0)
+	case "created_at":
+		result = compareInts(int(a.CreatedAt.Sub(b.CreatedAt)), 0)
+	}
+
+	if result != 0 {
+		return result
+	}
+
+	return compareInts(a.ID, b.ID)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func orderSign(ascending bool) int {
+	if ascending {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// MemoryUserStatusHistoryRepository is thread-safe in-memory implementation of UserStatusHistoryRepositoryProvider.
+type MemoryUserStatusHistoryRepository struct {
+	mu      sync.RWMutex
+	changes []model.UserStatusChange
+	now     func() time.Time
+}
+
+// NewMemoryUserStatusHistoryRepository creates new instance of MemoryUserStatusHistoryRepository.
+func NewMemoryUserStatusHistoryRepository() *MemoryUserStatusHistoryRepository {
+	return &MemoryUserStatusHistoryRepository{
+		now: time.Now,
+	}
+}
+
+// Add stores transition of user status
+func (r *MemoryUserStatusHistoryRepository) Add(change *model.UserStatusChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change.ID = len(r.changes) + 1
+	change.CreatedAt = r.now().UTC().Truncate(time.Microsecond)
+	r.changes = append(r.changes, *change)
+
+	return nil
+}
+
+// FindByUserID returns all status transitions of the user from the oldest one
+func (r *MemoryUserStatusHistoryRepository) FindByUserID(userID int) ([]model.UserStatusChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	changes := []model.UserStatusChange{}
+	for _, change := range r.changes {
+		if change.UserID == userID {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+// snapshot returns number of stored changes, history is append only.
+func (r *MemoryUserStatusHistoryRepository) snapshot() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.changes)
+}
+
+// restore drops changes stored after the snapshot.
+func (r *MemoryUserStatusHistoryRepository) restore(count int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.changes = r.changes[:count]
+}
@@ -0,0 +1,220 @@
+package dao
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// MFARepositoryProvider provides an interface to work with database MFA entity and recovery codes
+type MFARepositoryProvider interface {
+	// GetByUserID returns MFA object of the user
+	GetByUserID(userID int) (*model.MFA, error)
+	// Enroll sets secret of pending MFA of the user, enabled MFA is kept
+	Enroll(userID int, secret string) (bool, error)
+	// Enable enables pending MFA of the user, step of the confirmed code can't be used again
+	Enable(userID int, step int64, enabledAt time.Time) (bool, error)
+	// UseStep records usage of code with the step, only steps newer than the last used one are accepted
+	UseStep(userID int, step int64) (bool, error)
+	// Delete deletes MFA of the user together with recovery codes
+	Delete(userID int) error
+	// ReplaceRecoveryCodes replaces all recovery codes of the user
+	ReplaceRecoveryCodes(userID int, codeHashes []string) error
+	// UseRecoveryCode deletes recovery code of the user, only one of concurrent calls for the same code reports it was used
+	UseRecoveryCode(userID int, codeHash string) (bool, error)
+}
+
+// MFARepository represents object to work with database MFA entity and recovery codes
+type MFARepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewMFARepository creates new instance of MFARepository.
+func NewMFARepository(db *sqlx.DB) *MFARepository {
+	return newMFARepository(db)
+}
+
+func newMFARepository(db executor) *MFARepository {
+	return &MFARepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// GetByUserID returns MFA object of the user
+func (r MFARepository) GetByUserID(userID int) (*model.MFA, error) {
+	var mfa model.MFA
+	if err := r.db.Get(&mfa, r.dialect.Query(`
+		SELECT user_id,
+		       secret,
+		       enabled_at,
+		       last_used_step,
+		       created_at
+		FROM user_sch.user_mfa
+		WHERE user_id = ?`), userID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "impossible to get MFA of user, userID=%d", userID)
+	}
+
+	return &mfa, nil
+}
+
+// Enroll sets secret of pending MFA of the user, enabled MFA is kept
+func (r MFARepository) Enroll(userID int, secret string) (bool, error) {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	DELETE FROM user_sch.user_mfa
+	WHERE user_id = ?
+	AND enabled_at IS NULL`),
+		userID,
+	); err != nil {
+		return false, errors.Wrapf(err, "impossible to delete pending MFA of user, userID=%d", userID)
+	}
+
+	res, err := r.db.Exec(r.dialect.Query(r.dialect.InsertIgnore(`
+	INSERT INTO user_sch.user_mfa(
+		user_id,
+		secret
+	) VALUES (
+		?, ?
+	)`)),
+		userID,
+		secret,
+	)
+	if err != nil {
+		return false, errors.Wrapf(err, "impossible to create MFA of user, userID=%d", userID)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if MFA of user was created")
+	}
+
+	return count == 1, nil
+}
+
+// Enable enables pending MFA of the user, step of the confirmed code can't be used again
+func (r MFARepository) Enable(userID int, step int64, enabledAt time.Time) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.user_mfa
+	SET enabled_at = ?,
+	    last_used_step = ?
+	WHERE user_id = ?
+	AND enabled_at IS NULL`),
+		enabledAt,
+		step,
+		userID,
+	)
+	if err != nil {
+		return false, errors.Wrapf(err, "impossible to enable MFA of user, userID=%d", userID)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if MFA of user was enabled")
+	}
+
+	return count == 1, nil
+}
+
+// UseStep records usage of code with the step, only steps newer than the last used one are accepted
+func (r MFARepository) UseStep(userID int, step int64) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.user_mfa
+	SET last_used_step = ?
+	WHERE user_id = ?
+	AND last_used_step < ?`),
+		step,
+		userID,
+		step,
+	)
+	if err != nil {
+		return false, errors.Wrapf(err, "impossible to use MFA code of user, userID=%d", userID)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if MFA code of user was used")
+	}
+
+	return count == 1, nil
+}
+
+// Delete deletes MFA of the user together with recovery codes
+func (r MFARepository) Delete(userID int) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	DELETE FROM user_sch.user_mfa_recovery_code
+	WHERE user_id = ?`),
+		userID,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to delete recovery codes of user, userID=%d", userID)
+	}
+
+	if _, err := r.db.Exec(r.dialect.Query(`
+	DELETE FROM user_sch.user_mfa
+	WHERE user_id = ?`),
+		userID,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to delete MFA of user, userID=%d", userID)
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes replaces all recovery codes of the user
+func (r MFARepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	DELETE FROM user_sch.user_mfa_recovery_code
+	WHERE user_id = ?`),
+		userID,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to delete recovery codes of user, userID=%d", userID)
+	}
+
+	for _, codeHash := range codeHashes {
+		if _, err := r.db.Exec(r.dialect.Query(`
+		INSERT INTO user_sch.user_mfa_recovery_code(
+			user_id,
+			code_hash
+		) VALUES (
+			?, ?
+		)`),
+			userID,
+			codeHash,
+		); err != nil {
+			return errors.Wrapf(err, "impossible to create recovery code of user, userID=%d", userID)
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode deletes recovery code of the user, only one of concurrent calls for the same code reports it was used
+func (r MFARepository) UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	DELETE FROM user_sch.user_mfa_recovery_code
+	WHERE user_id = ?
+	AND code_hash = ?`),
+		userID,
+		codeHash,
+	)
+	if err != nil {
+		return false, errors.Wrapf(err, "impossible to use recovery code of user, userID=%d", userID)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if recovery code of user was used")
+	}
+
+	return count == 1, nil
+}
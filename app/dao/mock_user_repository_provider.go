@@ -4,6 +4,7 @@ package dao
 
 import mock "github.com/stretchr/testify/mock"
 import model "github.com/mmgopher/user-service/app/model"
+import time "time"
 
 // MockUserRepositoryProvider is an autogenerated mock type for the UserRepositoryProvider type
 type MockUserRepositoryProvider struct {
@@ -110,6 +111,29 @@ func (_m *MockUserRepositoryProvider) FindUsers(sb *UserSearchBuilder) ([]model.
 	return r0, r1, r2, r3
 }
 
+// GetByEmail provides a mock function with given fields: email
+func (_m *MockUserRepositoryProvider) GetByEmail(email string) (*model.User, error) {
+	ret := _m.Called(email)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
+		r0 = rf(email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: id
 func (_m *MockUserRepositoryProvider) GetByID(id int) (*model.User, error) {
 	ret := _m.Called(id)
@@ -156,6 +180,27 @@ func (_m *MockUserRepositoryProvider) GetByNameAndSurname(name string, surname s
 	return r0, r1
 }
 
+// MarkEmailVerified provides a mock function with given fields: userID, email, verifiedAt
+func (_m *MockUserRepositoryProvider) MarkEmailVerified(userID int, email string, verifiedAt time.Time) (bool, error) {
+	ret := _m.Called(userID, email, verifiedAt)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(int, string, time.Time) bool); ok {
+		r0 = rf(userID, email, verifiedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, string, time.Time) error); ok {
+		r1 = rf(userID, email, verifiedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: user
 func (_m *MockUserRepositoryProvider) Update(user *model.User) (bool, error) {
 	ret := _m.Called(user)
@@ -176,3 +221,24 @@ func (_m *MockUserRepositoryProvider) Update(user *model.User) (bool, error) {
 
 	return r0, r1
 }
+
+// UpdateStatus provides a mock function with given fields: userID, from, to
+func (_m *MockUserRepositoryProvider) UpdateStatus(userID int, from string, to string) (bool, error) {
+	ret := _m.Called(userID, from, to)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(int, string, string) bool); ok {
+		r0 = rf(userID, from, to)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, string, string) error); ok {
+		r1 = rf(userID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
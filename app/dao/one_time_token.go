@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// OneTimeTokenRepositoryProvider provides an interface to work with database OneTimeToken entity
+type OneTimeTokenRepositoryProvider interface {
+	// Create creates new OneTimeToken record
+	Create(token *model.OneTimeToken) error
+	// Get returns OneTimeToken object with the purpose by hash of the token
+	Get(purpose, tokenHash string) (*model.OneTimeToken, error)
+	// Use deletes OneTimeToken record, only one of concurrent calls for the same token reports it was used
+	Use(tokenHash string) (bool, error)
+	// DeleteByUserID deletes all OneTimeToken records of the user with the purpose
+	DeleteByUserID(purpose string, userID int) error
+}
+
+// OneTimeTokenRepository represents object to work with database OneTimeToken entity
+type OneTimeTokenRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewOneTimeTokenRepository creates new instance of OneTimeTokenRepository.
+func NewOneTimeTokenRepository(db *sqlx.DB) *OneTimeTokenRepository {
+	return &OneTimeTokenRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// Create creates new OneTimeToken record
+func (r OneTimeTokenRepository) Create(token *model.OneTimeToken) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	INSERT INTO user_sch.one_time_token(
+		token_hash,
+		purpose,
+		user_id,
+		subject,
+		expires_at
+	) VALUES (
+		?, ?, ?, ?, ?
+	)`),
+		token.TokenHash,
+		token.Purpose,
+		token.UserID,
+		token.Subject,
+		token.ExpiresAt,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to create one-time token, purpose=%s, userID=%d", token.Purpose, token.UserID)
+	}
+
+	return nil
+}
+
+// Get returns OneTimeToken object with the purpose by hash of the token
+func (r OneTimeTokenRepository) Get(purpose, tokenHash string) (*model.OneTimeToken, error) {
+	var token model.OneTimeToken
+	if err := r.db.Get(&token, r.dialect.Query(`
+		SELECT token_hash,
+		       purpose,
+		       user_id,
+		       subject,
+		       expires_at,
+		       created_at
+		FROM user_sch.one_time_token
+		WHERE token_hash = ?
+		AND purpose = ?`), tokenHash, purpose,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "impossible to get one-time token, purpose=%s", purpose)
+	}
+
+	return &token, nil
+}
+
+// Use deletes OneTimeToken record, only one of concurrent calls for the same token reports it was used
+func (r OneTimeTokenRepository) Use(tokenHash string) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	DELETE FROM user_sch.one_time_token
+	WHERE token_hash = ?`),
+		tokenHash,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to use one-time token")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if one-time token was used")
+	}
+
+	return count == 1, nil
+}
+
+// DeleteByUserID deletes all OneTimeToken records of the user with the purpose
+func (r OneTimeTokenRepository) DeleteByUserID(purpose string, userID int) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	DELETE FROM user_sch.one_time_token
+	WHERE user_id = ?
+	AND purpose = ?`),
+		userID,
+		purpose,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to delete one-time tokens, purpose=%s, userID=%d", purpose, userID)
+	}
+
+	return nil
+}
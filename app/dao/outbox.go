@@ -0,0 +1,128 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// OutboxRepositoryProvider provides an interface to work with database outbox events
+type OutboxRepositoryProvider interface {
+	// Add stores new event in outbox table
+	Add(event *model.Event) error
+	// FindPending returns the oldest unpublished event of every user which is due to be published at given time.
+	// Only the oldest event of the user is returned to keep events order per user ID.
+	FindPending(now time.Time, limit int) ([]model.Event, error)
+	// MarkPublished marks event as published
+	MarkPublished(eventID int64) error
+	// MarkFailed stores failed publishing attempt and schedules next one
+	MarkFailed(eventID int64, nextAttemptAt time.Time, reason string) error
+}
+
+// OutboxRepository represents object to work with database outbox events
+type OutboxRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewOutboxRepository creates new instance of OutboxRepository.
+func NewOutboxRepository(db *sqlx.DB) *OutboxRepository {
+	return newOutboxRepository(db)
+}
+
+func newOutboxRepository(db executor) *OutboxRepository {
+	return &OutboxRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// Add stores new event in outbox table
+func (r OutboxRepository) Add(event *model.Event) error {
+	err := r.dialect.InsertReturning(r.db, "user_sch.outbox_event", `
+	INSERT INTO user_sch.outbox_event(
+		aggregate_id,
+		type,
+		payload
+	) VALUES (
+		?, ?, ?
+	)`,
+		[]interface{}{event.AggregateID, event.Type, event.Payload},
+		[]string{"id", "next_attempt_at", "created_at"},
+		&event.ID, &event.NextAttemptAt, &event.CreatedAt,
+	)
+
+	if err != nil {
+		return errors.Wrapf(err, "impossible to create outbox event, type=%s", event.Type)
+	}
+
+	return nil
+}
+
+// FindPending returns the oldest unpublished event of every user which is due to be published at given time.
+// Only the oldest event of the user is returned to keep events order per user ID.
+func (r OutboxRepository) FindPending(now time.Time, limit int) ([]model.Event, error) {
+	var events []model.Event
+	if err := r.db.Select(&events, r.dialect.Query(`
+		SELECT id,
+		       aggregate_id,
+		       type,
+		       payload,
+		       attempts,
+		       last_error,
+		       next_attempt_at,
+		       published_at,
+		       created_at
+		FROM user_sch.outbox_event
+		WHERE id IN (
+			SELECT min(id)
+			FROM user_sch.outbox_event
+			WHERE published_at IS NULL
+			GROUP BY aggregate_id
+		)
+		AND next_attempt_at <= ?
+		ORDER BY id
+		LIMIT ?`), now, limit,
+	); err != nil {
+		return nil, errors.Wrap(err, "impossible to get pending outbox events")
+	}
+
+	return events, nil
+}
+
+// MarkPublished marks event as published
+func (r OutboxRepository) MarkPublished(eventID int64) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.outbox_event
+	SET published_at = CURRENT_TIMESTAMP,
+	    attempts = attempts + 1
+	WHERE id = ?`),
+		eventID,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to mark outbox event as published, eventID=%d", eventID)
+	}
+
+	return nil
+}
+
+// MarkFailed stores failed publishing attempt and schedules next one
+func (r OutboxRepository) MarkFailed(eventID int64, nextAttemptAt time.Time, reason string) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.outbox_event
+	SET attempts = attempts + 1,
+	    last_error = ?,
+	    next_attempt_at = ?
+	WHERE id = ?`),
+		reason,
+		nextAttemptAt,
+		eventID,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to mark outbox event as failed, eventID=%d", eventID)
+	}
+
+	return nil
+}
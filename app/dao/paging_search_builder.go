@@ -54,8 +54,10 @@ type PagingSearchBuilder struct {
 	Limit      int
 	StartID    int
 	SortColumn string
-	SortOrder  PagingSortOrder
-	NextPage   bool
+	// SortValueParam is a placeholder of sort column value in where criteria, ? is used when it is empty.
+	SortValueParam string
+	SortOrder      PagingSortOrder
+	NextPage       bool
 }
 
 // NewPagingSearchBuilder creates new instance of Paging Search Builder.
@@ -112,29 +114,42 @@ func (b PagingSearchBuilder) isSortByPrimaryKey() bool {
 // GetOrderByCriteria returns order criteria.
 // If results is sorted by primary key it returns primary_key ASC/DESC
 // In query it is used like this: ORDER BY primary_key ASC/DESC
-// if it is sorted by another column it returns  column_name ASC/DESC, primary_key
-// In query it is used like this: column_name ASC/DESC, primary_key
+// if it is sorted by another column it returns  column_name ASC/DESC, primary_key ASC/DESC
+// Primary key is sorted in the same direction, so rows with equal column value keep stable order in both directions.
 func (b PagingSearchBuilder) GetOrderByCriteria() string {
 	if b.isSortByPrimaryKey() {
 		return fmt.Sprintf("%s %s", b.PrimaryKey, b.getSortOrder())
 	}
 
-	return fmt.Sprintf("%s %s,%s", b.SortColumn, b.getSortOrder(), b.PrimaryKey)
+	return fmt.Sprintf("%s %s,%s %s", b.SortColumn, b.getSortOrder(), b.PrimaryKey, b.getSortOrder())
 
 }
 
 // GetWhereCriteria returns "where" criteria used to know the place where to start quering next or prevoius page
-func (b PagingSearchBuilder) GetWhereCriteria() (whereCondition string, args []interface{}) {
-
-	if b.StartID > 0 {
-		whereCondition = fmt.Sprintf("%s %s", b.SortColumn, b.getWhereOperator())
-		if b.isSortByPrimaryKey() {
-			args = []interface{}{b.StartID}
-		}
-	} else {
-		whereCondition = "1 ="
-		args = []interface{}{1}
+// startValue is a value of sort column in the row with StartID, it is ignored when sorted by primary key.
+// Rows with the same value of sort column are compared by primary key, so they are not skipped.
+func (b PagingSearchBuilder) GetWhereCriteria(startValue interface{}) (whereCondition string, args []interface{}) {
+
+	if b.StartID == 0 {
+		return "1 = ?", []interface{}{1}
+	}
+
+	operator := b.getWhereOperator()
+	if b.isSortByPrimaryKey() {
+		return fmt.Sprintf("%s %s ?", b.PrimaryKey, operator), []interface{}{b.StartID}
 	}
 
-	return
+	param := b.SortValueParam
+	if param == "" {
+		param = "?"
+	}
+
+	whereCondition = fmt.Sprintf("(%[1]s %[2]s %[4]s OR (%[1]s = %[4]s AND %[3]s %[2]s ?))",
+		b.SortColumn, operator, b.PrimaryKey, param)
+	return whereCondition, []interface{}{startValue, startValue, b.StartID}
+}
+
+// NeedsStartValue returns TRUE when where criteria requires value of sort column in the row with StartID.
+func (b PagingSearchBuilder) NeedsStartValue() bool {
+	return b.StartID > 0 && !b.isSortByPrimaryKey()
 }
@@ -0,0 +1,102 @@
+package dao
+
+import (
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// ReplicaProvider provides connections to read replicas.
+type ReplicaProvider interface {
+	// Replica returns connection to read replica or to primary when there is no healthy replica.
+	Replica() *sqlx.DB
+}
+
+// ReplicaUserRepository reads users from read replicas when the read tolerates replication lag.
+// Writes and reads done in transactions go to the primary, so data written by a request is read back from primary.
+// Reads which decide about access, e.g. status checks of login, must not use it, because replicas can be stale.
+type ReplicaUserRepository struct {
+	*UserRepository
+	replicas ReplicaProvider
+	// repositories caches UserRepository of every replica connection
+	repositories sync.Map
+}
+
+// NewReplicaUserRepository creates new instance of ReplicaUserRepository.
+func NewReplicaUserRepository(primary *sqlx.DB, replicas ReplicaProvider) *ReplicaUserRepository {
+	return &ReplicaUserRepository{
+		UserRepository: NewUserRepository(primary),
+		replicas:       replicas,
+	}
+}
+
+// GetByID returns User object by ID read from replica.
+// Primary is used when replica fails.
+func (r *ReplicaUserRepository) GetByID(id int) (*model.User, error) {
+	user, err := r.replica().GetByID(id)
+	if err != nil {
+		logReplicaFallback(err)
+		return r.UserRepository.GetByID(id)
+	}
+
+	return user, nil
+}
+
+// FindUsers finds users in replica using pagination, sorting and filtering.
+// Primary is used when replica fails.
+func (r *ReplicaUserRepository) FindUsers(sb *UserSearchBuilder,
+) ([]model.User, int, int, error) {
+	users, beforeID, afterID, err := r.replica().FindUsers(sb)
+	if err != nil {
+		logReplicaFallback(err)
+		return r.UserRepository.FindUsers(sb)
+	}
+
+	return users, beforeID, afterID, nil
+}
+
+// replica returns UserRepository of replica connection, it is created once per connection.
+func (r *ReplicaUserRepository) replica() *UserRepository {
+	db := r.replicas.Replica()
+	if repository, ok := r.repositories.Load(db); ok {
+		return repository.(*UserRepository)
+	}
+
+	repository, _ := r.repositories.LoadOrStore(db, NewUserRepository(db))
+	return repository.(*UserRepository)
+}
+
+// ReplicaUserChangeRepository reads the change feed of User entity from read replicas.
+type ReplicaUserChangeRepository struct {
+	*UserChangeRepository
+	replicas ReplicaProvider
+}
+
+// NewReplicaUserChangeRepository creates new instance of ReplicaUserChangeRepository.
+func NewReplicaUserChangeRepository(primary *sqlx.DB, replicas ReplicaProvider) *ReplicaUserChangeRepository {
+	return &ReplicaUserChangeRepository{
+		UserChangeRepository: NewUserChangeRepository(primary),
+		replicas:             replicas,
+	}
+}
+
+// FindAfter returns changes with sequence greater than seq read from replica.
+// Primary is used when replica fails.
+func (r ReplicaUserChangeRepository) FindAfter(seq int64, limit int) ([]model.UserChange, error) {
+	changes, err := NewUserChangeRepository(r.replicas.Replica()).FindAfter(seq, limit)
+	if err != nil {
+		logReplicaFallback(err)
+		return r.UserChangeRepository.FindAfter(seq, limit)
+	}
+
+	return changes, nil
+}
+
+func logReplicaFallback(err error) {
+	log.WithFields(log.Fields{
+		"err": err,
+	}).Warn("impossible to read from replica, reading from primary")
+}
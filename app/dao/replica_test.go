@@ -0,0 +1,89 @@
+// +build unit
+
+package dao
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+type staticReplicaProvider struct {
+	replica *sqlx.DB
+}
+
+func (p staticReplicaProvider) Replica() *sqlx.DB {
+	return p.replica
+}
+
+func openMigratedSQLite(t *testing.T, dir, name string) *sqlx.DB {
+	connection, err := db.GetConnection(db.Settings{Type: db.SQLiteType, Name: filepath.Join(dir, name)})
+	require.Nil(t, err)
+	require.Nil(t, helpers.ApplyMigrations(connection, helpers.MigrationsDir(db.SQLiteType)))
+	return connection
+}
+
+func TestReplicaUserRepository(t *testing.T) {
+	dir, err := ioutil.TempDir("", "user-service")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	primary := openMigratedSQLite(t, dir, "primary.db")
+	defer primary.Close()
+	replica := openMigratedSQLite(t, dir, "replica.db")
+	defer replica.Close()
+
+	repository := NewReplicaUserRepository(primary, staticReplicaProvider{replica: replica})
+	ids := createConformanceTestUsers(t, repository)
+
+	t.Run("writes go to primary", func(t *testing.T) {
+		user, err := NewUserRepository(primary).GetByID(ids[0])
+		require.Nil(t, err)
+		require.NotNil(t, user)
+		assert.Equal(t, "Alan", user.Name)
+	})
+
+	t.Run("reads go to replica", func(t *testing.T) {
+		user, err := repository.GetByID(ids[0])
+		require.Nil(t, err)
+		assert.Nil(t, user)
+
+		users, _, _, err := repository.FindUsers(NewUserSearchBuilder(&request.FindUsers{Sort: "id:asc"}))
+		require.Nil(t, err)
+		assert.Len(t, users, 0)
+
+		changes, err := NewReplicaUserChangeRepository(primary, staticReplicaProvider{replica: replica}).FindAfter(0, 10)
+		require.Nil(t, err)
+		assert.Len(t, changes, 0)
+	})
+
+	t.Run("repository is created once per replica connection", func(t *testing.T) {
+		assert.Same(t, repository.replica(), repository.replica())
+	})
+
+	t.Run("reads fall back to primary when replica fails", func(t *testing.T) {
+		require.Nil(t, replica.Close())
+
+		user, err := repository.GetByID(ids[0])
+		require.Nil(t, err)
+		require.NotNil(t, user)
+		assert.Equal(t, ids[0], user.ID)
+
+		users, _, _, err := repository.FindUsers(NewUserSearchBuilder(&request.FindUsers{Sort: "id:asc"}))
+		require.Nil(t, err)
+		assert.Len(t, users, len(ids))
+
+		changes, err := NewReplicaUserChangeRepository(primary, staticReplicaProvider{replica: replica}).FindAfter(0, 10)
+		require.Nil(t, err)
+		assert.Len(t, changes, len(ids))
+	})
+}
@@ -0,0 +1,225 @@
+package dao
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// SessionRepositoryProvider provides an interface to work with database Session entity and its refresh tokens
+type SessionRepositoryProvider interface {
+	// Create creates new Session record with its first refresh token
+	Create(session *model.Session) error
+	// GetByRefreshToken returns Session object which the refresh token was issued for, including rotated tokens
+	GetByRefreshToken(tokenHash string) (*model.Session, error)
+	// Rotate replaces current refresh token of active session, only one of concurrent calls for the same token succeeds
+	Rotate(session *model.Session, previousTokenHash string) (bool, error)
+	// FindActiveByUserID returns sessions of the user which are neither revoked nor expired at given time,
+	// the most recently used first
+	FindActiveByUserID(userID int, now time.Time) ([]model.Session, error)
+	// Revoke revokes the session of the user, false is returned when there is no such active session
+	Revoke(userID, sessionID int, revokedAt time.Time) (bool, error)
+	// RevokeAll revokes all sessions of the user
+	RevokeAll(userID int, revokedAt time.Time) error
+}
+
+// SessionRepository represents object to work with database Session entity and its refresh tokens
+type SessionRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewSessionRepository creates new instance of SessionRepository.
+func NewSessionRepository(db *sqlx.DB) *SessionRepository {
+	return newSessionRepository(db)
+}
+
+func newSessionRepository(db executor) *SessionRepository {
+	return &SessionRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// Create creates new Session record with its first refresh token
+func (r SessionRepository) Create(session *model.Session) error {
+	err := r.dialect.InsertReturning(r.db, "user_sch.user_session", `
+	INSERT INTO user_sch.user_session(
+		user_id,
+		refresh_token_hash,
+		user_agent,
+		ip,
+		last_seen_at,
+		expires_at
+	) VALUES (
+		?, ?, ?, ?, ?, ?
+	)`,
+		[]interface{}{
+			session.UserID,
+			session.RefreshTokenHash,
+			session.UserAgent,
+			session.IP,
+			session.LastSeenAt,
+			session.ExpiresAt,
+		},
+		[]string{"id", "created_at"},
+		&session.ID, &session.CreatedAt,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "impossible to create session, userID=%d", session.UserID)
+	}
+
+	return r.addRefreshToken(session)
+}
+
+// GetByRefreshToken returns Session object which the refresh token was issued for, including rotated tokens
+func (r SessionRepository) GetByRefreshToken(tokenHash string) (*model.Session, error) {
+	var session model.Session
+	if err := r.db.Get(&session, r.dialect.Query(`
+		SELECT s.id,
+		       s.user_id,
+		       s.refresh_token_hash,
+		       s.user_agent,
+		       s.ip,
+		       s.created_at,
+		       s.last_seen_at,
+		       s.expires_at,
+		       s.revoked_at
+		FROM user_sch.user_session s
+		JOIN user_sch.user_session_refresh_token t ON t.session_id = s.id
+		WHERE t.token_hash = ?`), tokenHash,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "impossible to get session by refresh token")
+	}
+
+	return &session, nil
+}
+
+// Rotate replaces current refresh token of active session, only one of concurrent calls for the same token succeeds
+func (r SessionRepository) Rotate(session *model.Session, previousTokenHash string) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.user_session
+	SET refresh_token_hash = ?,
+	    user_agent = ?,
+	    ip = ?,
+	    last_seen_at = ?,
+	    expires_at = ?
+	WHERE id = ?
+	AND refresh_token_hash = ?
+	AND revoked_at IS NULL`),
+		session.RefreshTokenHash,
+		session.UserAgent,
+		session.IP,
+		session.LastSeenAt,
+		session.ExpiresAt,
+		session.ID,
+		previousTokenHash,
+	)
+	if err != nil {
+		return false, errors.Wrapf(err, "impossible to rotate refresh token of session, sessionID=%d", session.ID)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if refresh token of session was rotated")
+	}
+
+	if count != 1 {
+		return false, nil
+	}
+
+	return true, r.addRefreshToken(session)
+}
+
+// FindActiveByUserID returns sessions of the user which are neither revoked nor expired at given time,
+// the most recently used first
+func (r SessionRepository) FindActiveByUserID(userID int, now time.Time) ([]model.Session, error) {
+	sessions := []model.Session{}
+	if err := r.db.Select(&sessions, r.dialect.Query(`
+		SELECT id,
+		       user_id,
+		       refresh_token_hash,
+		       user_agent,
+		       ip,
+		       created_at,
+		       last_seen_at,
+		       expires_at,
+		       revoked_at
+		FROM user_sch.user_session
+		WHERE user_id = ?
+		AND revoked_at IS NULL
+		AND expires_at > ?
+		ORDER BY last_seen_at DESC, id DESC`), userID, now,
+	); err != nil {
+		return nil, errors.Wrapf(err, "impossible to find sessions of user, userID=%d", userID)
+	}
+
+	return sessions, nil
+}
+
+// Revoke revokes the session of the user, false is returned when there is no such active session
+func (r SessionRepository) Revoke(userID, sessionID int, revokedAt time.Time) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.user_session
+	SET revoked_at = ?
+	WHERE id = ?
+	AND user_id = ?
+	AND revoked_at IS NULL`),
+		revokedAt,
+		sessionID,
+		userID,
+	)
+	if err != nil {
+		return false, errors.Wrapf(err, "impossible to revoke session, userID=%d, sessionID=%d", userID, sessionID)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if session was revoked")
+	}
+
+	return count == 1, nil
+}
+
+// RevokeAll revokes all sessions of the user
+func (r SessionRepository) RevokeAll(userID int, revokedAt time.Time) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.user_session
+	SET revoked_at = ?
+	WHERE user_id = ?
+	AND revoked_at IS NULL`),
+		revokedAt,
+		userID,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to revoke sessions of user, userID=%d", userID)
+	}
+
+	return nil
+}
+
+// addRefreshToken records current refresh token of the session, so its reuse is detected after rotation.
+func (r SessionRepository) addRefreshToken(session *model.Session) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	INSERT INTO user_sch.user_session_refresh_token(
+		token_hash,
+		session_id
+	) VALUES (
+		?, ?
+	)`),
+		session.RefreshTokenHash,
+		session.ID,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to create refresh token of session, sessionID=%d", session.ID)
+	}
+
+	return nil
+}
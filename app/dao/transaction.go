@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Repositories groups repositories which share the same database transaction.
+type Repositories struct {
+	Users         UserRepositoryProvider
+	Outbox        OutboxRepositoryProvider
+	Addresses     AddressRepositoryProvider
+	StatusHistory UserStatusHistoryRepositoryProvider
+	Credentials   CredentialRepositoryProvider
+	MFA           MFARepositoryProvider
+	Sessions      SessionRepositoryProvider
+}
+
+// TransactionProvider provides an interface to run several repository operations atomically.
+type TransactionProvider interface {
+	// RunInTransaction executes fn in database transaction.
+	// Transaction is committed when fn returns nil, otherwise it is rolled back and fn error is returned.
+	RunInTransaction(fn func(repositories Repositories) error) error
+}
+
+// Transactor represents object to run repository operations in database transaction.
+type Transactor struct {
+	db *sqlx.DB
+}
+
+// NewTransactor creates new instance of Transactor.
+func NewTransactor(db *sqlx.DB) *Transactor {
+	return &Transactor{
+		db: db,
+	}
+}
+
+// RunInTransaction executes fn in database transaction.
+// Transaction is committed when fn returns nil, otherwise it is rolled back and fn error is returned.
+func (t Transactor) RunInTransaction(fn func(repositories Repositories) error) error {
+	return runInTransaction(t.db, func(tx *sqlx.Tx) error {
+		return fn(Repositories{
+			Users:         newUserRepository(tx),
+			Outbox:        newOutboxRepository(tx),
+			Addresses:     newAddressRepository(tx),
+			StatusHistory: newUserStatusHistoryRepository(tx),
+			Credentials:   newCredentialRepository(tx),
+			MFA:           newMFARepository(tx),
+			Sessions:      newSessionRepository(tx),
+		})
+	})
+}
+
+// runInTransaction executes fn in database transaction.
+// Transaction is committed when fn returns nil, otherwise it is rolled back and fn error is returned.
+func runInTransaction(db *sqlx.DB, fn func(tx *sqlx.Tx) error) (err error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "impossible to begin transaction")
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			//nolint
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		//nolint
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "impossible to commit transaction")
+	}
+
+	return nil
+}
@@ -2,7 +2,9 @@ package dao
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
@@ -29,33 +31,75 @@ type UserRepositoryProvider interface {
 	// FindUsers finds users in database using pagination, sorting and filtering.
 	FindUsers(sb *UserSearchBuilder,
 	) ([]model.User, int, int, error)
+	// GetByEmail returns User object by case-insensitive email
+	GetByEmail(email string) (*model.User, error)
+	// MarkEmailVerified marks email of the user as verified if the user still has this email.
+	// Returns TRUE if user was updated
+	MarkEmailVerified(userID int, email string, verifiedAt time.Time) (bool, error)
+	// UpdateStatus changes status of the user if the user still has status from.
+	// Returns TRUE if user was updated
+	UpdateStatus(userID int, from, to string) (bool, error)
 }
 
 // UserRepository represents object to work with  database User entity
 type UserRepository struct {
-	db               *sqlx.DB
+	db               executor
+	dialect          dbhelper.Dialect
 	setOfUserColumns map[string]struct{}
 }
 
 // NewUserRepository creates new instance of Repository.
 func NewUserRepository(db *sqlx.DB) *UserRepository {
-	//I am not checking an error, because this function is called internally and I send Struct type as input parameter
-	columns, _ := dbhelper.FindColumnNames(model.User{})
+	return newUserRepository(db)
+}
+
+func newUserRepository(db executor) *UserRepository {
 	return &UserRepository{
 		db:               db,
-		setOfUserColumns: columns,
+		dialect:          dbhelper.NewDialect(db.DriverName()),
+		setOfUserColumns: userSortColumns(),
+	}
+}
+
+// userSortColumns returns columns which users can be sorted by.
+// Nullable email_verified_at can not be used as a start value of the next page.
+// Attributes are sorted by the value of single attribute, see UserSearchBuilder.
+func userSortColumns() map[string]struct{} {
+	//I am not checking an error, because this function is called internally and I send Struct type as input parameter
+	columns, _ := dbhelper.FindColumnNames(model.User{})
+	delete(columns, "email_verified_at")
+	delete(columns, "attributes")
+	return columns
+}
+
+// attributesJSON returns attributes of the user as JSON text, user without attributes has empty object.
+// Text is used, because SQLite JSON functions do not accept blobs.
+func attributesJSON(user *model.User) string {
+	if len(user.Attributes) == 0 {
+		return "{}"
 	}
+
+	return string(user.Attributes)
+}
+
+// userStatus returns status of the user, user without status is active like users created before statuses.
+func userStatus(user *model.User) string {
+	if user.Status == "" {
+		return model.UserStatusActive
+	}
+
+	return user.Status
 }
 
 // CheckIfExistWithNameAndSurname checks if user with provided name and surname exists in DB
 // Returns TRUE if user already exist
 func (r UserRepository) CheckIfExistWithNameAndSurname(name, surname string) (bool, error) {
 	var total int
-	if err := r.db.Get(&total, `
+	if err := r.db.Get(&total, r.dialect.Query(`
 		SELECT count(*)
 		FROM user_sch.user
-		WHERE name = $1
-		AND surname = $2`, name, surname,
+		WHERE name = ?
+		AND surname = ?`), name, surname,
 	); err != nil {
 		return false, errors.Wrap(err, "impossible to get count of users")
 	}
@@ -66,17 +110,22 @@ func (r UserRepository) CheckIfExistWithNameAndSurname(name, surname string) (bo
 // GetByNameAndSurname returns User object by name and surname
 func (r UserRepository) GetByNameAndSurname(name, surname string) (*model.User, error) {
 	var user model.User
-	if err := r.db.Get(&user, `
+	if err := r.db.Get(&user, r.dialect.Query(`
 		SELECT id,
 		       name,
 		       surname,
 		       gender,
-		       age,
+		       date_of_birth,
 			   address,
+			   email,
+			   phone,
+			   email_verified_at,
+			   attributes,
+			   status,
 			   created_at
 		FROM user_sch.user
-		WHERE name = $1
-		AND surname = $2`, name, surname,
+		WHERE name = ?
+		AND surname = ?`), name, surname,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -91,16 +140,21 @@ func (r UserRepository) GetByNameAndSurname(name, surname string) (*model.User,
 // GetByID returns User object by ID
 func (r UserRepository) GetByID(userID int) (*model.User, error) {
 	var user model.User
-	if err := r.db.Get(&user, `
+	if err := r.db.Get(&user, r.dialect.Query(`
 		SELECT id,
 		       name,
 		       surname,
 		       gender,
-		       age,
+		       date_of_birth,
 			   address,
+			   email,
+			   phone,
+			   email_verified_at,
+			   attributes,
+			   status,
 			   created_at
 		FROM user_sch.user
-		WHERE id = $1`, userID,
+		WHERE id = ?`), userID,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -112,28 +166,74 @@ func (r UserRepository) GetByID(userID int) (*model.User, error) {
 	return &user, nil
 }
 
+// GetByEmail returns User object by case-insensitive email
+func (r UserRepository) GetByEmail(email string) (*model.User, error) {
+	var user model.User
+	if err := r.db.Get(&user, r.dialect.Query(`
+		SELECT id,
+		       name,
+		       surname,
+		       gender,
+		       date_of_birth,
+			   address,
+			   email,
+			   phone,
+			   email_verified_at,
+			   attributes,
+			   status,
+			   created_at
+		FROM user_sch.user
+		WHERE lower(NULLIF(email, '')) = lower(?)`), email,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "impossible to get user by email")
+	}
+
+	return &user, nil
+}
+
 // Create creates new User record
 func (r UserRepository) Create(user *model.User) (int, error) {
+	err := r.inTransaction(func(db executor) error {
+		if err := r.dialect.InsertReturning(db, "user_sch.user", `
+		INSERT INTO user_sch.user(
+			name,
+			surname,
+			gender,
+			date_of_birth,
+			address,
+			email,
+			phone,
+			attributes,
+			status
+		) VALUES (
+			 ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)`,
+			[]interface{}{
+				user.Name,
+				user.Surname,
+				user.Gender,
+				model.Date(user.DateOfBirth),
+				user.Address,
+				user.Email,
+				user.Phone,
+				attributesJSON(user),
+				userStatus(user),
+			},
+			[]string{"id", "created_at"},
+			&user.ID, &user.CreatedAt,
+		); err != nil {
+			return errors.Wrap(err, "impossible to create user record")
+		}
 
-	err := r.db.QueryRow(`
-	INSERT INTO user_sch.user(
-		name,                                   
-		surname,
-		gender,
-		age,
-		address
-	) VALUES (
-		 $1, $2, $3, $4, $5
-	) RETURNING id`,
-		user.Name,
-		user.Surname,
-		user.Gender,
-		user.Age,
-		user.Address,
-	).Scan(&user.ID)
+		return r.recordChange(db, user.ID, model.UserChangeCreated)
+	})
 
 	if err != nil {
-		return 0, errors.Wrap(err, "impossible to create user record")
+		return 0, err
 	}
 
 	return user.ID, nil
@@ -141,51 +241,146 @@ func (r UserRepository) Create(user *model.User) (int, error) {
 
 // Update updates user record
 func (r UserRepository) Update(user *model.User) (bool, error) {
-	res, err := r.db.Exec(`
-	UPDATE user_sch.user
-	SET  name = $1,
-		 surname = $2,
-		 gender = $3,
-		 age = $4,
-		 address = $5
-	WHERE id = $6`,
-		user.Name,
-		user.Surname,
-		user.Gender,
-		user.Age,
-		user.Address,
-		user.ID,
-	)
+	var updated bool
+	err := r.inTransaction(func(db executor) error {
+		res, err := db.Exec(r.dialect.Query(`
+		UPDATE user_sch.user
+		SET  name = ?,
+			 surname = ?,
+			 gender = ?,
+			 date_of_birth = ?,
+			 address = ?,
+			 email_verified_at = CASE WHEN lower(email) = lower(?) THEN email_verified_at ELSE NULL END,
+			 email = ?,
+			 phone = ?,
+			 attributes = ?
+		WHERE id = ?`),
+			user.Name,
+			user.Surname,
+			user.Gender,
+			model.Date(user.DateOfBirth),
+			user.Address,
+			user.Email,
+			user.Email,
+			user.Phone,
+			attributesJSON(user),
+			user.ID,
+		)
+
+		if err != nil {
+			return errors.Wrap(err, "impossible to update user record")
+		}
 
-	if err != nil {
-		return false, errors.Wrap(err, "impossible to update user record")
-	}
+		count, err := res.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "impossible to check if user  was updated")
+		}
 
-	count, err := res.RowsAffected()
-	if err != nil {
-		return false, errors.Wrap(err, "impossible to check if user  was updated")
-	}
+		if updated = count == 1; !updated {
+			return nil
+		}
+
+		return r.recordChange(db, user.ID, model.UserChangeUpdated)
+	})
+
+	return updated, err
+}
+
+// MarkEmailVerified marks email of the user as verified if the user still has this email.
+// Returns TRUE if user was updated
+func (r UserRepository) MarkEmailVerified(userID int, email string, verifiedAt time.Time) (bool, error) {
+	var updated bool
+	err := r.inTransaction(func(db executor) error {
+		res, err := db.Exec(r.dialect.Query(`
+		UPDATE user_sch.user
+		SET email_verified_at = ?
+		WHERE id = ?
+		AND lower(email) = lower(?)`),
+			verifiedAt,
+			userID,
+			email,
+		)
+
+		if err != nil {
+			return errors.Wrap(err, "impossible to mark email as verified")
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "impossible to check if email was marked as verified")
+		}
+
+		if updated = count == 1; !updated {
+			return nil
+		}
+
+		return r.recordChange(db, userID, model.UserChangeUpdated)
+	})
 
-	return count == 1, nil
+	return updated, err
+}
+
+// UpdateStatus changes status of the user if the user still has status from.
+// Returns TRUE if user was updated
+func (r UserRepository) UpdateStatus(userID int, from, to string) (bool, error) {
+	var updated bool
+	err := r.inTransaction(func(db executor) error {
+		res, err := db.Exec(r.dialect.Query(`
+		UPDATE user_sch.user
+		SET status = ?
+		WHERE id = ?
+		AND status = ?`),
+			to,
+			userID,
+			from,
+		)
+
+		if err != nil {
+			return errors.Wrap(err, "impossible to update status of user")
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "impossible to check if status of user was updated")
+		}
+
+		if updated = count == 1; !updated {
+			return nil
+		}
+
+		return r.recordChange(db, userID, model.UserChangeUpdated)
+	})
+
+	return updated, err
 }
 
 // Delete deletes user record
 func (r UserRepository) Delete(userID int) (bool, error) {
-	res, err := r.db.Exec(`
-	DELETE from  user_sch.user
-	WHERE id = $1`,
-		userID,
-	)
+	var deleted bool
+	err := r.inTransaction(func(db executor) error {
+		res, err := db.Exec(r.dialect.Query(`
+		DELETE from  user_sch.user
+		WHERE id = ?`),
+			userID,
+		)
+
+		if err != nil {
+			return errors.Wrap(err, "impossible to delete user record")
+		}
 
-	if err != nil {
-		return false, errors.Wrap(err, "impossible to delete user record")
-	}
+		count, err := res.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "impossible to check if user  was deleted")
+		}
 
-	count, err := res.RowsAffected()
-	if err != nil {
-		return false, errors.Wrap(err, "impossible to check if user  was deleted")
-	}
-	return count == 1, nil
+		if deleted = count == 1; !deleted {
+			return nil
+		}
+
+		return r.recordChange(db, userID, model.UserChangeDeleted)
+	})
+
+	return deleted, err
 }
 
 // FindUsers finds users in database using pagination, sorting and filtering.
@@ -194,73 +389,126 @@ func (r UserRepository) FindUsers(sb *UserSearchBuilder,
 
 	//Input sanitization for sort column name
 	//Check if column provided as sort parameter is one of the columns on User entity
-	if _, ok := r.setOfUserColumns[sb.SortColumn]; !ok {
+	if _, ok := r.setOfUserColumns[sb.SortColumn]; !ok && sb.sortAttribute == "" {
 		return nil, 0, 0, errors.Errorf("column used to sort does not exist in user table, sortColumn=%s",
 			sb.SortColumn)
 	}
 
+	sb, err := sb.forDialect(r.dialect)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
 	orderByCriteria := sb.GetOrderByCriteria()
-	filterCriteria, filterArgs := sb.GetFilterCriteria()
-	whereCriteria, whereArgs := sb.GetWhereCriteria()
+	filterCriteria, filterArgs := sb.GetFilterCriteria(r.dialect)
 
 	// Used when querying next page to find row to start db searching
-	if len(whereArgs) == 0 {
-		var whereValue interface{}
-		if err := r.db.Get(&whereValue,
+	var startValue interface{}
+	if sb.NeedsStartValue() && sb.sortAttribute != "" {
+		if startValue, err = r.attributeValue(sb.StartID, sb.sortAttribute); err != nil {
+			return nil, 0, 0, err
+		}
+	} else if sb.NeedsStartValue() {
+		if err := r.db.Get(&startValue,
 			// nolint
-			fmt.Sprintf(`
+			r.dialect.Query(fmt.Sprintf(`
 				SELECT %s
 				FROM user_sch.user
-				WHERE id = $1`,
+				WHERE id = ?`,
 				sb.SortColumn,
-			), sb.StartID); err != nil {
+			)), sb.StartID); err != nil {
 			if err == sql.ErrNoRows {
 				return nil, 0, 0, errors.Errorf("row with start ID for next page not found, creator_profile_id=%d",
 					sb.StartID)
 			}
 			return nil, 0, 0, err
 		}
-		whereArgs = append(whereArgs, whereValue)
 	}
+	whereCriteria, whereArgs := sb.GetWhereCriteria(startValue)
 
 	args := append(whereArgs, filterArgs...)
 	args = append(args, sb.Limit+1)
 
 	query := sb.BuildSearchQuery(whereCriteria, filterCriteria, orderByCriteria)
-	query = r.db.Rebind(query)
+	query = r.dialect.Query(query)
 
 	var users []model.User
 	if err := r.db.Select(&users, query, args...); err != nil {
 		return nil, 0, 0, err
 	}
 
-	rowsReturnedCount := len(users)
-	if rowsReturnedCount == 0 {
-		return []model.User{}, 0, 0, nil
+	usersToReturn, beforeID, afterID := sb.Page(users)
+	return usersToReturn, beforeID, afterID, nil
+}
+
+// attributeValue returns JSON encoded value of custom attribute of the user.
+// It is decoded in Go, because JSON functions of databases return values in different formats.
+func (r UserRepository) attributeValue(userID int, name string) (string, error) {
+	user, err := r.GetByID(userID)
+	if err != nil {
+		return "", err
 	}
 
-	var beforeID int
-	var afterID int
-	var usersToReturn []model.User
-	if sb.NextPage {
-		if sb.StartID > 0 {
-			beforeID = users[0].ID
-		}
-		if rowsReturnedCount > sb.Limit {
-			afterID = users[rowsReturnedCount-2].ID
-			usersToReturn = users[:rowsReturnedCount-1]
-		} else {
-			usersToReturn = users
-		}
-	} else {
-		afterID = users[rowsReturnedCount-1].ID
-		if rowsReturnedCount > sb.Limit {
-			usersToReturn = users[1:rowsReturnedCount]
-			beforeID = users[1].ID
-		} else {
-			usersToReturn = users
-		}
+	if user == nil {
+		return "", errors.Errorf("row with start ID for next page not found, creator_profile_id=%d", userID)
 	}
 
-	return usersToReturn, beforeID, afterID, nil
+	value, err := json.Marshal(user.AttributeValues()[name])
+	if err != nil {
+		return "", errors.Wrapf(err, "impossible to encode attribute value, name=%s", name)
+	}
+
+	return string(value), nil
+}
+
+// inTransaction executes fn in database transaction.
+// Repository which is already bound to the transaction executes fn in that transaction.
+func (r UserRepository) inTransaction(fn func(db executor) error) error {
+	db, ok := r.db.(*sqlx.DB)
+	if !ok {
+		return fn(r.db)
+	}
+
+	return runInTransaction(db, func(tx *sqlx.Tx) error {
+		return fn(tx)
+	})
+}
+
+// recordChange appends the change of the user to the change feed.
+// The row of change sequence stays locked until the end of transaction,
+// so sequence numbers of the changes follow the commit order.
+func (r UserRepository) recordChange(db executor, userID int, operation string) error {
+	if _, err := db.Exec(r.dialect.Query(`
+	UPDATE user_sch.change_sequence
+	SET value = value + 1
+	WHERE id = 1`),
+	); err != nil {
+		return errors.Wrap(err, "impossible to increment change sequence")
+	}
+
+	var seq int64
+	if err := db.Get(&seq, r.dialect.Query(`
+	SELECT value
+	FROM user_sch.change_sequence
+	WHERE id = 1`),
+	); err != nil {
+		return errors.Wrap(err, "impossible to get next change sequence")
+	}
+
+	if _, err := db.Exec(r.dialect.Query(`
+	INSERT INTO user_sch.user_change(
+		seq,
+		user_id,
+		operation
+	) VALUES (
+		?, ?, ?
+	)`),
+		seq,
+		userID,
+		operation,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to record user change, userID=%d", userID)
+	}
+
+	return nil
 }
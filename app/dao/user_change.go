@@ -0,0 +1,90 @@
+package dao
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// UserChangeRepositoryProvider provides an interface to read the change feed of User entity
+type UserChangeRepositoryProvider interface {
+	// FindAfter returns changes with sequence greater than seq in commit order together with current state of the users
+	FindAfter(seq int64, limit int) ([]model.UserChange, error)
+}
+
+// UserChangeRepository represents object to read the change feed of User entity
+type UserChangeRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewUserChangeRepository creates new instance of UserChangeRepository.
+func NewUserChangeRepository(db *sqlx.DB) *UserChangeRepository {
+	return &UserChangeRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// FindAfter returns changes with sequence greater than seq in commit order together with current state of the users
+func (r UserChangeRepository) FindAfter(seq int64, limit int) ([]model.UserChange, error) {
+	changes := []model.UserChange{}
+	if err := r.db.Select(&changes, r.dialect.Query(`
+		SELECT seq,
+		       user_id,
+		       operation,
+		       changed_at
+		FROM user_sch.user_change
+		WHERE seq > ?
+		ORDER BY seq
+		LIMIT ?`), seq, limit,
+	); err != nil {
+		return nil, errors.Wrapf(err, "impossible to get user changes, seq=%d", seq)
+	}
+
+	if len(changes) == 0 {
+		return changes, nil
+	}
+
+	userIDs := make([]int, 0, len(changes))
+	for _, c := range changes {
+		userIDs = append(userIDs, c.UserID)
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT id,
+		       name,
+		       surname,
+		       gender,
+		       date_of_birth,
+			   address,
+			   email,
+			   phone,
+			   email_verified_at,
+			   attributes,
+			   status,
+			   created_at
+		FROM user_sch.user
+		WHERE id IN (?)`, userIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "impossible to build users query")
+	}
+
+	var users []model.User
+	if err := r.db.Select(&users, r.dialect.Query(query), args...); err != nil {
+		return nil, errors.Wrap(err, "impossible to get changed users")
+	}
+
+	usersByID := make(map[int]*model.User, len(users))
+	for i := range users {
+		usersByID[users[i].ID] = &users[i]
+	}
+
+	for i := range changes {
+		changes[i].User = usersByID[changes[i].UserID]
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,551 @@
+// +build unit
+
+package dao
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// bornYearsAgo returns date of birth of the user who is age years old and had birthday ten days ago.
+func bornYearsAgo(age int) time.Time {
+	return model.Date(time.Now()).AddDate(-age, 0, -10)
+}
+
+var conformanceTestUsers = []model.User{
+	{Name: "Alan", Surname: "Brown", Gender: "male", DateOfBirth: bornYearsAgo(30), Address: "California 70 Jett Lane"},
+	{Name: "alice", Surname: "Smith", Gender: "female", DateOfBirth: bornYearsAgo(25), Address: "Texas 12 Oak Street"},
+	{Name: "Bob", Surname: "Allen", Gender: "male", DateOfBirth: bornYearsAgo(41), Address: "Ohio 5 Elm Street"},
+	{Name: "Carol", Surname: "Jones", Gender: "female", DateOfBirth: bornYearsAgo(35), Address: "Texas 99 Pine Road"},
+	{Name: "Dave", Surname: "Miller", Gender: "male", DateOfBirth: bornYearsAgo(25), Address: "Nevada 1 Main Street"},
+}
+
+// conformanceRepositories are repositories of users and data related to them which share the same storage.
+type conformanceRepositories struct {
+	Users     UserRepositoryProvider
+	Addresses AddressRepositoryProvider
+	Groups    GroupRepositoryProvider
+}
+
+func TestMemoryUserRepositoryConformance(t *testing.T) {
+	testUserRepositoryConformance(t, func(t *testing.T) conformanceRepositories {
+		users := NewMemoryUserRepository()
+		return conformanceRepositories{Users: users, Addresses: users.Addresses(), Groups: users.Groups()}
+	})
+}
+
+func TestUserRepositoryConformance(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		testUserRepositoryConformance(t, func(t *testing.T) conformanceRepositories {
+			_, err := db.Exec(NewUserRepository(db).dialect.Query(`DELETE FROM user_sch.user`))
+			require.Nil(t, err)
+			_, err = db.Exec(NewUserRepository(db).dialect.Query(`DELETE FROM user_sch.` +
+				NewUserRepository(db).dialect.Quote("groups")))
+			require.Nil(t, err)
+			return conformanceRepositories{
+				Users:     NewUserRepository(db),
+				Addresses: NewAddressRepository(db),
+				Groups:    NewGroupRepository(db),
+			}
+		})
+	})
+}
+
+// testUserRepositoryConformance is a test suite which every UserRepositoryProvider implementation must pass.
+// newRepositories must return repositories without users.
+func testUserRepositoryConformance(t *testing.T, newRepositories func(t *testing.T) conformanceRepositories) {
+
+	t.Run("CRUD", func(t *testing.T) {
+		repository := newRepositories(t).Users
+		ids := createConformanceTestUsers(t, repository)
+
+		user, err := repository.GetByID(ids[0])
+		require.Nil(t, err)
+		require.NotNil(t, user)
+		assert.Equal(t, "Alan", user.Name)
+		assert.Equal(t, "California 70 Jett Lane", user.Address)
+
+		exist, err := repository.CheckIfExistWithNameAndSurname("Alan", "Brown")
+		require.Nil(t, err)
+		assert.True(t, exist)
+
+		exist, err = repository.CheckIfExistWithNameAndSurname("Alan", "Smith")
+		require.Nil(t, err)
+		assert.False(t, exist)
+
+		user, err = repository.GetByNameAndSurname("Bob", "Allen")
+		require.Nil(t, err)
+		require.NotNil(t, user)
+		assert.Equal(t, ids[2], user.ID)
+
+		user.DateOfBirth = time.Date(1978, 2, 28, 0, 0, 0, 0, time.UTC)
+		updated, err := repository.Update(user)
+		require.Nil(t, err)
+		assert.True(t, updated)
+
+		// update with unchanged values still finds the user
+		updated, err = repository.Update(user)
+		require.Nil(t, err)
+		assert.True(t, updated)
+
+		stored, err := repository.GetByID(ids[2])
+		require.Nil(t, err)
+		assert.Equal(t, "1978-02-28", stored.DateOfBirth.Format(model.DateLayout))
+		assert.Equal(t, user.CreatedAt.Unix(), stored.CreatedAt.Unix())
+
+		deleted, err := repository.Delete(ids[2])
+		require.Nil(t, err)
+		assert.True(t, deleted)
+
+		deleted, err = repository.Delete(ids[2])
+		require.Nil(t, err)
+		assert.False(t, deleted)
+
+		user, err = repository.GetByID(ids[2])
+		require.Nil(t, err)
+		assert.Nil(t, user)
+
+		user, err = repository.GetByNameAndSurname("Bob", "Allen")
+		require.Nil(t, err)
+		assert.Nil(t, user)
+
+		updated, err = repository.Update(&model.User{ID: ids[2], Name: "Bob"})
+		require.Nil(t, err)
+		assert.False(t, updated)
+	})
+
+	t.Run("FindUsers", func(t *testing.T) {
+		repository := newRepositories(t).Users
+		ids := createConformanceTestUsers(t, repository)
+
+		// ascending by date of birth: Bob(41), Carol(35), Alan(30), alice(25), Dave(25)
+		var testData = []struct {
+			name             string
+			request          request.FindUsers
+			expectedIDs      []int
+			expectedBeforeID int
+			expectedAfterID  int
+		}{
+			{"FirstPage", request.FindUsers{Limit: 2}, []int{ids[0], ids[1]}, 0, ids[1]},
+			{"NextPage", request.FindUsers{Limit: 2, AfterID: ids[1]}, []int{ids[2], ids[3]}, ids[2], ids[3]},
+			{"LastPage", request.FindUsers{Limit: 2, AfterID: ids[3]}, []int{ids[4]}, ids[4], 0},
+			{"PreviousPage", request.FindUsers{Limit: 2, BeforeID: ids[3]}, []int{ids[1], ids[2]}, ids[1], ids[2]},
+			{"FirstPreviousPage", request.FindUsers{Limit: 2, BeforeID: ids[2]}, []int{ids[0], ids[1]}, 0, ids[1]},
+			{"DescFirstPage", request.FindUsers{Limit: 2, Sort: "id:desc"}, []int{ids[4], ids[3]}, 0, ids[3]},
+			{"DescNextPage", request.FindUsers{Limit: 2, Sort: "id:desc", AfterID: ids[3]},
+				[]int{ids[2], ids[1]}, ids[2], ids[1]},
+			{"DescPreviousPage", request.FindUsers{Limit: 2, Sort: "id:desc", BeforeID: ids[1]},
+				[]int{ids[3], ids[2]}, ids[3], ids[2]},
+			{"CaseInsensitiveName", request.FindUsers{Name: "AL"}, []int{ids[0], ids[1]}, 0, 0},
+			{"Surname", request.FindUsers{Surname: "all"}, []int{ids[2]}, 0, 0},
+			{"Address", request.FindUsers{Address: "texas"}, []int{ids[1], ids[3]}, 0, 0},
+			{"GenderIsNotSubstring", request.FindUsers{Gender: "MALE"}, []int{ids[0], ids[2], ids[4]}, 0, 0},
+			{"AgeRange", request.FindUsers{MinAge: 26, MaxAge: 35}, []int{ids[0], ids[3]}, 0, 0},
+			{"AgeRangeBoundaries", request.FindUsers{MinAge: 25, MaxAge: 25}, []int{ids[1], ids[4]}, 0, 0},
+			{"NoResults", request.FindUsers{Name: "zzz"}, []int{}, 0, 0},
+			{"SortByDateOfBirth", request.FindUsers{Sort: "date_of_birth:asc", Limit: 2},
+				[]int{ids[2], ids[3]}, 0, ids[3]},
+			{"SortByDateOfBirthNextPage", request.FindUsers{Sort: "date_of_birth:asc", Limit: 2, AfterID: ids[3]},
+				[]int{ids[0], ids[1]}, ids[0], ids[1]},
+			{"SortByDateOfBirthNextPageWithinTie", request.FindUsers{Sort: "date_of_birth:asc", Limit: 2, AfterID: ids[1]},
+				[]int{ids[4]}, ids[4], 0},
+			{"SortByDateOfBirthPreviousPageWithinTie",
+				request.FindUsers{Sort: "date_of_birth:asc", Limit: 1, BeforeID: ids[4]}, []int{ids[1]}, ids[1], ids[1]},
+			{"SortByDateOfBirthDesc", request.FindUsers{Sort: "date_of_birth:desc", Limit: 3},
+				[]int{ids[4], ids[1], ids[0]}, 0, ids[0]},
+			{"SortByDateOfBirthDescNextPage", request.FindUsers{Sort: "date_of_birth:desc", Limit: 3, AfterID: ids[0]},
+				[]int{ids[3], ids[2]}, ids[3], 0},
+			{"SortByDateOfBirthDescPreviousPage",
+				request.FindUsers{Sort: "date_of_birth:desc", Limit: 2, BeforeID: ids[3]}, []int{ids[1], ids[0]}, ids[1], ids[0]},
+			{"SortByAge", request.FindUsers{Sort: "age:asc", Limit: 2}, []int{ids[4], ids[1]}, 0, ids[1]},
+			{"SortByAgeDesc", request.FindUsers{Sort: "age:desc", Limit: 2}, []int{ids[2], ids[3]}, 0, ids[3]},
+			{"SortBySurname", request.FindUsers{Sort: "surname:asc", Limit: 2}, []int{ids[2], ids[0]}, 0, ids[0]},
+			{"SortWithFilter",
+				request.FindUsers{Sort: "date_of_birth:asc", Gender: "male", Limit: 2, AfterID: ids[2]},
+				[]int{ids[0], ids[4]}, ids[0], 0},
+		}
+
+		for _, tt := range testData {
+			t.Run(tt.name, func(t *testing.T) {
+				req := tt.request
+				users, beforeID, afterID, err := repository.FindUsers(NewUserSearchBuilder(&req))
+				require.Nil(t, err)
+				assert.Equal(t, tt.expectedIDs, userIDs(users))
+				assert.Equal(t, tt.expectedBeforeID, beforeID)
+				assert.Equal(t, tt.expectedAfterID, afterID)
+			})
+		}
+	})
+
+	t.Run("Email", func(t *testing.T) {
+		repository := newRepositories(t).Users
+		ids := createConformanceTestUsers(t, repository)
+
+		user, err := repository.GetByEmail("")
+		require.Nil(t, err)
+		assert.Nil(t, user, "users without email are not found by empty email")
+
+		user, err = repository.GetByID(ids[0])
+		require.Nil(t, err)
+		user.Email = "Alan.Brown@Example.com"
+		user.Phone = "+12025550143"
+		updated, err := repository.Update(user)
+		require.Nil(t, err)
+		assert.True(t, updated)
+
+		found, err := repository.GetByEmail("alan.brown@example.com")
+		require.Nil(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, ids[0], found.ID)
+		assert.Equal(t, "Alan.Brown@Example.com", found.Email)
+		assert.Equal(t, "+12025550143", found.Phone)
+		assert.Nil(t, found.EmailVerifiedAt)
+
+		_, err = repository.Create(&model.User{Name: "Alan", Surname: "Green", Gender: "male", DateOfBirth: bornYearsAgo(20),
+			Address: "Ohio", Email: "ALAN.BROWN@example.com"})
+		assert.NotNil(t, err, "email has to be unique regardless of letter case")
+
+		other, err := repository.GetByID(ids[1])
+		require.Nil(t, err)
+		other.Email = "alan.brown@example.com"
+		_, err = repository.Update(other)
+		assert.NotNil(t, err, "email has to be unique regardless of letter case")
+
+		verifiedAt := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+		verified, err := repository.MarkEmailVerified(ids[0], "old@example.com", verifiedAt)
+		require.Nil(t, err)
+		assert.False(t, verified, "email changed after verification was requested")
+
+		verified, err = repository.MarkEmailVerified(ids[0], "alan.brown@example.com", verifiedAt)
+		require.Nil(t, err)
+		assert.True(t, verified)
+
+		user, err = repository.GetByID(ids[0])
+		require.Nil(t, err)
+		require.NotNil(t, user.EmailVerifiedAt)
+		assert.True(t, verifiedAt.Equal(*user.EmailVerifiedAt))
+
+		user.Phone = ""
+		_, err = repository.Update(user)
+		require.Nil(t, err)
+		user, err = repository.GetByID(ids[0])
+		require.Nil(t, err)
+		assert.NotNil(t, user.EmailVerifiedAt, "verification is kept while email is not changed")
+
+		user.Email = "alan@example.com"
+		_, err = repository.Update(user)
+		require.Nil(t, err)
+		user, err = repository.GetByID(ids[0])
+		require.Nil(t, err)
+		assert.Nil(t, user.EmailVerifiedAt, "verification is reset when email is changed")
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		repository := newRepositories(t).Users
+		ids := createConformanceTestUsers(t, repository)
+
+		user, err := repository.GetByID(ids[0])
+		require.Nil(t, err)
+		assert.Equal(t, model.UserStatusActive, user.Status, "users are active by default")
+
+		updated, err := repository.UpdateStatus(ids[0], model.UserStatusSuspended, model.UserStatusClosed)
+		require.Nil(t, err)
+		assert.False(t, updated, "status is changed only from the expected one")
+
+		updated, err = repository.UpdateStatus(ids[0], model.UserStatusActive, model.UserStatusSuspended)
+		require.Nil(t, err)
+		assert.True(t, updated)
+
+		updated, err = repository.UpdateStatus(ids[3], model.UserStatusActive, model.UserStatusSuspended)
+		require.Nil(t, err)
+		assert.True(t, updated)
+
+		user, err = repository.GetByID(ids[0])
+		require.Nil(t, err)
+		assert.Equal(t, model.UserStatusSuspended, user.Status)
+
+		users, _, _, err := repository.FindUsers(NewUserSearchBuilder(&request.FindUsers{Status: "suspended"}))
+		require.Nil(t, err)
+		assert.Equal(t, []int{ids[0], ids[3]}, userIDs(users))
+
+		updated, err = repository.UpdateStatus(0, model.UserStatusActive, model.UserStatusSuspended)
+		require.Nil(t, err)
+		assert.False(t, updated)
+	})
+
+	t.Run("Addresses", func(t *testing.T) {
+		repositories := newRepositories(t)
+		ids := createConformanceTestUsers(t, repositories.Users)
+
+		for _, address := range []model.Address{
+			{UserID: ids[0], Type: model.AddressHome, City: "Los Angeles", Country: "US", Primary: true},
+			{UserID: ids[1], Type: model.AddressHome, City: "Austin", Country: "US", Primary: true},
+			{UserID: ids[1], Type: model.AddressBilling, City: "Berlin", Country: "DE"},
+			{UserID: ids[3], Type: model.AddressHome, City: "BERLIN", Country: "DE", Primary: true},
+		} {
+			address := address
+			id, err := repositories.Addresses.Create(&address)
+			require.Nil(t, err)
+			assert.Equal(t, id, address.ID)
+		}
+
+		var testData = []struct {
+			name        string
+			request     request.FindUsers
+			expectedIDs []int
+		}{
+			{"CaseInsensitiveCity", request.FindUsers{City: "berlin"}, []int{ids[1], ids[3]}},
+			{"CityIsNotSubstring", request.FindUsers{City: "Los"}, []int{}},
+			{"Country", request.FindUsers{Country: "us"}, []int{ids[0], ids[1]}},
+			{"CityAndCountryOfDifferentAddresses", request.FindUsers{City: "Berlin", Country: "US"}, []int{ids[1]}},
+			{"AddressWithOtherFilter", request.FindUsers{Country: "DE", Gender: "female", Sort: "id:desc"},
+				[]int{ids[3], ids[1]}},
+		}
+
+		for _, tt := range testData {
+			t.Run(tt.name, func(t *testing.T) {
+				req := tt.request
+				users, _, _, err := repositories.Users.FindUsers(NewUserSearchBuilder(&req))
+				require.Nil(t, err)
+				assert.Equal(t, tt.expectedIDs, userIDs(users))
+			})
+		}
+
+		deleted, err := repositories.Users.Delete(ids[3])
+		require.Nil(t, err)
+		assert.True(t, deleted)
+
+		addresses, err := repositories.Addresses.FindByUserID(ids[3])
+		require.Nil(t, err)
+		assert.Len(t, addresses, 0, "addresses are deleted together with the user")
+
+		_, err = repositories.Addresses.Create(&model.Address{UserID: ids[3], Type: model.AddressHome, City: "Berlin"})
+		assert.NotNil(t, err, "address of not existing user can not be created")
+	})
+
+	t.Run("Attributes", func(t *testing.T) {
+		repository := newRepositories(t).Users
+		var ids []int
+		for i, attributes := range []string{
+			`{"department":"sales","headcount":12,"remote":true}`,
+			`{"department":"support","headcount":3,"remote":true}`,
+			`{"department":"sales","headcount":7,"remote":false}`,
+			`{}`,
+			`{"department":"support"}`,
+		} {
+			id, err := repository.Create(&model.User{
+				Name:        "Attribute",
+				Surname:     fmt.Sprintf("User%d", i),
+				DateOfBirth: bornYearsAgo(30),
+				Attributes:  types.JSONText(attributes),
+			})
+			require.Nil(t, err)
+			ids = append(ids, id)
+		}
+
+		var testData = []struct {
+			name             string
+			request          request.FindUsers
+			attributes       map[string]interface{}
+			expectedIDs      []int
+			expectedBeforeID int
+			expectedAfterID  int
+		}{
+			{"Filter", request.FindUsers{}, map[string]interface{}{"department": "sales"}, []int{ids[0], ids[2]}, 0, 0},
+			{"FilterByManyAttributes", request.FindUsers{},
+				map[string]interface{}{"department": "sales", "remote": true}, []int{ids[0]}, 0, 0},
+			{"FilterByNumber", request.FindUsers{}, map[string]interface{}{"headcount": float64(3)}, []int{ids[1]}, 0, 0},
+			{"FilterWithoutResults", request.FindUsers{}, map[string]interface{}{"department": "marketing"}, []int{}, 0, 0},
+			{"Sort", request.FindUsers{Sort: "attr.headcount:asc", Limit: 2}, nil, []int{ids[1], ids[2]}, 0, ids[2]},
+			{"SortWithFilter", request.FindUsers{Sort: "attr.headcount:desc"},
+				map[string]interface{}{"department": "sales"}, []int{ids[0], ids[2]}, 0, 0},
+			{"SortMissingLast", request.FindUsers{Sort: "attr.headcount:asc", Limit: 2, AfterID: ids[2]}, nil,
+				[]int{ids[0], ids[3]}, ids[0], ids[3]},
+			{"SortMissingLastDesc", request.FindUsers{Sort: "attr.headcount:desc", Limit: 2, AfterID: ids[2]}, nil,
+				[]int{ids[1], ids[4]}, ids[1], ids[4]},
+			{"SortPreviousPageOfMissing", request.FindUsers{Sort: "attr.headcount:asc", Limit: 2, BeforeID: ids[4]}, nil,
+				[]int{ids[0], ids[3]}, ids[0], ids[3]},
+			{"SortPreviousPageWithinMissing", request.FindUsers{Sort: "attr.headcount:desc", Limit: 1, BeforeID: ids[3]},
+				nil, []int{ids[4]}, ids[4], ids[4]},
+			{"SortByString", request.FindUsers{Sort: "attr.department:desc", Limit: 3}, nil,
+				[]int{ids[4], ids[1], ids[2]}, 0, ids[2]},
+			{"SortByBoolean", request.FindUsers{Sort: "attr.remote:asc"}, nil,
+				[]int{ids[2], ids[0], ids[1], ids[3], ids[4]}, 0, 0},
+		}
+
+		for _, tt := range testData {
+			t.Run(tt.name, func(t *testing.T) {
+				req := tt.request
+				sb := NewUserSearchBuilder(&req).WithAttributes(tt.attributes)
+				users, beforeID, afterID, err := repository.FindUsers(sb)
+				require.Nil(t, err)
+				assert.Equal(t, tt.expectedIDs, userIDs(users))
+				assert.Equal(t, tt.expectedBeforeID, beforeID)
+				assert.Equal(t, tt.expectedAfterID, afterID)
+			})
+		}
+
+		walk := func(sort string, expected []int) {
+			var visited []int
+			afterID := 0
+			for {
+				sb := NewUserSearchBuilder(&request.FindUsers{Sort: sort, Limit: 1, AfterID: afterID})
+				users, _, nextID, err := repository.FindUsers(sb)
+				require.Nil(t, err)
+				visited = append(visited, userIDs(users)...)
+				if nextID == 0 {
+					break
+				}
+				afterID = nextID
+			}
+			assert.Equal(t, expected, visited, sort)
+
+			visited = []int{expected[len(expected)-1]}
+			beforeID := expected[len(expected)-1]
+			for beforeID != 0 {
+				sb := NewUserSearchBuilder(&request.FindUsers{Sort: sort, Limit: 1, BeforeID: beforeID})
+				users, previousID, _, err := repository.FindUsers(sb)
+				require.Nil(t, err)
+				visited = append(userIDs(users), visited...)
+				beforeID = previousID
+			}
+			assert.Equal(t, expected, visited, sort)
+		}
+		walk("attr.headcount:asc", []int{ids[1], ids[2], ids[0], ids[3], ids[4]})
+		walk("attr.headcount:desc", []int{ids[0], ids[2], ids[1], ids[4], ids[3]})
+
+		_, _, _, err := repository.FindUsers(NewUserSearchBuilder(&request.FindUsers{Sort: "attr.Head-Count:asc"}))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Groups", func(t *testing.T) {
+		repositories := newRepositories(t)
+		ids := createConformanceTestUsers(t, repositories.Users)
+
+		betaID, err := repositories.Groups.Create(&model.Group{Name: "beta", Description: "beta testers"})
+		require.Nil(t, err)
+		staffID, err := repositories.Groups.Create(&model.Group{Name: "staff"})
+		require.Nil(t, err)
+		emptyID, err := repositories.Groups.Create(&model.Group{Name: "empty"})
+		require.Nil(t, err)
+
+		_, err = repositories.Groups.Create(&model.Group{Name: "beta"})
+		assert.NotNil(t, err, "names of groups are unique")
+		_, err = repositories.Groups.Update(&model.Group{ID: staffID, Name: "beta"})
+		assert.NotNil(t, err, "names of groups are unique")
+
+		groups, err := repositories.Groups.FindByNames([]string{"staff", "beta", "unknown"})
+		require.Nil(t, err)
+		require.Len(t, groups, 2)
+		assert.Equal(t, betaID, groups[0].ID)
+		assert.Equal(t, "beta testers", groups[0].Description)
+
+		added, err := repositories.Groups.AddMembers(betaID, []int{ids[0], ids[1], ids[2], ids[4] + 1000})
+		require.Nil(t, err)
+		assert.Equal(t, 3, added, "unknown users are skipped")
+		added, err = repositories.Groups.AddMembers(staffID, []int{ids[1], ids[2], ids[3], ids[4]})
+		require.Nil(t, err)
+		assert.Equal(t, 4, added)
+		added, err = repositories.Groups.AddMembers(staffID, []int{ids[4], ids[0]})
+		require.Nil(t, err)
+		assert.Equal(t, 1, added, "existing members are skipped")
+		removed, err := repositories.Groups.RemoveMembers(staffID, []int{ids[0], ids[0] + 1000})
+		require.Nil(t, err)
+		assert.Equal(t, 1, removed)
+
+		var testData = []struct {
+			name            string
+			request         request.FindUsers
+			groupIDs        []int
+			all             bool
+			expectedIDs     []int
+			expectedAfterID int
+		}{
+			{"AnyGroup", request.FindUsers{}, []int{betaID, staffID}, false, ids, 0},
+			{"AllGroups", request.FindUsers{}, []int{betaID, staffID}, true, []int{ids[1], ids[2]}, 0},
+			{"OneGroup", request.FindUsers{}, []int{staffID}, true, []int{ids[1], ids[2], ids[3], ids[4]}, 0},
+			{"EmptyGroup", request.FindUsers{}, []int{emptyID}, false, []int{}, 0},
+			{"AllGroupsWithEmptyGroup", request.FindUsers{}, []int{betaID, emptyID}, true, []int{}, 0},
+			{"AnyGroupWithPaging", request.FindUsers{Limit: 2, AfterID: ids[0], Sort: "date_of_birth:asc"},
+				[]int{betaID}, false, []int{ids[1]}, 0},
+			{"AllGroupsWithOtherFilter", request.FindUsers{Gender: "female", Sort: "id:desc", Limit: 1},
+				[]int{betaID, staffID}, true, []int{ids[1]}, 0},
+			{"AnyGroupWithSort", request.FindUsers{Sort: "surname:asc", Limit: 2},
+				[]int{staffID}, false, []int{ids[2], ids[3]}, ids[3]},
+		}
+
+		for _, tt := range testData {
+			t.Run(tt.name, func(t *testing.T) {
+				req := tt.request
+				sb := NewUserSearchBuilder(&req).WithGroups(tt.groupIDs, tt.all)
+				users, _, afterID, err := repositories.Users.FindUsers(sb)
+				require.Nil(t, err)
+				assert.Equal(t, tt.expectedIDs, userIDs(users))
+				assert.Equal(t, tt.expectedAfterID, afterID)
+			})
+		}
+
+		deleted, err := repositories.Users.Delete(ids[1])
+		require.Nil(t, err)
+		assert.True(t, deleted)
+		deleted, err = repositories.Groups.Delete(staffID)
+		require.Nil(t, err)
+		assert.True(t, deleted)
+
+		findMembers := func(groupID int) []int {
+			sb := NewUserSearchBuilder(&request.FindUsers{}).WithGroups([]int{groupID}, false)
+			users, _, _, err := repositories.Users.FindUsers(sb)
+			require.Nil(t, err)
+			return userIDs(users)
+		}
+		assert.Equal(t, []int{ids[0], ids[2]}, findMembers(betaID), "memberships are deleted together with the user")
+		assert.Len(t, findMembers(staffID), 0, "memberships are deleted together with the group")
+	})
+
+	t.Run("FindUsersErrors", func(t *testing.T) {
+		repository := newRepositories(t).Users
+		createConformanceTestUsers(t, repository)
+
+		_, _, _, err := repository.FindUsers(NewUserSearchBuilder(&request.FindUsers{Sort: "password:asc"}))
+		assert.NotNil(t, err)
+
+		_, _, _, err = repository.FindUsers(NewUserSearchBuilder(&request.FindUsers{Sort: "date_of_birth:asc", AfterID: 1}))
+		assert.NotNil(t, err)
+
+		_, _, _, err = repository.FindUsers(NewUserSearchBuilder(&request.FindUsers{Sort: "email_verified_at:asc"}))
+		assert.NotNil(t, err)
+	})
+}
+
+func createConformanceTestUsers(t *testing.T, repository UserRepositoryProvider) []int {
+	ids := make([]int, 0, len(conformanceTestUsers))
+	for i := range conformanceTestUsers {
+		user := conformanceTestUsers[i]
+		id, err := repository.Create(&user)
+		require.Nil(t, err)
+		assert.Equal(t, id, user.ID)
+		assert.False(t, user.CreatedAt.IsZero())
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func userIDs(users []model.User) []int {
+	ids := make([]int, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+
+	return ids
+}
@@ -1,20 +1,35 @@
 package dao
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
+
+	"github.com/pkg/errors"
 
 	"github.com/mmgopher/user-service/app/api/request"
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
 )
 
 const (
 	userListDefaultPageSize = 30
 	userListMaxPageSize     = 200
+	userAgeSortColumn       = "age"
+	// userAttributeSortPrefix is a prefix of sort columns which sort by custom attribute, e.g. attr.department.
+	userAttributeSortPrefix = "attr."
 )
 
+// attributeNameRegex matches names of custom attributes which can be safely used in queries.
+var attributeNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
 // UserSearchBuilder represents input parameters used to find creator activity data.
 type UserSearchBuilder struct {
 	filter userFilter
+	// sortAttribute is a name of custom attribute used to sort users.
+	sortAttribute string
 	PagingSearchBuilder
 }
 
@@ -24,8 +39,28 @@ type userFilter struct {
 	surname string
 	gender  string
 	address string
+	city    string
+	country string
+	status  string
 	minAge  int
 	maxAge  int
+	// attributes are values of custom attributes which users must have.
+	attributes map[string]interface{}
+	// groupIDs are groups of users, users have to be members of all of them when allGroups is set.
+	groupIDs  []int
+	allGroups bool
+	// today is a date to which ages are calculated.
+	today time.Time
+}
+
+// maxDateOfBirth returns the latest date of birth of users who are at least minAge years old.
+func (f userFilter) maxDateOfBirth() time.Time {
+	return f.today.AddDate(-f.minAge, 0, 0)
+}
+
+// minDateOfBirth returns the date after which users who are at most maxAge years old were born.
+func (f userFilter) minDateOfBirth() time.Time {
+	return f.today.AddDate(-f.maxAge-1, 0, 0)
 }
 
 // NewUserSearchBuilder creates new instance of User Search Builder.
@@ -45,6 +80,21 @@ func NewUserSearchBuilder(request *request.FindUsers) *UserSearchBuilder {
 		sortOrder = s[1]
 	}
 
+	// Age is not stored, users are sorted by date of birth in the opposite order instead.
+	if sortColumn == userAgeSortColumn {
+		sortColumn = "date_of_birth"
+		if sortOrder == "desc" {
+			sortOrder = "asc"
+		} else {
+			sortOrder = "desc"
+		}
+	}
+
+	var sortAttribute string
+	if strings.HasPrefix(sortColumn, userAttributeSortPrefix) {
+		sortAttribute = strings.TrimPrefix(sortColumn, userAttributeSortPrefix)
+	}
+
 	pagingSearchBuilder := NewPagingSearchBuilder(
 		rowsToReturn,
 		request.AfterID,
@@ -59,53 +109,167 @@ func NewUserSearchBuilder(request *request.FindUsers) *UserSearchBuilder {
 			surname: request.Surname,
 			gender:  request.Gender,
 			address: request.Address,
+			city:    request.City,
+			country: request.Country,
+			status:  request.Status,
 			minAge:  request.MinAge,
 			maxAge:  request.MaxAge,
+			today:   model.Date(time.Now()),
 		},
+		sortAttribute:       sortAttribute,
 		PagingSearchBuilder: pagingSearchBuilder,
 	}
 }
 
+// WithAttributes filters users by values of custom attributes.
+// Values have to be of Go types which JSON values of attributes are decoded to.
+func (usb *UserSearchBuilder) WithAttributes(attributes map[string]interface{}) *UserSearchBuilder {
+	usb.filter.attributes = attributes
+	return usb
+}
+
+// WithGroups filters users by membership in groups.
+// Users have to be members of any of the groups or of all of them when all is TRUE.
+func (usb *UserSearchBuilder) WithGroups(groupIDs []int, all bool) *UserSearchBuilder {
+	usb.filter.groupIDs = groupIDs
+	usb.filter.allGroups = all
+	return usb
+}
+
+// forDialect returns copy of the builder which sorts by expression of the dialect when users are sorted by attribute.
+func (usb UserSearchBuilder) forDialect(dialect dbhelper.Dialect) (*UserSearchBuilder, error) {
+	if usb.sortAttribute == "" {
+		return &usb, nil
+	}
+
+	if !attributeNameRegex.MatchString(usb.sortAttribute) {
+		return nil, errors.Errorf("attribute used to sort has incorrect name, sortAttribute=%s", usb.sortAttribute)
+	}
+
+	usb.SortColumn = dialect.JSONValue("attributes", usb.sortAttribute)
+	usb.SortValueParam = dialect.JSONParam()
+	return &usb, nil
+}
+
 // GetFilterCriteria returns "filter" criteria to filter results
-func (usb UserSearchBuilder) GetFilterCriteria() (string, []interface{}) {
+func (usb UserSearchBuilder) GetFilterCriteria(dialect dbhelper.Dialect) (string, []interface{}) {
 
 	var sb strings.Builder
 	sb.WriteString("1 = ?")
 	args := []interface{}{1}
 
 	if strings.TrimSpace(usb.filter.name) != "" {
-		sb.WriteString(" AND name ilike ?")
+		sb.WriteString(" AND " + dialect.ILike("name"))
 		args = append(args, fmt.Sprintf("%%%s%%", usb.filter.name))
 	}
 
 	if strings.TrimSpace(usb.filter.surname) != "" {
-		sb.WriteString(" AND surname ilike ?")
+		sb.WriteString(" AND " + dialect.ILike("surname"))
 		args = append(args, fmt.Sprintf("%%%s%%", usb.filter.surname))
 	}
 
 	if strings.TrimSpace(usb.filter.gender) != "" {
-		sb.WriteString(" AND gender ilike ?")
+		sb.WriteString(" AND " + dialect.ILike("gender"))
 		args = append(args, fmt.Sprintf("%s", usb.filter.gender))
 	}
 
-	if strings.TrimSpace(usb.filter.surname) != "" {
-		sb.WriteString(" AND address ilike ?")
+	if strings.TrimSpace(usb.filter.address) != "" {
+		sb.WriteString(" AND " + dialect.ILike("address"))
 		args = append(args, fmt.Sprintf("%%%s%%", usb.filter.address))
 	}
 
+	if strings.TrimSpace(usb.filter.city) != "" {
+		sb.WriteString(" AND id IN (SELECT user_id FROM user_sch.address WHERE " + dialect.ILike("city") + ")")
+		args = append(args, usb.filter.city)
+	}
+
+	if strings.TrimSpace(usb.filter.country) != "" {
+		sb.WriteString(" AND id IN (SELECT user_id FROM user_sch.address WHERE country = ?)")
+		args = append(args, strings.ToUpper(usb.filter.country))
+	}
+
+	if usb.filter.status != "" {
+		sb.WriteString(" AND status = ?")
+		args = append(args, usb.filter.status)
+	}
+
+	if len(usb.filter.attributes) > 0 {
+		// attributes are marshalled from decoded JSON values, so it can not fail
+		attributes, _ := json.Marshal(usb.filter.attributes)
+		sb.WriteString(" AND " + dialect.JSONContains("attributes"))
+		args = append(args, string(attributes))
+	}
+
+	if len(usb.filter.groupIDs) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(usb.filter.groupIDs)), ", ")
+		sb.WriteString(" AND id IN (SELECT user_id FROM user_sch.user_groups WHERE group_id IN (" + placeholders + ")")
+		for _, groupID := range usb.filter.groupIDs {
+			args = append(args, groupID)
+		}
+
+		// memberships are unique, so user is member of all groups when it has one membership per group
+		if usb.filter.allGroups {
+			sb.WriteString(" GROUP BY user_id HAVING COUNT(*) = ?")
+			args = append(args, len(usb.filter.groupIDs))
+		}
+		sb.WriteString(")")
+	}
+
 	if usb.filter.minAge > 0 {
-		sb.WriteString(" AND age >= ?")
-		args = append(args, usb.filter.minAge)
+		sb.WriteString(" AND date_of_birth <= ?")
+		args = append(args, usb.filter.maxDateOfBirth())
 	}
 
 	if usb.filter.maxAge > 0 {
-		sb.WriteString(" AND age <= ?")
-		args = append(args, usb.filter.maxAge)
+		sb.WriteString(" AND date_of_birth > ?")
+		args = append(args, usb.filter.minDateOfBirth())
 	}
 
 	return sb.String(), args
 }
 
+// GetOrderByCriteria returns order criteria, users without the sort attribute are last in both sort orders.
+func (usb UserSearchBuilder) GetOrderByCriteria() string {
+	return usb.orderBy(usb.NextPage)
+}
+
+// GetWhereCriteria returns "where" criteria used to know the place where to start querying next or previous page.
+// When users are sorted by attribute, startValue is JSON encoded value of the attribute, null when it is missing.
+// Users without the attribute are compared only by primary key, they follow all users with the attribute.
+func (usb UserSearchBuilder) GetWhereCriteria(startValue interface{}) (string, []interface{}) {
+	if usb.sortAttribute == "" || usb.StartID == 0 {
+		return usb.PagingSearchBuilder.GetWhereCriteria(startValue)
+	}
+
+	missing := usb.SortColumn + " IS NULL"
+	present := usb.SortColumn + " IS NOT NULL"
+	if startValue == "null" {
+		byID := fmt.Sprintf("%s %s ?", usb.PrimaryKey, usb.getWhereOperator())
+		if usb.NextPage {
+			return fmt.Sprintf("(%s AND %s)", missing, byID), []interface{}{usb.StartID}
+		}
+		return fmt.Sprintf("(%s OR (%s AND %s))", present, missing, byID), []interface{}{usb.StartID}
+	}
+
+	where, args := usb.PagingSearchBuilder.GetWhereCriteria(startValue)
+	if usb.NextPage {
+		return fmt.Sprintf("((%s AND %s) OR %s)", present, where, missing), args
+	}
+	return fmt.Sprintf("(%s AND %s)", present, where), args
+}
+
+// orderBy returns order of users when next or previous page is queried.
+func (usb UserSearchBuilder) orderBy(nextPage bool) string {
+	paging := usb.PagingSearchBuilder
+	paging.NextPage = nextPage
+	if usb.sortAttribute == "" {
+		return paging.GetOrderByCriteria()
+	}
+
+	missingLast := fmt.Sprintf("CASE WHEN %s IS NULL THEN 1 ELSE 0 END %s", usb.SortColumn, Asc.GetOrder(nextPage))
+	return missingLast + "," + paging.GetOrderByCriteria()
+}
+
 // BuildSearchQuery builds final query with all criteria
 func (usb UserSearchBuilder) BuildSearchQuery(whereCriteria, filterCriteria, orderByCriteria string) string {
 
@@ -116,11 +280,16 @@ func (usb UserSearchBuilder) BuildSearchQuery(whereCriteria, filterCriteria, ord
 		name,
 		surname,
 		gender,
-		age,
+		date_of_birth,
 		address,
+		email,
+		phone,
+		email_verified_at,
+		attributes,
+		status,
 		created_at
 	FROM user_sch.user
-	WHERE %s ?
+	WHERE %s
 	AND %s
 	ORDER BY %s
 	LIMIT ?`,
@@ -135,9 +304,42 @@ func (usb UserSearchBuilder) BuildSearchQuery(whereCriteria, filterCriteria, ord
 	return fmt.Sprintf(`
 	SELECT * 
 	FROM (%s) as alias
-	ORDER BY alias.%s %s`,
+	ORDER BY %s`,
 		basicQuery,
-		usb.SortColumn,
-		usb.SortOrder.GetOrder(!usb.NextPage),
+		usb.orderBy(true),
 	)
 }
+
+// Page splits users found with limit increased by one into returned page and IDs
+// used to query previous (beforeID) and next (afterID) pages. Zero ID means there is no such page.
+func (usb UserSearchBuilder) Page(users []model.User) ([]model.User, int, int) {
+	rowsReturnedCount := len(users)
+	if rowsReturnedCount == 0 {
+		return []model.User{}, 0, 0
+	}
+
+	var beforeID int
+	var afterID int
+	var usersToReturn []model.User
+	if usb.NextPage {
+		if usb.StartID > 0 {
+			beforeID = users[0].ID
+		}
+		if rowsReturnedCount > usb.Limit {
+			afterID = users[rowsReturnedCount-2].ID
+			usersToReturn = users[:rowsReturnedCount-1]
+		} else {
+			usersToReturn = users
+		}
+	} else {
+		afterID = users[rowsReturnedCount-1].ID
+		if rowsReturnedCount > usb.Limit {
+			usersToReturn = users[1:rowsReturnedCount]
+			beforeID = users[1].ID
+		} else {
+			usersToReturn = users
+		}
+	}
+
+	return usersToReturn, beforeID, afterID
+}
@@ -0,0 +1,83 @@
+package dao
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// UserStatusHistoryRepositoryProvider provides an interface to work with database UserStatusChange entity
+type UserStatusHistoryRepositoryProvider interface {
+	// Add stores transition of user status
+	Add(change *model.UserStatusChange) error
+	// FindByUserID returns all status transitions of the user from the oldest one
+	FindByUserID(userID int) ([]model.UserStatusChange, error)
+}
+
+// UserStatusHistoryRepository represents object to work with database UserStatusChange entity
+type UserStatusHistoryRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewUserStatusHistoryRepository creates new instance of UserStatusHistoryRepository.
+func NewUserStatusHistoryRepository(db *sqlx.DB) *UserStatusHistoryRepository {
+	return newUserStatusHistoryRepository(db)
+}
+
+func newUserStatusHistoryRepository(db executor) *UserStatusHistoryRepository {
+	return &UserStatusHistoryRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// Add stores transition of user status
+func (r UserStatusHistoryRepository) Add(change *model.UserStatusChange) error {
+	err := r.dialect.InsertReturning(r.db, "user_sch.user_status_history", `
+	INSERT INTO user_sch.user_status_history(
+		user_id,
+		from_status,
+		to_status,
+		reason
+	) VALUES (
+		 ?, ?, ?, ?
+	)`,
+		[]interface{}{
+			change.UserID,
+			change.FromStatus,
+			change.ToStatus,
+			change.Reason,
+		},
+		[]string{"id", "created_at"},
+		&change.ID, &change.CreatedAt,
+	)
+
+	if err != nil {
+		return errors.Wrapf(err, "impossible to create user status history record, userID=%d", change.UserID)
+	}
+
+	return nil
+}
+
+// FindByUserID returns all status transitions of the user from the oldest one
+func (r UserStatusHistoryRepository) FindByUserID(userID int) ([]model.UserStatusChange, error) {
+	changes := []model.UserStatusChange{}
+	if err := r.db.Select(&changes, r.dialect.Query(`
+		SELECT id,
+		       user_id,
+		       from_status,
+		       to_status,
+		       reason,
+		       created_at
+		FROM user_sch.user_status_history
+		WHERE user_id = ?
+		ORDER BY id`), userID,
+	); err != nil {
+		return nil, errors.Wrapf(err, "impossible to find status history of user, userID=%d", userID)
+	}
+
+	return changes, nil
+}
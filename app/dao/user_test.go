@@ -0,0 +1,719 @@
+// +build unit
+
+package dao
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+func TestUserRepositoryRecordsChanges(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		repository := NewUserRepository(db)
+		ids := createConformanceTestUsers(t, repository)
+		assert.Equal(t, 500, ids[0])
+
+		user, err := repository.GetByID(ids[2])
+		require.Nil(t, err)
+		_, err = repository.Update(user)
+		require.Nil(t, err)
+		_, err = repository.Delete(ids[2])
+		require.Nil(t, err)
+
+		changes, err := NewUserChangeRepository(db).FindAfter(0, 100)
+		require.Nil(t, err)
+		require.Len(t, changes, len(ids)+2)
+		assert.Equal(t, "Alan", changes[0].User.Name)
+		assert.Equal(t, model.UserChangeUpdated, changes[len(ids)].Operation)
+		last := changes[len(changes)-1]
+		assert.Equal(t, int64(len(ids)+2), last.Seq)
+		assert.Equal(t, model.UserChangeDeleted, last.Operation)
+		assert.Nil(t, last.User)
+	})
+}
+
+func TestTransactorWritesUserAndOutboxEvent(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		var userID int
+		err := NewTransactor(db).RunInTransaction(func(repositories Repositories) error {
+			user := conformanceTestUsers[0]
+			var err error
+			if userID, err = repositories.Users.Create(&user); err != nil {
+				return err
+			}
+
+			event := &model.Event{
+				AggregateID: userID,
+				Type:        model.EventUserCreated,
+				Payload:     types.JSONText(`{"id":500}`),
+			}
+			if err := repositories.Outbox.Add(event); err != nil {
+				return err
+			}
+			assert.True(t, event.ID > 0)
+			return nil
+		})
+		require.Nil(t, err)
+
+		outboxRepository := NewOutboxRepository(db)
+		events, err := outboxRepository.FindPending(time.Now().Add(time.Minute), 10)
+		require.Nil(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, userID, events[0].AggregateID)
+		assert.JSONEq(t, `{"id":500}`, string(events[0].Payload))
+
+		require.Nil(t, outboxRepository.MarkPublished(events[0].ID))
+		events, err = outboxRepository.FindPending(time.Now().Add(time.Minute), 10)
+		require.Nil(t, err)
+		assert.Len(t, events, 0)
+	})
+}
+
+func TestIdempotencyKeyRepositoryCreateOnce(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		repository := NewIdempotencyKeyRepository(db)
+		key := &model.IdempotencyKey{
+			Scope:       "POST /v1/users",
+			Key:         "key",
+			Fingerprint: "fingerprint",
+			Status:      model.IdempotencyKeyProcessing,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}
+
+		created, err := repository.Create(key)
+		require.Nil(t, err)
+		assert.True(t, created)
+
+		created, err = repository.Create(key)
+		require.Nil(t, err)
+		assert.False(t, created)
+
+		stored, err := repository.Get(key.Scope, key.Key)
+		require.Nil(t, err)
+		require.NotNil(t, stored)
+		assert.Equal(t, "fingerprint", stored.Fingerprint)
+	})
+}
+
+func TestOneTimeTokenRepository(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		ids := createConformanceTestUsers(t, NewUserRepository(db))
+		repository := NewOneTimeTokenRepository(db)
+		expiresAt := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+
+		for i, purpose := range []string{model.TokenPurposeEmailVerification, model.TokenPurposePasswordReset} {
+			require.Nil(t, repository.Create(&model.OneTimeToken{
+				TokenHash: fmt.Sprintf("hash%d", i),
+				Purpose:   purpose,
+				UserID:    ids[0],
+				Subject:   "alan@example.com",
+				ExpiresAt: expiresAt,
+			}))
+		}
+
+		token, err := repository.Get(model.TokenPurposeEmailVerification, "hash0")
+		require.Nil(t, err)
+		require.NotNil(t, token)
+		assert.Equal(t, ids[0], token.UserID)
+		assert.Equal(t, "alan@example.com", token.Subject)
+		assert.True(t, expiresAt.Equal(token.ExpiresAt))
+
+		token, err = repository.Get(model.TokenPurposePasswordReset, "hash0")
+		require.Nil(t, err)
+		assert.Nil(t, token, "token can't be used for another purpose")
+
+		used, err := repository.Use("hash0")
+		require.Nil(t, err)
+		assert.True(t, used)
+		used, err = repository.Use("hash0")
+		require.Nil(t, err)
+		assert.False(t, used, "token is single use")
+
+		require.Nil(t, repository.DeleteByUserID(model.TokenPurposePasswordReset, ids[0]))
+		token, err = repository.Get(model.TokenPurposePasswordReset, "hash1")
+		require.Nil(t, err)
+		assert.Nil(t, token)
+
+		// tokens are deleted together with the user
+		require.Nil(t, repository.Create(&model.OneTimeToken{
+			TokenHash: "hash2",
+			Purpose:   model.TokenPurposePasswordReset,
+			UserID:    ids[1],
+			ExpiresAt: expiresAt,
+		}))
+		_, err = NewUserRepository(db).Delete(ids[1])
+		require.Nil(t, err)
+		token, err = repository.Get(model.TokenPurposePasswordReset, "hash2")
+		require.Nil(t, err)
+		assert.Nil(t, token)
+	})
+}
+
+func TestAddressRepository(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		userRepository := NewUserRepository(db)
+		ids := createConformanceTestUsers(t, userRepository)
+		repository := NewAddressRepository(db)
+
+		home := &model.Address{
+			UserID:  ids[0],
+			Type:    model.AddressHome,
+			Street:  "Marszałkowska 1",
+			City:    "Warszawa",
+			Country: "PL",
+			Primary: true,
+		}
+		homeID, err := repository.Create(home)
+		require.Nil(t, err)
+		assert.True(t, homeID > 0)
+		assert.False(t, home.CreatedAt.IsZero())
+
+		_, err = repository.Create(&model.Address{
+			UserID:  ids[0],
+			Type:    model.AddressBilling,
+			Street:  "Unter den Linden 1",
+			City:    "Berlin",
+			Country: "DE",
+			Primary: true,
+		})
+		assert.NotNil(t, err, "only one primary address per user")
+
+		require.Nil(t, repository.ClearPrimary(ids[0]))
+		billing := &model.Address{
+			UserID:  ids[0],
+			Type:    model.AddressBilling,
+			Street:  "Unter den Linden 1",
+			City:    "Berlin",
+			Country: "DE",
+			Primary: true,
+		}
+		_, err = repository.Create(billing)
+		require.Nil(t, err)
+
+		addresses, err := repository.FindByUserID(ids[0])
+		require.Nil(t, err)
+		require.Len(t, addresses, 2)
+		assert.Equal(t, homeID, addresses[0].ID)
+		assert.False(t, addresses[0].Primary)
+		assert.True(t, addresses[1].Primary)
+
+		home.City = "Kraków"
+		home.Primary = false
+		updated, err := repository.Update(home)
+		require.Nil(t, err)
+		assert.True(t, updated)
+		stored, err := repository.GetByID(homeID)
+		require.Nil(t, err)
+		require.NotNil(t, stored)
+		assert.Equal(t, "Kraków", stored.City)
+
+		users, _, _, err := userRepository.FindUsers(NewUserSearchBuilder(&request.FindUsers{City: "berlin"}))
+		require.Nil(t, err)
+		assert.Equal(t, []int{ids[0]}, userIDs(users))
+		users, _, _, err = userRepository.FindUsers(NewUserSearchBuilder(&request.FindUsers{Country: "pl"}))
+		require.Nil(t, err)
+		assert.Equal(t, []int{ids[0]}, userIDs(users))
+		users, _, _, err = userRepository.FindUsers(NewUserSearchBuilder(&request.FindUsers{Country: "FR"}))
+		require.Nil(t, err)
+		assert.Len(t, users, 0)
+
+		deleted, err := repository.Delete(homeID)
+		require.Nil(t, err)
+		assert.True(t, deleted)
+		stored, err = repository.GetByID(homeID)
+		require.Nil(t, err)
+		assert.Nil(t, stored)
+
+		_, err = userRepository.Delete(ids[0])
+		require.Nil(t, err)
+		addresses, err = repository.FindByUserID(ids[0])
+		require.Nil(t, err)
+		assert.Len(t, addresses, 0)
+	})
+}
+
+func TestAttributeDefinitionRepository(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		repository := NewAttributeDefinitionRepository(db)
+
+		department := &model.AttributeDefinition{
+			Name:     "department",
+			Type:     model.AttributeString,
+			Required: true,
+			Enum:     types.JSONText(`["sales","support"]`),
+		}
+		departmentID, err := repository.Create(department)
+		require.Nil(t, err)
+		assert.True(t, departmentID > 0)
+		assert.False(t, department.CreatedAt.IsZero())
+
+		_, err = repository.Create(&model.AttributeDefinition{Name: "department", Type: model.AttributeNumber})
+		assert.NotNil(t, err, "names of attributes are unique")
+
+		_, err = repository.Create(&model.AttributeDefinition{Name: "active", Type: model.AttributeBoolean})
+		require.Nil(t, err)
+
+		definitions, err := repository.FindAll()
+		require.Nil(t, err)
+		require.Len(t, definitions, 2)
+		assert.Equal(t, "active", definitions[0].Name)
+		assert.Equal(t, []string{}, definitions[0].EnumValues())
+		assert.Equal(t, []string{"sales", "support"}, definitions[1].EnumValues())
+
+		department.Required = false
+		department.Enum = nil
+		department.Pattern = "^[a-z]+$"
+		updated, err := repository.Update(department)
+		require.Nil(t, err)
+		assert.True(t, updated)
+		stored, err := repository.GetByName("department")
+		require.Nil(t, err)
+		require.NotNil(t, stored)
+		assert.False(t, stored.Required)
+		assert.Equal(t, "^[a-z]+$", stored.Pattern)
+		assert.Equal(t, []string{}, stored.EnumValues())
+
+		used, err := repository.IsUsed("department")
+		require.Nil(t, err)
+		assert.False(t, used)
+		_, err = NewUserRepository(db).Create(&model.User{
+			Name:       "Ada",
+			Surname:    "Lovelace",
+			Attributes: types.JSONText(`{"department":"sales"}`),
+		})
+		require.Nil(t, err)
+		used, err = repository.IsUsed("department")
+		require.Nil(t, err)
+		assert.True(t, used)
+		_, err = repository.IsUsed("department') OR 1=1 --")
+		assert.NotNil(t, err)
+
+		deleted, err := repository.Delete(departmentID)
+		require.Nil(t, err)
+		assert.True(t, deleted)
+		stored, err = repository.GetByID(departmentID)
+		require.Nil(t, err)
+		assert.Nil(t, stored)
+	})
+}
+
+func TestUserRepositoryFindUsersByAttributes(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		repository := NewUserRepository(db)
+		var ids []int
+		for i, attributes := range []string{
+			`{"department":"sales","headcount":12,"remote":true}`,
+			`{"department":"support","headcount":3,"remote":true}`,
+			`{"department":"sales","headcount":7,"remote":false}`,
+			`{}`,
+		} {
+			id, err := repository.Create(&model.User{
+				Name:       "Attribute",
+				Surname:    fmt.Sprintf("User%d", i),
+				Attributes: types.JSONText(attributes),
+			})
+			require.Nil(t, err)
+			ids = append(ids, id)
+		}
+
+		user, err := repository.GetByID(ids[0])
+		require.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"department": "sales", "headcount": float64(12), "remote": true},
+			user.AttributeValues())
+
+		find := func(sort string, attributes map[string]interface{}) []int {
+			sb := NewUserSearchBuilder(&request.FindUsers{Sort: sort, Limit: 2}).WithAttributes(attributes)
+			users, _, _, err := repository.FindUsers(sb)
+			require.Nil(t, err)
+			return userIDs(users)
+		}
+
+		assert.Equal(t, []int{ids[0], ids[2]}, find("", map[string]interface{}{"department": "sales"}))
+		assert.Equal(t, []int{ids[0]}, find("", map[string]interface{}{"department": "sales", "remote": true}))
+		assert.Equal(t, []int{ids[1]}, find("", map[string]interface{}{"headcount": float64(3)}))
+		assert.Len(t, find("", map[string]interface{}{"department": "marketing"}), 0)
+
+		assert.Equal(t, []int{ids[1], ids[2]}, find("attr.headcount:asc", nil))
+		assert.Equal(t, []int{ids[0], ids[2]}, find("attr.headcount:desc", map[string]interface{}{"department": "sales"}))
+
+		sb := NewUserSearchBuilder(&request.FindUsers{Sort: "attr.headcount:asc", Limit: 2, AfterID: ids[2]})
+		users, beforeID, afterID, err := repository.FindUsers(sb)
+		require.Nil(t, err)
+		assert.Equal(t, []int{ids[0], ids[3]}, userIDs(users), "users without the attribute are sorted last")
+		assert.Equal(t, ids[0], beforeID)
+		assert.Equal(t, 0, afterID)
+
+		sb = NewUserSearchBuilder(&request.FindUsers{Sort: "attr.headcount:asc", Limit: 1, BeforeID: ids[0]})
+		users, _, _, err = repository.FindUsers(sb)
+		require.Nil(t, err)
+		assert.Equal(t, []int{ids[2]}, userIDs(users))
+
+		_, _, _, err = repository.FindUsers(NewUserSearchBuilder(&request.FindUsers{Sort: "attr.Head-Count:asc"}))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGroupRepository(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		repository := NewGroupRepository(db)
+		userRepository := NewUserRepository(db)
+
+		beta := &model.Group{Name: "beta", Description: "beta testers"}
+		betaID, err := repository.Create(beta)
+		require.Nil(t, err)
+		assert.True(t, betaID > 0)
+		assert.False(t, beta.CreatedAt.IsZero())
+
+		_, err = repository.Create(&model.Group{Name: "beta"})
+		assert.NotNil(t, err, "names of groups are unique")
+
+		staffID, err := repository.Create(&model.Group{Name: "staff"})
+		require.Nil(t, err)
+
+		groups, err := repository.FindAll()
+		require.Nil(t, err)
+		require.Len(t, groups, 2)
+		assert.Equal(t, "beta", groups[0].Name)
+		assert.Equal(t, "beta testers", groups[0].Description)
+
+		groups, err = repository.FindByIDs([]int{staffID, 0})
+		require.Nil(t, err)
+		require.Len(t, groups, 1)
+		assert.Equal(t, "staff", groups[0].Name)
+
+		groups, err = repository.FindByNames([]string{"beta", "unknown"})
+		require.Nil(t, err)
+		require.Len(t, groups, 1)
+		assert.Equal(t, betaID, groups[0].ID)
+
+		beta.Name = "early_access"
+		beta.Description = ""
+		updated, err := repository.Update(beta)
+		require.Nil(t, err)
+		assert.True(t, updated)
+		stored, err := repository.GetByName("early_access")
+		require.Nil(t, err)
+		require.NotNil(t, stored)
+		assert.Equal(t, betaID, stored.ID)
+		assert.Equal(t, "", stored.Description)
+
+		var memberIDs []int
+		for i := 0; i < 3; i++ {
+			userID, err := userRepository.Create(&model.User{Name: "Group", Surname: fmt.Sprintf("Member%d", i)})
+			require.Nil(t, err)
+			memberIDs = append(memberIDs, userID)
+		}
+
+		added, err := repository.AddMembers(betaID, []int{memberIDs[0], memberIDs[1], memberIDs[2] + 1000})
+		require.Nil(t, err)
+		assert.Equal(t, 2, added, "unknown users are skipped")
+		added, err = repository.AddMembers(betaID, []int{memberIDs[1], memberIDs[2]})
+		require.Nil(t, err)
+		assert.Equal(t, 1, added, "existing members are skipped")
+
+		removed, err := repository.RemoveMembers(betaID, []int{memberIDs[0], memberIDs[0] + 1000})
+		require.Nil(t, err)
+		assert.Equal(t, 1, removed)
+
+		users, _, _, err := userRepository.FindUsers(
+			NewUserSearchBuilder(&request.FindUsers{}).WithGroups([]int{betaID}, false),
+		)
+		require.Nil(t, err)
+		assert.Equal(t, []int{memberIDs[1], memberIDs[2]}, userIDs(users))
+
+		deleted, err := repository.Delete(betaID)
+		require.Nil(t, err)
+		assert.True(t, deleted)
+		stored, err = repository.GetByID(betaID)
+		require.Nil(t, err)
+		assert.Nil(t, stored)
+		deleted, err = repository.Delete(betaID)
+		require.Nil(t, err)
+		assert.False(t, deleted)
+	})
+}
+
+func TestUserRepositoryFindUsersByGroups(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		repository := NewUserRepository(db)
+		groupRepository := NewGroupRepository(db)
+		var ids []int
+		for i := 0; i < 4; i++ {
+			id, err := repository.Create(&model.User{Name: "Group", Surname: fmt.Sprintf("User%d", i)})
+			require.Nil(t, err)
+			ids = append(ids, id)
+		}
+
+		betaID, err := groupRepository.Create(&model.Group{Name: "beta"})
+		require.Nil(t, err)
+		staffID, err := groupRepository.Create(&model.Group{Name: "staff"})
+		require.Nil(t, err)
+		_, err = groupRepository.AddMembers(betaID, []int{ids[0], ids[1], ids[2]})
+		require.Nil(t, err)
+		_, err = groupRepository.AddMembers(staffID, []int{ids[1], ids[2], ids[3]})
+		require.Nil(t, err)
+
+		find := func(findRequest *request.FindUsers, groupIDs []int, all bool) []int {
+			users, _, _, err := repository.FindUsers(NewUserSearchBuilder(findRequest).WithGroups(groupIDs, all))
+			require.Nil(t, err)
+			return userIDs(users)
+		}
+
+		assert.Equal(t, ids, find(&request.FindUsers{}, []int{betaID, staffID}, false))
+		assert.Equal(t, []int{ids[1], ids[2]}, find(&request.FindUsers{}, []int{betaID, staffID}, true))
+		assert.Equal(t, []int{ids[2]}, find(&request.FindUsers{Sort: "surname:desc", Limit: 1},
+			[]int{betaID, staffID}, true))
+		assert.Equal(t, []int{ids[3]}, find(&request.FindUsers{AfterID: ids[2]}, []int{staffID}, true))
+		assert.Len(t, find(&request.FindUsers{Surname: "User0"}, []int{staffID}, false), 0)
+	})
+}
+
+func TestUserStatusHistoryRepository(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		repository := NewUserStatusHistoryRepository(db)
+		userRepository := NewUserRepository(db)
+		userID, err := userRepository.Create(&model.User{Name: "Status", Surname: "History"})
+		require.Nil(t, err)
+
+		suspended := &model.UserStatusChange{UserID: userID, FromStatus: model.UserStatusActive,
+			ToStatus: model.UserStatusSuspended, Reason: "chargeback"}
+		require.Nil(t, repository.Add(suspended))
+		assert.True(t, suspended.ID > 0)
+		assert.False(t, suspended.CreatedAt.IsZero())
+		require.Nil(t, repository.Add(&model.UserStatusChange{UserID: userID, FromStatus: model.UserStatusSuspended,
+			ToStatus: model.UserStatusActive, Reason: "resolved"}))
+
+		_, err = userRepository.Delete(userID)
+		require.Nil(t, err)
+
+		changes, err := repository.FindByUserID(userID)
+		require.Nil(t, err)
+		require.Len(t, changes, 2, "history is kept after the user is deleted")
+		assert.Equal(t, suspended.ID, changes[0].ID)
+		assert.Equal(t, "chargeback", changes[0].Reason)
+		assert.Equal(t, model.UserStatusSuspended, changes[1].FromStatus)
+		assert.Equal(t, model.UserStatusActive, changes[1].ToStatus)
+
+		changes, err = repository.FindByUserID(0)
+		require.Nil(t, err)
+		assert.Len(t, changes, 0)
+	})
+}
+
+func TestCredentialRepository(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		repository := NewCredentialRepository(db)
+		userRepository := NewUserRepository(db)
+		userID, err := userRepository.Create(&model.User{Name: "Credential", Surname: "Owner"})
+		require.Nil(t, err)
+
+		credential, err := repository.GetByUserID(userID)
+		require.Nil(t, err)
+		assert.Nil(t, credential, "users don't have credential by default")
+
+		require.Nil(t, repository.Save(userID, "first"))
+		attempts, err := repository.AddFailedAttempt(userID)
+		require.Nil(t, err)
+		assert.Equal(t, 1, attempts)
+		attempts, err = repository.AddFailedAttempt(userID)
+		require.Nil(t, err)
+		assert.Equal(t, 2, attempts)
+
+		credential, err = repository.GetByUserID(userID)
+		require.Nil(t, err)
+		require.NotNil(t, credential)
+		assert.Equal(t, "first", credential.PasswordHash)
+		assert.Equal(t, 2, credential.FailedAttempts)
+		assert.False(t, credential.UpdatedAt.IsZero())
+
+		require.Nil(t, repository.Save(userID, "second"))
+		credential, err = repository.GetByUserID(userID)
+		require.Nil(t, err)
+		assert.Equal(t, "second", credential.PasswordHash)
+		assert.Equal(t, 0, credential.FailedAttempts, "failed attempts are reset when password is changed")
+
+		rehashed, err := repository.Rehash(userID, "first", "third")
+		require.Nil(t, err)
+		assert.False(t, rehashed, "password changed in the meantime is kept")
+		rehashed, err = repository.Rehash(userID, "second", "third")
+		require.Nil(t, err)
+		assert.True(t, rehashed)
+
+		_, err = repository.AddFailedAttempt(userID)
+		require.Nil(t, err)
+		require.Nil(t, repository.ResetFailedAttempts(userID))
+		credential, err = repository.GetByUserID(userID)
+		require.Nil(t, err)
+		assert.Equal(t, "third", credential.PasswordHash)
+		assert.Equal(t, 0, credential.FailedAttempts)
+
+		attempts, err = repository.AddFailedAttempt(0)
+		require.Nil(t, err)
+		assert.Equal(t, 0, attempts)
+
+		_, err = userRepository.Delete(userID)
+		require.Nil(t, err)
+		credential, err = repository.GetByUserID(userID)
+		require.Nil(t, err)
+		assert.Nil(t, credential, "credential is deleted with the user")
+	})
+}
+
+func TestMFARepository(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		repository := NewMFARepository(db)
+		userID, err := NewUserRepository(db).Create(&model.User{Name: "MFA", Surname: "Owner"})
+		require.Nil(t, err)
+
+		mfa, err := repository.GetByUserID(userID)
+		require.Nil(t, err)
+		assert.Nil(t, mfa)
+
+		for _, secret := range []string{"FIRST", "SECOND"} {
+			enrolled, err := repository.Enroll(userID, secret)
+			require.Nil(t, err)
+			assert.True(t, enrolled, "pending enrolment is replaced")
+		}
+
+		mfa, err = repository.GetByUserID(userID)
+		require.Nil(t, err)
+		require.NotNil(t, mfa)
+		assert.Equal(t, "SECOND", mfa.Secret)
+		assert.False(t, mfa.Enabled())
+
+		enabledAt := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+		enabled, err := repository.Enable(userID, 100, enabledAt)
+		require.Nil(t, err)
+		assert.True(t, enabled)
+		enabled, err = repository.Enable(userID, 101, enabledAt)
+		require.Nil(t, err)
+		assert.False(t, enabled)
+
+		enrolled, err := repository.Enroll(userID, "THIRD")
+		require.Nil(t, err)
+		assert.False(t, enrolled, "enabled MFA is kept")
+
+		mfa, err = repository.GetByUserID(userID)
+		require.Nil(t, err)
+		assert.Equal(t, "SECOND", mfa.Secret)
+		require.True(t, mfa.Enabled())
+		assert.True(t, enabledAt.Equal(*mfa.EnabledAt))
+		assert.Equal(t, int64(100), mfa.LastUsedStep)
+
+		for step, expected := range map[int64]bool{99: false, 100: false, 101: true} {
+			used, err := repository.UseStep(userID, step)
+			require.Nil(t, err)
+			assert.Equal(t, expected, used, "step %d", step)
+		}
+
+		require.Nil(t, repository.ReplaceRecoveryCodes(userID, []string{"a", "b"}))
+		require.Nil(t, repository.ReplaceRecoveryCodes(userID, []string{"c", "d"}))
+		used, err := repository.UseRecoveryCode(userID, "a")
+		require.Nil(t, err)
+		assert.False(t, used, "replaced codes can't be used")
+		used, err = repository.UseRecoveryCode(userID, "c")
+		require.Nil(t, err)
+		assert.True(t, used)
+		used, err = repository.UseRecoveryCode(userID, "c")
+		require.Nil(t, err)
+		assert.False(t, used, "recovery code is single use")
+
+		require.Nil(t, repository.Delete(userID))
+		mfa, err = repository.GetByUserID(userID)
+		require.Nil(t, err)
+		assert.Nil(t, mfa)
+		used, err = repository.UseRecoveryCode(userID, "d")
+		require.Nil(t, err)
+		assert.False(t, used)
+	})
+}
+
+func TestSessionRepository(t *testing.T) {
+	helpers.ForEachDatabase(t, func(t *testing.T, db *sqlx.DB) {
+		repository := NewSessionRepository(db)
+		now := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+
+		first := &model.Session{
+			UserID:           700,
+			RefreshTokenHash: "first-1",
+			UserAgent:        "agent/1.0",
+			IP:               "192.0.2.10",
+			LastSeenAt:       now.Add(-2 * time.Hour),
+			ExpiresAt:        now.Add(time.Hour),
+		}
+		require.Nil(t, repository.Create(first))
+		assert.NotZero(t, first.ID)
+		second := &model.Session{UserID: 700, RefreshTokenHash: "second-1", LastSeenAt: now.Add(-time.Hour),
+			ExpiresAt: now.Add(time.Hour)}
+		require.Nil(t, repository.Create(second))
+		expired := &model.Session{UserID: 700, RefreshTokenHash: "expired-1", LastSeenAt: now.Add(-time.Hour),
+			ExpiresAt: now.Add(-time.Minute)}
+		require.Nil(t, repository.Create(expired))
+
+		sessions, err := repository.FindActiveByUserID(700, now)
+		require.Nil(t, err)
+		require.Len(t, sessions, 2)
+		assert.Equal(t, second.ID, sessions[0].ID, "the most recently used session is the first one")
+		assert.Equal(t, first.ID, sessions[1].ID)
+		assert.Equal(t, "agent/1.0", sessions[1].UserAgent)
+
+		rotated := *first
+		rotated.RefreshTokenHash = "first-2"
+		rotated.IP = "198.51.100.7"
+		rotated.LastSeenAt = now
+		rotated.ExpiresAt = now.Add(2 * time.Hour)
+		ok, err := repository.Rotate(&rotated, "first-1")
+		require.Nil(t, err)
+		assert.True(t, ok)
+		ok, err = repository.Rotate(&rotated, "first-1")
+		require.Nil(t, err)
+		assert.False(t, ok, "refresh token can be rotated once")
+
+		for _, tokenHash := range []string{"first-1", "first-2"} {
+			session, err := repository.GetByRefreshToken(tokenHash)
+			require.Nil(t, err)
+			require.NotNil(t, session, tokenHash)
+			assert.Equal(t, first.ID, session.ID)
+			assert.Equal(t, "first-2", session.RefreshTokenHash)
+			assert.Equal(t, "198.51.100.7", session.IP)
+		}
+
+		session, err := repository.GetByRefreshToken("unknown")
+		require.Nil(t, err)
+		assert.Nil(t, session)
+
+		revoked, err := repository.Revoke(701, first.ID, now)
+		require.Nil(t, err)
+		assert.False(t, revoked, "session of another user")
+		revoked, err = repository.Revoke(700, first.ID, now)
+		require.Nil(t, err)
+		assert.True(t, revoked)
+		revoked, err = repository.Revoke(700, first.ID, now)
+		require.Nil(t, err)
+		assert.False(t, revoked)
+
+		ok, err = repository.Rotate(&model.Session{ID: first.ID, RefreshTokenHash: "first-3"}, "first-2")
+		require.Nil(t, err)
+		assert.False(t, ok, "revoked session can't be rotated")
+
+		require.Nil(t, repository.RevokeAll(700, now))
+		sessions, err = repository.FindActiveByUserID(700, now)
+		require.Nil(t, err)
+		assert.Len(t, sessions, 0)
+
+		session, err = repository.GetByRefreshToken("second-1")
+		require.Nil(t, err)
+		require.NotNil(t, session.RevokedAt)
+		assert.True(t, now.Equal(*session.RevokedAt))
+	})
+}
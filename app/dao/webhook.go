@@ -0,0 +1,174 @@
+package dao
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// WebhookRepositoryProvider provides an interface to work with database Webhook entity
+type WebhookRepositoryProvider interface {
+	// GetByID returns Webhook object by ID
+	GetByID(webhookID int) (*model.Webhook, error)
+	// FindAll returns all webhooks ordered by ID
+	FindAll() ([]model.Webhook, error)
+	// FindActive returns all active webhooks ordered by ID
+	FindActive() ([]model.Webhook, error)
+	// Create creates new Webhook record
+	Create(webhook *model.Webhook) (int, error)
+	// Update updates webhook record
+	Update(webhook *model.Webhook) (bool, error)
+	// Delete deletes webhook record together with its deliveries
+	Delete(webhookID int) (bool, error)
+}
+
+// WebhookRepository represents object to work with database Webhook entity
+type WebhookRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewWebhookRepository creates new instance of WebhookRepository.
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// GetByID returns Webhook object by ID
+func (r WebhookRepository) GetByID(webhookID int) (*model.Webhook, error) {
+	var webhook model.Webhook
+	if err := r.db.Get(&webhook, r.dialect.Query(`
+		SELECT id,
+		       url,
+		       event_types,
+		       secret,
+		       active,
+		       created_at
+		FROM user_sch.webhook
+		WHERE id = ?`), webhookID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "impossible to get webhook, webhookID=%d", webhookID)
+	}
+
+	return &webhook, nil
+}
+
+// FindAll returns all webhooks ordered by ID
+func (r WebhookRepository) FindAll() ([]model.Webhook, error) {
+	webhooks := []model.Webhook{}
+	if err := r.db.Select(&webhooks, r.dialect.Query(`
+		SELECT id,
+		       url,
+		       event_types,
+		       secret,
+		       active,
+		       created_at
+		FROM user_sch.webhook
+		ORDER BY id`),
+	); err != nil {
+		return nil, errors.Wrap(err, "impossible to get webhooks")
+	}
+
+	return webhooks, nil
+}
+
+// FindActive returns all active webhooks ordered by ID
+func (r WebhookRepository) FindActive() ([]model.Webhook, error) {
+	webhooks := []model.Webhook{}
+	if err := r.db.Select(&webhooks, r.dialect.Query(`
+		SELECT id,
+		       url,
+		       event_types,
+		       secret,
+		       active,
+		       created_at
+		FROM user_sch.webhook
+		WHERE active = true
+		ORDER BY id`),
+	); err != nil {
+		return nil, errors.Wrap(err, "impossible to get active webhooks")
+	}
+
+	return webhooks, nil
+}
+
+// Create creates new Webhook record
+func (r WebhookRepository) Create(webhook *model.Webhook) (int, error) {
+	err := r.dialect.InsertReturning(r.db, "user_sch.webhook", `
+	INSERT INTO user_sch.webhook(
+		url,
+		event_types,
+		secret,
+		active
+	) VALUES (
+		 ?, ?, ?, ?
+	)`,
+		[]interface{}{webhook.URL, webhook.EventTypes, webhook.Secret, webhook.Active},
+		[]string{"id", "created_at"},
+		&webhook.ID, &webhook.CreatedAt,
+	)
+
+	if err != nil {
+		return 0, errors.Wrap(err, "impossible to create webhook record")
+	}
+
+	return webhook.ID, nil
+}
+
+// Update updates webhook record
+func (r WebhookRepository) Update(webhook *model.Webhook) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.webhook
+	SET  url = ?,
+		 event_types = ?,
+		 secret = ?,
+		 active = ?
+	WHERE id = ?`),
+		webhook.URL,
+		webhook.EventTypes,
+		webhook.Secret,
+		webhook.Active,
+		webhook.ID,
+	)
+
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to update webhook record")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if webhook was updated")
+	}
+
+	return count == 1, nil
+}
+
+// Delete deletes webhook record together with its deliveries
+func (r WebhookRepository) Delete(webhookID int) (bool, error) {
+	res, err := r.db.Exec(r.dialect.Query(`
+	DELETE FROM user_sch.webhook
+	WHERE id = ?`),
+		webhookID,
+	)
+
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to delete webhook record")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "impossible to check if webhook was deleted")
+	}
+
+	return count == 1, nil
+}
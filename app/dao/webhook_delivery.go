@@ -0,0 +1,175 @@
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	dbhelper "github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// WebhookDeliveryRepositoryProvider provides an interface to work with database WebhookDelivery entity
+type WebhookDeliveryRepositoryProvider interface {
+	// CreateAll creates delivery records with a single statement, either all or none of them are created
+	CreateAll(deliveries []model.WebhookDelivery) error
+	// FindPending returns pending deliveries which are due to be sent at given time
+	FindPending(now time.Time, limit int) ([]model.WebhookDelivery, error)
+	// MarkDelivered marks delivery as delivered
+	MarkDelivered(deliveryID int64, responseStatus int) error
+	// MarkFailed stores failed delivery attempt.
+	// Status stays pending to retry the delivery at nextAttemptAt or it is changed to dead.
+	MarkFailed(deliveryID int64, status string, nextAttemptAt time.Time, responseStatus int, reason string) error
+	// FindByWebhookID returns deliveries of the webhook from the newest one.
+	// Only deliveries with ID lower than beforeID are returned if beforeID is greater than 0.
+	FindByWebhookID(webhookID int, beforeID int64, limit int) ([]model.WebhookDelivery, error)
+}
+
+// WebhookDeliveryRepository represents object to work with database WebhookDelivery entity
+type WebhookDeliveryRepository struct {
+	db      executor
+	dialect dbhelper.Dialect
+}
+
+// NewWebhookDeliveryRepository creates new instance of WebhookDeliveryRepository.
+func NewWebhookDeliveryRepository(db *sqlx.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		db:      db,
+		dialect: dbhelper.NewDialect(db.DriverName()),
+	}
+}
+
+// CreateAll creates delivery records with a single statement, either all or none of them are created
+func (r WebhookDeliveryRepository) CreateAll(deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(deliveries))
+	args := make([]interface{}, 0, len(deliveries)*4)
+	for _, d := range deliveries {
+		values = append(values, "(?, ?, ?, ?)")
+		args = append(args, d.WebhookID, d.EventID, d.EventType, d.Payload)
+	}
+
+	// nolint
+	if _, err := r.db.Exec(r.dialect.Query(`
+	INSERT INTO user_sch.webhook_delivery(
+		webhook_id,
+		event_id,
+		event_type,
+		payload
+	) VALUES `+strings.Join(values, ", ")),
+		args...,
+	); err != nil {
+		return errors.Wrap(err, "impossible to create webhook delivery records")
+	}
+
+	return nil
+}
+
+// FindPending returns pending deliveries which are due to be sent at given time
+func (r WebhookDeliveryRepository) FindPending(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	if err := r.db.Select(&deliveries, r.dialect.Query(`
+		SELECT id,
+		       webhook_id,
+		       event_id,
+		       event_type,
+		       payload,
+		       status,
+		       attempts,
+		       response_status,
+		       last_error,
+		       next_attempt_at,
+		       delivered_at,
+		       created_at
+		FROM user_sch.webhook_delivery
+		WHERE status = ?
+		AND next_attempt_at <= ?
+		ORDER BY id
+		LIMIT ?`), model.WebhookDeliveryPending, now, limit,
+	); err != nil {
+		return nil, errors.Wrap(err, "impossible to get pending webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// MarkDelivered marks delivery as delivered
+func (r WebhookDeliveryRepository) MarkDelivered(deliveryID int64, responseStatus int) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.webhook_delivery
+	SET status = ?,
+	    attempts = attempts + 1,
+	    response_status = ?,
+	    last_error = '',
+	    delivered_at = CURRENT_TIMESTAMP
+	WHERE id = ?`),
+		model.WebhookDeliveryDelivered,
+		responseStatus,
+		deliveryID,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to mark webhook delivery as delivered, deliveryID=%d", deliveryID)
+	}
+
+	return nil
+}
+
+// MarkFailed stores failed delivery attempt.
+// Status stays pending to retry the delivery at nextAttemptAt or it is changed to dead.
+func (r WebhookDeliveryRepository) MarkFailed(
+	deliveryID int64, status string, nextAttemptAt time.Time, responseStatus int, reason string,
+) error {
+	if _, err := r.db.Exec(r.dialect.Query(`
+	UPDATE user_sch.webhook_delivery
+	SET status = ?,
+	    attempts = attempts + 1,
+	    next_attempt_at = ?,
+	    response_status = ?,
+	    last_error = ?
+	WHERE id = ?`),
+		status,
+		nextAttemptAt,
+		responseStatus,
+		reason,
+		deliveryID,
+	); err != nil {
+		return errors.Wrapf(err, "impossible to mark webhook delivery as failed, deliveryID=%d", deliveryID)
+	}
+
+	return nil
+}
+
+// FindByWebhookID returns deliveries of the webhook from the newest one.
+// Only deliveries with ID lower than beforeID are returned if beforeID is greater than 0.
+func (r WebhookDeliveryRepository) FindByWebhookID(webhookID int, beforeID int64, limit int) (
+	[]model.WebhookDelivery, error) {
+
+	deliveries := []model.WebhookDelivery{}
+	if err := r.db.Select(&deliveries, r.dialect.Query(`
+		SELECT id,
+		       webhook_id,
+		       event_id,
+		       event_type,
+		       payload,
+		       status,
+		       attempts,
+		       response_status,
+		       last_error,
+		       next_attempt_at,
+		       delivered_at,
+		       created_at
+		FROM user_sch.webhook_delivery
+		WHERE webhook_id = ?
+		AND (? = 0 OR id < ?)
+		ORDER BY id DESC
+		LIMIT ?`), webhookID, beforeID, beforeID, limit,
+	); err != nil {
+		return nil, errors.Wrapf(err, "impossible to get webhook deliveries, webhookID=%d", webhookID)
+	}
+
+	return deliveries, nil
+}
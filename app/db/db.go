@@ -1,50 +1,298 @@
 package db
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 )
 
-// Supported database types
+// Supported database types.
+// Values are also names of database drivers.
 const (
 	PostgresType = "postgres"
+	MySQLType    = "mysql"
+	SQLiteType   = "sqlite3"
 )
 
-// GetConnection returns connection based on provided database type.
-// Now only Postgress is supported
-func GetConnection(dbType, user, password, name, host string) (*sqlx.DB, error) {
+// SSL modes of database connection, they have the same meaning as Postgres sslmode parameter.
+const (
+	SSLModeDisable    = "disable"
+	SSLModeRequire    = "require"
+	SSLModeVerifyCA   = "verify-ca"
+	SSLModeVerifyFull = "verify-full"
+)
+
+// mysqlTLSConfigName is a name under which TLS config of MySQL connection is registered in the driver.
+const mysqlTLSConfigName = "user-service"
+
+// Settings represents database connection settings.
+type Settings struct {
+	Type string
+	// DSN is a data source name in the format of the driver, it replaces connection, SSL and session settings.
+	DSN string
+
+	// Connection settings. For SQLite Name is a path of database file, other fields are ignored.
+	User     string
+	Password string
+	Name     string
+	Host     string
+	Port     int
+
+	// SSL settings, SSLMode is one of SSLModeDisable, SSLModeRequire, SSLModeVerifyCA or SSLModeVerifyFull.
+	SSLMode     string
+	SSLRootCert string
+	SSLCert     string
+	SSLKey      string
+
+	// Session settings, StatementTimeout equal to 0 disables the timeout.
+	ApplicationName  string
+	StatementTimeout time.Duration
+
+	// Pool settings, they have the same meaning as in sql.DB. SQLite always uses one open connection.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// ConnectRetries is a number of retries when database is not reachable on startup,
+	// delay between retries grows exponentially from ConnectRetryBackoff up to ConnectMaxBackoff.
+	ConnectRetries      int
+	ConnectRetryBackoff time.Duration
+	ConnectMaxBackoff   time.Duration
+}
+
+// GetConnection returns connection based on provided settings.
+// Database is pinged until it is reachable or retries are exhausted.
+func GetConnection(settings Settings) (*sqlx.DB, error) {
+
+	connection, err := open(settings, settings.DSN)
+	if err != nil {
+		return nil, errors.Wrapf(err, "impossible to get %s database connection", settings.Type)
+	}
+
+	if err := ping(connection, settings); err != nil {
+		// nolint
+		connection.Close()
+		return nil, errors.Wrap(err, "impossible to reach database")
+	}
+
+	return connection, nil
+}
+
+// open opens connection pool without reaching the database.
+// Data source name is built from settings when dsn is empty.
+func open(settings Settings, dsn string) (*sqlx.DB, error) {
 
 	var err error
-	var connection *sqlx.DB
-	switch dbType {
-	case PostgresType:
-		connection, err = getPostgresConnection(user, password, name, host)
-	default:
-		return nil, errors.New("unsupported database type")
+	if dsn == "" {
+		switch settings.Type {
+		case PostgresType:
+			dsn = postgresDSN(settings)
+		case MySQLType:
+			dsn, err = mysqlDSN(settings)
+		case SQLiteType:
+			dsn = sqliteDSN(settings)
+		default:
+			return nil, errors.New("unsupported database type")
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	connection, err := sqlx.Open(settings.Type, dsn)
 	if err != nil {
-		return nil, errors.Wrapf(err, "impossible to get %s database connection", dbType)
+		return nil, err
 	}
 
-	if err := connection.Ping(); err != nil {
-		return nil, errors.Wrap(err, "impossible to reach database")
+	connection.SetMaxOpenConns(settings.MaxOpenConns)
+	connection.SetMaxIdleConns(settings.MaxIdleConns)
+	connection.SetConnMaxLifetime(settings.ConnMaxLifetime)
+	if settings.Type == SQLiteType {
+		// SQLite allows only one writer, concurrent writers would fail with database is locked error
+		connection.SetMaxOpenConns(1)
 	}
 
 	return connection, nil
 }
 
-func getPostgresConnection(user, password, name, host string) (*sqlx.DB, error) {
+func ping(connection *sqlx.DB, settings Settings) error {
+	delay := settings.ConnectRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := connection.Ping()
+		if err == nil || attempt >= settings.ConnectRetries {
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"err":     err,
+			"attempt": attempt + 1,
+			"delay":   delay,
+		}).Warn("database is not reachable, retrying")
+
+		time.Sleep(delay)
+		if delay *= 2; delay > settings.ConnectMaxBackoff {
+			delay = settings.ConnectMaxBackoff
+		}
+	}
+}
+
+func postgresDSN(settings Settings) string {
+	query := url.Values{}
+	query.Set("sslmode", SSLModeDisable)
+	if settings.SSLMode != "" {
+		query.Set("sslmode", settings.SSLMode)
+	}
+	if settings.SSLRootCert != "" {
+		query.Set("sslrootcert", settings.SSLRootCert)
+	}
+	if settings.SSLCert != "" {
+		query.Set("sslcert", settings.SSLCert)
+	}
+	if settings.SSLKey != "" {
+		query.Set("sslkey", settings.SSLKey)
+	}
+	if settings.ApplicationName != "" {
+		query.Set("application_name", settings.ApplicationName)
+	}
+	if settings.StatementTimeout > 0 {
+		// unknown parameters are sent by the driver as run-time parameters of the session
+		query.Set("statement_timeout", strconv.FormatInt(settings.StatementTimeout.Milliseconds(), 10))
+	}
+
+	dsn := url.URL{
+		Scheme:   PostgresType,
+		User:     url.UserPassword(settings.User, settings.Password),
+		Host:     hostPort(settings.Host, settings.Port),
+		Path:     "/" + settings.Name,
+		RawQuery: query.Encode(),
+	}
+
+	return dsn.String()
+}
+
+func mysqlDSN(settings Settings) (string, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = settings.User
+	cfg.Passwd = settings.Password
+	cfg.Net = "tcp"
+	cfg.Addr = hostPort(settings.Host, settings.Port)
+	cfg.DBName = settings.Name
+	cfg.ParseTime = true
+	cfg.MultiStatements = true
+	// number of matched rows is returned instead of changed ones,
+	// so update with unchanged values is not reported as missing row
+	cfg.ClientFoundRows = true
+	if settings.StatementTimeout > 0 {
+		// unknown parameters are set by the driver as system variables of the session
+		cfg.Params = map[string]string{
+			"max_execution_time": strconv.FormatInt(settings.StatementTimeout.Milliseconds(), 10),
+		}
+	}
+
+	tlsConfig, err := newTLSConfig(settings)
+	if err != nil {
+		return "", err
+	}
+	if tlsConfig != nil {
+		if err := mysql.RegisterTLSConfig(mysqlTLSConfigName, tlsConfig); err != nil {
+			return "", errors.Wrap(err, "impossible to register TLS config")
+		}
+		cfg.TLSConfig = mysqlTLSConfigName
+	}
+
+	return cfg.FormatDSN(), nil
+}
+
+func sqliteDSN(settings Settings) string {
+	return fmt.Sprintf(`file:%s?_foreign_keys=1&_busy_timeout=5000&_txlock=immediate`, settings.Name)
+}
+
+// newTLSConfig returns TLS config for drivers which do not support SSL settings in data source name.
+// Nil is returned when SSL is disabled. Certificate of the server is not verified in SSLModeRequire,
+// SSLModeVerifyCA verifies only certificate chain and SSLModeVerifyFull verifies also host name.
+func newTLSConfig(settings Settings) (*tls.Config, error) {
+	if settings.SSLMode == "" || settings.SSLMode == SSLModeDisable {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{ServerName: settings.Host}
+	if settings.SSLRootCert != "" {
+		pem, err := ioutil.ReadFile(settings.SSLRootCert)
+		if err != nil {
+			return nil, errors.Wrap(err, "impossible to read SSL root certificate")
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("SSL root certificate does not contain PEM encoded certificates")
+		}
+	}
+
+	if settings.SSLCert != "" || settings.SSLKey != "" {
+		certificate, err := tls.LoadX509KeyPair(settings.SSLCert, settings.SSLKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "impossible to load SSL client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{certificate}
+	}
+
+	switch settings.SSLMode {
+	case SSLModeRequire:
+		cfg.InsecureSkipVerify = true
+	case SSLModeVerifyCA:
+		// host name is not verified, so the chain is verified manually
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyCertificateChain(rawCerts, cfg.RootCAs)
+		}
+	case SSLModeVerifyFull:
+	default:
+		return nil, errors.Errorf("unsupported SSL mode %s", settings.SSLMode)
+	}
+
+	return cfg, nil
+}
+
+func verifyCertificateChain(rawCerts [][]byte, roots *x509.CertPool) error {
+	certificates := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		certificate, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return errors.New("server did not provide certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	_, err := certificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+func hostPort(host string, port int) string {
+	if port == 0 {
+		return host
+	}
 
-	return sqlx.Connect(PostgresType, fmt.Sprintf(
-		`postgres://%s:%s@%s/%s?sslmode=disable`,
-		user,
-		url.QueryEscape(password),
-		host,
-		name,
-	))
+	return net.JoinHostPort(host, strconv.Itoa(port))
 }
@@ -0,0 +1,243 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// applicationSchema is a schema used in queries. Databases without schema support keep tables
+// in the database provided in connection settings.
+const applicationSchema = "user_sch."
+
+// Dialect hides SQL differences between supported databases.
+// Queries are written with ? placeholders and tables qualified with user_sch schema.
+type Dialect interface {
+	// Query adapts query to the database: resolves table names and rebinds placeholders.
+	Query(query string) string
+	// ILike returns case-insensitive LIKE condition of the column with one placeholder.
+	ILike(column string) string
+	// Quote quotes identifier which is a reserved word in some databases.
+	Quote(identifier string) string
+	// InsertIgnore returns INSERT query which skips rows violating unique constraints.
+	InsertIgnore(query string) string
+	// InsertReturning executes INSERT query into the table and scans provided columns of created row into dest.
+	// First column must be an auto generated ID of the table.
+	InsertReturning(db sqlx.Ext, table, query string, args []interface{}, columns []string, dest ...interface{}) error
+	// JSONValue returns expression with value of the key of JSON object stored in the column.
+	// Values are compared and sorted with respect to their JSON types, the key is not escaped.
+	JSONValue(column, key string) string
+	// JSONParam returns placeholder of JSON encoded value which can be compared with JSONValue.
+	JSONParam() string
+	// JSONContains returns condition with one placeholder which matches rows with JSON object in the column
+	// containing every key of JSON object passed as the parameter with equal value.
+	JSONContains(column string) string
+	// ReplicationLag returns how far the replica is behind its primary.
+	// Zero is returned for database which is not a replica.
+	ReplicationLag(ctx context.Context, db *sqlx.DB) (time.Duration, error)
+}
+
+// NewDialect returns Dialect of the database driver.
+// Postgres dialect is returned for unknown drivers.
+func NewDialect(driverName string) Dialect {
+	switch driverName {
+	case MySQLType:
+		return mysqlDialect{}
+	case SQLiteType:
+		return sqliteDialect{}
+	default:
+		return postgresDialect{}
+	}
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Query(query string) string {
+	return sqlx.Rebind(sqlx.DOLLAR, query)
+}
+
+func (postgresDialect) ILike(column string) string {
+	return column + " ILIKE ?"
+}
+
+func (postgresDialect) Quote(identifier string) string {
+	return `"` + identifier + `"`
+}
+
+func (postgresDialect) InsertIgnore(query string) string {
+	return query + " ON CONFLICT DO NOTHING"
+}
+
+func (d postgresDialect) InsertReturning(
+	db sqlx.Ext, _, query string, args []interface{}, columns []string, dest ...interface{},
+) error {
+	return insertReturning(db, d, query, args, columns, dest...)
+}
+
+func (postgresDialect) JSONValue(column, key string) string {
+	return column + "->'" + key + "'"
+}
+
+func (postgresDialect) JSONParam() string {
+	return "CAST(? AS jsonb)"
+}
+
+// JSONContains uses @> operator which is served by GIN index of the column.
+func (postgresDialect) JSONContains(column string) string {
+	return column + " @> CAST(? AS jsonb)"
+}
+
+// ReplicationLag returns time since the last replayed transaction when replica has not replayed all received WAL.
+func (postgresDialect) ReplicationLag(ctx context.Context, db *sqlx.DB) (time.Duration, error) {
+	var seconds float64
+	if err := db.GetContext(ctx, &seconds, `
+		SELECT CASE
+			WHEN NOT pg_is_in_recovery() THEN 0
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		END`,
+	); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// sqliteDialect keeps all tables in the main database, e.g. user_sch.user is stored as user.
+type sqliteDialect struct{}
+
+func (sqliteDialect) Query(query string) string {
+	return strings.Replace(query, applicationSchema, "", -1)
+}
+
+func (sqliteDialect) ILike(column string) string {
+	return "LOWER(" + column + ") LIKE LOWER(?)"
+}
+
+func (sqliteDialect) Quote(identifier string) string {
+	return `"` + identifier + `"`
+}
+
+func (sqliteDialect) InsertIgnore(query string) string {
+	return query + " ON CONFLICT DO NOTHING"
+}
+
+func (d sqliteDialect) InsertReturning(
+	db sqlx.Ext, _, query string, args []interface{}, columns []string, dest ...interface{},
+) error {
+	return insertReturning(db, d, query, args, columns, dest...)
+}
+
+func (sqliteDialect) JSONValue(column, key string) string {
+	return "json_extract(" + column + ", '$." + key + "')"
+}
+
+func (sqliteDialect) JSONParam() string {
+	return "json_extract(?, '$')"
+}
+
+// JSONContains compares every key of the parameter, because SQLite has no JSON containment function.
+func (sqliteDialect) JSONContains(column string) string {
+	return "NOT EXISTS (SELECT 1 FROM json_each(?) AS expected WHERE json_extract(" + column +
+		", expected.fullkey) IS NOT expected.value)"
+}
+
+// ReplicationLag returns zero, because SQLite does not support replication.
+func (sqliteDialect) ReplicationLag(context.Context, *sqlx.DB) (time.Duration, error) {
+	return 0, nil
+}
+
+// mysqlDialect keeps all tables in the connected database, e.g. user_sch.user is stored as user.
+type mysqlDialect struct{}
+
+func (mysqlDialect) Query(query string) string {
+	return strings.Replace(query, applicationSchema, "", -1)
+}
+
+func (mysqlDialect) ILike(column string) string {
+	return "LOWER(" + column + ") LIKE LOWER(?)"
+}
+
+func (mysqlDialect) Quote(identifier string) string {
+	return "`" + identifier + "`"
+}
+
+func (mysqlDialect) InsertIgnore(query string) string {
+	return strings.Replace(query, "INSERT INTO", "INSERT IGNORE INTO", 1)
+}
+
+// InsertReturning reads created row by last insert ID, because MySQL does not support RETURNING clause.
+func (d mysqlDialect) InsertReturning(
+	db sqlx.Ext, table, query string, args []interface{}, columns []string, dest ...interface{},
+) error {
+	res, err := db.Exec(d.Query(query), args...)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	// nolint
+	return db.QueryRowx(d.Query(fmt.Sprintf(
+		"SELECT %s FROM %s WHERE %s = ?",
+		strings.Join(columns, ", "),
+		table,
+		columns[0],
+	)), id).Scan(dest...)
+}
+
+func (mysqlDialect) JSONValue(column, key string) string {
+	return "JSON_EXTRACT(" + column + ", '$." + key + "')"
+}
+
+func (mysqlDialect) JSONParam() string {
+	return "CAST(? AS JSON)"
+}
+
+func (mysqlDialect) JSONContains(column string) string {
+	return "JSON_CONTAINS(" + column + ", ?)"
+}
+
+// ReplicationLag returns Seconds_Behind_Master reported by the replica.
+func (mysqlDialect) ReplicationLag(ctx context.Context, db *sqlx.DB) (time.Duration, error) {
+	rows, err := db.QueryxContext(ctx, "SHOW SLAVE STATUS")
+	if err != nil {
+		return 0, err
+	}
+	// nolint
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, rows.Err()
+	}
+
+	status := map[string]interface{}{}
+	if err := rows.MapScan(status); err != nil {
+		return 0, err
+	}
+
+	value, ok := status["Seconds_Behind_Master"].([]byte)
+	if !ok {
+		return 0, errors.New("replication is not running")
+	}
+
+	seconds, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+func insertReturning(db sqlx.Ext, d Dialect, query string, args []interface{}, columns []string, dest ...interface{},
+) error {
+	return db.QueryRowx(d.Query(query+" RETURNING "+strings.Join(columns, ", ")), args...).Scan(dest...)
+}
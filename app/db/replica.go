@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// replicaCheckTimeout limits duration of a single replica health check.
+const replicaCheckTimeout = 5 * time.Second
+
+// ConnectionRouter routes reads which tolerate replication lag to read replicas.
+// Replicas are selected round-robin from the healthy ones, primary is used when there is no healthy replica.
+type ConnectionRouter struct {
+	primary  *sqlx.DB
+	replicas []*replica
+	maxLag   time.Duration
+	next     uint32
+}
+
+type replica struct {
+	db      *sqlx.DB
+	healthy int32
+}
+
+// NewConnectionRouter creates new instance of ConnectionRouter.
+// Replicas are unhealthy until the first health check, maxLag equal to 0 disables replication lag check.
+func NewConnectionRouter(primary *sqlx.DB, replicas []*sqlx.DB, maxLag time.Duration) *ConnectionRouter {
+	router := &ConnectionRouter{
+		primary: primary,
+		maxLag:  maxLag,
+	}
+	for _, db := range replicas {
+		router.replicas = append(router.replicas, &replica{db: db})
+	}
+
+	return router
+}
+
+// OpenReplicas opens connections to replicas without reaching them,
+// so the service starts when replica is down. Such replica is skipped by health checks.
+// Pool settings of primary are applied to replicas.
+func OpenReplicas(settings Settings, dsns []string) ([]*sqlx.DB, error) {
+	replicas := make([]*sqlx.DB, 0, len(dsns))
+	for _, dsn := range dsns {
+		db, err := open(settings, dsn)
+		if err != nil {
+			return nil, errors.Wrapf(err, "impossible to open %s replica connection", settings.Type)
+		}
+		replicas = append(replicas, db)
+	}
+
+	return replicas, nil
+}
+
+// Primary returns connection to primary database used for writes and reads which require the latest data.
+func (r *ConnectionRouter) Primary() *sqlx.DB {
+	return r.primary
+}
+
+// Replica returns connection to the next healthy replica or to primary when there is no healthy replica.
+func (r *ConnectionRouter) Replica() *sqlx.DB {
+	count := len(r.replicas)
+	for i := 0; i < count; i++ {
+		next := r.replicas[int(atomic.AddUint32(&r.next, 1)-1)%count]
+		if atomic.LoadInt32(&next.healthy) == 1 {
+			return next.db
+		}
+	}
+
+	return r.primary
+}
+
+// CheckReplicas checks if replicas are reachable and their replication lag does not exceed the limit.
+func (r *ConnectionRouter) CheckReplicas(ctx context.Context) {
+	for i, replica := range r.replicas {
+		err := r.checkReplica(ctx, replica.db)
+		var healthy int32
+		if err == nil {
+			healthy = 1
+		}
+
+		if previous := atomic.SwapInt32(&replica.healthy, healthy); previous != healthy {
+			logger := log.WithFields(log.Fields{
+				"replica": i,
+			})
+			if err != nil {
+				logger.WithField("err", err).Warn("replica is excluded from reads")
+			} else {
+				logger.Info("replica is included in reads")
+			}
+		}
+	}
+}
+
+// Run checks replicas periodically until the context is cancelled.
+func (r *ConnectionRouter) Run(ctx context.Context, interval time.Duration) {
+	if len(r.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.CheckReplicas(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *ConnectionRouter) checkReplica(ctx context.Context, db *sqlx.DB) error {
+	ctx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		return errors.Wrap(err, "impossible to reach replica")
+	}
+
+	if r.maxLag == 0 {
+		return nil
+	}
+
+	lag, err := NewDialect(db.DriverName()).ReplicationLag(ctx, db)
+	if err != nil {
+		return errors.Wrap(err, "impossible to check replication lag")
+	}
+
+	if lag > r.maxLag {
+		return errors.Errorf("replication lag %s exceeds %s", lag, r.maxLag)
+	}
+
+	return nil
+}
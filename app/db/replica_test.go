@@ -0,0 +1,76 @@
+// +build unit
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openSQLite(t *testing.T) *sqlx.DB {
+	connection, err := sqlx.Connect(SQLiteType, ":memory:")
+	require.Nil(t, err)
+	return connection
+}
+
+func TestConnectionRouterUsesPrimaryBeforeHealthCheck(t *testing.T) {
+	primary := openSQLite(t)
+	defer primary.Close()
+	replica := openSQLite(t)
+	defer replica.Close()
+
+	router := NewConnectionRouter(primary, []*sqlx.DB{replica}, 0)
+
+	assert.Equal(t, primary, router.Primary())
+	assert.Equal(t, primary, router.Replica())
+}
+
+func TestConnectionRouterRoundRobinsHealthyReplicas(t *testing.T) {
+	primary := openSQLite(t)
+	defer primary.Close()
+	first := openSQLite(t)
+	defer first.Close()
+	second := openSQLite(t)
+	defer second.Close()
+
+	router := NewConnectionRouter(primary, []*sqlx.DB{first, second}, 0)
+	router.CheckReplicas(context.Background())
+
+	assert.Equal(t, first, router.Replica())
+	assert.Equal(t, second, router.Replica())
+	assert.Equal(t, first, router.Replica())
+}
+
+func TestConnectionRouterSkipsUnhealthyReplicas(t *testing.T) {
+	primary := openSQLite(t)
+	defer primary.Close()
+	healthy := openSQLite(t)
+	defer healthy.Close()
+	unreachable := openSQLite(t)
+	require.Nil(t, unreachable.Close())
+
+	router := NewConnectionRouter(primary, []*sqlx.DB{unreachable, healthy}, 10)
+	router.CheckReplicas(context.Background())
+
+	assert.Equal(t, healthy, router.Replica())
+	assert.Equal(t, healthy, router.Replica())
+
+	require.Nil(t, healthy.Close())
+	router.CheckReplicas(context.Background())
+
+	assert.Equal(t, primary, router.Replica())
+}
+
+func TestConnectionRouterWithoutReplicas(t *testing.T) {
+	primary := openSQLite(t)
+	defer primary.Close()
+
+	router := NewConnectionRouter(primary, nil, 0)
+	router.CheckReplicas(context.Background())
+
+	assert.Equal(t, primary, router.Replica())
+}
@@ -1,9 +1,5 @@
 package httperrors
 
-import (
-	"fmt"
-)
-
 // Common Application errors.
 // Format AAXXXBB
 // AA - endpoint
@@ -25,20 +21,58 @@ var (
 	)
 
 	EntityNotFoundError = func(entity string) *HTTPError {
-		return NewNotFound(1040400, fmt.Sprintf(
-			"`%s` entity not found", entity),
-		)
+		return NewNotFound(1040400, "`%s` entity not found").withArgs(entity)
+	}
+
+	RequestSchemaViolation = NewBadRequest(
+		1040004, "the request does not match the API schema",
+	)
+
+	RequestValidationFailed = NewBadRequest(
+		1040005, "the request contains invalid fields",
+	)
+
+	IdempotencyKeyIncorrect = NewBadRequest(
+		1040003, "`Idempotency-Key` header has to be at most 255 characters long",
+	)
+
+	IdempotencyKeyInProgress = NewConflict(
+		1040900, "request with the same `Idempotency-Key` is still in progress",
+	)
+
+	IdempotencyKeyReused = NewUnprocessableEntity(
+		1042200, "`Idempotency-Key` was already used with different request",
+	)
+)
+
+// Problems reported as details of RequestSchemaViolation.
+var (
+	SchemaUnknownField = func(field string) *HTTPError {
+		return NewBadRequest(1040010, "unknown field `%s`").withArgs(field)
+	}
+
+	SchemaTypeMismatch = func(field, expected string) *HTTPError {
+		return NewBadRequest(1040011, "`%s` has to be of type %s").withArgs(field, expected)
+	}
+
+	SchemaMaxLengthExceeded = func(field string, maxLength int) *HTTPError {
+		return NewBadRequest(1040012, "`%s` has to be at most %d characters long").withArgs(field, maxLength)
+	}
+
+	SchemaParameterRepeated = func(field string) *HTTPError {
+		return NewBadRequest(1040013, "`%s` has to be passed once").withArgs(field)
 	}
 )
 
 // Application errors for `POST /v1/users` and `PUT /v1/users/:user_id
+// Field errors are reported as details of RequestValidationFailed.
 var (
 	UserNameEmpty = NewBadRequest(
 		2040001, "`name` can't be empty",
 	)
 
 	UserSurnameEmpty = NewBadRequest(
-		2040002, "`surnname` can't be empty",
+		2040002, "`surname` can't be empty",
 	)
 
 	UserAgeIncorrect = NewBadRequest(
@@ -50,9 +84,7 @@ var (
 	)
 
 	UserGenderNotSupported = func(gender string) *HTTPError {
-		return NewBadRequest(2040005, fmt.Sprintf(
-			"`gender` %s is not supported", gender),
-		)
+		return NewBadRequest(2040005, "`gender` %s is not supported").withArgs(gender)
 	}
 
 	UserAddressEmpty = NewBadRequest(
@@ -62,9 +94,30 @@ var (
 	UserAlreadyRegistered = NewBadRequest(
 		2040007, "user with provided name and surname already exists",
 	)
+
+	UserEmailIncorrect = NewBadRequest(
+		2040008, "`email` has to be a valid email address",
+	)
+
+	UserPhoneIncorrect = NewBadRequest(
+		2040009, "`phone` has to be an international phone number, e.g. +48123456789",
+	)
+
+	UserEmailAlreadyRegistered = NewBadRequest(
+		2040010, "user with provided email already exists",
+	)
+
+	UserDateOfBirthIncorrect = NewBadRequest(
+		2040011, "`date_of_birth` has to be a date in format YYYY-MM-DD",
+	)
+
+	UserDateOfBirthOutOfRange = NewBadRequest(
+		2040012, "`date_of_birth` can't be in the future or more than 120 years ago",
+	)
 )
 
 // Application errors for GET /v1/users endpoint
+// reported as details of RequestValidationFailed.
 var (
 	PaginationAfterIDNegative = NewBadRequest(
 		2140001, "`afterID` can not be negative",
@@ -86,3 +139,296 @@ var (
 		2140005, "`sort` parameter does not match sort pattern",
 	)
 )
+
+// Application errors for `POST /v1/webhooks` and `PUT /v1/webhooks/:webhook_id`
+// reported as details of RequestValidationFailed.
+var (
+	WebhookURLIncorrect = NewBadRequest(
+		2240001, "`url` has to be absolute http or https URL",
+	)
+
+	WebhookEventTypeNotSupported = func(eventType string) *HTTPError {
+		return NewBadRequest(2240002, "`event_types` %s is not supported").withArgs(eventType)
+	}
+
+	WebhookSecretTooShort = NewBadRequest(
+		2240003, "`secret` has to be at least 16 characters long",
+	)
+)
+
+// Application errors for `GET /v1/webhooks/:webhook_id/deliveries`
+// reported as details of RequestValidationFailed.
+var (
+	WebhookDeliveriesLimitNegative = NewBadRequest(
+		2340001, "`limit` can not be negative",
+	)
+
+	WebhookDeliveriesBeforeIDNegative = NewBadRequest(
+		2340002, "`before_id` can not be negative",
+	)
+)
+
+// Application errors for `GET /v1/users/changes`
+// Limit and wait errors are reported as details of RequestValidationFailed.
+var (
+	ChangeFeedTokenIncorrect = NewBadRequest(
+		2440001, "`since` is not a valid change feed token",
+	)
+
+	ChangeFeedLimitNegative = NewBadRequest(
+		2440002, "`limit` can not be negative",
+	)
+
+	ChangeFeedWaitIncorrect = NewBadRequest(
+		2440003, "`wait` has to be between `0s` and `60s`",
+	)
+)
+
+// Application errors for `POST /v1/users/:user_id/email/verify` and `POST /v1/users/:user_id/email/verify/confirm`
+// Token errors are reported as details of RequestValidationFailed.
+var (
+	EmailVerificationTokenEmpty = NewBadRequest(
+		2540001, "`token` can't be empty",
+	)
+
+	EmailVerificationTokenIncorrect = NewBadRequest(
+		2540002, "`token` is invalid or expired",
+	)
+
+	EmailVerificationEmailMissing = NewConflict(
+		2540900, "user has no email to verify",
+	)
+
+	EmailVerificationAlreadyVerified = NewConflict(
+		2540901, "email of the user is already verified",
+	)
+)
+
+// Application errors for `POST /v1/users/:user_id/addresses` and `PUT /v1/users/:user_id/addresses/:address_id`
+// reported as details of RequestValidationFailed.
+var (
+	AddressTypeNotSupported = func(addressType string) *HTTPError {
+		return NewBadRequest(2640001, "`type` %s is not supported").withArgs(addressType)
+	}
+
+	AddressStreetEmpty = NewBadRequest(
+		2640002, "`street` can't be empty",
+	)
+
+	AddressCityEmpty = NewBadRequest(
+		2640003, "`city` can't be empty",
+	)
+
+	AddressCountryIncorrect = NewBadRequest(
+		2640004, "`country` has to be ISO 3166-1 alpha-2 country code, e.g. PL",
+	)
+)
+
+// Application errors for `/v1/admin/attribute-definitions` endpoints and custom attributes of users.
+// Errors of attribute values are reported as details of RequestValidationFailed with `/attributes/<name>` pointer.
+var (
+	AttributeNameIncorrect = NewBadRequest(
+		2740001, "`name` has to start with a lowercase letter and contain only lowercase letters, digits and underscores, at most 64 characters",
+	)
+
+	AttributeTypeNotSupported = func(attributeType string) *HTTPError {
+		return NewBadRequest(2740002, "`type` %s is not supported").withArgs(attributeType)
+	}
+
+	AttributeEnumNotAllowed = NewBadRequest(
+		2740003, "`enum` can be used only with string attributes",
+	)
+
+	AttributePatternNotAllowed = NewBadRequest(
+		2740004, "`pattern` can be used only with string attributes",
+	)
+
+	AttributePatternIncorrect = NewBadRequest(
+		2740005, "`pattern` has to be a valid regular expression",
+	)
+
+	AttributeUnknown = func(name string) *HTTPError {
+		return NewBadRequest(2740006, "attribute `%s` is not defined").withArgs(name)
+	}
+
+	AttributeRequired = func(name string) *HTTPError {
+		return NewBadRequest(2740007, "attribute `%s` is required").withArgs(name)
+	}
+
+	AttributeTypeMismatch = func(name, attributeType string) *HTTPError {
+		return NewBadRequest(2740008, "attribute `%s` has to be of type %s").withArgs(name, attributeType)
+	}
+
+	AttributeValueNotAllowed = func(name string) *HTTPError {
+		return NewBadRequest(2740009, "attribute `%s` has to be one of the allowed values").withArgs(name)
+	}
+
+	AttributePatternMismatch = func(name string) *HTTPError {
+		return NewBadRequest(2740010, "attribute `%s` does not match the pattern").withArgs(name)
+	}
+
+	AttributeDefinitionAlreadyExists = NewConflict(
+		2740900, "attribute with provided name already exists",
+	)
+
+	AttributeDefinitionInUse = NewConflict(
+		2740901, "attribute is set for some users, it can't be deleted",
+	)
+)
+
+// Application errors for `/v1/groups` endpoints and `group` and `tag` filters of `GET /v1/users`.
+// Errors of fields and query parameters are reported as details of RequestValidationFailed.
+var (
+	GroupNameEmpty = NewBadRequest(
+		2840001, "`name` can't be empty",
+	)
+
+	GroupMatchNotSupported = func(match string) *HTTPError {
+		return NewBadRequest(2840002, "`group_match` %s is not supported, use any or all").withArgs(match)
+	}
+
+	GroupUnknown = func(groupID int) *HTTPError {
+		return NewBadRequest(2840003, "group %d does not exist").withArgs(groupID)
+	}
+
+	GroupTagUnknown = func(tag string) *HTTPError {
+		return NewBadRequest(2840004, "tag `%s` does not exist").withArgs(tag)
+	}
+
+	GroupMembersEmpty = NewBadRequest(
+		2840005, "`user_ids` can't be empty",
+	)
+
+	GroupMembersTooMany = func(max int) *HTTPError {
+		return NewBadRequest(2840006, "`user_ids` can contain at most %d users").withArgs(max)
+	}
+
+	GroupMemberIDIncorrect = NewBadRequest(
+		2840007, "user ID has to be positive",
+	)
+
+	GroupAlreadyExists = NewConflict(
+		2840900, "group with provided name already exists",
+	)
+)
+
+// Application errors for status transition endpoints and `status` filter of `GET /v1/users`.
+// Errors of fields and query parameters are reported as details of RequestValidationFailed.
+var (
+	UserStatusReasonEmpty = NewBadRequest(
+		2940001, "`reason` can't be empty",
+	)
+
+	UserStatusReasonTooLong = func(max int) *HTTPError {
+		return NewBadRequest(2940002, "`reason` can contain at most %d characters").withArgs(max)
+	}
+
+	UserStatusNotSupported = func(status string) *HTTPError {
+		return NewBadRequest(2940003, "`status` %s is not supported").withArgs(status)
+	}
+
+	UserStatusTransitionNotAllowed = func(from, to string) *HTTPError {
+		return NewConflict(2940900, "status of the user can't be changed from %s to %s").withArgs(from, to)
+	}
+)
+
+// Application errors for `POST /v1/auth/login`, `POST /v1/auth/password-reset`
+// and `POST /v1/users/:user_id/password`.
+// Field errors are reported as details of RequestValidationFailed.
+var (
+	AuthEmailEmpty = NewBadRequest(
+		3040001, "`email` can't be empty",
+	)
+
+	AuthPasswordEmpty = NewBadRequest(
+		3040002, "`password` can't be empty",
+	)
+
+	PasswordTooShort = func(min int) *HTTPError {
+		return NewBadRequest(3040003, "`new_password` has to be at least %d characters long").withArgs(min)
+	}
+
+	PasswordTooLong = func(max int) *HTTPError {
+		return NewBadRequest(3040004, "`new_password` can contain at most %d characters").withArgs(max)
+	}
+
+	PasswordTooWeak = NewBadRequest(
+		3040005, "`new_password` has to contain a letter and a digit",
+	)
+
+	PasswordNotChanged = NewBadRequest(
+		3040006, "`new_password` has to be different from the current password",
+	)
+
+	PasswordResetTokenEmpty = NewBadRequest(
+		3040007, "`token` can't be empty",
+	)
+
+	PasswordResetTokenIncorrect = NewBadRequest(
+		3040008, "password reset token is incorrect or expired",
+	)
+
+	AuthInvalidCredentials = NewUnauthorized(
+		3040100, "email or password is incorrect",
+	)
+
+	AuthUserNotActive = func(status string) *HTTPError {
+		return NewForbidden(3040300, "user with status %s can't log in").withArgs(status)
+	}
+
+	PasswordCurrentIncorrect = NewForbidden(
+		3040301, "`current_password` is incorrect",
+	)
+
+	PasswordNotSet = NewConflict(
+		3040900, "user has no password, it can be set with password reset",
+	)
+)
+
+// Application errors for `POST /v1/users/:user_id/mfa/*` and `POST /v1/auth/login/mfa`.
+// Field errors are reported as details of RequestValidationFailed.
+var (
+	MFACodeEmpty = NewBadRequest(
+		3140001, "`code` can't be empty",
+	)
+
+	MFATokenEmpty = NewBadRequest(
+		3140002, "`mfa_token` can't be empty",
+	)
+
+	MFAChallengeIncorrect = NewUnauthorized(
+		3140100, "MFA challenge is incorrect or expired",
+	)
+
+	MFALoginCodeIncorrect = NewUnauthorized(
+		3140101, "MFA code is incorrect",
+	)
+
+	MFACodeIncorrect = NewForbidden(
+		3140300, "`code` is incorrect",
+	)
+
+	MFAAlreadyEnabled = NewConflict(
+		3140900, "MFA of the user is already enabled",
+	)
+
+	MFANotEnrolled = NewConflict(
+		3140901, "MFA enrolment of the user has to be started first",
+	)
+
+	MFANotEnabled = NewConflict(
+		3140902, "MFA of the user is not enabled",
+	)
+)
+
+// Application errors for `POST /v1/auth/refresh` and `/v1/users/:user_id/sessions` endpoints.
+// Field errors are reported as details of RequestValidationFailed.
+var (
+	RefreshTokenEmpty = NewBadRequest(
+		3240001, "`refresh_token` can't be empty",
+	)
+
+	RefreshTokenIncorrect = NewUnauthorized(
+		3240100, "refresh token is incorrect, expired or revoked",
+	)
+)
@@ -1,6 +1,7 @@
 package httperrors
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -11,10 +12,23 @@ import (
 
 // HTTPError represents generic http error response
 type HTTPError struct {
-	HTTPCode      int    `json:"-"`
-	Code          int    `json:"code"`
-	Message       string `json:"message"`
-	OriginalError error  `json:"-"`
+	HTTPCode      int      `json:"-"`
+	Code          int      `json:"code"`
+	Message       string   `json:"message"`
+	Details       []Detail `json:"details,omitempty"`
+	OriginalError error    `json:"-"`
+
+	// args are arguments of parameterized message, they are used to translate it.
+	args []interface{}
+}
+
+// Detail describes single problem of the request, Field is JSON pointer of the invalid value.
+type Detail struct {
+	Field   string `json:"field"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+
+	args []interface{}
 }
 
 // WithCause adds original error object to http error.
@@ -23,9 +37,61 @@ func (e *HTTPError) WithCause(err error) *HTTPError {
 	return e
 }
 
-// Error displays code and message as JSON.
+// Details collects problems of the request, e.g. every invalid field found by a validator.
+type Details []Detail
+
+// Add adds problem of the field described by http error, nil error is ignored.
+func (d *Details) Add(field string, err *HTTPError) {
+	if err == nil {
+		return
+	}
+	*d = append(*d, Detail{Field: field, Code: err.Code, Message: err.Message, args: err.args})
+}
+
+// Err returns RequestValidationFailed with collected problems or nil when there are none.
+func (d Details) Err() error {
+	if len(d) == 0 {
+		return nil
+	}
+
+	return RequestValidationFailed.WithDetails(d...)
+}
+
+// WithDetails returns copy of http error with the details.
+func (e *HTTPError) WithDetails(details ...Detail) *HTTPError {
+	withDetails := *e
+	withDetails.Details = details
+	return &withDetails
+}
+
+// Localize returns copy of http error with message and details translated to the language.
+// Messages without translation are left in English.
+func (e *HTTPError) Localize(language string) *HTTPError {
+	localized := *e
+	localized.Message = translate(language, e.Code, e.Message, e.args)
+	if len(e.Details) > 0 {
+		localized.Details = make([]Detail, len(e.Details))
+		for i, detail := range e.Details {
+			detail.Message = translate(language, detail.Code, detail.Message, detail.args)
+			localized.Details[i] = detail
+		}
+	}
+
+	return &localized
+}
+
+// withArgs formats message of http error with the args, they are kept to translate the message.
+func (e *HTTPError) withArgs(args ...interface{}) *HTTPError {
+	e.args = args
+	e.Message = fmt.Sprintf(e.Message, args...)
+	e.OriginalError = errors.New(e.Message)
+	return e
+}
+
+// Error displays code, message and details as JSON.
 func (e *HTTPError) Error() string {
-	return fmt.Sprintf(`{"code":%d,"message":"%s"}`, e.Code, e.Message)
+	body, _ := json.Marshal(e)
+	return string(body)
 }
 
 // New creates new HTTP error with the given HTTP status code.
@@ -43,17 +109,40 @@ func NewBadRequest(code int, message string) *HTTPError {
 	return New(http.StatusBadRequest, code, message)
 }
 
+// NewUnauthorized creates new HTTP error with status 401.
+func NewUnauthorized(code int, message string) *HTTPError {
+	return New(http.StatusUnauthorized, code, message)
+}
+
+// NewForbidden creates new HTTP error with status 403.
+func NewForbidden(code int, message string) *HTTPError {
+	return New(http.StatusForbidden, code, message)
+}
+
 // NewNotFound creates new HTTP error with status 404.
 func NewNotFound(code int, message string) *HTTPError {
 	return New(http.StatusNotFound, code, message)
 }
 
+// NewConflict creates new HTTP error with status 409.
+func NewConflict(code int, message string) *HTTPError {
+	return New(http.StatusConflict, code, message)
+}
+
+// NewUnprocessableEntity creates new HTTP error with status 422.
+func NewUnprocessableEntity(code int, message string) *HTTPError {
+	return New(http.StatusUnprocessableEntity, code, message)
+}
+
 // NewHTTPInternalServerError creates new HTTP error with status 500.
 func NewHTTPInternalServerError(code int, message string) *HTTPError {
 	return New(http.StatusInternalServerError, code, message)
 }
 
-// Emit sets the http error in Gin context and logs the stacktrace
+// Emit sets the http error in Gin context and logs the stacktrace.
+// Message is translated to the language preferred by `Accept-Language` header.
+// Error is rendered as RFC 7807 problem details when the request accepts `application/problem+json`
+// or problem details are enabled for all requests.
 func Emit(ctx *gin.Context, err error) {
 	httpError, ok := err.(*HTTPError)
 	if !ok {
@@ -65,5 +154,14 @@ func Emit(ctx *gin.Context, err error) {
 		log.Errorf("%+v", httpError.OriginalError)
 	}
 
-	ctx.JSON(httpError.HTTPCode, httpError)
+	language := DefaultLanguage
+	if ctx.Request != nil {
+		language = PreferredLanguage(ctx.GetHeader(AcceptLanguageHeader))
+	}
+	ctx.Header(ContentLanguageHeader, language)
+	if wantsProblem(ctx) {
+		emitProblem(ctx, httpError.Localize(language))
+		return
+	}
+	ctx.JSON(httpError.HTTPCode, httpError.Localize(language))
 }
@@ -0,0 +1,85 @@
+package httperrors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Headers used to negotiate language of error messages.
+const (
+	AcceptLanguageHeader  = "Accept-Language"
+	ContentLanguageHeader = "Content-Language"
+)
+
+// Supported languages of error messages.
+const (
+	LanguageEnglish = "en"
+	LanguageGerman  = "de"
+	LanguagePolish  = "pl"
+)
+
+// DefaultLanguage is used when the client does not accept any supported language.
+const DefaultLanguage = LanguageEnglish
+
+// Languages lists all supported languages.
+var Languages = []string{LanguageEnglish, LanguageGerman, LanguagePolish}
+
+// PreferredLanguage returns supported language with the highest quality in `Accept-Language` header,
+// e.g. `de` for `fr;q=0.9, de-AT;q=0.8, en;q=0.5`. Regional variants are matched by their primary language.
+func PreferredLanguage(acceptLanguage string) string {
+	preferred := DefaultLanguage
+	bestQuality := 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		params := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(params[0]))
+		if i := strings.Index(tag, "-"); i >= 0 {
+			tag = tag[:i]
+		}
+		if !isSupported(tag) {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				if err != nil {
+					q = 0
+				}
+				quality = q
+			}
+		}
+
+		if quality > bestQuality {
+			preferred, bestQuality = tag, quality
+		}
+	}
+
+	return preferred
+}
+
+func isSupported(language string) bool {
+	for _, supported := range Languages {
+		if language == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// translate returns message of the code in the language, message is formatted with the args.
+// English message of the error is returned when there is no translation.
+func translate(language string, code int, message string, args []interface{}) string {
+	template, ok := translations[language][code]
+	if !ok {
+		return message
+	}
+	if len(args) == 0 {
+		return template
+	}
+
+	return fmt.Sprintf(template, args...)
+}
@@ -0,0 +1,92 @@
+package httperrors
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is a media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// DefaultProblemTypeBaseURI prefixes error code in `type` of problem details.
+const DefaultProblemTypeBaseURI = "urn:user-service:error:"
+
+// Keys of Gin context which configure rendering of problem details, see middleware.ProblemDetails.
+const (
+	ProblemAlwaysKey      = "httperrors.problem_always"
+	ProblemTypeBaseURIKey = "httperrors.problem_type_base_uri"
+)
+
+// Problem represents RFC 7807 problem details of HTTPError.
+// Code and details of the error are added as extension members.
+type Problem struct {
+	Type     string   `json:"type"`
+	Title    string   `json:"title"`
+	Status   int      `json:"status"`
+	Detail   string   `json:"detail"`
+	Instance string   `json:"instance"`
+	Code     int      `json:"code"`
+	Details  []Detail `json:"details,omitempty"`
+}
+
+// Problem returns problem details of http error which occurred for the instance, e.g. request path.
+// Type of the problem is typeBaseURI followed by the error code.
+func (e *HTTPError) Problem(typeBaseURI, instance string) *Problem {
+	return &Problem{
+		Type:     typeBaseURI + strconv.Itoa(e.Code),
+		Title:    http.StatusText(e.HTTPCode),
+		Status:   e.HTTPCode,
+		Detail:   e.Message,
+		Instance: instance,
+		Code:     e.Code,
+		Details:  e.Details,
+	}
+}
+
+// wantsProblem reports whether error of the request has to be rendered as problem details:
+// it is enabled for all requests or the request accepts problem details.
+func wantsProblem(ctx *gin.Context) bool {
+	if ctx.GetBool(ProblemAlwaysKey) {
+		return true
+	}
+	if ctx.Request == nil {
+		return false
+	}
+
+	return acceptsProblem(ctx.GetHeader("Accept"))
+}
+
+// acceptsProblem reports whether `Accept` header explicitly accepts problem details.
+func acceptsProblem(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != ProblemContentType {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+// emitProblem renders http error as problem details of the request.
+func emitProblem(ctx *gin.Context, httpError *HTTPError) {
+	typeBaseURI := ctx.GetString(ProblemTypeBaseURIKey)
+	if typeBaseURI == "" {
+		typeBaseURI = DefaultProblemTypeBaseURI
+	}
+	var instance string
+	if ctx.Request != nil {
+		instance = ctx.Request.URL.Path
+	}
+
+	ctx.Header("Content-Type", ProblemContentType)
+	ctx.JSON(httpError.HTTPCode, httpError.Problem(typeBaseURI, instance))
+}
@@ -0,0 +1,98 @@
+// +build unit
+
+package httperrors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func emitForRequest(accept string, always bool) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/v1/users/10?limit=1", nil)
+	context.Request.Header.Set("Accept", accept)
+	context.Set(ProblemAlwaysKey, always)
+
+	var details Details
+	details.Add("/date_of_birth", UserDateOfBirthIncorrect)
+	Emit(context, details.Err())
+
+	return recorder
+}
+
+func TestEmitProblemForAcceptHeader(t *testing.T) {
+	recorder := emitForRequest("application/problem+json, application/json;q=0.9", false)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, ProblemContentType, recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "urn:user-service:error:1040005",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "the request contains invalid fields",
+		"instance": "/v1/users/10",
+		"code": 1040005,
+		"details": [{"field": "/date_of_birth", "code": 2040011, "message": "`+"`date_of_birth` has to be a date in format YYYY-MM-DD"+`"}]
+	}`, recorder.Body.String())
+}
+
+func TestEmitProblemForAllRequests(t *testing.T) {
+	recorder := emitForRequest("*/*", true)
+
+	assert.Equal(t, ProblemContentType, recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), `"type":"urn:user-service:error:1040005"`)
+}
+
+func TestEmitProblemTypeBaseURI(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/v1/users/10", nil)
+	context.Set(ProblemAlwaysKey, true)
+	context.Set(ProblemTypeBaseURIKey, "https://errors.example.com/")
+
+	Emit(context, EntityNotFoundError("user"))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.JSONEq(t, `{
+		"type": "https://errors.example.com/1040400",
+		"title": "Not Found",
+		"status": 404,
+		"detail": "`+"`user`"+` entity not found",
+		"instance": "/v1/users/10",
+		"code": 1040400
+	}`, recorder.Body.String())
+}
+
+func TestEmitLegacyFormatByDefault(t *testing.T) {
+	recorder := emitForRequest("application/json", false)
+
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+	var details Details
+	details.Add("/date_of_birth", UserDateOfBirthIncorrect)
+	assert.JSONEq(t, details.Err().Error(), recorder.Body.String())
+}
+
+func TestAcceptsProblem(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"*/*", false},
+		{"application/problem+json", true},
+		{"application/json, Application/Problem+JSON;q=0.5", true},
+		{"application/problem+json;q=0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert.Equal(t, tt.expected, acceptsProblem(tt.accept))
+		})
+	}
+}
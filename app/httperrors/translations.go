@@ -0,0 +1,214 @@
+package httperrors
+
+// translations is a message catalog keyed by language and HTTPError.Code.
+// English messages are defined with errors, parameterized messages use the same verbs as English ones.
+var translations = map[string]map[int]string{
+	LanguageGerman: {
+		1050000: "interner Serverfehler",
+		1040000: "der Anfragetext konnte nicht verarbeitet werden",
+		1040001: "die Abfrageparameter konnten nicht verarbeitet werden",
+		1040002: "die Pfadparameter konnten nicht verarbeitet werden",
+		1040400: "Entität `%s` wurde nicht gefunden",
+		1040003: "der Header `Idempotency-Key` darf höchstens 255 Zeichen lang sein",
+		1040004: "die Anfrage entspricht nicht dem API-Schema",
+		1040005: "die Anfrage enthält ungültige Felder",
+		1040900: "eine Anfrage mit demselben `Idempotency-Key` wird noch verarbeitet",
+		1042200: "`Idempotency-Key` wurde bereits für eine andere Anfrage verwendet",
+		1040010: "unbekanntes Feld `%s`",
+		1040011: "`%s` muss vom Typ %s sein",
+		1040012: "`%s` darf höchstens %d Zeichen lang sein",
+		1040013: "`%s` darf nur einmal übergeben werden",
+
+		2040001: "`name` darf nicht leer sein",
+		2040002: "`surname` darf nicht leer sein",
+		2040003: "`age` muss `>0` und `<120` sein",
+		2040004: "`gender` darf nicht leer sein",
+		2040005: "`gender` %s wird nicht unterstützt",
+		2040006: "`address` darf nicht leer sein",
+		2040007: "ein Benutzer mit diesem Namen und Nachnamen existiert bereits",
+		2040008: "`email` muss eine gültige E-Mail-Adresse sein",
+		2040009: "`phone` muss eine internationale Telefonnummer sein, z. B. +48123456789",
+		2040010: "ein Benutzer mit dieser E-Mail-Adresse existiert bereits",
+		2040011: "`date_of_birth` muss ein Datum im Format YYYY-MM-DD sein",
+		2040012: "`date_of_birth` darf nicht in der Zukunft oder mehr als 120 Jahre zurück liegen",
+
+		2140001: "`afterID` darf nicht negativ sein",
+		2140002: "`beforeID` darf nicht negativ sein",
+		2140003: "`afterID` und `beforeID` dürfen nicht gleichzeitig angegeben werden",
+		2140004: "`limit` darf nicht negativ sein",
+		2140005: "der Parameter `sort` entspricht nicht dem Sortiermuster",
+
+		2240001: "`url` muss eine absolute http- oder https-URL sein",
+		2240002: "`event_types` %s wird nicht unterstützt",
+		2240003: "`secret` muss mindestens 16 Zeichen lang sein",
+
+		2340001: "`limit` darf nicht negativ sein",
+		2340002: "`before_id` darf nicht negativ sein",
+
+		2440001: "`since` ist kein gültiges Token des Änderungsfeeds",
+		2440002: "`limit` darf nicht negativ sein",
+		2440003: "`wait` muss zwischen `0s` und `60s` liegen",
+
+		2540001: "`token` darf nicht leer sein",
+		2540002: "`token` ist ungültig oder abgelaufen",
+		2540900: "der Benutzer hat keine E-Mail-Adresse zum Bestätigen",
+		2540901: "die E-Mail-Adresse des Benutzers ist bereits bestätigt",
+
+		2640001: "`type` %s wird nicht unterstützt",
+		2640002: "`street` darf nicht leer sein",
+		2640003: "`city` darf nicht leer sein",
+		2640004: "`country` muss ein Ländercode nach ISO 3166-1 alpha-2 sein, z. B. PL",
+
+		2740001: "`name` muss mit einem Kleinbuchstaben beginnen und darf nur Kleinbuchstaben, Ziffern und Unterstriche enthalten, höchstens 64 Zeichen",
+		2740002: "`type` %s wird nicht unterstützt",
+		2740003: "`enum` kann nur für Attribute vom Typ string verwendet werden",
+		2740004: "`pattern` kann nur für Attribute vom Typ string verwendet werden",
+		2740005: "`pattern` muss ein gültiger regulärer Ausdruck sein",
+		2740006: "das Attribut `%s` ist nicht definiert",
+		2740007: "das Attribut `%s` ist erforderlich",
+		2740008: "das Attribut `%s` muss vom Typ %s sein",
+		2740009: "das Attribut `%s` muss einer der erlaubten Werte sein",
+		2740010: "das Attribut `%s` entspricht nicht dem Muster",
+		2740900: "ein Attribut mit diesem Namen existiert bereits",
+		2740901: "das Attribut ist bei einigen Benutzern gesetzt und kann nicht gelöscht werden",
+
+		2840001: "`name` darf nicht leer sein",
+		2840002: "`group_match` %s wird nicht unterstützt, verwenden Sie any oder all",
+		2840003: "Gruppe %d existiert nicht",
+		2840004: "Tag `%s` existiert nicht",
+		2840005: "`user_ids` darf nicht leer sein",
+		2840006: "`user_ids` darf höchstens %d Benutzer enthalten",
+		2840007: "Benutzer-ID muss positiv sein",
+		2840900: "eine Gruppe mit diesem Namen existiert bereits",
+		2940001: "`reason` darf nicht leer sein",
+		2940002: "`reason` darf höchstens %d Zeichen enthalten",
+		2940003: "`status` %s wird nicht unterstützt",
+		2940900: "der Status des Benutzers kann nicht von %s zu %s geändert werden",
+		3040001: "`email` darf nicht leer sein",
+		3040002: "`password` darf nicht leer sein",
+		3040003: "`new_password` muss mindestens %d Zeichen lang sein",
+		3040004: "`new_password` darf höchstens %d Zeichen enthalten",
+		3040005: "`new_password` muss einen Buchstaben und eine Ziffer enthalten",
+		3040006: "`new_password` muss sich vom aktuellen Passwort unterscheiden",
+		3040007: "`token` darf nicht leer sein",
+		3040008: "Token zum Zurücksetzen des Passworts ist falsch oder abgelaufen",
+		3040100: "E-Mail oder Passwort ist falsch",
+		3040300: "Benutzer mit dem Status %s kann sich nicht anmelden",
+		3040301: "`current_password` ist falsch",
+		3040900: "Benutzer hat kein Passwort, es kann mit dem Zurücksetzen des Passworts festgelegt werden",
+		3140001: "`code` darf nicht leer sein",
+		3140002: "`mfa_token` darf nicht leer sein",
+		3140100: "MFA-Anfrage ist falsch oder abgelaufen",
+		3140101: "MFA-Code ist falsch",
+		3140300: "`code` ist falsch",
+		3140900: "MFA des Benutzers ist bereits aktiviert",
+		3140901: "MFA-Einrichtung des Benutzers muss zuerst gestartet werden",
+		3140902: "MFA des Benutzers ist nicht aktiviert",
+		3240001: "`refresh_token` darf nicht leer sein",
+		3240100: "Refresh-Token ist falsch, abgelaufen oder widerrufen",
+	},
+	LanguagePolish: {
+		1050000: "wewnętrzny błąd serwera",
+		1040000: "nie można przetworzyć treści żądania",
+		1040001: "nie można przetworzyć parametrów zapytania",
+		1040002: "nie można przetworzyć parametrów ścieżki",
+		1040400: "nie znaleziono encji `%s`",
+		1040003: "nagłówek `Idempotency-Key` może mieć co najwyżej 255 znaków",
+		1040004: "żądanie nie jest zgodne ze schematem API",
+		1040005: "żądanie zawiera niepoprawne pola",
+		1040900: "żądanie z tym samym `Idempotency-Key` jest wciąż przetwarzane",
+		1042200: "`Idempotency-Key` został już użyty z innym żądaniem",
+		1040010: "nieznane pole `%s`",
+		1040011: "`%s` musi być typu %s",
+		1040012: "`%s` może mieć co najwyżej %d znaków",
+		1040013: "`%s` może zostać przekazany tylko raz",
+
+		2040001: "`name` nie może być puste",
+		2040002: "`surname` nie może być puste",
+		2040003: "`age` musi być `>0` i `<120`",
+		2040004: "`gender` nie może być puste",
+		2040005: "`gender` %s nie jest obsługiwane",
+		2040006: "`address` nie może być puste",
+		2040007: "użytkownik o podanym imieniu i nazwisku już istnieje",
+		2040008: "`email` musi być poprawnym adresem e-mail",
+		2040009: "`phone` musi być międzynarodowym numerem telefonu, np. +48123456789",
+		2040010: "użytkownik o podanym adresie e-mail już istnieje",
+		2040011: "`date_of_birth` musi być datą w formacie YYYY-MM-DD",
+		2040012: "`date_of_birth` nie może być w przyszłości ani więcej niż 120 lat temu",
+
+		2140001: "`afterID` nie może być ujemne",
+		2140002: "`beforeID` nie może być ujemne",
+		2140003: "`afterID` i `beforeID` nie mogą być podane jednocześnie",
+		2140004: "`limit` nie może być ujemny",
+		2140005: "parametr `sort` nie pasuje do wzorca sortowania",
+
+		2240001: "`url` musi być bezwzględnym adresem http lub https",
+		2240002: "`event_types` %s nie jest obsługiwany",
+		2240003: "`secret` musi mieć co najmniej 16 znaków",
+
+		2340001: "`limit` nie może być ujemny",
+		2340002: "`before_id` nie może być ujemne",
+
+		2440001: "`since` nie jest poprawnym tokenem strumienia zmian",
+		2440002: "`limit` nie może być ujemny",
+		2440003: "`wait` musi mieścić się między `0s` a `60s`",
+
+		2540001: "`token` nie może być pusty",
+		2540002: "`token` jest nieprawidłowy lub wygasł",
+		2540900: "użytkownik nie ma adresu e-mail do potwierdzenia",
+		2540901: "adres e-mail użytkownika jest już potwierdzony",
+
+		2640001: "`type` %s nie jest obsługiwany",
+		2640002: "`street` nie może być puste",
+		2640003: "`city` nie może być puste",
+		2640004: "`country` musi być kodem kraju ISO 3166-1 alpha-2, np. PL",
+
+		2740001: "`name` musi zaczynać się małą literą i zawierać tylko małe litery, cyfry i podkreślenia, maksymalnie 64 znaki",
+		2740002: "`type` %s nie jest obsługiwany",
+		2740003: "`enum` może być użyte tylko dla atrybutów typu string",
+		2740004: "`pattern` może być użyty tylko dla atrybutów typu string",
+		2740005: "`pattern` musi być poprawnym wyrażeniem regularnym",
+		2740006: "atrybut `%s` nie jest zdefiniowany",
+		2740007: "atrybut `%s` jest wymagany",
+		2740008: "atrybut `%s` musi być typu %s",
+		2740009: "atrybut `%s` musi mieć jedną z dozwolonych wartości",
+		2740010: "atrybut `%s` nie pasuje do wzorca",
+		2740900: "atrybut o podanej nazwie już istnieje",
+		2740901: "atrybut jest ustawiony dla niektórych użytkowników i nie może zostać usunięty",
+
+		2840001: "`name` nie może być puste",
+		2840002: "`group_match` %s nie jest obsługiwany, użyj any lub all",
+		2840003: "grupa %d nie istnieje",
+		2840004: "tag `%s` nie istnieje",
+		2840005: "`user_ids` nie może być puste",
+		2840006: "`user_ids` może zawierać maksymalnie %d użytkowników",
+		2840007: "ID użytkownika musi być dodatnie",
+		2840900: "grupa o podanej nazwie już istnieje",
+		2940001: "`reason` nie może być puste",
+		2940002: "`reason` może zawierać maksymalnie %d znaków",
+		2940003: "`status` %s nie jest obsługiwany",
+		2940900: "status użytkownika nie może zostać zmieniony z %s na %s",
+		3040001: "`email` nie może być puste",
+		3040002: "`password` nie może być puste",
+		3040003: "`new_password` musi mieć co najmniej %d znaków",
+		3040004: "`new_password` może zawierać maksymalnie %d znaków",
+		3040005: "`new_password` musi zawierać literę i cyfrę",
+		3040006: "`new_password` musi różnić się od obecnego hasła",
+		3040007: "`token` nie może być pusty",
+		3040008: "token resetowania hasła jest nieprawidłowy lub wygasł",
+		3040100: "email lub hasło jest nieprawidłowe",
+		3040300: "użytkownik ze statusem %s nie może się zalogować",
+		3040301: "`current_password` jest nieprawidłowe",
+		3040900: "użytkownik nie ma hasła, można je ustawić przez reset hasła",
+		3140001: "`code` nie może być pusty",
+		3140002: "`mfa_token` nie może być pusty",
+		3140100: "wezwanie MFA jest nieprawidłowe lub wygasło",
+		3140101: "kod MFA jest nieprawidłowy",
+		3140300: "`code` jest nieprawidłowy",
+		3140900: "MFA użytkownika jest już włączone",
+		3140901: "najpierw należy rozpocząć konfigurację MFA użytkownika",
+		3140902: "MFA użytkownika nie jest włączone",
+		3240001: "`refresh_token` nie może być pusty",
+		3240100: "token odświeżania jest nieprawidłowy, wygasł lub został unieważniony",
+	},
+}
@@ -0,0 +1,121 @@
+// +build unit
+
+package httperrors
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var verbRegex = regexp.MustCompile(`%[sdv]`)
+
+// definedMessages returns English messages of all errors defined in error.go by their codes.
+func definedMessages(t *testing.T) map[int]string {
+	file, err := parser.ParseFile(token.NewFileSet(), "error.go", nil, 0)
+	require.NoError(t, err)
+
+	messages := make(map[int]string)
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		if fun, ok := call.Fun.(*ast.Ident); !ok || !strings.HasPrefix(fun.Name, "New") {
+			return true
+		}
+		code, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || code.Kind != token.INT {
+			return true
+		}
+		message, ok := call.Args[1].(*ast.BasicLit)
+		require.True(t, ok, "message of %s has to be a literal", code.Value)
+
+		c, _ := strconv.Atoi(code.Value)
+		messages[c], _ = strconv.Unquote(message.Value)
+		return true
+	})
+	require.NotEmpty(t, messages)
+
+	return messages
+}
+
+func TestTranslationsCoverEveryCode(t *testing.T) {
+	messages := definedMessages(t)
+
+	for language, catalog := range translations {
+		for code, message := range messages {
+			translation, ok := catalog[code]
+			if assert.True(t, ok, "code %d has no %s translation", code, language) {
+				assert.Equal(t, verbRegex.FindAllString(message, -1), verbRegex.FindAllString(translation, -1),
+					"%s translation of code %d has to use the same verbs as English message", language, code)
+			}
+		}
+		for code := range catalog {
+			_, ok := messages[code]
+			assert.True(t, ok, "%s translation of code %d does not belong to any error", language, code)
+		}
+	}
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		expected       string
+	}{
+		{"", LanguageEnglish},
+		{"de", LanguageGerman},
+		{"pl-PL", LanguagePolish},
+		{"fr;q=0.9, de-AT;q=0.8, en;q=0.5", LanguageGerman},
+		{"en;q=0.4, PL;q=0.7", LanguagePolish},
+		{"pl;q=0, de;q=0.1", LanguageGerman},
+		{"fr, es", LanguageEnglish},
+		{"*", LanguageEnglish},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			assert.Equal(t, tt.expected, PreferredLanguage(tt.acceptLanguage))
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	var details Details
+	details.Add("/gender", UserGenderNotSupported("unknown"))
+	details.Add("/date_of_birth", UserDateOfBirthIncorrect)
+	err := details.Err().(*HTTPError)
+
+	localized := err.Localize(LanguageGerman)
+
+	assert.Equal(t, "die Anfrage enthält ungültige Felder", localized.Message)
+	assert.Equal(t, []Detail{
+		{Field: "/gender", Code: 2040005, Message: "`gender` unknown wird nicht unterstützt", args: []interface{}{"unknown"}},
+		{Field: "/date_of_birth", Code: 2040011, Message: "`date_of_birth` muss ein Datum im Format YYYY-MM-DD sein"},
+	}, localized.Details)
+	assert.Equal(t, "`gender` unknown is not supported", err.Details[0].Message, "original error has to be left intact")
+	assert.Equal(t, err, err.Localize(LanguageEnglish))
+}
+
+func TestEmitLocalizesMessage(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	context.Request.Header.Set(AcceptLanguageHeader, "de-DE,de;q=0.9")
+
+	Emit(context, EntityNotFoundError("user"))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, LanguageGerman, recorder.Header().Get(ContentLanguageHeader))
+	assert.JSONEq(t, `{"code":1040400,"message":"Entität `+"`user`"+` wurde nicht gefunden"}`, recorder.Body.String())
+}
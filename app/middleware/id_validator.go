@@ -11,7 +11,12 @@ import (
 
 // Request placeholders keys
 const (
-	UserIDParamKey = "user_id"
+	UserIDParamKey                = "user_id"
+	WebhookIDParamKey             = "webhook_id"
+	AddressIDParamKey             = "address_id"
+	AttributeDefinitionIDParamKey = "definition_id"
+	GroupIDParamKey               = "group_id"
+	SessionIDParamKey             = "session_id"
 )
 
 // ValidateUserID validates :user_id placeholder from the request.
@@ -19,6 +24,31 @@ func ValidateUserID(context *gin.Context) {
 	validateURLParamAsNumber(context, UserIDParamKey)
 }
 
+// ValidateWebhookID validates :webhook_id placeholder from the request.
+func ValidateWebhookID(context *gin.Context) {
+	validateURLParamAsNumber(context, WebhookIDParamKey)
+}
+
+// ValidateAddressID validates :address_id placeholder from the request.
+func ValidateAddressID(context *gin.Context) {
+	validateURLParamAsNumber(context, AddressIDParamKey)
+}
+
+// ValidateAttributeDefinitionID validates :definition_id placeholder from the request.
+func ValidateAttributeDefinitionID(context *gin.Context) {
+	validateURLParamAsNumber(context, AttributeDefinitionIDParamKey)
+}
+
+// ValidateGroupID validates :group_id placeholder from the request.
+func ValidateGroupID(context *gin.Context) {
+	validateURLParamAsNumber(context, GroupIDParamKey)
+}
+
+// ValidateSessionID validates :session_id placeholder from the request.
+func ValidateSessionID(context *gin.Context) {
+	validateURLParamAsNumber(context, SessionIDParamKey)
+}
+
 func validateURLParamAsNumber(context *gin.Context, paramName string) {
 
 	value, err := strconv.Atoi(context.Param(paramName))
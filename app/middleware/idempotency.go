@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/service/idempotency"
+)
+
+// Idempotency headers
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+const (
+	idempotencyKeyMaxLength  = 255
+	defaultReplayContentType = "application/json; charset=utf-8"
+)
+
+// responseRecorder keeps copy of the response body written by the handler.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes request with `Idempotency-Key` header safe to retry.
+// Response of the first request is stored and replayed for retries with the same key and the same request.
+// Reusing the key with different request is rejected with 422.
+// Responses with 5xx status and panics are not stored, so such requests can be retried.
+func Idempotency(service idempotency.Provider) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		key := context.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			context.Next()
+			return
+		}
+
+		if len(key) > idempotencyKeyMaxLength {
+			httperrors.Emit(context, httperrors.IdempotencyKeyIncorrect)
+			context.Abort()
+			return
+		}
+
+		body, err := ioutil.ReadAll(context.Request.Body)
+		if err != nil {
+			httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(
+				errors.Wrap(err, "impossible to read request body"),
+			))
+			context.Abort()
+			return
+		}
+		context.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		scope := context.Request.Method + " " + context.FullPath()
+		stored, err := service.Begin(scope, key, fingerprint(context.Request, body))
+		if err != nil {
+			httperrors.Emit(context, err)
+			context.Abort()
+			return
+		}
+
+		if stored != nil {
+			contentType := stored.ResponseContentType
+			if contentType == "" {
+				contentType = defaultReplayContentType
+			}
+			context.Header(IdempotentReplayedHeader, "true")
+			context.Data(stored.ResponseStatus, contentType, []byte(stored.ResponseBody))
+			context.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: context.Writer}
+		context.Writer = recorder
+
+		// panic is recovered by outer middleware, the key is released so the request can be retried
+		defer func() {
+			if r := recover(); r != nil {
+				if err := service.Release(scope, key); err != nil {
+					log.Errorf("%+v", err)
+				}
+				panic(r)
+			}
+		}()
+		context.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err := service.Release(scope, key); err != nil {
+				log.Errorf("%+v", err)
+			}
+			return
+		}
+
+		if err := service.Complete(
+			scope, key, status, recorder.Header().Get("Content-Type"), recorder.body.String(),
+		); err != nil {
+			log.Errorf("%+v", err)
+		}
+	}
+}
+
+// fingerprint identifies the request by its method, path and body.
+func fingerprint(request *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(request.Method + " " + request.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
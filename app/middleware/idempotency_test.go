@@ -0,0 +1,153 @@
+// +build unit
+
+package middleware
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// fakeIdempotencyService keeps idempotency keys in memory.
+type fakeIdempotencyService struct {
+	keys map[string]*model.IdempotencyKey
+}
+
+func (s *fakeIdempotencyService) Begin(scope, key, fingerprint string) (*model.IdempotencyKey, error) {
+	existing, ok := s.keys[scope+key]
+	if !ok {
+		s.keys[scope+key] = &model.IdempotencyKey{Fingerprint: fingerprint, Status: model.IdempotencyKeyProcessing}
+		return nil, nil
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, httperrors.IdempotencyKeyReused
+	}
+	if existing.Status != model.IdempotencyKeyCompleted {
+		return nil, httperrors.IdempotencyKeyInProgress
+	}
+	return existing, nil
+}
+
+func (s *fakeIdempotencyService) Complete(scope, key string, status int, contentType, body string) error {
+	existing := s.keys[scope+key]
+	existing.Status = model.IdempotencyKeyCompleted
+	existing.ResponseStatus = status
+	existing.ResponseContentType = contentType
+	existing.ResponseBody = body
+	return nil
+}
+
+func (s *fakeIdempotencyService) Release(scope, key string) error {
+	delete(s.keys, scope+key)
+	return nil
+}
+
+func newIdempotentRouter(service *fakeIdempotencyService, handler gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.POST("/users", Idempotency(service), handler)
+	return router
+}
+
+func doIdempotentRequest(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(&fakeIdempotencyService{keys: map[string]*model.IdempotencyKey{}},
+		func(context *gin.Context) {
+			calls++
+			context.JSON(http.StatusCreated, gin.H{"id": calls})
+		})
+
+	first := doIdempotentRequest(router, "key-1", `{"name":"Alan"}`)
+	second := doIdempotentRequest(router, "key-1", `{"name":"Alan"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.JSONEq(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, first.Header().Get("Content-Type"), second.Header().Get("Content-Type"))
+}
+
+func TestIdempotencyKeyReusedWithDifferentBody(t *testing.T) {
+	router := newIdempotentRouter(&fakeIdempotencyService{keys: map[string]*model.IdempotencyKey{}},
+		func(context *gin.Context) {
+			context.JSON(http.StatusCreated, gin.H{"id": 1})
+		})
+
+	doIdempotentRequest(router, "key-1", `{"name":"Alan"}`)
+	second := doIdempotentRequest(router, "key-1", `{"name":"Bob"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, second.Code)
+	assert.JSONEq(t, httperrors.IdempotencyKeyReused.Error(), second.Body.String())
+}
+
+func TestIdempotencyServerErrorIsNotStored(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(&fakeIdempotencyService{keys: map[string]*model.IdempotencyKey{}},
+		func(context *gin.Context) {
+			calls++
+			if calls == 1 {
+				context.JSON(http.StatusInternalServerError, gin.H{})
+				return
+			}
+			context.JSON(http.StatusCreated, gin.H{"id": 1})
+		})
+
+	first := doIdempotentRequest(router, "key-1", `{"name":"Alan"}`)
+	second := doIdempotentRequest(router, "key-1", `{"name":"Alan"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyPanicIsNotStored(t *testing.T) {
+	calls := 0
+	service := &fakeIdempotencyService{keys: map[string]*model.IdempotencyKey{}}
+	router := gin.New()
+	router.Use(gin.RecoveryWithWriter(ioutil.Discard))
+	router.POST("/users", Idempotency(service), func(context *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("unexpected error")
+		}
+		context.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	first := doIdempotentRequest(router, "key-1", `{"name":"Alan"}`)
+	second := doIdempotentRequest(router, "key-1", `{"name":"Alan"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyWithoutKey(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(&fakeIdempotencyService{keys: map[string]*model.IdempotencyKey{}},
+		func(context *gin.Context) {
+			calls++
+			context.JSON(http.StatusCreated, gin.H{"id": calls})
+		})
+
+	doIdempotentRequest(router, "", `{"name":"Alan"}`)
+	doIdempotentRequest(router, "", `{"name":"Alan"}`)
+
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+// ProblemDetails configures rendering of errors by httperrors.Emit as RFC 7807 problem details.
+// When always is false only requests with `Accept: application/problem+json` get problem details,
+// typeBaseURI is followed by error code in `type` of the problem.
+func ProblemDetails(always bool, typeBaseURI string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		context.Set(httperrors.ProblemAlwaysKey, always)
+		context.Set(httperrors.ProblemTypeBaseURIKey, typeBaseURI)
+		context.Next()
+	}
+}
@@ -0,0 +1,53 @@
+// +build unit
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+func newProblemTestRouter(always bool) *gin.Engine {
+	router := gin.New()
+	router.Use(ProblemDetails(always, "https://errors.example.com/"))
+	router.GET("/users/:user_id", ValidateUserID)
+	return router
+}
+
+func TestProblemDetails_Always(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	newProblemTestRouter(true).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/abc", nil))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, httperrors.ProblemContentType, recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "https://errors.example.com/1040002",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "could not parse the path parameters",
+		"instance": "/users/abc",
+		"code": 1040002
+	}`, recorder.Body.String())
+}
+
+func TestProblemDetails_OptIn(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	newProblemTestRouter(false).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/abc", nil))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(t, httperrors.PathParametersParsingError.Error(), recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/users/abc", nil)
+	request.Header.Set("Accept", httperrors.ProblemContentType)
+	newProblemTestRouter(false).ServeHTTP(recorder, request)
+
+	assert.Equal(t, httperrors.ProblemContentType, recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), `"type":"https://errors.example.com/1040002"`)
+}
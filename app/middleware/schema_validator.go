@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/openapi"
+)
+
+// ValidateRequestSchema validates query string and JSON body of the request against the operation
+// documented for the matched route. Every violation is reported as a detail of RequestSchemaViolation
+// before the controller runs. Requests of undocumented routes are passed through.
+// Body larger than maxBodySize bytes is rejected without being read to the end.
+func ValidateRequestSchema(doc *openapi.Document, maxBodySize int64) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		operation := doc.Operation(context.Request.Method, openapi.PathFromRoute(context.FullPath()))
+		if operation == nil {
+			context.Next()
+			return
+		}
+
+		violations := doc.ValidateQuery(operation, context.Request.URL.Query())
+
+		if operation.RequestBody != nil && context.Request.Body != nil {
+			body, err := ioutil.ReadAll(http.MaxBytesReader(context.Writer, context.Request.Body, maxBodySize))
+			if err != nil {
+				httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(
+					errors.Wrap(err, "impossible to read the request body"),
+				))
+				context.Abort()
+				return
+			}
+			context.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			if len(bytes.TrimSpace(body)) > 0 {
+				var value interface{}
+				decoder := json.NewDecoder(bytes.NewReader(body))
+				decoder.UseNumber()
+				if err := decoder.Decode(&value); err != nil {
+					httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(
+						errors.Wrap(err, "wrong format of the request body"),
+					))
+					context.Abort()
+					return
+				}
+
+				// body has to be a single JSON value, e.g. `{"a":1}garbage` is rejected
+				if _, err := decoder.Token(); err != io.EOF {
+					httperrors.Emit(context, httperrors.RequestBodyParsingError.WithCause(
+						errors.New("unexpected data after JSON value of the request body"),
+					))
+					context.Abort()
+					return
+				}
+				violations = append(violations, doc.ValidateBody(operation, value)...)
+			}
+		}
+
+		if len(violations) > 0 {
+			var details httperrors.Details
+			for _, violation := range violations {
+				details.Add(violation.Pointer, violationError(violation))
+			}
+			httperrors.Emit(context, httperrors.RequestSchemaViolation.WithDetails(details...))
+			context.Abort()
+			return
+		}
+
+		context.Next()
+	}
+}
+
+// violationError returns error which describes the schema violation.
+func violationError(violation openapi.Violation) *httperrors.HTTPError {
+	switch violation.Kind {
+	case openapi.ViolationUnknownProperty:
+		return httperrors.SchemaUnknownField(violation.Name)
+	case openapi.ViolationMaxLength:
+		return httperrors.SchemaMaxLengthExceeded(violation.Name, violation.MaxLength)
+	case openapi.ViolationRepeated:
+		return httperrors.SchemaParameterRepeated(violation.Name)
+	default:
+		return httperrors.SchemaTypeMismatch(violation.Name, violation.Expected)
+	}
+}
@@ -0,0 +1,119 @@
+// +build unit
+
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/openapi"
+)
+
+type schemaTestBody struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+type schemaTestQuery struct {
+	Limit int `form:"limit"`
+}
+
+func newSchemaTestRouter() *gin.Engine {
+	doc := openapi.New("test", "1.0.0")
+	doc.Add(http.MethodPost, "/tests/:test_id", &openapi.Operation{
+		Parameters:  doc.QueryParameters(schemaTestQuery{}),
+		RequestBody: doc.RequestBody(schemaTestBody{}),
+	})
+
+	router := gin.New()
+	handler := func(context *gin.Context) {
+		body, _ := ioutil.ReadAll(context.Request.Body)
+		context.String(http.StatusOK, string(body))
+	}
+	router.POST("/tests/:test_id", ValidateRequestSchema(doc, 64), handler)
+	router.POST("/undocumented", ValidateRequestSchema(doc, 64), handler)
+
+	return router
+}
+
+func TestValidateRequestSchema_OK(t *testing.T) {
+	body := `{"name":"John","age":30}`
+	recorder := httptest.NewRecorder()
+	newSchemaTestRouter().ServeHTTP(recorder,
+		httptest.NewRequest(http.MethodPost, "/tests/1?limit=10", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, body, recorder.Body.String())
+}
+
+func TestValidateRequestSchema_Violations(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	newSchemaTestRouter().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/tests/1?limit=ten",
+		strings.NewReader(`{"name":"John","age":"30","zip":"00-001"}`)))
+
+	var details httperrors.Details
+	details.Add("/limit", httperrors.SchemaTypeMismatch("limit", "integer"))
+	details.Add("/age", httperrors.SchemaTypeMismatch("age", "integer"))
+	details.Add("/zip", httperrors.SchemaUnknownField("zip"))
+	expected := httperrors.RequestSchemaViolation.WithDetails(details...)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(t, expected.Error(), recorder.Body.String())
+}
+
+func TestValidateRequestSchema_MalformedBody(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	newSchemaTestRouter().ServeHTTP(recorder,
+		httptest.NewRequest(http.MethodPost, "/tests/1", strings.NewReader(`{"name":`)))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(t, httperrors.RequestBodyParsingError.Error(), recorder.Body.String())
+}
+
+func TestValidateRequestSchema_TrailingData(t *testing.T) {
+	for _, body := range []string{`{"name":"John"}garbage`, `{"name":"John"}}`, `{"name":"John"} {}`} {
+		recorder := httptest.NewRecorder()
+		newSchemaTestRouter().ServeHTTP(recorder,
+			httptest.NewRequest(http.MethodPost, "/tests/1", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, body)
+		assert.JSONEq(t, httperrors.RequestBodyParsingError.Error(), recorder.Body.String(), body)
+	}
+}
+
+func TestValidateRequestSchema_BodyTooLarge(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	newSchemaTestRouter().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/tests/1",
+		strings.NewReader(`{"name":"`+strings.Repeat("a", 64)+`"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(t, httperrors.RequestBodyParsingError.Error(), recorder.Body.String())
+}
+
+func TestValidateRequestSchema_UndocumentedRoute(t *testing.T) {
+	body := `{"anything":true}`
+	recorder := httptest.NewRecorder()
+	newSchemaTestRouter().ServeHTTP(recorder,
+		httptest.NewRequest(http.MethodPost, "/undocumented?any=1", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, body, recorder.Body.String())
+}
+
+func TestValidateRequestSchema_LocalizedViolations(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/tests/1", strings.NewReader(`{"zip":"00-001"}`))
+	request.Header.Set(httperrors.AcceptLanguageHeader, "pl-PL, en;q=0.5")
+	newSchemaTestRouter().ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, httperrors.LanguagePolish, recorder.Header().Get(httperrors.ContentLanguageHeader))
+	assert.JSONEq(t, `{"code":1040004,"message":"żądanie nie jest zgodne ze schematem API",`+
+		`"details":[{"field":"/zip","code":1040010,"message":"nieznane pole `+"`zip`"+`"}]}`, recorder.Body.String())
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Supported address types
+const (
+	AddressHome     = "home"
+	AddressBilling  = "billing"
+	AddressShipping = "shipping"
+)
+
+// Address represents postal address of the user.
+// Every user with addresses has exactly one primary address.
+type Address struct {
+	ID         int       `db:"id"`
+	UserID     int       `db:"user_id"`
+	Type       string    `db:"type"`
+	Street     string    `db:"street"`
+	City       string    `db:"city"`
+	PostalCode string    `db:"postal_code"`
+	Region     string    `db:"region"`
+	Country    string    `db:"country"`
+	Primary    bool      `db:"is_primary"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// String returns address in one line, e.g. "70 Jett Lane, 90001 Los Angeles, California, US".
+// It is stored as legacy address of the user.
+func (a Address) String() string {
+	city := strings.TrimSpace(a.PostalCode + " " + a.City)
+	parts := make([]string, 0, 4)
+	for _, part := range []string{a.Street, city, a.Region, a.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,34 @@
+// +build unit
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressString(t *testing.T) {
+	var testData = []struct {
+		name     string
+		address  Address
+		expected string
+	}{
+		{
+			"AllParts",
+			Address{Street: "70 Jett Lane", City: "Los Angeles", PostalCode: "90001", Region: "California", Country: "US"},
+			"70 Jett Lane, 90001 Los Angeles, California, US",
+		},
+		{
+			"WithoutPostalCodeAndRegion",
+			Address{Street: "Marszałkowska 1", City: "Warszawa", Country: "PL"},
+			"Marszałkowska 1, Warszawa, PL",
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.address.String())
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Supported attribute types
+const (
+	AttributeString  = "string"
+	AttributeNumber  = "number"
+	AttributeBoolean = "boolean"
+)
+
+// AttributeDefinition describes custom attribute which users of the deployment can have.
+// Enum and Pattern restrict values of string attributes.
+type AttributeDefinition struct {
+	ID        int            `db:"id"`
+	Name      string         `db:"name"`
+	Type      string         `db:"type"`
+	Required  bool           `db:"required"`
+	Enum      types.JSONText `db:"enum_values"`
+	Pattern   string         `db:"pattern"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+// EnumValues returns allowed values of the attribute, empty list allows any value.
+func (d AttributeDefinition) EnumValues() []string {
+	var values []string
+	if err := json.Unmarshal(d.Enum, &values); err != nil {
+		return nil
+	}
+
+	return values
+}
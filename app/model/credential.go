@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Credential represents password of the user, users without credential can't log in.
+type Credential struct {
+	UserID int `db:"user_id"`
+	// PasswordHash is argon2id hash encoded with its parameters.
+	PasswordHash   string    `db:"password_hash"`
+	FailedAttempts int       `db:"failed_attempts"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Supported user lifecycle event types
+const (
+	EventUserCreated = "user.created"
+	EventUserUpdated = "user.updated"
+	EventUserDeleted = "user.deleted"
+)
+
+// Event represents user lifecycle event stored in outbox table
+type Event struct {
+	ID            int64          `db:"id"`
+	AggregateID   int            `db:"aggregate_id"`
+	Type          string         `db:"type"`
+	Payload       types.JSONText `db:"payload"`
+	Attempts      int            `db:"attempts"`
+	LastError     string         `db:"last_error"`
+	NextAttemptAt time.Time      `db:"next_attempt_at"`
+	PublishedAt   *time.Time     `db:"published_at"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
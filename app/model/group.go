@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Group represents cohort of users, name of the group is also used as a tag of its members.
+type Group struct {
+	ID          int       `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Supported idempotency key statuses
+const (
+	IdempotencyKeyProcessing = "processing"
+	IdempotencyKeyCompleted  = "completed"
+)
+
+// IdempotencyKey represents request processed with Idempotency-Key header and its stored response
+type IdempotencyKey struct {
+	Scope               string    `db:"scope"`
+	Key                 string    `db:"key"`
+	Fingerprint         string    `db:"fingerprint"`
+	Status              string    `db:"status"`
+	ResponseStatus      int       `db:"response_status"`
+	ResponseContentType string    `db:"response_content_type"`
+	ResponseBody        string    `db:"response_body"`
+	CreatedAt           time.Time `db:"created_at"`
+	ExpiresAt           time.Time `db:"expires_at"`
+}
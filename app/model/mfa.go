@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// MFA represents TOTP second factor of the user, it is pending until the first code is confirmed.
+type MFA struct {
+	UserID int `db:"user_id"`
+	// Secret is base32 encoded TOTP key shared with authenticator app of the user.
+	Secret    string     `db:"secret"`
+	EnabledAt *time.Time `db:"enabled_at"`
+	// LastUsedStep is TOTP time step of the last accepted code, older and the same codes are rejected.
+	LastUsedStep int64     `db:"last_used_step"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// Enabled reports whether the second factor is required on login.
+func (m MFA) Enabled() bool {
+	return m.EnabledAt != nil
+}
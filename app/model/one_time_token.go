@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Purposes of one-time tokens
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeMFAChallenge      = "mfa_challenge"
+)
+
+// OneTimeToken represents secret delivered to the user which can be used once for its purpose before it expires.
+// Only hash of the token is stored.
+type OneTimeToken struct {
+	TokenHash string `db:"token_hash"`
+	Purpose   string `db:"purpose"`
+	UserID    int    `db:"user_id"`
+	// Subject is value the token was issued for, e.g. email which is verified.
+	Subject   string    `db:"subject"`
+	ExpiresAt time.Time `db:"expires_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Session represents login of the user on a device, it is extended by rotating its refresh token.
+// Only hash of the current refresh token is stored.
+type Session struct {
+	ID               int        `db:"id"`
+	UserID           int        `db:"user_id"`
+	RefreshTokenHash string     `db:"refresh_token_hash"`
+	UserAgent        string     `db:"user_agent"`
+	IP               string     `db:"ip"`
+	CreatedAt        time.Time  `db:"created_at"`
+	LastSeenAt       time.Time  `db:"last_seen_at"`
+	ExpiresAt        time.Time  `db:"expires_at"`
+	RevokedAt        *time.Time `db:"revoked_at"`
+}
+
+// Active reports whether refresh token of the session can be used at given time.
+func (s Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
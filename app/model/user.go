@@ -1,14 +1,55 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// DateLayout is a format of dates without time, e.g. date of birth.
+const DateLayout = "2006-01-02"
 
 // User represents User entity
 type User struct {
-	ID        int       `db:"id"`
-	Name      string    `db:"name"`
-	Surname   string    `db:"surname"`
-	Gender    string    `db:"gender"`
-	Age       int       `db:"age"`
-	Address   string    `db:"address"`
+	ID              int        `db:"id"`
+	Name            string     `db:"name"`
+	Surname         string     `db:"surname"`
+	Gender          string     `db:"gender"`
+	DateOfBirth     time.Time  `db:"date_of_birth"`
+	Address         string     `db:"address"`
+	Email           string     `db:"email"`
+	Phone           string     `db:"phone"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
+	// Attributes is JSON object with custom attributes described by AttributeDefinition.
+	Attributes types.JSONText `db:"attributes"`
+	// Status is a state of the account, see UserStatusPending and other statuses.
+	Status    string    `db:"status"`
 	CreatedAt time.Time `db:"created_at"`
 }
+
+// AgeAt returns age of the user in full years at the given time.
+func (u User) AgeAt(t time.Time) int {
+	age := t.Year() - u.DateOfBirth.Year()
+	if t.Month() < u.DateOfBirth.Month() || (t.Month() == u.DateOfBirth.Month() && t.Day() < u.DateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
+
+// AttributeValues returns custom attributes of the user, empty map is returned when the user has none.
+func (u User) AttributeValues() map[string]interface{} {
+	values := make(map[string]interface{})
+	if len(u.Attributes) > 0 {
+		// Attributes are always stored as valid JSON object.
+		_ = json.Unmarshal(u.Attributes, &values)
+	}
+
+	return values
+}
+
+// Date returns midnight UTC of the calendar day of t, dates are stored and compared in this form.
+func Date(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
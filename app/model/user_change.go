@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Supported user change operations
+const (
+	UserChangeCreated = "created"
+	UserChangeUpdated = "updated"
+	UserChangeDeleted = "deleted"
+)
+
+// UserChange represents single change of User entity in the change feed.
+// Seq is monotonic and reflects commit order of the changes.
+type UserChange struct {
+	Seq       int64     `db:"seq"`
+	UserID    int       `db:"user_id"`
+	Operation string    `db:"operation"`
+	ChangedAt time.Time `db:"changed_at"`
+	// User is the current state of the user, it is nil when user does not exist anymore.
+	User *User `db:"-"`
+}
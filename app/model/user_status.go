@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Statuses of user accounts
+const (
+	UserStatusPending   = "pending"
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusLocked    = "locked"
+	UserStatusClosed    = "closed"
+)
+
+// UserStatusChange represents transition of user status recorded for auditing.
+type UserStatusChange struct {
+	ID         int       `db:"id"`
+	UserID     int       `db:"user_id"`
+	FromStatus string    `db:"from_status"`
+	ToStatus   string    `db:"to_status"`
+	Reason     string    `db:"reason"`
+	CreatedAt  time.Time `db:"created_at"`
+}
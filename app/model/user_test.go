@@ -0,0 +1,38 @@
+// +build unit
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAgeAt(t *testing.T) {
+	user := User{DateOfBirth: time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC)}
+
+	var testData = []struct {
+		name     string
+		at       time.Time
+		expected int
+	}{
+		{"DayOfBirth", time.Date(2000, 2, 29, 12, 0, 0, 0, time.UTC), 0},
+		{"DayBeforeBirthday", time.Date(2020, 2, 28, 23, 59, 0, 0, time.UTC), 19},
+		{"Birthday", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC), 20},
+		{"BirthdayInNonLeapYear", time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), 21},
+		{"BeforeBirthdayInNonLeapYear", time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC), 20},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, user.AgeAt(tt.at))
+		})
+	}
+}
+
+func TestDate(t *testing.T) {
+	at := time.Date(2020, 10, 19, 23, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	assert.Equal(t, time.Date(2020, 10, 19, 0, 0, 0, 0, time.UTC), Date(at))
+}
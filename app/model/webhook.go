@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Supported webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// Webhook represents webhook subscription entity
+type Webhook struct {
+	ID         int       `db:"id"`
+	URL        string    `db:"url"`
+	EventTypes string    `db:"event_types"`
+	Secret     string    `db:"secret"`
+	Active     bool      `db:"active"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// WebhookDelivery represents single delivery of the event to the webhook
+type WebhookDelivery struct {
+	ID             int64          `db:"id"`
+	WebhookID      int            `db:"webhook_id"`
+	EventID        int64          `db:"event_id"`
+	EventType      string         `db:"event_type"`
+	Payload        types.JSONText `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	ResponseStatus int            `db:"response_status"`
+	LastError      string         `db:"last_error"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	DeliveredAt    *time.Time     `db:"delivered_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
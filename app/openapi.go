@@ -0,0 +1,1019 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/middleware"
+	"github.com/mmgopher/user-service/app/openapi"
+)
+
+// API documentation routes.
+const (
+	OpenAPIRoute      = "/openapi.json"
+	SwaggerUIRoute    = "/docs"
+	SwaggerUICSSRoute = SwaggerUIRoute + "/" + openapi.SwaggerUICSS
+	SwaggerUIJSRoute  = SwaggerUIRoute + "/" + openapi.SwaggerUIJS
+)
+
+const (
+	apiTitle   = "User service API"
+	apiVersion = "1.0.0"
+)
+
+// ageDeprecation documents breaking change of user requests which store date of birth instead of age.
+const ageDeprecation = "Breaking change: `age` is no longer stored, send `date_of_birth` instead. " +
+	"Deprecated `age` is still accepted when `date_of_birth` is empty and date of birth is approximated " +
+	"as `age` years before the current day. Users are returned with `age` calculated from `date_of_birth`."
+
+// NewOpenAPI returns OpenAPI document which describes every route of the router.
+func NewOpenAPI() *openapi.Document {
+	doc := openapi.New(apiTitle, apiVersion)
+
+	idempotencyKey := &openapi.Parameter{
+		Name:        middleware.IdempotencyKeyHeader,
+		In:          "header",
+		Description: "makes the request safe to retry, response of the first request is replayed for the same key",
+		Schema:      &openapi.Schema{Type: "string", MaxLength: intPtr(255)},
+	}
+	idempotencyErrors := []*httperrors.HTTPError{
+		httperrors.IdempotencyKeyIncorrect,
+		httperrors.IdempotencyKeyInProgress,
+		httperrors.IdempotencyKeyReused,
+	}
+	var userProblems httperrors.Details
+	userProblems.Add("/name", httperrors.UserNameEmpty)
+	userProblems.Add("/surname", httperrors.UserSurnameEmpty)
+	userProblems.Add("/date_of_birth", httperrors.UserDateOfBirthIncorrect)
+	userProblems.Add("/date_of_birth", httperrors.UserDateOfBirthOutOfRange)
+	userProblems.Add("/age", httperrors.UserAgeIncorrect)
+	userProblems.Add("/gender", httperrors.UserGenderEmpty)
+	userProblems.Add("/gender", httperrors.UserGenderNotSupported("unknown"))
+	userProblems.Add("/address", httperrors.UserAddressEmpty)
+	userProblems.Add("/email", httperrors.UserEmailIncorrect)
+	userProblems.Add("/phone", httperrors.UserPhoneIncorrect)
+	userProblems.Add("/attributes/department", httperrors.AttributeRequired("department"))
+	userProblems.Add("/attributes/department", httperrors.AttributeTypeMismatch("department", "string"))
+	userProblems.Add("/attributes/department", httperrors.AttributeValueNotAllowed("department"))
+	userProblems.Add("/attributes/department", httperrors.AttributePatternMismatch("department"))
+	userProblems.Add("/attributes/unknown", httperrors.AttributeUnknown("unknown"))
+	userErrors := []*httperrors.HTTPError{
+		httperrors.RequestBodyParsingError,
+		schemaViolation("date_of_birth", "string"),
+		httperrors.RequestValidationFailed.WithDetails(userProblems...),
+		httperrors.UserAlreadyRegistered,
+		httperrors.UserEmailAlreadyRegistered,
+	}
+
+	var addressProblems httperrors.Details
+	addressProblems.Add("/type", httperrors.AddressTypeNotSupported("unknown"))
+	addressProblems.Add("/street", httperrors.AddressStreetEmpty)
+	addressProblems.Add("/city", httperrors.AddressCityEmpty)
+	addressProblems.Add("/country", httperrors.AddressCountryIncorrect)
+	addressErrors := []*httperrors.HTTPError{
+		httperrors.RequestBodyParsingError,
+		schemaViolation("primary", "boolean"),
+		httperrors.RequestValidationFailed.WithDetails(addressProblems...),
+	}
+
+	var webhookProblems httperrors.Details
+	webhookProblems.Add("/url", httperrors.WebhookURLIncorrect)
+	webhookProblems.Add("/event_types/0", httperrors.WebhookEventTypeNotSupported("unknown"))
+	webhookProblems.Add("/secret", httperrors.WebhookSecretTooShort)
+	webhookErrors := []*httperrors.HTTPError{
+		httperrors.RequestBodyParsingError,
+		schemaViolation("active", "boolean"),
+		httperrors.RequestValidationFailed.WithDetails(webhookProblems...),
+	}
+
+	doc.Add(http.MethodGet, RootPath+GetUserRoute, &openapi.Operation{
+		OperationID: "getUser",
+		Summary:     "Returns user",
+		Tags:        []string{"users"},
+		Responses: responses(
+			"200", doc.JSON("User", response.User{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodPost, RootPath+CreateUserRoute, &openapi.Operation{
+		OperationID: "createUser",
+		Summary:     "Creates user",
+		Description: ageDeprecation,
+		Tags:        []string{"users"},
+		Parameters:  []*openapi.Parameter{idempotencyKey},
+		RequestBody: doc.RequestBody(request.CreateUser{}),
+		Responses: responses(
+			"201", replayable(doc.JSON("ID of created user", response.CreateUser{})),
+			doc.Errors(append(append(userErrors, idempotencyErrors...), httperrors.InternalServerError)...),
+		),
+	})
+
+	doc.Add(http.MethodPut, RootPath+UpdateUserRoute, &openapi.Operation{
+		OperationID: "updateUser",
+		Summary:     "Updates user",
+		Description: ageDeprecation,
+		Tags:        []string{"users"},
+		Parameters:  []*openapi.Parameter{idempotencyKey},
+		RequestBody: doc.RequestBody(request.UpdateUser{}),
+		Responses: responses(
+			"200", replayable(doc.JSON("User is updated", struct{}{})),
+			doc.Errors(append(append(userErrors, idempotencyErrors...),
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.InternalServerError,
+			)...),
+		),
+	})
+
+	doc.Add(http.MethodDelete, RootPath+DeleteUserRoute, &openapi.Operation{
+		OperationID: "deleteUser",
+		Summary:     "Deletes user and revokes its sessions",
+		Tags:        []string{"users"},
+		Responses: responses(
+			"200", doc.JSON("User is deleted", struct{}{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	var paginationProblems httperrors.Details
+	paginationProblems.Add("/after_id", httperrors.PaginationAfterIDNegative)
+	paginationProblems.Add("/before_id", httperrors.PaginationBeforeIDNegative)
+	paginationProblems.Add("/before_id", httperrors.PaginationAfterIDAndBeforeIDDeclared)
+	paginationProblems.Add("/limit", httperrors.PaginationLimitNegative)
+	paginationProblems.Add("/sort", httperrors.PaginationSortIncorrectFormat)
+	userListProblems := append(httperrors.Details{}, paginationProblems...)
+	userListProblems.Add("/sort", httperrors.AttributeUnknown("unknown"))
+	userListProblems.Add("/attr.unknown", httperrors.AttributeUnknown("unknown"))
+	userListProblems.Add("/attr.headcount", httperrors.AttributeTypeMismatch("headcount", "number"))
+	userListProblems.Add("/group/0", httperrors.GroupUnknown(42))
+	userListProblems.Add("/tag/0", httperrors.GroupTagUnknown("unknown"))
+	userListProblems.Add("/group_match", httperrors.GroupMatchNotSupported("some"))
+	userListProblems.Add("/status", httperrors.UserStatusNotSupported("deleted"))
+	doc.Add(http.MethodGet, RootPath+GetUserListRoute, &openapi.Operation{
+		OperationID: "findUsers",
+		Summary:     "Finds users with filtering, sorting and keyset pagination",
+		Tags:        []string{"users"},
+		Parameters: append(doc.QueryParameters(request.FindUsers{}), &openapi.Parameter{
+			Name:        "attr.*",
+			In:          "query",
+			Description: "filter by custom attribute, e.g. attr.department=sales",
+			Schema:      &openapi.Schema{Type: "string"},
+		}),
+		Responses: responses(
+			"200", doc.JSON("Page of users with links to the previous and the next page", response.UserListWithPagination{}),
+			doc.Errors(
+				httperrors.QueryParametersParsingError,
+				schemaViolation("limit", "integer"),
+				httperrors.RequestValidationFailed.WithDetails(userListProblems...),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	changes := doc.JSON("Changes after the token, streamed as Server-Sent Events for `Accept: text/event-stream`",
+		response.UserChangeList{})
+	changes.Content["text/event-stream"] = &openapi.MediaType{
+		Schema: &openapi.Schema{
+			Type:        "string",
+			Description: "`change` events with UserChange data and event ID equal to the change token",
+		},
+	}
+	var changeFeedProblems httperrors.Details
+	changeFeedProblems.Add("/limit", httperrors.ChangeFeedLimitNegative)
+	changeFeedProblems.Add("/wait", httperrors.ChangeFeedWaitIncorrect)
+	doc.Add(http.MethodGet, RootPath+GetUserChanges, &openapi.Operation{
+		OperationID: "findUserChanges",
+		Summary:     "Returns feed of user changes",
+		Tags:        []string{"users"},
+		Parameters:  doc.QueryParameters(request.FindUserChanges{}),
+		Responses: responses(
+			"200", changes,
+			doc.Errors(
+				httperrors.QueryParametersParsingError,
+				schemaViolation("limit", "integer"),
+				httperrors.ChangeFeedTokenIncorrect,
+				httperrors.RequestValidationFailed.WithDetails(changeFeedProblems...),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodPost, RootPath+RequestEmailVerificationRoute, &openapi.Operation{
+		OperationID: "requestEmailVerification",
+		Summary:     "Delivers new email verification token to the user, tokens issued earlier stop working",
+		Tags:        []string{"users"},
+		Responses: responses(
+			"202", doc.JSON("Token is sent", response.EmailVerification{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.EmailVerificationEmailMissing,
+				httperrors.EmailVerificationAlreadyVerified,
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	var verificationProblems httperrors.Details
+	verificationProblems.Add("/token", httperrors.EmailVerificationTokenEmpty)
+	doc.Add(http.MethodPost, RootPath+ConfirmEmailVerificationRoute, &openapi.Operation{
+		OperationID: "confirmEmailVerification",
+		Summary:     "Marks email of the user as verified with the delivered token",
+		Tags:        []string{"users"},
+		RequestBody: doc.RequestBody(request.ConfirmEmailVerification{}),
+		Responses: responses(
+			"200", doc.JSON("Email is verified", struct{}{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.RequestBodyParsingError,
+				schemaViolation("token", "string"),
+				httperrors.RequestValidationFailed.WithDetails(verificationProblems...),
+				httperrors.EmailVerificationTokenIncorrect,
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	var statusProblems httperrors.Details
+	statusProblems.Add("/reason", httperrors.UserStatusReasonEmpty)
+	statusProblems.Add("/reason", httperrors.UserStatusReasonTooLong(500))
+	for _, transition := range []struct {
+		route, operationID, summary, from, to string
+	}{
+		{ActivateUserRoute, "activateUser", "Activates pending, suspended or locked user", "closed", "active"},
+		{SuspendUserRoute, "suspendUser", "Suspends active user and revokes its sessions", "pending", "suspended"},
+		{LockUserRoute, "lockUser", "Locks active user", "pending", "locked"},
+		{CloseUserRoute, "closeUser",
+			"Closes account of the user and revokes its sessions, closed user can't be changed to another status",
+			"closed", "closed"},
+	} {
+		doc.Add(http.MethodPost, RootPath+transition.route, &openapi.Operation{
+			OperationID: transition.operationID,
+			Summary:     transition.summary,
+			Tags:        []string{"users"},
+			RequestBody: doc.RequestBody(request.ChangeUserStatus{}),
+			Responses: responses(
+				"200", doc.JSON("Status is changed and recorded in status history", struct{}{}),
+				doc.Errors(
+					httperrors.PathParametersParsingError,
+					httperrors.RequestBodyParsingError,
+					schemaViolation("reason", "string"),
+					httperrors.RequestValidationFailed.WithDetails(statusProblems...),
+					httperrors.EntityNotFoundError("user"),
+					httperrors.UserStatusTransitionNotAllowed(transition.from, transition.to),
+					httperrors.InternalServerError,
+				),
+			),
+		})
+	}
+
+	doc.Add(http.MethodGet, RootPath+GetUserStatusHistoryRoute, &openapi.Operation{
+		OperationID: "getUserStatusHistory",
+		Summary:     "Returns status transitions of the user from the oldest one",
+		Tags:        []string{"users"},
+		Responses: responses(
+			"200", doc.JSON("Status history", response.UserStatusHistory{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	var loginProblems httperrors.Details
+	loginProblems.Add("/email", httperrors.AuthEmailEmpty)
+	loginProblems.Add("/password", httperrors.AuthPasswordEmpty)
+	loginResponses := responses(
+		"200", doc.JSON("Access token and refresh token of new session", response.Token{}),
+		doc.Errors(
+			httperrors.RequestBodyParsingError,
+			schemaViolation("password", "string"),
+			httperrors.RequestValidationFailed.WithDetails(loginProblems...),
+			httperrors.AuthInvalidCredentials,
+			httperrors.AuthUserNotActive("locked"),
+			httperrors.InternalServerError,
+		),
+	)
+	loginResponses["202"] = doc.JSON("MFA of the user is enabled, challenge has to be confirmed", response.MFAChallenge{})
+	doc.Add(http.MethodPost, RootPath+LoginRoute, &openapi.Operation{
+		OperationID: "login",
+		Summary:     "Starts session of active user or returns MFA challenge, user is locked after too many failed attempts",
+		Tags:        []string{"auth"},
+		RequestBody: doc.RequestBody(request.Login{}),
+		Responses:   loginResponses,
+	})
+
+	var loginMFAProblems httperrors.Details
+	loginMFAProblems.Add("/mfa_token", httperrors.MFATokenEmpty)
+	loginMFAProblems.Add("/code", httperrors.MFACodeEmpty)
+	doc.Add(http.MethodPost, RootPath+LoginMFARoute, &openapi.Operation{
+		OperationID: "loginMFA",
+		Summary:     "Starts session of the user when MFA challenge and code are correct",
+		Tags:        []string{"auth"},
+		RequestBody: doc.RequestBody(request.LoginMFA{}),
+		Responses: responses(
+			"200", doc.JSON("Access token and refresh token of new session", response.Token{}),
+			doc.Errors(
+				httperrors.RequestBodyParsingError,
+				schemaViolation("code", "string"),
+				httperrors.RequestValidationFailed.WithDetails(loginMFAProblems...),
+				httperrors.MFAChallengeIncorrect,
+				httperrors.MFALoginCodeIncorrect,
+				httperrors.AuthUserNotActive("locked"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	var refreshTokenProblems httperrors.Details
+	refreshTokenProblems.Add("/refresh_token", httperrors.RefreshTokenEmpty)
+	doc.Add(http.MethodPost, RootPath+RefreshTokenRoute, &openapi.Operation{
+		OperationID: "refreshToken",
+		Summary:     "Rotates refresh token of the session, reuse of rotated token revokes the session",
+		Tags:        []string{"auth"},
+		RequestBody: doc.RequestBody(request.RefreshToken{}),
+		Responses: responses(
+			"200", doc.JSON("New access token and refresh token of the session", response.Token{}),
+			doc.Errors(
+				httperrors.RequestBodyParsingError,
+				schemaViolation("refresh_token", "string"),
+				httperrors.RequestValidationFailed.WithDetails(refreshTokenProblems...),
+				httperrors.RefreshTokenIncorrect,
+				httperrors.AuthUserNotActive("locked"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	var passwordResetProblems httperrors.Details
+	passwordResetProblems.Add("/email", httperrors.AuthEmailEmpty)
+	doc.Add(http.MethodPost, RootPath+PasswordResetRoute, &openapi.Operation{
+		OperationID: "requestPasswordReset",
+		Summary:     "Delivers password reset token, response doesn't reveal whether the user exists",
+		Tags:        []string{"auth"},
+		RequestBody: doc.RequestBody(request.RequestPasswordReset{}),
+		Responses: responses(
+			"202", doc.JSON("Token is sent when the user exists", struct{}{}),
+			doc.Errors(
+				httperrors.RequestBodyParsingError,
+				schemaViolation("email", "string"),
+				httperrors.RequestValidationFailed.WithDetails(passwordResetProblems...),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	var confirmPasswordResetProblems httperrors.Details
+	confirmPasswordResetProblems.Add("/token", httperrors.PasswordResetTokenEmpty)
+	confirmPasswordResetProblems.Add("/new_password", httperrors.PasswordTooShort(10))
+	confirmPasswordResetProblems.Add("/new_password", httperrors.PasswordTooLong(128))
+	confirmPasswordResetProblems.Add("/new_password", httperrors.PasswordTooWeak)
+	doc.Add(http.MethodPost, RootPath+ConfirmPasswordResetRoute, &openapi.Operation{
+		OperationID: "confirmPasswordReset",
+		Summary:     "Sets password of the user with single use reset token",
+		Tags:        []string{"auth"},
+		RequestBody: doc.RequestBody(request.ConfirmPasswordReset{}),
+		Responses: responses(
+			"200", doc.JSON("Password is changed", struct{}{}),
+			doc.Errors(
+				httperrors.RequestBodyParsingError,
+				schemaViolation("token", "string"),
+				httperrors.RequestValidationFailed.WithDetails(confirmPasswordResetProblems...),
+				httperrors.PasswordResetTokenIncorrect,
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	var passwordProblems httperrors.Details
+	passwordProblems.Add("/new_password", httperrors.PasswordTooShort(10))
+	passwordProblems.Add("/new_password", httperrors.PasswordTooLong(128))
+	passwordProblems.Add("/new_password", httperrors.PasswordTooWeak)
+	passwordProblems.Add("/new_password", httperrors.PasswordNotChanged)
+	doc.Add(http.MethodPost, RootPath+ChangePasswordRoute, &openapi.Operation{
+		OperationID: "changePassword",
+		Summary:     "Changes password of the user, user without password has to set it with password reset",
+		Tags:        []string{"auth"},
+		RequestBody: doc.RequestBody(request.ChangePassword{}),
+		Responses: responses(
+			"200", doc.JSON("Password is changed", struct{}{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.RequestBodyParsingError,
+				schemaViolation("new_password", "string"),
+				httperrors.RequestValidationFailed.WithDetails(passwordProblems...),
+				httperrors.PasswordCurrentIncorrect,
+				httperrors.PasswordNotSet,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodPost, RootPath+EnrollMFARoute, &openapi.Operation{
+		OperationID: "enrollMFA",
+		Summary:     "Generates TOTP secret of the user, MFA is enabled after the first code is confirmed",
+		Tags:        []string{"mfa"},
+		Responses: responses(
+			"200", doc.JSON("Pending TOTP secret", response.MFAEnrolment{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.MFAAlreadyEnabled,
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	var mfaCodeProblems httperrors.Details
+	mfaCodeProblems.Add("/code", httperrors.MFACodeEmpty)
+	doc.Add(http.MethodPost, RootPath+ActivateMFARoute, &openapi.Operation{
+		OperationID: "activateMFA",
+		Summary:     "Enables MFA of the user with the first TOTP code and returns recovery codes",
+		Tags:        []string{"mfa"},
+		RequestBody: doc.RequestBody(request.MFACode{}),
+		Responses: responses(
+			"200", doc.JSON("MFA is enabled", response.MFARecoveryCodes{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.RequestBodyParsingError,
+				schemaViolation("code", "string"),
+				httperrors.RequestValidationFailed.WithDetails(mfaCodeProblems...),
+				httperrors.MFACodeIncorrect,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.MFANotEnrolled,
+				httperrors.MFAAlreadyEnabled,
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodPost, RootPath+DisableMFARoute, &openapi.Operation{
+		OperationID: "disableMFA",
+		Summary:     "Disables MFA of the user with TOTP code or recovery code",
+		Tags:        []string{"mfa"},
+		RequestBody: doc.RequestBody(request.MFACode{}),
+		Responses: responses(
+			"200", doc.JSON("MFA is disabled", struct{}{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.RequestBodyParsingError,
+				schemaViolation("code", "string"),
+				httperrors.RequestValidationFailed.WithDetails(mfaCodeProblems...),
+				httperrors.MFACodeIncorrect,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.MFANotEnabled,
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodPost, RootPath+RegenerateMFARecoveryCodesRoute, &openapi.Operation{
+		OperationID: "regenerateMFARecoveryCodes",
+		Summary:     "Replaces recovery codes of the user, TOTP code or recovery code is required",
+		Tags:        []string{"mfa"},
+		RequestBody: doc.RequestBody(request.MFACode{}),
+		Responses: responses(
+			"200", doc.JSON("New recovery codes", response.MFARecoveryCodes{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.RequestBodyParsingError,
+				schemaViolation("code", "string"),
+				httperrors.RequestValidationFailed.WithDetails(mfaCodeProblems...),
+				httperrors.MFACodeIncorrect,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.MFANotEnabled,
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodGet, RootPath+GetSessionListRoute, &openapi.Operation{
+		OperationID: "getSessionList",
+		Summary:     "Returns active sessions of the user, the most recently used first",
+		Tags:        []string{"sessions"},
+		Responses: responses(
+			"200", doc.JSON("Active sessions", response.SessionList{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodDelete, RootPath+RevokeSessionRoute, &openapi.Operation{
+		OperationID: "revokeSession",
+		Summary:     "Revokes the session, issued access tokens stay valid until they expire",
+		Tags:        []string{"sessions"},
+		Responses: responses(
+			"200", doc.JSON("Session is revoked", struct{}{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("session"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodDelete, RootPath+RevokeSessionsRoute, &openapi.Operation{
+		OperationID: "revokeSessions",
+		Summary:     "Revokes all sessions of the user",
+		Tags:        []string{"sessions"},
+		Responses: responses(
+			"200", doc.JSON("Sessions are revoked", struct{}{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodGet, RootPath+GetAddressRoute, &openapi.Operation{
+		OperationID: "getAddress",
+		Summary:     "Returns address of the user",
+		Tags:        []string{"addresses"},
+		Responses: responses(
+			"200", doc.JSON("Address", response.Address{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("address"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodPost, RootPath+CreateAddressRoute, &openapi.Operation{
+		OperationID: "createAddress",
+		Summary:     "Creates address of the user, the first address of the user is primary",
+		Tags:        []string{"addresses"},
+		RequestBody: doc.RequestBody(request.CreateAddress{}),
+		Responses: responses(
+			"201", doc.JSON("ID of created address", response.CreateAddress{}),
+			doc.Errors(append(addressErrors,
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.InternalServerError,
+			)...),
+		),
+	})
+
+	doc.Add(http.MethodPut, RootPath+UpdateAddressRoute, &openapi.Operation{
+		OperationID: "updateAddress",
+		Summary:     "Updates address of the user",
+		Tags:        []string{"addresses"},
+		RequestBody: doc.RequestBody(request.UpdateAddress{}),
+		Responses: responses(
+			"200", doc.JSON("Address is updated", struct{}{}),
+			doc.Errors(append(addressErrors,
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("address"),
+				httperrors.InternalServerError,
+			)...),
+		),
+	})
+
+	doc.Add(http.MethodDelete, RootPath+DeleteAddressRoute, &openapi.Operation{
+		OperationID: "deleteAddress",
+		Summary:     "Deletes address of the user, the oldest remaining address becomes primary",
+		Tags:        []string{"addresses"},
+		Responses: responses(
+			"200", doc.JSON("Address is deleted", struct{}{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("address"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodGet, RootPath+GetAddressListRoute, &openapi.Operation{
+		OperationID: "getAddresses",
+		Summary:     "Returns all addresses of the user",
+		Tags:        []string{"addresses"},
+		Responses: responses(
+			"200", doc.JSON("Addresses", response.AddressList{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("user"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodGet, RootPath+GetWebhookRoute, &openapi.Operation{
+		OperationID: "getWebhook",
+		Summary:     "Returns webhook",
+		Tags:        []string{"webhooks"},
+		Responses: responses(
+			"200", doc.JSON("Webhook", response.Webhook{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("webhook"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodPost, RootPath+CreateWebhookRoute, &openapi.Operation{
+		OperationID: "createWebhook",
+		Summary:     "Creates webhook",
+		Tags:        []string{"webhooks"},
+		RequestBody: doc.RequestBody(request.CreateWebhook{}),
+		Responses: responses(
+			"201", doc.JSON("ID and secret of created webhook", response.CreateWebhook{}),
+			doc.Errors(append(webhookErrors, httperrors.InternalServerError)...),
+		),
+	})
+
+	doc.Add(http.MethodPut, RootPath+UpdateWebhookRoute, &openapi.Operation{
+		OperationID: "updateWebhook",
+		Summary:     "Updates webhook",
+		Tags:        []string{"webhooks"},
+		RequestBody: doc.RequestBody(request.UpdateWebhook{}),
+		Responses: responses(
+			"200", doc.JSON("Webhook is updated", struct{}{}),
+			doc.Errors(append(webhookErrors,
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("webhook"),
+				httperrors.InternalServerError,
+			)...),
+		),
+	})
+
+	doc.Add(http.MethodDelete, RootPath+DeleteWebhookRoute, &openapi.Operation{
+		OperationID: "deleteWebhook",
+		Summary:     "Deletes webhook with its deliveries",
+		Tags:        []string{"webhooks"},
+		Responses: responses(
+			"200", doc.JSON("Webhook is deleted", struct{}{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("webhook"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodGet, RootPath+GetWebhookListRoute, &openapi.Operation{
+		OperationID: "getWebhooks",
+		Summary:     "Returns all webhooks",
+		Tags:        []string{"webhooks"},
+		Responses: responses(
+			"200", doc.JSON("Webhooks", response.WebhookList{}),
+			doc.Errors(httperrors.InternalServerError),
+		),
+	})
+
+	var deliveryProblems httperrors.Details
+	deliveryProblems.Add("/limit", httperrors.WebhookDeliveriesLimitNegative)
+	deliveryProblems.Add("/before_id", httperrors.WebhookDeliveriesBeforeIDNegative)
+	doc.Add(http.MethodGet, RootPath+GetWebhookDeliveryListRoute, &openapi.Operation{
+		OperationID: "findWebhookDeliveries",
+		Summary:     "Returns deliveries of the webhook from the newest one",
+		Tags:        []string{"webhooks"},
+		Parameters:  doc.QueryParameters(request.FindWebhookDeliveries{}),
+		Responses: responses(
+			"200", doc.JSON("Page of deliveries with link to the next page", response.WebhookDeliveryList{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.QueryParametersParsingError,
+				schemaViolation("limit", "integer"),
+				httperrors.RequestValidationFailed.WithDetails(deliveryProblems...),
+				httperrors.EntityNotFoundError("webhook"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	var attributeDefinitionProblems httperrors.Details
+	attributeDefinitionProblems.Add("/name", httperrors.AttributeNameIncorrect)
+	attributeDefinitionProblems.Add("/type", httperrors.AttributeTypeNotSupported("unknown"))
+	attributeDefinitionProblems.Add("/enum", httperrors.AttributeEnumNotAllowed)
+	attributeDefinitionProblems.Add("/pattern", httperrors.AttributePatternNotAllowed)
+	attributeDefinitionProblems.Add("/pattern", httperrors.AttributePatternIncorrect)
+	attributeDefinitionErrors := []*httperrors.HTTPError{
+		httperrors.RequestBodyParsingError,
+		schemaViolation("required", "boolean"),
+		httperrors.RequestValidationFailed.WithDetails(attributeDefinitionProblems...),
+	}
+
+	doc.Add(http.MethodGet, RootPath+GetAttributeDefinitionRoute, &openapi.Operation{
+		OperationID: "getAttributeDefinition",
+		Summary:     "Returns definition of custom user attribute",
+		Tags:        []string{"attributes"},
+		Responses: responses(
+			"200", doc.JSON("Attribute definition", response.AttributeDefinition{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("attribute definition"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodPost, RootPath+CreateAttributeDefinitionRoute, &openapi.Operation{
+		OperationID: "createAttributeDefinition",
+		Summary:     "Defines custom user attribute",
+		Tags:        []string{"attributes"},
+		RequestBody: doc.RequestBody(request.CreateAttributeDefinition{}),
+		Responses: responses(
+			"201", doc.JSON("ID of created attribute definition", response.CreateAttributeDefinition{}),
+			doc.Errors(append(attributeDefinitionErrors,
+				httperrors.AttributeDefinitionAlreadyExists,
+				httperrors.InternalServerError,
+			)...),
+		),
+	})
+
+	doc.Add(http.MethodPut, RootPath+UpdateAttributeDefinitionRoute, &openapi.Operation{
+		OperationID: "updateAttributeDefinition",
+		Summary:     "Updates restrictions of custom user attribute, name and type can't be changed",
+		Tags:        []string{"attributes"},
+		RequestBody: doc.RequestBody(request.UpdateAttributeDefinition{}),
+		Responses: responses(
+			"200", doc.JSON("Attribute definition is updated", struct{}{}),
+			doc.Errors(append(attributeDefinitionErrors,
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("attribute definition"),
+				httperrors.InternalServerError,
+			)...),
+		),
+	})
+
+	doc.Add(http.MethodDelete, RootPath+DeleteAttributeDefinitionRoute, &openapi.Operation{
+		OperationID: "deleteAttributeDefinition",
+		Summary:     "Deletes definition of custom user attribute which no user has",
+		Tags:        []string{"attributes"},
+		Responses: responses(
+			"200", doc.JSON("Attribute definition is deleted", struct{}{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("attribute definition"),
+				httperrors.AttributeDefinitionInUse,
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodGet, RootPath+GetAttributeDefinitionListRoute, &openapi.Operation{
+		OperationID: "getAttributeDefinitions",
+		Summary:     "Returns definitions of all custom user attributes",
+		Tags:        []string{"attributes"},
+		Responses: responses(
+			"200", doc.JSON("Attribute definitions", response.AttributeDefinitionList{}),
+			doc.Errors(httperrors.InternalServerError),
+		),
+	})
+
+	var groupProblems httperrors.Details
+	groupProblems.Add("/name", httperrors.GroupNameEmpty)
+	groupErrors := []*httperrors.HTTPError{
+		httperrors.RequestBodyParsingError,
+		schemaViolation("name", "string"),
+		httperrors.RequestValidationFailed.WithDetails(groupProblems...),
+		httperrors.GroupAlreadyExists,
+	}
+
+	doc.Add(http.MethodGet, RootPath+GetGroupRoute, &openapi.Operation{
+		OperationID: "getGroup",
+		Summary:     "Returns group of users",
+		Tags:        []string{"groups"},
+		Responses: responses(
+			"200", doc.JSON("Group", response.Group{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("group"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodPost, RootPath+CreateGroupRoute, &openapi.Operation{
+		OperationID: "createGroup",
+		Summary:     "Creates group of users, its name is used as a tag of members",
+		Tags:        []string{"groups"},
+		RequestBody: doc.RequestBody(request.CreateGroup{}),
+		Responses: responses(
+			"201", doc.JSON("ID of created group", response.CreateGroup{}),
+			doc.Errors(append(groupErrors, httperrors.InternalServerError)...),
+		),
+	})
+
+	doc.Add(http.MethodPut, RootPath+UpdateGroupRoute, &openapi.Operation{
+		OperationID: "updateGroup",
+		Summary:     "Updates group of users",
+		Tags:        []string{"groups"},
+		RequestBody: doc.RequestBody(request.UpdateGroup{}),
+		Responses: responses(
+			"200", doc.JSON("Group is updated", struct{}{}),
+			doc.Errors(append(groupErrors,
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("group"),
+				httperrors.InternalServerError,
+			)...),
+		),
+	})
+
+	doc.Add(http.MethodDelete, RootPath+DeleteGroupRoute, &openapi.Operation{
+		OperationID: "deleteGroup",
+		Summary:     "Deletes group of users, its members are not deleted",
+		Tags:        []string{"groups"},
+		Responses: responses(
+			"200", doc.JSON("Group is deleted", struct{}{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.EntityNotFoundError("group"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	doc.Add(http.MethodGet, RootPath+GetGroupListRoute, &openapi.Operation{
+		OperationID: "getGroups",
+		Summary:     "Returns all groups of users",
+		Tags:        []string{"groups"},
+		Responses: responses(
+			"200", doc.JSON("Groups", response.GroupList{}),
+			doc.Errors(httperrors.InternalServerError),
+		),
+	})
+
+	doc.Add(http.MethodGet, RootPath+GetGroupMemberListRoute, &openapi.Operation{
+		OperationID: "findGroupMembers",
+		Summary:     "Returns members of the group with keyset pagination",
+		Tags:        []string{"groups"},
+		Parameters:  doc.QueryParameters(request.FindGroupMembers{}),
+		Responses: responses(
+			"200", doc.JSON("Page of members with links to the previous and the next page",
+				response.UserListWithPagination{}),
+			doc.Errors(
+				httperrors.PathParametersParsingError,
+				httperrors.QueryParametersParsingError,
+				schemaViolation("limit", "integer"),
+				httperrors.RequestValidationFailed.WithDetails(paginationProblems...),
+				httperrors.EntityNotFoundError("group"),
+				httperrors.InternalServerError,
+			),
+		),
+	})
+
+	var groupMembersProblems httperrors.Details
+	groupMembersProblems.Add("/user_ids", httperrors.GroupMembersEmpty)
+	groupMembersProblems.Add("/user_ids", httperrors.GroupMembersTooMany(1000))
+	groupMembersProblems.Add("/user_ids/0", httperrors.GroupMemberIDIncorrect)
+	groupMembersErrors := []*httperrors.HTTPError{
+		httperrors.PathParametersParsingError,
+		httperrors.RequestBodyParsingError,
+		schemaViolation("user_ids", "array"),
+		httperrors.RequestValidationFailed.WithDetails(groupMembersProblems...),
+		httperrors.EntityNotFoundError("group"),
+		httperrors.InternalServerError,
+	}
+
+	doc.Add(http.MethodPost, RootPath+AddGroupMembersRoute, &openapi.Operation{
+		OperationID: "addGroupMembers",
+		Summary:     "Adds users to the group, unknown users and existing members are skipped",
+		Tags:        []string{"groups"},
+		RequestBody: doc.RequestBody(request.GroupMembers{}),
+		Responses: responses(
+			"200", doc.JSON("Number of added members", response.GroupMembersChange{}),
+			doc.Errors(groupMembersErrors...),
+		),
+	})
+
+	doc.Add(http.MethodDelete, RootPath+RemoveGroupMembersRoute, &openapi.Operation{
+		OperationID: "removeGroupMembers",
+		Summary:     "Removes users from the group, users which are not members are skipped",
+		Tags:        []string{"groups"},
+		RequestBody: doc.RequestBody(request.GroupMembers{}),
+		Responses: responses(
+			"200", doc.JSON("Number of removed members", response.GroupMembersChange{}),
+			doc.Errors(groupMembersErrors...),
+		),
+	})
+
+	doc.Add(http.MethodGet, DebugVarsRoute, &openapi.Operation{
+		OperationID: "getDebugVars",
+		Summary:     "Returns user cache hits and misses",
+		Tags:        []string{"debug"},
+		Responses: map[string]*openapi.Response{
+			"200": doc.JSON("Counters", response.DebugVars{}),
+		},
+	})
+
+	doc.Add(http.MethodGet, OpenAPIRoute, &openapi.Operation{
+		OperationID: "getOpenAPI",
+		Summary:     "Returns this document",
+		Tags:        []string{"docs"},
+		Responses: map[string]*openapi.Response{
+			"200": doc.JSON("OpenAPI document", map[string]interface{}{}),
+		},
+	})
+
+	doc.Add(http.MethodGet, SwaggerUIRoute, &openapi.Operation{
+		OperationID: "getSwaggerUI",
+		Summary:     "Returns Swagger UI which renders this document",
+		Tags:        []string{"docs"},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "HTML page",
+				Content: map[string]*openapi.MediaType{
+					"text/html": {Schema: &openapi.Schema{Type: "string"}},
+				},
+			},
+		},
+	})
+
+	for _, asset := range []struct{ route, operationID, contentType string }{
+		{SwaggerUICSSRoute, "getSwaggerUICSS", "text/css"},
+		{SwaggerUIJSRoute, "getSwaggerUIJS", "application/javascript"},
+	} {
+		doc.Add(http.MethodGet, asset.route, &openapi.Operation{
+			OperationID: asset.operationID,
+			Summary:     "Returns asset of Swagger UI embedded in the binary",
+			Tags:        []string{"docs"},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Asset of swagger-ui-dist package",
+					Content: map[string]*openapi.MediaType{
+						asset.contentType: {Schema: &openapi.Schema{Type: "string"}},
+					},
+				},
+				"404": {Description: "Assets are not embedded, Swagger UI loads them from CDN"},
+			},
+		})
+	}
+
+	return doc
+}
+
+// openAPIHandlers returns handlers which serve OpenAPI document and Swagger UI.
+func openAPIHandlers(doc *openapi.Document) (spec gin.HandlerFunc, ui gin.HandlerFunc) {
+	page := openapi.SwaggerUI(apiTitle, OpenAPIRoute, SwaggerUIRoute)
+
+	spec = func(context *gin.Context) {
+		context.JSON(http.StatusOK, doc)
+	}
+	ui = func(context *gin.Context) {
+		context.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	}
+
+	return spec, ui
+}
+
+// swaggerUIAsset returns handler which serves embedded file of swagger-ui-dist package.
+func swaggerUIAsset(name string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		content, contentType, ok := openapi.SwaggerUIAsset(name)
+		if !ok {
+			context.Status(http.StatusNotFound)
+			return
+		}
+		context.Data(http.StatusOK, contentType, content)
+	}
+}
+
+// responses merges success response with error responses.
+func responses(status string, success *openapi.Response, errors map[string]*openapi.Response,
+) map[string]*openapi.Response {
+	errors[status] = success
+	return errors
+}
+
+// replayable documents header of responses replayed for the same `Idempotency-Key`.
+func replayable(response *openapi.Response) *openapi.Response {
+	response.Headers = map[string]*openapi.Header{
+		middleware.IdempotentReplayedHeader: {
+			Description: "`true` when the response is replayed for repeated `Idempotency-Key`",
+			Schema:      &openapi.Schema{Type: "string"},
+		},
+	}
+	return response
+}
+
+// schemaViolation returns example of RequestSchemaViolation for the field of wrong type.
+func schemaViolation(field, expected string) *httperrors.HTTPError {
+	var details httperrors.Details
+	details.Add("/"+field, httperrors.SchemaTypeMismatch(field, expected))
+	return httperrors.RequestSchemaViolation.WithDetails(details...)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
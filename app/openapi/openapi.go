@@ -0,0 +1,349 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+// Version of OpenAPI specification used by Document.
+const Version = "3.0.3"
+
+// schemaRefPrefix is a prefix of references to component schemas.
+const schemaRefPrefix = "#/components/schemas/"
+
+var (
+	pathParamRegex = regexp.MustCompile(`:([a-z_]+)`)
+	timeType       = reflect.TypeOf(time.Time{})
+	durationType   = reflect.TypeOf(time.Duration(0))
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Document represents OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info represents metadata of the API.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem represents operations available on a single path.
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+// Components holds reusable schemas.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Operation describes single API operation on a path.
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter describes single path, query or header parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes body of the request.
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response describes single response of the operation.
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// Header describes header of the response.
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType describes content of request or response body.
+type MediaType struct {
+	Schema   *Schema             `json:"schema"`
+	Examples map[string]*Example `json:"examples,omitempty"`
+}
+
+// Example represents example value of the content.
+type Example struct {
+	Summary string      `json:"summary,omitempty"`
+	Value   interface{} `json:"value"`
+}
+
+// Schema represents subset of JSON schema used by OpenAPI.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Deprecated           bool               `json:"deprecated,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+}
+
+// New creates new instance of Document.
+func New(title, version string) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       Info{Title: title, Version: version},
+		Paths:      make(map[string]*PathItem),
+		Components: Components{Schemas: make(map[string]*Schema)},
+	}
+}
+
+// Add adds operation on the path in gin format, e.g. /users/:user_id.
+// Path parameters are added to the operation as integers.
+func (d *Document) Add(method, path string, operation *Operation) {
+	var params []*Parameter
+	for _, match := range pathParamRegex.FindAllStringSubmatch(path, -1) {
+		params = append(params, &Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "integer"},
+		})
+	}
+	operation.Parameters = append(params, operation.Parameters...)
+
+	path = PathFromRoute(path)
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+
+	switch method {
+	case http.MethodGet:
+		item.Get = operation
+	case http.MethodPut:
+		item.Put = operation
+	case http.MethodPost:
+		item.Post = operation
+	case http.MethodDelete:
+		item.Delete = operation
+	}
+}
+
+// Operations returns operations of the path by HTTP method.
+func (p *PathItem) Operations() map[string]*Operation {
+	operations := make(map[string]*Operation)
+	for method, operation := range map[string]*Operation{
+		http.MethodGet:    p.Get,
+		http.MethodPut:    p.Put,
+		http.MethodPost:   p.Post,
+		http.MethodDelete: p.Delete,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+
+	return operations
+}
+
+// PathFromRoute converts gin route to OpenAPI path, e.g. /users/:user_id to /users/{user_id}.
+func PathFromRoute(route string) string {
+	return pathParamRegex.ReplaceAllString(route, "{$1}")
+}
+
+// RequestBody returns JSON request body with schema of the struct.
+// Unknown properties are not allowed.
+func (d *Document) RequestBody(v interface{}) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content: map[string]*MediaType{
+			"application/json": {Schema: d.schemaOf(reflect.TypeOf(v), true)},
+		},
+	}
+}
+
+// JSON returns JSON response with schema of the struct.
+func (d *Document) JSON(description string, v interface{}) *Response {
+	return &Response{
+		Description: description,
+		Content: map[string]*MediaType{
+			"application/json": {Schema: d.schemaOf(reflect.TypeOf(v), false)},
+		},
+	}
+}
+
+// QueryParameters returns query parameters of the struct bound by gin form tags.
+func (d *Document) QueryParameters(v interface{}) []*Parameter {
+	t := reflect.TypeOf(v)
+	params := make([]*Parameter, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("form")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		schema := d.schemaOf(field.Type, true)
+		params = append(params, &Parameter{
+			Name:        name,
+			In:          "query",
+			Description: field.Tag.Get("description"),
+			Schema:      schema,
+		})
+	}
+
+	return params
+}
+
+// Errors returns error responses grouped by HTTP status with every error as an example.
+// Errors can be also returned as RFC 7807 problem details.
+func (d *Document) Errors(errs ...*httperrors.HTTPError) map[string]*Response {
+	schema := d.schemaOf(reflect.TypeOf(httperrors.HTTPError{}), false)
+	problem := d.schemaOf(reflect.TypeOf(httperrors.Problem{}), false)
+	responses := make(map[string]*Response)
+	for _, err := range errs {
+		status := strconv.Itoa(err.HTTPCode)
+		response, ok := responses[status]
+		if !ok {
+			response = &Response{
+				Description: http.StatusText(err.HTTPCode),
+				Content: map[string]*MediaType{
+					"application/json": {
+						Schema:   schema,
+						Examples: make(map[string]*Example),
+					},
+					httperrors.ProblemContentType: {Schema: problem},
+				},
+			}
+			responses[status] = response
+		}
+
+		response.Content["application/json"].Examples[strconv.Itoa(err.Code)] = &Example{
+			Summary: err.Message,
+			Value:   err,
+		}
+	}
+
+	return responses
+}
+
+// schemaOf returns schema of the type, structs are registered as components and referenced.
+// Request structs do not allow unknown properties, all properties of response structs
+// without omitempty option are required.
+func (d *Document) schemaOf(t reflect.Type, request bool) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "string", Format: "duration", Description: "Go duration, e.g. `30s`"}
+	case rawMessageType:
+		return &Schema{Description: "any JSON value"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := d.schemaOf(t.Elem(), request)
+		if schema.Ref != "" {
+			return schema
+		}
+		nullable := *schema
+		nullable.Nullable = true
+		return &nullable
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Slice:
+		return &Schema{Type: "array", Items: d.schemaOf(t.Elem(), request)}
+	case reflect.Struct:
+		return d.structSchema(t, request)
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	}
+
+	return &Schema{}
+}
+
+// structSchema registers schema of named struct as component and returns reference to it.
+// Schema of anonymous struct is returned inline.
+func (d *Document) structSchema(t reflect.Type, request bool) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	ref := schema
+	if name := t.Name(); name != "" {
+		if request {
+			name += "Request"
+		}
+		ref = &Schema{Ref: schemaRefPrefix + name}
+		if _, ok := d.Components.Schemas[name]; ok {
+			return ref
+		}
+		d.Components.Schemas[name] = schema
+	}
+	if request {
+		additional := false
+		schema.AdditionalProperties = &additional
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		if tag[0] == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		property := d.schemaOf(field.Type, request)
+		description := field.Tag.Get("description")
+		deprecated := field.Tag.Get("deprecated") == "true"
+		if description != "" || deprecated {
+			described := *property
+			described.Description = description
+			described.Deprecated = deprecated
+			property = &described
+		}
+		schema.Properties[tag[0]] = property
+
+		if !request && !(len(tag) > 1 && tag[1] == "omitempty") {
+			schema.Required = append(schema.Required, tag[0])
+		}
+	}
+	sort.Strings(schema.Required)
+
+	return ref
+}
@@ -0,0 +1,61 @@
+package openapi
+
+//go:generate go run ui_gen.go -dir ../../build/swagger-ui-dist
+
+import (
+	"fmt"
+	"html"
+	"mime"
+	"path"
+)
+
+// swaggerUIVersion is a version of swagger-ui-dist package used by the UI page, see `make swagger_ui`.
+const swaggerUIVersion = "4.15.5"
+
+// Files of swagger-ui-dist package used by the UI page.
+const (
+	SwaggerUICSS = "swagger-ui.css"
+	SwaggerUIJS  = "swagger-ui-bundle.js"
+)
+
+// SwaggerUIEmbedded reports whether swagger-ui-dist assets are embedded in the binary.
+func SwaggerUIEmbedded() bool {
+	_, css := swaggerUIAssets[SwaggerUICSS]
+	_, js := swaggerUIAssets[SwaggerUIJS]
+	return css && js
+}
+
+// SwaggerUIAsset returns embedded file of swagger-ui-dist package and its content type.
+func SwaggerUIAsset(name string) ([]byte, string, bool) {
+	content, ok := swaggerUIAssets[name]
+	if !ok {
+		return nil, "", false
+	}
+
+	return []byte(content), mime.TypeByExtension(path.Ext(name)), true
+}
+
+// SwaggerUI returns HTML page with Swagger UI which renders the document from specURL.
+// Assets are served from assetsURL when they are embedded, otherwise the browser loads them from unpkg CDN.
+func SwaggerUI(title, specURL, assetsURL string) string {
+	if !SwaggerUIEmbedded() {
+		assetsURL = "https://unpkg.com/swagger-ui-dist@" + swaggerUIVersion
+	}
+
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%[1]s</title>
+  <link rel="stylesheet" href="%[2]s/%[3]s">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%[2]s/%[4]s"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: %[5]q, dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`, html.EscapeString(title), assetsURL, SwaggerUICSS, SwaggerUIJS, specURL)
+}
@@ -0,0 +1,43 @@
+// +build ignore
+
+// ui_gen.go embeds files of swagger-ui-dist package into ui_assets.go, it is run by `make swagger_ui`.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+)
+
+var assets = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+func main() {
+	dir := flag.String("dir", "", "directory with extracted swagger-ui-dist package")
+	flag.Parse()
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by ui_gen.go; DO NOT EDIT.\n\npackage openapi\n\n")
+	buf.WriteString("// swaggerUIAssets are files of swagger-ui-dist package by name.\n")
+	buf.WriteString("var swaggerUIAssets = map[string]string{\n")
+	for _, name := range assets {
+		content, err := ioutil.ReadFile(filepath.Join(*dir, name))
+		if err != nil {
+			log.Fatalf("impossible to read swagger-ui-dist asset: %v", err)
+		}
+		fmt.Fprintf(&buf, "\t%q: %q,\n", name, content)
+	}
+	buf.WriteString("}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("impossible to format generated source: %v", err)
+	}
+
+	if err := ioutil.WriteFile("ui_assets.go", source, 0644); err != nil {
+		log.Fatalf("impossible to write ui_assets.go: %v", err)
+	}
+}
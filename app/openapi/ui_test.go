@@ -0,0 +1,51 @@
+// +build unit
+
+package openapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwaggerUIAssetsAreCommitted(t *testing.T) {
+	assert.True(t, SwaggerUIEmbedded(), "regenerate ui_assets.go with `make swagger_ui`")
+
+	css, _, _ := SwaggerUIAsset(SwaggerUICSS)
+	assert.True(t, strings.HasPrefix(string(css), ".swagger-ui{"), "swagger-ui.css is not a stylesheet of Swagger UI")
+
+	js, _, _ := SwaggerUIAsset(SwaggerUIJS)
+	assert.Contains(t, string(js), "SwaggerUIBundle")
+	assert.Contains(t, string(js), `"`+swaggerUIVersion+`"`, "swaggerUIVersion differs from version of embedded assets")
+}
+
+func TestSwaggerUIServesEmbeddedAssets(t *testing.T) {
+	defer func(assets map[string]string) { swaggerUIAssets = assets }(swaggerUIAssets)
+	swaggerUIAssets = map[string]string{
+		SwaggerUICSS: "body{}",
+		SwaggerUIJS:  "var SwaggerUIBundle;",
+	}
+
+	page := SwaggerUI("API", "/openapi.json", "/docs")
+	assert.Contains(t, page, `href="/docs/swagger-ui.css"`)
+	assert.Contains(t, page, `src="/docs/swagger-ui-bundle.js"`)
+	assert.NotContains(t, page, "unpkg.com")
+
+	content, contentType, ok := SwaggerUIAsset(SwaggerUIJS)
+	assert.True(t, ok)
+	assert.Equal(t, "var SwaggerUIBundle;", string(content))
+	assert.Contains(t, contentType, "javascript")
+
+	_, _, ok = SwaggerUIAsset("index.html")
+	assert.False(t, ok)
+}
+
+func TestSwaggerUIWithoutEmbeddedAssets(t *testing.T) {
+	defer func(assets map[string]string) { swaggerUIAssets = assets }(swaggerUIAssets)
+	swaggerUIAssets = map[string]string{}
+
+	page := SwaggerUI("API", "/openapi.json", "/docs")
+	assert.Contains(t, page, `href="https://unpkg.com/swagger-ui-dist@`+swaggerUIVersion+`/swagger-ui.css"`)
+	assert.False(t, SwaggerUIEmbedded())
+}
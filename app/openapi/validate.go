@@ -0,0 +1,280 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of violations found by validation.
+const (
+	ViolationUnknownProperty = "unknown_property"
+	ViolationType            = "type"
+	ViolationMaxLength       = "max_length"
+	ViolationRepeated        = "repeated"
+)
+
+// Violation describes a value which does not match the schema.
+// Pointer is JSON pointer of the value, query parameters are addressed as properties of an object.
+// Name is human readable name of the value, e.g. `items.0.name`, Expected is expected type of the value
+// and MaxLength is maximum length of the string.
+type Violation struct {
+	Kind      string
+	Pointer   string
+	Name      string
+	Expected  string
+	MaxLength int
+	Message   string
+}
+
+// Operation returns operation of the method on the path or nil when it is not documented.
+func (d *Document) Operation(method, path string) *Operation {
+	item, ok := d.Paths[path]
+	if !ok {
+		return nil
+	}
+
+	return item.Operations()[method]
+}
+
+// ValidateQuery validates query string against query parameters of the operation.
+// Values are validated as strings converted to the type of parameter schema,
+// only array parameters can be passed more than once.
+// Parameter with name ending with * matches every parameter with the prefix, e.g. attr.* matches attr.department.
+func (d *Document) ValidateQuery(operation *Operation, query url.Values) []Violation {
+	params := make(map[string]*Parameter)
+	var wildcards []*Parameter
+	for _, param := range operation.Parameters {
+		if param.In != "query" {
+			continue
+		}
+
+		if strings.HasSuffix(param.Name, "*") {
+			wildcards = append(wildcards, param)
+		} else {
+			params[param.Name] = param
+		}
+	}
+
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []Violation
+	for _, name := range names {
+		pointer := "/" + escapePointer(name)
+		param, ok := params[name]
+		if !ok {
+			param = matchWildcard(wildcards, name)
+		}
+
+		if param == nil {
+			violations = append(violations, unknownViolation(pointer))
+			continue
+		}
+
+		values := query[name]
+		if param.Schema.Type == "array" {
+			for i, value := range values {
+				if !matchesQueryType(param.Schema.Items, value) {
+					violations = append(violations, typeViolation(pointer+"/"+strconv.Itoa(i), param.Schema.Items))
+				}
+			}
+			continue
+		}
+
+		if len(values) > 1 {
+			violations = append(violations, Violation{
+				Kind:    ViolationRepeated,
+				Pointer: pointer,
+				Name:    name,
+				Message: fmt.Sprintf("`%s` has to be passed once", name),
+			})
+			continue
+		}
+
+		if !matchesQueryType(param.Schema, values[0]) {
+			violations = append(violations, typeViolation(pointer, param.Schema))
+		}
+	}
+
+	return violations
+}
+
+// ValidateBody validates JSON value decoded with json.Number numbers against request body schema of the operation.
+func (d *Document) ValidateBody(operation *Operation, value interface{}) []Violation {
+	if operation.RequestBody == nil {
+		return nil
+	}
+
+	return d.validate(operation.RequestBody.Content["application/json"].Schema, value, "")
+}
+
+func (d *Document) validate(schema *Schema, value interface{}, pointer string) []Violation {
+	schema = d.resolve(schema)
+	if value == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return []Violation{typeViolation(pointer, schema)}
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []Violation{typeViolation(pointer, schema)}
+		}
+		return d.validateObject(schema, object, pointer)
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return []Violation{typeViolation(pointer, schema)}
+		}
+		var violations []Violation
+		for i, item := range array {
+			violations = append(violations, d.validate(schema.Items, item, pointer+"/"+strconv.Itoa(i))...)
+		}
+		return violations
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return []Violation{typeViolation(pointer, schema)}
+		}
+		if schema.MaxLength != nil && len([]rune(s)) > *schema.MaxLength {
+			return []Violation{{
+				Kind:      ViolationMaxLength,
+				Pointer:   pointer,
+				Name:      pointerName(pointer),
+				MaxLength: *schema.MaxLength,
+				Message:   fmt.Sprintf("`%s` has to be at most %d characters long", pointerName(pointer), *schema.MaxLength),
+			}}
+		}
+	case "integer":
+		n, ok := value.(json.Number)
+		if _, err := n.Int64(); !ok || err != nil {
+			return []Violation{typeViolation(pointer, schema)}
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return []Violation{typeViolation(pointer, schema)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []Violation{typeViolation(pointer, schema)}
+		}
+	}
+
+	return nil
+}
+
+func (d *Document) validateObject(schema *Schema, object map[string]interface{}, pointer string) []Violation {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []Violation
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+		if !ok {
+			if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+				violations = append(violations, unknownViolation(pointer+"/"+escapePointer(name)))
+			}
+			continue
+		}
+		violations = append(violations, d.validate(property, object[name], pointer+"/"+escapePointer(name))...)
+	}
+
+	return violations
+}
+
+// resolve returns schema referenced by $ref.
+func (d *Document) resolve(schema *Schema) *Schema {
+	if schema.Ref == "" {
+		return schema
+	}
+
+	if resolved, ok := d.Components.Schemas[strings.TrimPrefix(schema.Ref, schemaRefPrefix)]; ok {
+		return resolved
+	}
+
+	return &Schema{}
+}
+
+// matchWildcard returns wildcard parameter matching the name or nil when there is none.
+func matchWildcard(wildcards []*Parameter, name string) *Parameter {
+	for _, param := range wildcards {
+		prefix := strings.TrimSuffix(param.Name, "*")
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return param
+		}
+	}
+
+	return nil
+}
+
+func matchesQueryType(schema *Schema, value string) bool {
+	var err error
+	switch {
+	case schema.Type == "integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case schema.Type == "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case schema.Type == "boolean":
+		_, err = strconv.ParseBool(value)
+	case schema.Format == "duration":
+		_, err = time.ParseDuration(value)
+	}
+
+	return err == nil
+}
+
+func typeViolation(pointer string, schema *Schema) Violation {
+	expected := schema.Type
+	switch {
+	case schema.Format == "duration":
+		expected = "duration"
+	case expected == "":
+		expected = "any"
+	}
+
+	return Violation{
+		Kind:     ViolationType,
+		Pointer:  pointer,
+		Name:     pointerName(pointer),
+		Expected: expected,
+		Message:  fmt.Sprintf("`%s` has to be of type %s", pointerName(pointer), expected),
+	}
+}
+
+func unknownViolation(pointer string) Violation {
+	return Violation{
+		Kind:    ViolationUnknownProperty,
+		Pointer: pointer,
+		Name:    pointerName(pointer),
+		Message: fmt.Sprintf("unknown field `%s`", pointerName(pointer)),
+	}
+}
+
+// pointerName returns human readable name of the value addressed by JSON pointer.
+func pointerName(pointer string) string {
+	if pointer == "" {
+		return "body"
+	}
+
+	name := strings.Replace(strings.TrimPrefix(pointer, "/"), "/", ".", -1)
+	return strings.Replace(strings.Replace(name, "~1", "/", -1), "~0", "~", -1)
+}
+
+// escapePointer escapes reference token of JSON pointer.
+func escapePointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
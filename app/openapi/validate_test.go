@@ -0,0 +1,210 @@
+// +build unit
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	Name string `json:"name"`
+}
+
+type testBody struct {
+	Name   string     `json:"name"`
+	Age    int        `json:"age" deprecated:"true"`
+	Active *bool      `json:"active"`
+	Tags   []string   `json:"tags"`
+	Items  []testItem `json:"items"`
+}
+
+type testQuery struct {
+	Limit int           `form:"limit"`
+	Wait  time.Duration `form:"wait"`
+	Name  string        `form:"name"`
+}
+
+func newTestDocument() (*Document, *Operation) {
+	doc := New("test", "1.0.0")
+	operation := &Operation{
+		Parameters:  doc.QueryParameters(testQuery{}),
+		RequestBody: doc.RequestBody(testBody{}),
+	}
+	doc.Add(http.MethodPost, "/tests/:test_id", operation)
+
+	return doc, operation
+}
+
+func decode(t *testing.T, body string) interface{} {
+	var value interface{}
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&value))
+	return value
+}
+
+func TestDocument_Operation(t *testing.T) {
+	doc, operation := newTestDocument()
+
+	assert.Equal(t, operation, doc.Operation(http.MethodPost, "/tests/{test_id}"))
+	assert.Nil(t, doc.Operation(http.MethodGet, "/tests/{test_id}"))
+	assert.Nil(t, doc.Operation(http.MethodPost, "/unknown"))
+}
+
+func TestDocument_DeprecatedProperty(t *testing.T) {
+	doc, _ := newTestDocument()
+
+	schema := doc.Components.Schemas["testBodyRequest"]
+	require.NotNil(t, schema)
+	assert.True(t, schema.Properties["age"].Deprecated)
+	assert.False(t, schema.Properties["name"].Deprecated)
+}
+
+func TestDocument_ValidateBody(t *testing.T) {
+	doc, operation := newTestDocument()
+
+	tests := []struct {
+		name     string
+		body     string
+		expected []Violation
+	}{
+		{
+			name: "valid",
+			body: `{"name":"John","age":30,"active":null,"tags":["a"],"items":[{"name":"x"}]}`,
+		},
+		{
+			name: "unknown fields",
+			body: `{"name":"John","zip":"00-001","items":[{"name":"x","color":"red"}]}`,
+			expected: []Violation{
+				{Kind: ViolationUnknownProperty, Pointer: "/items/0/color", Name: "items.0.color", Message: "unknown field `items.0.color`"},
+				{Kind: ViolationUnknownProperty, Pointer: "/zip", Name: "zip", Message: "unknown field `zip`"},
+			},
+		},
+		{
+			name: "wrong types",
+			body: `{"name":1,"age":"30","active":"yes","tags":"a","items":[{"name":"x"},1]}`,
+			expected: []Violation{
+				{Kind: ViolationType, Pointer: "/active", Name: "active", Expected: "boolean", Message: "`active` has to be of type boolean"},
+				{Kind: ViolationType, Pointer: "/age", Name: "age", Expected: "integer", Message: "`age` has to be of type integer"},
+				{Kind: ViolationType, Pointer: "/items/1", Name: "items.1", Expected: "object", Message: "`items.1` has to be of type object"},
+				{Kind: ViolationType, Pointer: "/name", Name: "name", Expected: "string", Message: "`name` has to be of type string"},
+				{Kind: ViolationType, Pointer: "/tags", Name: "tags", Expected: "array", Message: "`tags` has to be of type array"},
+			},
+		},
+		{
+			name: "fraction is not an integer",
+			body: `{"age":30.5}`,
+			expected: []Violation{
+				{Kind: ViolationType, Pointer: "/age", Name: "age", Expected: "integer", Message: "`age` has to be of type integer"},
+			},
+		},
+		{
+			name: "null of not nullable field",
+			body: `{"name":null}`,
+			expected: []Violation{
+				{Kind: ViolationType, Pointer: "/name", Name: "name", Expected: "string", Message: "`name` has to be of type string"},
+			},
+		},
+		{
+			name: "not an object",
+			body: `[]`,
+			expected: []Violation{
+				{Kind: ViolationType, Pointer: "", Name: "body", Expected: "object", Message: "`body` has to be of type object"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, doc.ValidateBody(operation, decode(t, tt.body)))
+		})
+	}
+}
+
+func TestDocument_ValidateBody_MaxLength(t *testing.T) {
+	doc := New("test", "1.0.0")
+	maxLength := 3
+	operation := &Operation{
+		RequestBody: &RequestBody{Content: map[string]*MediaType{
+			"application/json": {Schema: &Schema{Type: "object", Properties: map[string]*Schema{
+				"code": {Type: "string", MaxLength: &maxLength},
+			}}},
+		}},
+	}
+
+	assert.Empty(t, doc.ValidateBody(operation, decode(t, `{"code":"żół"}`)))
+	assert.Equal(t, []Violation{
+		{
+			Kind:      ViolationMaxLength,
+			Pointer:   "/code",
+			Name:      "code",
+			MaxLength: 3,
+			Message:   "`code` has to be at most 3 characters long",
+		},
+	}, doc.ValidateBody(operation, decode(t, `{"code":"abcd"}`)))
+}
+
+func TestDocument_ValidateQuery(t *testing.T) {
+	doc, operation := newTestDocument()
+	operation.Parameters = append(operation.Parameters, &Parameter{
+		Name:   "attr.*",
+		In:     "query",
+		Schema: &Schema{Type: "string"},
+	})
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []Violation
+	}{
+		{
+			name:  "valid",
+			query: "limit=10&wait=5s&name=John",
+		},
+		{
+			name:  "unknown parameter",
+			query: "limit=10&offset=20",
+			expected: []Violation{
+				{Kind: ViolationUnknownProperty, Pointer: "/offset", Name: "offset", Message: "unknown field `offset`"},
+			},
+		},
+		{
+			name:  "wrong types",
+			query: "limit=ten&wait=5&name=John",
+			expected: []Violation{
+				{Kind: ViolationType, Pointer: "/limit", Name: "limit", Expected: "integer", Message: "`limit` has to be of type integer"},
+				{Kind: ViolationType, Pointer: "/wait", Name: "wait", Expected: "duration", Message: "`wait` has to be of type duration"},
+			},
+		},
+		{
+			name:  "wildcard parameter",
+			query: "attr.department=sales&attr.=empty",
+			expected: []Violation{
+				{Kind: ViolationUnknownProperty, Pointer: "/attr.", Name: "attr.", Message: "unknown field `attr.`"},
+			},
+		},
+		{
+			name:  "repeated parameter",
+			query: "name=John&name=Jane",
+			expected: []Violation{
+				{Kind: ViolationRepeated, Pointer: "/name", Name: "name", Message: "`name` has to be passed once"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, doc.ValidateQuery(operation, query))
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// +build unit
+
+package app
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/config"
+	"github.com/mmgopher/user-service/app/controller"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/openapi"
+)
+
+// openAPIFile is OpenAPI document published for API clients.
+var openAPIFile = filepath.Join("..", "docs", "openapi.json")
+
+var updateOpenAPI = flag.Bool("update-openapi", false, "rewrite docs/openapi.json with current document")
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return NewRouter(&config.Config{}, controller.New(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil), nil, nil)
+}
+
+func TestOpenAPIDescribesEveryRoute(t *testing.T) {
+	var routes []string
+	for _, route := range newTestRouter().Routes() {
+		routes = append(routes, route.Method+" "+openapi.PathFromRoute(route.Path))
+	}
+
+	var operations []string
+	for path, item := range NewOpenAPI().Paths {
+		for method := range item.Operations() {
+			operations = append(operations, method+" "+path)
+		}
+	}
+
+	sort.Strings(routes)
+	sort.Strings(operations)
+	assert.Equal(t, routes, operations)
+}
+
+// TestOpenAPIFileIsUpToDate fails when request or response structs, error codes or routes change
+// without updating the published document. Run the test with -update-openapi flag to rewrite it.
+func TestOpenAPIFileIsUpToDate(t *testing.T) {
+	current, err := json.MarshalIndent(NewOpenAPI(), "", "  ")
+	require.Nil(t, err)
+	current = append(current, '\n')
+
+	if *updateOpenAPI {
+		require.Nil(t, ioutil.WriteFile(openAPIFile, current, 0644))
+	}
+
+	published, err := ioutil.ReadFile(openAPIFile)
+	require.Nil(t, err)
+	assert.Equal(t, string(published), string(current),
+		"docs/openapi.json is outdated, run `go test -tags unit ./app -update-openapi`")
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	router := newTestRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, OpenAPIRoute, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc openapi.Document
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	assert.Contains(t, doc.Paths, "/v1/users/{user_id}")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, SwaggerUIRoute, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), `url: "/openapi.json"`)
+}
+
+type fakeCacheStats dao.CacheStats
+
+func (s fakeCacheStats) Stats() dao.CacheStats {
+	return dao.CacheStats(s)
+}
+
+func TestDebugVarsRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := controller.New(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	w := httptest.NewRecorder()
+	router := NewRouter(&config.Config{}, controller, nil, fakeCacheStats{Hits: 3, Misses: 1})
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, DebugVarsRoute, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_cache":{"hits":3,"misses":1}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router = NewRouter(&config.Config{}, controller, nil, nil)
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, DebugVarsRoute, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{}`, w.Body.String())
+}
@@ -1,16 +1,25 @@
 package app
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 
+	"github.com/mmgopher/user-service/app/api/response"
 	"github.com/mmgopher/user-service/app/config"
 	"github.com/mmgopher/user-service/app/controller"
+	"github.com/mmgopher/user-service/app/dao"
 	"github.com/mmgopher/user-service/app/middleware"
+	"github.com/mmgopher/user-service/app/openapi"
+	"github.com/mmgopher/user-service/app/service/idempotency"
 )
 
 // RootPath - current api version
 const RootPath = "/v1"
 
+// DebugVarsRoute exposes user cache hits and misses.
+const DebugVarsRoute = "/debug/vars"
+
 // supported route creators.
 const (
 	GetUserRoute     = "/users/:user_id"
@@ -18,20 +27,164 @@ const (
 	DeleteUserRoute  = "/users/:user_id"
 	CreateUserRoute  = "/users"
 	GetUserListRoute = "/users"
+	GetUserChanges   = "/users/changes"
+
+	RequestEmailVerificationRoute = "/users/:user_id/email/verify"
+	ConfirmEmailVerificationRoute = "/users/:user_id/email/verify/confirm"
+
+	ActivateUserRoute         = "/users/:user_id/activate"
+	SuspendUserRoute          = "/users/:user_id/suspend"
+	LockUserRoute             = "/users/:user_id/lock"
+	CloseUserRoute            = "/users/:user_id/close"
+	GetUserStatusHistoryRoute = "/users/:user_id/status-history"
+
+	LoginRoute                = "/auth/login"
+	LoginMFARoute             = "/auth/login/mfa"
+	RefreshTokenRoute         = "/auth/refresh"
+	PasswordResetRoute        = "/auth/password-reset"
+	ConfirmPasswordResetRoute = "/auth/password-reset/confirm"
+	ChangePasswordRoute       = "/users/:user_id/password"
+
+	EnrollMFARoute                  = "/users/:user_id/mfa/enroll"
+	ActivateMFARoute                = "/users/:user_id/mfa/activate"
+	DisableMFARoute                 = "/users/:user_id/mfa/disable"
+	RegenerateMFARecoveryCodesRoute = "/users/:user_id/mfa/recovery-codes"
+
+	GetSessionListRoute = "/users/:user_id/sessions"
+	RevokeSessionRoute  = "/users/:user_id/sessions/:session_id"
+	RevokeSessionsRoute = "/users/:user_id/sessions"
+
+	GetAddressRoute     = "/users/:user_id/addresses/:address_id"
+	UpdateAddressRoute  = "/users/:user_id/addresses/:address_id"
+	DeleteAddressRoute  = "/users/:user_id/addresses/:address_id"
+	CreateAddressRoute  = "/users/:user_id/addresses"
+	GetAddressListRoute = "/users/:user_id/addresses"
+
+	GetWebhookRoute             = "/webhooks/:webhook_id"
+	UpdateWebhookRoute          = "/webhooks/:webhook_id"
+	DeleteWebhookRoute          = "/webhooks/:webhook_id"
+	CreateWebhookRoute          = "/webhooks"
+	GetWebhookListRoute         = "/webhooks"
+	GetWebhookDeliveryListRoute = "/webhooks/:webhook_id/deliveries"
+
+	GetAttributeDefinitionRoute     = "/admin/attribute-definitions/:definition_id"
+	UpdateAttributeDefinitionRoute  = "/admin/attribute-definitions/:definition_id"
+	DeleteAttributeDefinitionRoute  = "/admin/attribute-definitions/:definition_id"
+	CreateAttributeDefinitionRoute  = "/admin/attribute-definitions"
+	GetAttributeDefinitionListRoute = "/admin/attribute-definitions"
+
+	GetGroupRoute           = "/groups/:group_id"
+	UpdateGroupRoute        = "/groups/:group_id"
+	DeleteGroupRoute        = "/groups/:group_id"
+	CreateGroupRoute        = "/groups"
+	GetGroupListRoute       = "/groups"
+	GetGroupMemberListRoute = "/groups/:group_id/members"
+	AddGroupMembersRoute    = "/groups/:group_id/members"
+	RemoveGroupMembersRoute = "/groups/:group_id/members"
 )
 
 // NewRouter initializes the gin router and routes.
-// config is currently not used but can be used to configure CORS
-func NewRouter(config *config.Config, controller *controller.Controller) *gin.Engine {
+// config selects format of error responses, it can be also used to configure CORS.
+// userCache is nil when user cache is disabled.
+func NewRouter(
+	config *config.Config,
+	controller *controller.Controller,
+	idempotencyService idempotency.Provider,
+	userCache dao.CacheStatsProvider,
+) *gin.Engine {
+
+	idempotent := middleware.Idempotency(idempotencyService)
+	doc := NewOpenAPI()
 
 	g := gin.Default()
-	v1 := g.Group(RootPath)
+	g.Use(middleware.ProblemDetails(config.ProblemErrors(), config.ErrorProblemTypeBaseURI))
+	g.GET(DebugVarsRoute, debugVars(userCache))
+	spec, ui := openAPIHandlers(doc)
+	g.GET(OpenAPIRoute, spec)
+	g.GET(SwaggerUIRoute, ui)
+	g.GET(SwaggerUICSSRoute, swaggerUIAsset(openapi.SwaggerUICSS))
+	g.GET(SwaggerUIJSRoute, swaggerUIAsset(openapi.SwaggerUIJS))
+	v1 := g.Group(RootPath, middleware.ValidateRequestSchema(doc, int64(config.RequestBodyMaxSize)))
 	{
 		v1.GET(GetUserRoute, middleware.ValidateUserID, controller.GetUser)
-		v1.POST(CreateUserRoute, controller.CreateUser)
-		v1.PUT(UpdateUserRoute, middleware.ValidateUserID, controller.UpdateUser)
+		v1.POST(CreateUserRoute, idempotent, controller.CreateUser)
+		v1.PUT(UpdateUserRoute, middleware.ValidateUserID, idempotent, controller.UpdateUser)
 		v1.DELETE(DeleteUserRoute, middleware.ValidateUserID, controller.DeleteUser)
 		v1.GET(GetUserListRoute, controller.GetUserList)
+		v1.GET(GetUserChanges, controller.GetUserChanges)
+		v1.POST(RequestEmailVerificationRoute, middleware.ValidateUserID, controller.RequestEmailVerification)
+		v1.POST(ConfirmEmailVerificationRoute, middleware.ValidateUserID, controller.ConfirmEmailVerification)
+		v1.POST(ActivateUserRoute, middleware.ValidateUserID, controller.ActivateUser)
+		v1.POST(SuspendUserRoute, middleware.ValidateUserID, controller.SuspendUser)
+		v1.POST(LockUserRoute, middleware.ValidateUserID, controller.LockUser)
+		v1.POST(CloseUserRoute, middleware.ValidateUserID, controller.CloseUser)
+		v1.GET(GetUserStatusHistoryRoute, middleware.ValidateUserID, controller.GetUserStatusHistory)
+
+		v1.POST(LoginRoute, controller.Login)
+		v1.POST(LoginMFARoute, controller.LoginMFA)
+		v1.POST(RefreshTokenRoute, controller.RefreshToken)
+		v1.POST(PasswordResetRoute, controller.RequestPasswordReset)
+		v1.POST(ConfirmPasswordResetRoute, controller.ConfirmPasswordReset)
+		v1.POST(ChangePasswordRoute, middleware.ValidateUserID, controller.ChangePassword)
+		v1.POST(EnrollMFARoute, middleware.ValidateUserID, controller.EnrollMFA)
+		v1.POST(ActivateMFARoute, middleware.ValidateUserID, controller.ActivateMFA)
+		v1.POST(DisableMFARoute, middleware.ValidateUserID, controller.DisableMFA)
+		v1.POST(RegenerateMFARecoveryCodesRoute, middleware.ValidateUserID, controller.RegenerateMFARecoveryCodes)
+		v1.GET(GetSessionListRoute, middleware.ValidateUserID, controller.GetSessionList)
+		v1.DELETE(RevokeSessionRoute, middleware.ValidateUserID, middleware.ValidateSessionID, controller.RevokeSession)
+		v1.DELETE(RevokeSessionsRoute, middleware.ValidateUserID, controller.RevokeSessions)
+
+		v1.GET(GetAddressRoute, middleware.ValidateUserID, middleware.ValidateAddressID, controller.GetAddress)
+		v1.POST(CreateAddressRoute, middleware.ValidateUserID, controller.CreateAddress)
+		v1.PUT(UpdateAddressRoute, middleware.ValidateUserID, middleware.ValidateAddressID, controller.UpdateAddress)
+		v1.DELETE(DeleteAddressRoute, middleware.ValidateUserID, middleware.ValidateAddressID, controller.DeleteAddress)
+		v1.GET(GetAddressListRoute, middleware.ValidateUserID, controller.GetAddressList)
+
+		v1.GET(GetWebhookRoute, middleware.ValidateWebhookID, controller.GetWebhook)
+		v1.POST(CreateWebhookRoute, controller.CreateWebhook)
+		v1.PUT(UpdateWebhookRoute, middleware.ValidateWebhookID, controller.UpdateWebhook)
+		v1.DELETE(DeleteWebhookRoute, middleware.ValidateWebhookID, controller.DeleteWebhook)
+		v1.GET(GetWebhookListRoute, controller.GetWebhookList)
+		v1.GET(GetWebhookDeliveryListRoute, middleware.ValidateWebhookID, controller.GetWebhookDeliveryList)
+
+		v1.GET(GetAttributeDefinitionRoute, middleware.ValidateAttributeDefinitionID, controller.GetAttributeDefinition)
+		v1.POST(CreateAttributeDefinitionRoute, controller.CreateAttributeDefinition)
+		v1.PUT(
+			UpdateAttributeDefinitionRoute,
+			middleware.ValidateAttributeDefinitionID,
+			controller.UpdateAttributeDefinition,
+		)
+		v1.DELETE(
+			DeleteAttributeDefinitionRoute,
+			middleware.ValidateAttributeDefinitionID,
+			controller.DeleteAttributeDefinition,
+		)
+		v1.GET(GetAttributeDefinitionListRoute, controller.GetAttributeDefinitionList)
+
+		v1.GET(GetGroupRoute, middleware.ValidateGroupID, controller.GetGroup)
+		v1.POST(CreateGroupRoute, controller.CreateGroup)
+		v1.PUT(UpdateGroupRoute, middleware.ValidateGroupID, controller.UpdateGroup)
+		v1.DELETE(DeleteGroupRoute, middleware.ValidateGroupID, controller.DeleteGroup)
+		v1.GET(GetGroupListRoute, controller.GetGroupList)
+		v1.GET(GetGroupMemberListRoute, middleware.ValidateGroupID, controller.GetGroupMemberList)
+		v1.POST(AddGroupMembersRoute, middleware.ValidateGroupID, controller.AddGroupMembers)
+		v1.DELETE(RemoveGroupMembersRoute, middleware.ValidateGroupID, controller.RemoveGroupMembers)
 	}
 	return g
 }
+
+// debugVars returns handler which serves counters of user cache.
+// Default expvar handler isn't used, because it publishes command line with secrets passed as flags.
+func debugVars(userCache dao.CacheStatsProvider) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		var vars response.DebugVars
+		if userCache != nil {
+			stats := userCache.Stats()
+			vars.UserCache = &response.CacheStats{
+				Hits:   stats.Hits,
+				Misses: stats.Misses,
+			}
+		}
+		context.JSON(http.StatusOK, vars)
+	}
+}
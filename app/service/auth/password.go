@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2id hashes are encoded in the format used by the reference implementation:
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+const argon2Prefix = "$argon2id$"
+
+// Argon2Params represents cost parameters of argon2id.
+type Argon2Params struct {
+	// Time is number of passes over the memory
+	Time uint32
+	// Memory is size of the memory in KiB
+	Memory uint32
+	// Threads is number of threads used to compute the hash
+	Threads uint8
+	// SaltLength and KeyLength are lengths of generated salt and hash in bytes
+	SaltLength uint32
+	KeyLength  uint32
+}
+
+// PasswordHasher hashes passwords with argon2id.
+type PasswordHasher struct {
+	params Argon2Params
+}
+
+// NewPasswordHasher creates new instance of PasswordHasher which hashes passwords with provided parameters.
+func NewPasswordHasher(params Argon2Params) *PasswordHasher {
+	return &PasswordHasher{
+		params: params,
+	}
+}
+
+// Hash returns argon2id hash of the password encoded with its salt and parameters.
+func (h PasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.Wrap(err, "impossible to generate password salt")
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Time, h.params.Memory, h.params.Threads, h.params.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2Prefix,
+		argon2.Version,
+		h.params.Memory,
+		h.params.Time,
+		h.params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks if the password matches the encoded hash. The hash is verified with parameters it was created with,
+// rehash is true when they differ from parameters of the hasher, so the password should be hashed again.
+func (h PasswordHasher) Verify(password, encodedHash string) (match bool, rehash bool, err error) {
+	params, salt, key, err := decodeHash(encodedHash)
+	if err != nil {
+		return false, false, err
+	}
+
+	computed := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, params.KeyLength)
+	if subtle.ConstantTimeCompare(key, computed) != 1 {
+		return false, false, nil
+	}
+
+	return true, params != h.params, nil
+}
+
+// decodeHash returns parameters, salt and key of the encoded hash.
+func decodeHash(encodedHash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+	if !strings.HasPrefix(encodedHash, argon2Prefix) {
+		return params, nil, nil, errors.New("password hash is not argon2id hash")
+	}
+
+	parts := strings.Split(strings.TrimPrefix(encodedHash, argon2Prefix), "$")
+	if len(parts) != 4 {
+		return params, nil, nil, errors.New("password hash has invalid format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errors.Errorf("password hash has unsupported version, version=%s", parts[0])
+	}
+
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return params, nil, nil, errors.Wrap(err, "password hash has invalid parameters")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return params, nil, nil, errors.Wrap(err, "password hash has invalid salt")
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return params, nil, nil, errors.Wrap(err, "password hash has invalid key")
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,66 @@
+// +build unit
+
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testParams = Argon2Params{Time: 1, Memory: 64, Threads: 1, SaltLength: 16, KeyLength: 32}
+
+func TestPasswordHasherVerify(t *testing.T) {
+	hasher := NewPasswordHasher(testParams)
+
+	hash, err := hasher.Hash("correct horse 1")
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	other, err := hasher.Hash("correct horse 1")
+	require.Nil(t, err)
+	assert.NotEqual(t, hash, other, "every hash has its own salt")
+
+	match, rehash, err := hasher.Verify("correct horse 1", hash)
+	require.Nil(t, err)
+	assert.True(t, match)
+	assert.False(t, rehash)
+
+	match, _, err = hasher.Verify("correct horse 2", hash)
+	require.Nil(t, err)
+	assert.False(t, match)
+}
+
+func TestPasswordHasherRehash(t *testing.T) {
+	hash, err := NewPasswordHasher(testParams).Hash("correct horse 1")
+	require.Nil(t, err)
+
+	stronger := testParams
+	stronger.Time = 2
+	match, rehash, err := NewPasswordHasher(stronger).Verify("correct horse 1", hash)
+	require.Nil(t, err)
+	assert.True(t, match, "hash is verified with parameters it was created with")
+	assert.True(t, rehash)
+
+	match, rehash, err = NewPasswordHasher(stronger).Verify("wrong", hash)
+	require.Nil(t, err)
+	assert.False(t, match)
+	assert.False(t, rehash)
+}
+
+func TestPasswordHasherInvalidHash(t *testing.T) {
+	hasher := NewPasswordHasher(testParams)
+	for _, hash := range []string{
+		"",
+		"$2a$10$bcrypt",
+		"$argon2id$v=19$m=64,t=1,p=1$salt",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=x,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+	} {
+		_, _, err := hasher.Verify("password", hash)
+		assert.NotNil(t, err, hash)
+	}
+}
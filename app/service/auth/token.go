@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// tokenHeader is encoded header of every issued token, only HS256 is supported.
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims represents registered claims of JSON Web Token and ID of the session it was issued for.
+type Claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	SessionID int    `json:"sid,omitempty"`
+}
+
+// Token represents signed access token.
+type Token struct {
+	Value     string
+	ExpiresAt time.Time
+}
+
+// TokenIssuer issues and verifies JSON Web Tokens signed with HMAC-SHA256.
+type TokenIssuer struct {
+	secret []byte
+	issuer string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewTokenIssuer creates new instance of TokenIssuer.
+func NewTokenIssuer(secret []byte, issuer string, ttl time.Duration) *TokenIssuer {
+	return &TokenIssuer{
+		secret: secret,
+		issuer: issuer,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Issue returns token of the user session which expires after TTL of the issuer.
+func (i TokenIssuer) Issue(userID, sessionID int) (*Token, error) {
+	now := i.now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(i.ttl)
+
+	payload, err := json.Marshal(Claims{
+		Issuer:    i.issuer,
+		Subject:   strconv.Itoa(userID),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "impossible to encode token claims")
+	}
+
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	return &Token{
+		Value:     unsigned + "." + i.sign(unsigned),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Parse verifies signature, issuer and expiration of the token and returns its claims.
+func (i TokenIssuer) Parse(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return nil, errors.New("token has invalid format")
+	}
+
+	if !hmac.Equal([]byte(parts[2]), []byte(i.sign(parts[0]+"."+parts[1]))) {
+		return nil, errors.New("token has invalid signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.Wrap(err, "token has invalid payload")
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.Wrap(err, "token has invalid claims")
+	}
+
+	if claims.Issuer != i.issuer {
+		return nil, errors.Errorf("token has unexpected issuer, issuer=%s", claims.Issuer)
+	}
+
+	if !i.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, errors.New("token expired")
+	}
+
+	return &claims, nil
+}
+
+// sign returns encoded HMAC-SHA256 signature of the unsigned token.
+func (i TokenIssuer) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, i.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,63 @@
+// +build unit
+
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTokenIssuer(secret string, now time.Time) *TokenIssuer {
+	issuer := NewTokenIssuer([]byte(secret), "user-service", time.Hour)
+	issuer.now = func() time.Time {
+		return now
+	}
+	return issuer
+}
+
+func TestTokenIssuerIssue(t *testing.T) {
+	now := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+	issuer := newTestTokenIssuer("secret", now)
+
+	token, err := issuer.Issue(500, 42)
+	require.Nil(t, err)
+	assert.Equal(t, now.Add(time.Hour), token.ExpiresAt)
+	assert.Len(t, strings.Split(token.Value, "."), 3)
+
+	claims, err := issuer.Parse(token.Value)
+	require.Nil(t, err)
+	assert.Equal(t, Claims{
+		Issuer:    "user-service",
+		Subject:   "500",
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Hour).Unix(),
+		SessionID: 42,
+	}, *claims)
+}
+
+func TestTokenIssuerParseErrors(t *testing.T) {
+	now := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+	token, err := newTestTokenIssuer("secret", now).Issue(500, 42)
+	require.Nil(t, err)
+
+	_, err = newTestTokenIssuer("other", now).Parse(token.Value)
+	assert.EqualError(t, err, "token has invalid signature")
+
+	_, err = newTestTokenIssuer("secret", now.Add(time.Hour)).Parse(token.Value)
+	assert.EqualError(t, err, "token expired")
+
+	other := NewTokenIssuer([]byte("secret"), "other-service", time.Hour)
+	other.now = func() time.Time { return now }
+	_, err = other.Parse(token.Value)
+	assert.NotNil(t, err)
+
+	parts := strings.Split(token.Value, ".")
+	_, err = newTestTokenIssuer("secret", now).Parse("e30." + parts[1] + "." + parts[2])
+	assert.EqualError(t, err, "token has invalid format", "only HS256 header is accepted")
+	_, err = newTestTokenIssuer("secret", now).Parse(parts[0] + "." + parts[1])
+	assert.EqualError(t, err, "token has invalid format")
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skip2/go-qrcode"
+)
+
+// TOTP parameters supported by all common authenticator apps.
+const (
+	totpDigits       = 6
+	totpModulus      = 1000000
+	totpPeriod       = 30
+	totpSecretLength = 20
+	totpQRCodeSize   = 256
+)
+
+// totpEncoding encodes secrets in the form expected by authenticator apps.
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTP generates secrets and validates time-based one-time passwords defined by RFC 6238.
+// HMAC-SHA1 is used because authenticator apps ignore other algorithms.
+type TOTP struct {
+	issuer string
+	skew   int
+	now    func() time.Time
+}
+
+// NewTOTP creates new instance of TOTP. Codes from skew time steps before and after the current one are accepted
+// to tolerate clock drift of user devices.
+func NewTOTP(issuer string, skew int) *TOTP {
+	return &TOTP{
+		issuer: issuer,
+		skew:   skew,
+		now:    time.Now,
+	}
+}
+
+// GenerateSecret returns random base32 encoded secret.
+func (t TOTP) GenerateSecret() (string, error) {
+	b := make([]byte, totpSecretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "impossible to generate TOTP secret")
+	}
+
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// KeyURI returns otpauth URI which is used by authenticator apps to add the account.
+func (t TOTP) KeyURI(account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", t.issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(totpDigits))
+	query.Set("period", strconv.Itoa(totpPeriod))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", url.PathEscape(t.issuer+":"+account), query.Encode())
+}
+
+// QRCode returns PNG image with QR code of the key URI.
+func (t TOTP) QRCode(account, secret string) ([]byte, error) {
+	png, err := qrcode.Encode(t.KeyURI(account, secret), qrcode.Medium, totpQRCodeSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "impossible to encode TOTP QR code")
+	}
+
+	return png, nil
+}
+
+// Code returns code of the current time step, it is what authenticator apps show.
+func (t TOTP) Code(secret string) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return totpCode(key, t.now().Unix()/totpPeriod), nil
+}
+
+// Validate checks the code against the secret and returns time step of the matching code.
+// Only steps newer than lastUsedStep are checked, so an accepted code can't be replayed.
+func (t TOTP) Validate(secret, code string, lastUsedStep int64) (int64, bool, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if len(code) != totpDigits {
+		return 0, false, nil
+	}
+
+	current := t.now().Unix() / totpPeriod
+	for step := current - int64(t.skew); step <= current+int64(t.skew); step++ {
+		if step <= lastUsedStep {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// decodeTOTPSecret returns key encoded in the secret.
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return nil, errors.Wrap(err, "impossible to decode TOTP secret")
+	}
+
+	return key, nil
+}
+
+// totpCode returns code of the time step, see RFC 4226 for dynamic truncation.
+func totpCode(key []byte, step int64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%totpModulus)
+}
@@ -0,0 +1,108 @@
+// +build unit
+
+package auth
+
+import (
+	"bytes"
+	"image/png"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is SHA1 key of RFC 6238 test vectors.
+var rfc6238Secret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func newTestTOTP(skew int, now time.Time) *TOTP {
+	totp := NewTOTP("User Service", skew)
+	totp.now = func() time.Time {
+		return now
+	}
+	return totp
+}
+
+func TestTOTPValidateRFC6238(t *testing.T) {
+	var testData = []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range testData {
+		step, ok, err := newTestTOTP(0, time.Unix(tt.unix, 0)).Validate(rfc6238Secret, tt.code, 0)
+		require.Nil(t, err)
+		assert.True(t, ok, tt.code)
+		assert.Equal(t, tt.unix/totpPeriod, step)
+	}
+}
+
+func TestTOTPValidateDriftAndReplay(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	key := []byte("12345678901234567890")
+	current := now.Unix() / totpPeriod
+	totp := newTestTOTP(1, now)
+
+	for _, step := range []int64{current - 1, current, current + 1} {
+		matched, ok, err := totp.Validate(rfc6238Secret, totpCode(key, step), 0)
+		require.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, step, matched)
+	}
+
+	for _, step := range []int64{current - 2, current + 2} {
+		_, ok, err := totp.Validate(rfc6238Secret, totpCode(key, step), 0)
+		require.Nil(t, err)
+		assert.False(t, ok, "code outside of drift window")
+	}
+
+	_, ok, err := totp.Validate(rfc6238Secret, totpCode(key, current), current)
+	require.Nil(t, err)
+	assert.False(t, ok, "used code can't be replayed")
+
+	_, ok, err = totp.Validate(rfc6238Secret, "12345", 0)
+	require.Nil(t, err)
+	assert.False(t, ok)
+
+	_, _, err = totp.Validate("not base32!", "123456", 0)
+	assert.NotNil(t, err)
+
+	code, err := totp.Code(rfc6238Secret)
+	require.Nil(t, err)
+	assert.Equal(t, "050471", code)
+}
+
+func TestTOTPEnrolment(t *testing.T) {
+	totp := NewTOTP("User Service", 1)
+	secret, err := totp.GenerateSecret()
+	require.Nil(t, err)
+	key, err := totpEncoding.DecodeString(secret)
+	require.Nil(t, err)
+	assert.Len(t, key, totpSecretLength)
+
+	uri, err := url.Parse(totp.KeyURI("john@example.com", secret))
+	require.Nil(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/User Service:john@example.com", uri.Path)
+	assert.Equal(t, url.Values{
+		"secret":    {secret},
+		"issuer":    {"User Service"},
+		"algorithm": {"SHA1"},
+		"digits":    {"6"},
+		"period":    {"30"},
+	}, uri.Query())
+
+	image, err := totp.QRCode("john@example.com", secret)
+	require.Nil(t, err)
+	config, err := png.DecodeConfig(bytes.NewReader(image))
+	require.Nil(t, err)
+	assert.Equal(t, totpQRCodeSize, config.Width)
+}
@@ -0,0 +1,89 @@
+package changefeed
+
+import (
+	"context"
+	"time"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/user/validator"
+)
+
+const (
+	changeListDefaultPageSize = 100
+	changeListMaxPageSize     = 1000
+)
+
+// Provider provides an interface to read the change feed of users
+type Provider interface {
+	// FindChanges returns changes after the continuation token in commit order and token to continue from.
+	// When there are no changes it waits up to request wait time for new ones.
+	FindChanges(ctx context.Context, request *request.FindUserChanges) ([]model.UserChange, string, error)
+}
+
+// Service represents change feed service
+type Service struct {
+	userChangeRepository dao.UserChangeRepositoryProvider
+	pollInterval         time.Duration
+}
+
+// NewService creates new instance of change feed service.
+func NewService(
+	userChangeRepository dao.UserChangeRepositoryProvider,
+	pollInterval time.Duration,
+) *Service {
+	return &Service{
+		userChangeRepository: userChangeRepository,
+		pollInterval:         pollInterval,
+	}
+}
+
+// FindChanges returns changes after the continuation token in commit order and token to continue from.
+// When there are no changes it waits up to request wait time for new ones.
+func (s Service) FindChanges(ctx context.Context, request *request.FindUserChanges) (
+	[]model.UserChange, string, error) {
+
+	if err := validator.ValidateFindUserChangesRequest(request); err != nil {
+		return nil, "", err
+	}
+
+	seq, err := DecodeToken(request.Since)
+	if err != nil {
+		return nil, "", httperrors.ChangeFeedTokenIncorrect.WithCause(err)
+	}
+
+	limit := request.Limit
+	if limit == 0 || limit > changeListMaxPageSize {
+		limit = changeListDefaultPageSize
+	}
+
+	deadline := time.Now().Add(request.Wait)
+	for {
+		changes, err := s.userChangeRepository.FindAfter(seq, limit)
+		if err != nil {
+			return nil, "", httperrors.InternalServerError.WithCause(err)
+		}
+
+		if len(changes) > 0 {
+			return changes, EncodeToken(changes[len(changes)-1].Seq), nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return changes, EncodeToken(seq), nil
+		}
+
+		delay := s.pollInterval
+		if remaining < delay {
+			delay = remaining
+		}
+
+		select {
+		case <-ctx.Done():
+			return changes, EncodeToken(seq), nil
+		case <-time.After(delay):
+		}
+	}
+}
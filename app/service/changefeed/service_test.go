@@ -0,0 +1,100 @@
+// +build unit
+
+package changefeed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+func TestTokenRoundTrip(t *testing.T) {
+	seq, err := DecodeToken(EncodeToken(12345))
+	require.Nil(t, err)
+	assert.Equal(t, int64(12345), seq)
+
+	seq, err = DecodeToken("")
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), seq)
+}
+
+func TestDecodeTokenError(t *testing.T) {
+	for _, token := range []string{"not base64!", "MTIz", EncodeToken(-1)} {
+		_, err := DecodeToken(token)
+		assert.NotNil(t, err, token)
+	}
+}
+
+func TestFindChangesOK(t *testing.T) {
+	changes := []model.UserChange{
+		{Seq: 11, UserID: 1, Operation: model.UserChangeUpdated},
+		{Seq: 12, UserID: 2, Operation: model.UserChangeDeleted},
+	}
+	mockUserChangeRepository := dao.MockUserChangeRepositoryProvider{}
+	mockUserChangeRepository.On("FindAfter", int64(10), changeListDefaultPageSize).Return(changes, nil)
+
+	service := NewService(&mockUserChangeRepository, time.Millisecond)
+	result, nextToken, err := service.FindChanges(context.Background(), &request.FindUserChanges{
+		Since: EncodeToken(10),
+	})
+	require.Nil(t, err)
+	assert.Equal(t, changes, result)
+	assert.Equal(t, EncodeToken(12), nextToken)
+}
+
+func TestFindChangesLongPolling(t *testing.T) {
+	changes := []model.UserChange{
+		{Seq: 1, UserID: 1, Operation: model.UserChangeCreated},
+	}
+	mockUserChangeRepository := dao.MockUserChangeRepositoryProvider{}
+	mockUserChangeRepository.On("FindAfter", int64(0), 10).Return([]model.UserChange{}, nil).Twice()
+	mockUserChangeRepository.On("FindAfter", int64(0), 10).Return(changes, nil).Once()
+
+	service := NewService(&mockUserChangeRepository, time.Millisecond)
+	result, nextToken, err := service.FindChanges(context.Background(), &request.FindUserChanges{
+		Limit: 10,
+		Wait:  time.Second,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, changes, result)
+	assert.Equal(t, EncodeToken(1), nextToken)
+	mockUserChangeRepository.AssertNumberOfCalls(t, "FindAfter", 3)
+}
+
+func TestFindChangesWaitTimeout(t *testing.T) {
+	mockUserChangeRepository := dao.MockUserChangeRepositoryProvider{}
+	mockUserChangeRepository.On("FindAfter", int64(5), changeListDefaultPageSize).Return([]model.UserChange{}, nil)
+
+	service := NewService(&mockUserChangeRepository, 10*time.Millisecond)
+	start := time.Now()
+	result, nextToken, err := service.FindChanges(context.Background(), &request.FindUserChanges{
+		Since: EncodeToken(5),
+		Wait:  30 * time.Millisecond,
+	})
+	require.Nil(t, err)
+	assert.Empty(t, result)
+	assert.Equal(t, EncodeToken(5), nextToken)
+	assert.True(t, time.Since(start) >= 30*time.Millisecond)
+}
+
+func TestFindChangesValidationError(t *testing.T) {
+	service := NewService(&dao.MockUserChangeRepositoryProvider{}, time.Millisecond)
+
+	_, _, err := service.FindChanges(context.Background(), &request.FindUserChanges{Since: "MTIz"})
+	require.NotNil(t, err)
+	assert.EqualError(t, httperrors.ChangeFeedTokenIncorrect, err.Error())
+
+	_, _, err = service.FindChanges(context.Background(), &request.FindUserChanges{Wait: time.Hour})
+	require.NotNil(t, err)
+	assert.EqualError(t, httperrors.RequestValidationFailed.WithDetails(httperrors.Detail{
+		Field: "/wait", Code: httperrors.ChangeFeedWaitIncorrect.Code, Message: httperrors.ChangeFeedWaitIncorrect.Message,
+	}), err.Error())
+}
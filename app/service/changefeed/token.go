@@ -0,0 +1,41 @@
+package changefeed
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const tokenPrefix = "v1:"
+
+// EncodeToken encodes change sequence as opaque continuation token.
+func EncodeToken(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(tokenPrefix + strconv.FormatInt(seq, 10)))
+}
+
+// DecodeToken returns change sequence encoded in continuation token.
+// Empty token means the beginning of the change feed.
+func DecodeToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errors.Wrap(err, "impossible to decode change feed token")
+	}
+
+	s := string(b)
+	if !strings.HasPrefix(s, tokenPrefix) {
+		return 0, errors.Errorf("unsupported change feed token version, token=%s", token)
+	}
+
+	seq, err := strconv.ParseInt(strings.TrimPrefix(s, tokenPrefix), 10, 64)
+	if err != nil || seq < 0 {
+		return 0, errors.Errorf("incorrect change feed sequence, token=%s", token)
+	}
+
+	return seq, nil
+}
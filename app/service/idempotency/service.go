@@ -0,0 +1,134 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// Provider provides an interface to work with idempotency keys
+type Provider interface {
+	// Begin starts processing of the request with idempotency key.
+	// It returns stored response when request with the key was already completed
+	// or nil when the request has to be processed.
+	Begin(scope, key, fingerprint string) (*model.IdempotencyKey, error)
+	// Complete stores response of the request
+	Complete(scope, key string, responseStatus int, responseContentType, responseBody string) error
+	// Release removes the key, so request with the key can be processed again
+	Release(scope, key string) error
+}
+
+// Service represents idempotency keys service
+type Service struct {
+	idempotencyKeyRepository dao.IdempotencyKeyRepositoryProvider
+	ttl                      time.Duration
+	now                      func() time.Time
+}
+
+// NewService creates new instance of idempotency keys service.
+// Keys expire after ttl, then the same key can be used for a new request.
+func NewService(
+	idempotencyKeyRepository dao.IdempotencyKeyRepositoryProvider,
+	ttl time.Duration,
+) *Service {
+	return &Service{
+		idempotencyKeyRepository: idempotencyKeyRepository,
+		ttl:                      ttl,
+		now:                      time.Now,
+	}
+}
+
+// Begin starts processing of the request with idempotency key.
+// It returns stored response when request with the key was already completed
+// or nil when the request has to be processed.
+func (s Service) Begin(scope, key, fingerprint string) (*model.IdempotencyKey, error) {
+	now := s.now()
+	created, err := s.idempotencyKeyRepository.Create(&model.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      model.IdempotencyKeyProcessing,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if created {
+		return nil, nil
+	}
+
+	existing, err := s.idempotencyKeyRepository.Get(scope, key)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	// Key was released or expired and removed in the meantime.
+	if existing == nil {
+		return nil, httperrors.IdempotencyKeyInProgress
+	}
+
+	if !existing.ExpiresAt.After(now) {
+		if err := s.idempotencyKeyRepository.Delete(scope, key); err != nil {
+			return nil, httperrors.InternalServerError.WithCause(err)
+		}
+		return s.Begin(scope, key, fingerprint)
+	}
+
+	if existing.Fingerprint != fingerprint {
+		return nil, httperrors.IdempotencyKeyReused
+	}
+
+	if existing.Status != model.IdempotencyKeyCompleted {
+		return nil, httperrors.IdempotencyKeyInProgress
+	}
+
+	return existing, nil
+}
+
+// Complete stores response of the request
+func (s Service) Complete(scope, key string, responseStatus int, responseContentType, responseBody string) error {
+	if err := s.idempotencyKeyRepository.Complete(&model.IdempotencyKey{
+		Scope:               scope,
+		Key:                 key,
+		ResponseStatus:      responseStatus,
+		ResponseContentType: responseContentType,
+		ResponseBody:        responseBody,
+	}); err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	return nil
+}
+
+// Release removes the key, so request with the key can be processed again
+func (s Service) Release(scope, key string) error {
+	if err := s.idempotencyKeyRepository.Delete(scope, key); err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	return nil
+}
+
+// RunCleanup deletes expired keys every interval until context is cancelled.
+func (s Service) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.idempotencyKeyRepository.DeleteExpired(s.now()); err != nil {
+			log.Errorf("%+v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
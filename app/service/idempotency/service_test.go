@@ -0,0 +1,120 @@
+// +build unit
+
+package idempotency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+const (
+	testScope = "POST /v1/users"
+	testKey   = "key"
+)
+
+var testNow = time.Date(2020, 5, 14, 16, 18, 40, 0, time.UTC)
+
+func newTestService(repository dao.IdempotencyKeyRepositoryProvider) *Service {
+	service := NewService(repository, time.Hour)
+	service.now = func() time.Time { return testNow }
+	return service
+}
+
+func TestBeginNewKey(t *testing.T) {
+	mockRepository := dao.MockIdempotencyKeyRepositoryProvider{}
+	mockRepository.On("Create", &model.IdempotencyKey{
+		Scope:       testScope,
+		Key:         testKey,
+		Fingerprint: "fingerprint",
+		Status:      model.IdempotencyKeyProcessing,
+		ExpiresAt:   testNow.Add(time.Hour),
+	}).Return(true, nil)
+
+	stored, err := newTestService(&mockRepository).Begin(testScope, testKey, "fingerprint")
+	require.Nil(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestBeginReplaysCompletedRequest(t *testing.T) {
+	existing := &model.IdempotencyKey{
+		Scope:          testScope,
+		Key:            testKey,
+		Fingerprint:    "fingerprint",
+		Status:         model.IdempotencyKeyCompleted,
+		ResponseStatus: 201,
+		ResponseBody:   `{"id":500}`,
+		ExpiresAt:      testNow.Add(time.Minute),
+	}
+	mockRepository := dao.MockIdempotencyKeyRepositoryProvider{}
+	mockRepository.On("Create", mock.Anything).Return(false, nil)
+	mockRepository.On("Get", testScope, testKey).Return(existing, nil)
+
+	stored, err := newTestService(&mockRepository).Begin(testScope, testKey, "fingerprint")
+	require.Nil(t, err)
+	assert.Equal(t, existing, stored)
+}
+
+func TestBeginErrors(t *testing.T) {
+	var testData = []struct {
+		name          string
+		existing      *model.IdempotencyKey
+		expectedError error
+	}{
+		{
+			"DifferentRequest",
+			&model.IdempotencyKey{
+				Fingerprint: "other",
+				Status:      model.IdempotencyKeyCompleted,
+				ExpiresAt:   testNow.Add(time.Minute),
+			},
+			httperrors.IdempotencyKeyReused,
+		},
+		{
+			"InProgress",
+			&model.IdempotencyKey{
+				Fingerprint: "fingerprint",
+				Status:      model.IdempotencyKeyProcessing,
+				ExpiresAt:   testNow.Add(time.Minute),
+			},
+			httperrors.IdempotencyKeyInProgress,
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := dao.MockIdempotencyKeyRepositoryProvider{}
+			mockRepository.On("Create", mock.Anything).Return(false, nil)
+			mockRepository.On("Get", testScope, testKey).Return(tt.existing, nil)
+
+			stored, err := newTestService(&mockRepository).Begin(testScope, testKey, "fingerprint")
+			require.NotNil(t, err)
+			assert.Nil(t, stored)
+			assert.EqualError(t, tt.expectedError, err.Error())
+		})
+	}
+}
+
+func TestBeginExpiredKey(t *testing.T) {
+	mockRepository := dao.MockIdempotencyKeyRepositoryProvider{}
+	mockRepository.On("Create", mock.Anything).Return(false, nil).Once()
+	mockRepository.On("Get", testScope, testKey).Return(&model.IdempotencyKey{
+		Fingerprint: "other",
+		Status:      model.IdempotencyKeyCompleted,
+		ExpiresAt:   testNow,
+	}, nil)
+	mockRepository.On("Delete", testScope, testKey).Return(nil)
+	mockRepository.On("Create", mock.Anything).Return(true, nil).Once()
+
+	stored, err := newTestService(&mockRepository).Begin(testScope, testKey, "fingerprint")
+	require.Nil(t, err)
+	assert.Nil(t, stored)
+	mockRepository.AssertExpectations(t)
+}
@@ -0,0 +1,78 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Supported notifier types
+const (
+	NotifierLog  = "log"
+	NotifierFile = "file"
+)
+
+// Supported notification types
+const (
+	NotificationEmailVerification = "email_verification"
+	NotificationPasswordReset     = "password_reset"
+)
+
+// Notification represents message which has to be delivered to the user, e.g. email verification token.
+type Notification struct {
+	Type      string    `json:"type"`
+	UserID    int       `json:"user_id"`
+	Recipient string    `json:"recipient"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Notifier provides an interface to deliver notifications to users.
+// Implementations sending emails or text messages can be plugged in without changes of services.
+type Notifier interface {
+	// Notify delivers notification to its recipient
+	Notify(notification *Notification) error
+}
+
+// NewNotifier creates notifier based on provided notifier type.
+// Both supported notifiers are meant for local use, tokens are written in plain text.
+func NewNotifier(notifierType, filePath string) (Notifier, error) {
+	switch notifierType {
+	case NotifierLog:
+		return NewWriterNotifier(os.Stdout), nil
+	case NotifierFile:
+		file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, errors.Wrapf(err, "impossible to open notifications file, path=%s", filePath)
+		}
+		return NewWriterNotifier(file), nil
+	default:
+		return nil, errors.Errorf("unsupported notifier type, notifierType=%s", notifierType)
+	}
+}
+
+// WriterNotifier writes notifications as JSON lines to io.Writer
+type WriterNotifier struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewWriterNotifier creates new instance of WriterNotifier.
+func NewWriterNotifier(w io.Writer) *WriterNotifier {
+	return &WriterNotifier{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Notify writes notification as single JSON line
+func (n *WriterNotifier) Notify(notification *Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return errors.Wrapf(n.encoder.Encode(notification),
+		"impossible to write %s notification, userID=%d", notification.Type, notification.UserID)
+}
@@ -0,0 +1,55 @@
+// +build unit
+
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterNotifierNotify(t *testing.T) {
+	var buf bytes.Buffer
+	notifier := NewWriterNotifier(&buf)
+	expiresAt := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	require.Nil(t, notifier.Notify(&Notification{
+		Type:      NotificationEmailVerification,
+		UserID:    500,
+		Recipient: "john@example.com",
+		Token:     "token",
+		ExpiresAt: expiresAt,
+	}))
+
+	assert.JSONEq(t, `{"type":"email_verification","user_id":500,"recipient":"john@example.com",`+
+		`"token":"token","expires_at":"2020-10-19T12:00:00Z"}`, buf.String())
+}
+
+func TestNewNotifierFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notifier")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "notifications.log")
+
+	notifier, err := NewNotifier(NotifierFile, path)
+	require.Nil(t, err)
+	require.Nil(t, notifier.Notify(&Notification{Type: NotificationEmailVerification, UserID: 500, Token: "token"}))
+
+	content, err := ioutil.ReadFile(path)
+	require.Nil(t, err)
+	var notification Notification
+	require.Nil(t, json.Unmarshal(content, &notification))
+	assert.Equal(t, "token", notification.Token)
+}
+
+func TestNewNotifierUnsupported(t *testing.T) {
+	_, err := NewNotifier("sms", "")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// kafkaRESTContentType is a content type of Kafka REST Proxy v2 request with JSON keys and values.
+const kafkaRESTContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaProducer provides an interface to produce record to Kafka topic.
+// Produce has to return after the broker acknowledged the record.
+type KafkaProducer interface {
+	Produce(ctx context.Context, topic string, key string, value []byte) error
+}
+
+// KafkaSink publishes messages to Kafka topic using user ID as a record key,
+// so all events of the user land in the same partition and keep their order.
+type KafkaSink struct {
+	producer KafkaProducer
+	topic    string
+	timeout  time.Duration
+}
+
+// NewKafkaSink creates new instance of KafkaSink.
+func NewKafkaSink(producer KafkaProducer, topic string, timeout time.Duration) *KafkaSink {
+	return &KafkaSink{
+		producer: producer,
+		topic:    topic,
+		timeout:  timeout,
+	}
+}
+
+// Publish publishes message to Kafka
+func (s KafkaSink) Publish(message *Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return errors.Wrapf(err, "impossible to marshal message, messageID=%d", message.ID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	return errors.Wrapf(s.producer.Produce(ctx, s.topic, strconv.Itoa(message.UserID), body),
+		"impossible to publish message to Kafka, messageID=%d", message.ID)
+}
+
+// KafkaRESTProducer produces records with Kafka REST Proxy v2 API
+type KafkaRESTProducer struct {
+	httpClient *http.Client
+	url        string
+}
+
+// NewKafkaRESTProducer creates new instance of KafkaRESTProducer.
+func NewKafkaRESTProducer(httpClient *http.Client, proxyURL string) *KafkaRESTProducer {
+	return &KafkaRESTProducer{
+		httpClient: httpClient,
+		url:        strings.TrimSuffix(proxyURL, "/"),
+	}
+}
+
+type kafkaRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Produce sends record to REST Proxy, the record is produced when proxy responds without error for the record.
+func (p KafkaRESTProducer) Produce(ctx context.Context, topic string, key string, value []byte) error {
+	body, err := json.Marshal(kafkaProduceRequest{Records: []kafkaRecord{{Key: key, Value: value}}})
+	if err != nil {
+		return errors.Wrap(err, "impossible to marshal Kafka record")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.url+"/topics/"+url.PathEscape(topic), bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "error creating new http request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", kafkaRESTContentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "error sending record to Kafka REST Proxy, topic=%s", topic)
+	}
+	//nolint
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("Kafka REST Proxy responded with status %d, topic=%s", resp.StatusCode, topic)
+	}
+
+	var produced kafkaProduceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&produced); err != nil {
+		return errors.Wrapf(err, "impossible to decode Kafka REST Proxy response, topic=%s", topic)
+	}
+	if len(produced.Offsets) != 1 {
+		return errors.Errorf("Kafka REST Proxy returned %d offsets for 1 record, topic=%s",
+			len(produced.Offsets), topic)
+	}
+	if produced.Offsets[0].ErrorCode != nil {
+		return errors.Errorf("Kafka REST Proxy failed to produce record, topic=%s, errorCode=%d, error=%s",
+			topic, *produced.Offsets[0].ErrorCode, produced.Offsets[0].Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// Message represents event published to the sink
+type Message struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	UserID     int             `json:"user_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewMessage creates message from outbox event.
+func NewMessage(event *model.Event) *Message {
+	return &Message{
+		ID:         event.ID,
+		Type:       event.Type,
+		UserID:     event.AggregateID,
+		OccurredAt: event.CreatedAt,
+		Data:       json.RawMessage(event.Payload),
+	}
+}
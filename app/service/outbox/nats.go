@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+)
+
+// NATSPublisher provides an interface to publish data to NATS subject.
+// Publish has to return after the server received the data.
+type NATSPublisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// NATSSink publishes messages to NATS subject `<subject>.<event type>`
+type NATSSink struct {
+	publisher NATSPublisher
+	subject   string
+}
+
+// NewNATSSink creates new instance of NATSSink.
+func NewNATSSink(publisher NATSPublisher, subject string) *NATSSink {
+	return &NATSSink{
+		publisher: publisher,
+		subject:   subject,
+	}
+}
+
+// Publish publishes message to NATS
+func (s NATSSink) Publish(message *Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return errors.Wrapf(err, "impossible to marshal message, messageID=%d", message.ID)
+	}
+
+	return errors.Wrapf(s.publisher.Publish(s.subject+"."+message.Type, body),
+		"impossible to publish message to NATS, messageID=%d", message.ID)
+}
+
+// NATSConnPublisher publishes data with NATS connection
+type NATSConnPublisher struct {
+	conn    *nats.Conn
+	timeout time.Duration
+}
+
+// NewNATSConnPublisher connects to NATS servers and creates new instance of NATSConnPublisher.
+func NewNATSConnPublisher(url string, timeout time.Duration) (*NATSConnPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("user-service"), nats.Timeout(timeout), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "impossible to connect to NATS, url=%s", url)
+	}
+
+	return &NATSConnPublisher{
+		conn:    conn,
+		timeout: timeout,
+	}, nil
+}
+
+// Publish publishes data and waits until the server processed it,
+// the connection buffers published data, so its Publish returns before the data are sent.
+func (p NATSConnPublisher) Publish(subject string, data []byte) error {
+	if err := p.conn.Publish(subject, data); err != nil {
+		return err
+	}
+
+	return p.conn.FlushTimeout(p.timeout)
+}
@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mmgopher/user-service/app/dao"
+)
+
+// Relay publishes events stored in outbox table to the sink.
+// Delivery is at-least-once: event is marked as published only after the sink accepted it.
+// Events of the same user are published in the order they were stored,
+// next event of the user is not published until the previous one is delivered.
+type Relay struct {
+	repository   dao.OutboxRepositoryProvider
+	sink         Sink
+	batchSize    int
+	pollInterval time.Duration
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
+	now          func() time.Time
+}
+
+// NewRelay creates new instance of Relay.
+func NewRelay(
+	repository dao.OutboxRepositoryProvider,
+	sink Sink,
+	batchSize int,
+	pollInterval time.Duration,
+	retryBackoff time.Duration,
+	maxBackoff time.Duration,
+) *Relay {
+	return &Relay{
+		repository:   repository,
+		sink:         sink,
+		batchSize:    batchSize,
+		pollInterval: pollInterval,
+		retryBackoff: retryBackoff,
+		maxBackoff:   maxBackoff,
+		now:          time.Now,
+	}
+}
+
+// Run publishes pending events every poll interval until context is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			published, err := r.PublishPending()
+			if err != nil {
+				log.Errorf("%+v", err)
+				break
+			}
+			// Drain the outbox while there is a progress.
+			if published == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending publishes one batch of pending events and returns number of published events.
+func (r *Relay) PublishPending() (int, error) {
+	now := r.now()
+	events, err := r.repository.FindPending(now, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for i := range events {
+		event := &events[i]
+		if err := r.sink.Publish(NewMessage(event)); err != nil {
+			log.WithFields(log.Fields{
+				"err":      err,
+				"eventID":  event.ID,
+				"attempts": event.Attempts + 1,
+			}).Warn("impossible to publish outbox event")
+
+			if err := r.repository.MarkFailed(
+				event.ID, now.Add(Backoff(r.retryBackoff, r.maxBackoff, event.Attempts)), err.Error(),
+			); err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		if err := r.repository.MarkPublished(event.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+// Backoff returns delay before next attempt, it grows exponentially with number of failed attempts
+// starting from base delay up to max delay.
+func Backoff(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 0; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
@@ -0,0 +1,98 @@
+// +build unit
+
+package outbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// sinkFunc allows to use function as a Sink
+type sinkFunc func(message *Message) error
+
+func (f sinkFunc) Publish(message *Message) error {
+	return f(message)
+}
+
+func TestRelayPublishPendingOK(t *testing.T) {
+	now := time.Date(2020, 5, 14, 16, 18, 40, 0, time.UTC)
+	events := []model.Event{
+		{ID: 1, AggregateID: 10, Type: model.EventUserCreated, Payload: []byte(`{"id":10}`)},
+		{ID: 2, AggregateID: 11, Type: model.EventUserDeleted, Payload: []byte(`{"id":11}`)},
+	}
+
+	mockOutboxRepository := dao.MockOutboxRepositoryProvider{}
+	mockOutboxRepository.On("FindPending", now, 100).Return(events, nil)
+	mockOutboxRepository.On("MarkPublished", int64(1)).Return(nil)
+	mockOutboxRepository.On("MarkPublished", int64(2)).Return(nil)
+
+	var published []int64
+	relay := NewRelay(&mockOutboxRepository, sinkFunc(func(message *Message) error {
+		published = append(published, message.ID)
+		return nil
+	}), 100, time.Second, time.Second, time.Minute)
+	relay.now = func() time.Time { return now }
+
+	count, err := relay.PublishPending()
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []int64{1, 2}, published)
+	mockOutboxRepository.AssertExpectations(t)
+}
+
+func TestRelayPublishPendingSinkError(t *testing.T) {
+	now := time.Date(2020, 5, 14, 16, 18, 40, 0, time.UTC)
+	events := []model.Event{
+		{ID: 1, AggregateID: 10, Type: model.EventUserCreated, Attempts: 2},
+		{ID: 2, AggregateID: 11, Type: model.EventUserCreated},
+	}
+
+	mockOutboxRepository := dao.MockOutboxRepositoryProvider{}
+	mockOutboxRepository.On("FindPending", now, 100).Return(events, nil)
+	mockOutboxRepository.On("MarkFailed", int64(1), now.Add(4*time.Second), "sink error").Return(nil)
+	mockOutboxRepository.On("MarkPublished", int64(2)).Return(nil)
+
+	relay := NewRelay(&mockOutboxRepository, sinkFunc(func(message *Message) error {
+		if message.ID == 1 {
+			return errors.New("sink error")
+		}
+		return nil
+	}), 100, time.Second, time.Second, time.Minute)
+	relay.now = func() time.Time { return now }
+
+	count, err := relay.PublishPending()
+	require.Nil(t, err)
+	assert.Equal(t, 1, count)
+	mockOutboxRepository.AssertExpectations(t)
+	mockOutboxRepository.AssertNotCalled(t, "MarkPublished", int64(1))
+}
+
+func TestRelayPublishPendingRepositoryError(t *testing.T) {
+	mockOutboxRepository := dao.MockOutboxRepositoryProvider{}
+	mockOutboxRepository.On("FindPending", mock.Anything, 100).Return(nil, errors.New("db error"))
+
+	relay := NewRelay(&mockOutboxRepository, sinkFunc(func(message *Message) error {
+		t.Fatal("sink should not be called")
+		return nil
+	}), 100, time.Second, time.Second, time.Minute)
+
+	count, err := relay.PublishPending()
+	require.NotNil(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(time.Second, 10*time.Second, 0))
+	assert.Equal(t, 2*time.Second, Backoff(time.Second, 10*time.Second, 1))
+	assert.Equal(t, 8*time.Second, Backoff(time.Second, 10*time.Second, 3))
+	assert.Equal(t, 10*time.Second, Backoff(time.Second, 10*time.Second, 4))
+	assert.Equal(t, 10*time.Second, Backoff(time.Second, 10*time.Second, 100))
+}
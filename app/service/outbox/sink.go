@@ -0,0 +1,166 @@
+package outbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Supported sink types
+const (
+	SinkNone    = "none"
+	SinkStdout  = "stdout"
+	SinkFile    = "file"
+	SinkWebhook = "webhook"
+	SinkNATS    = "nats"
+	SinkKafka   = "kafka"
+)
+
+// Sink provides an interface to publish outbox messages.
+// Publish has to return error if message was not delivered, the message is retried later then.
+type Sink interface {
+	// Publish delivers message to the destination
+	Publish(message *Message) error
+}
+
+// SinkSettings represents settings of the sink, only settings of the selected sink type are used.
+type SinkSettings struct {
+	Type string
+
+	FilePath string
+
+	WebhookURL     string
+	WebhookTimeout time.Duration
+
+	// NATSURL is a comma separated list of servers, messages are published to subject `<NATSSubject>.<event type>`.
+	NATSURL     string
+	NATSSubject string
+	NATSTimeout time.Duration
+
+	// KafkaURL is a URL of Kafka REST Proxy which publishes messages to KafkaTopic.
+	KafkaURL     string
+	KafkaTopic   string
+	KafkaTimeout time.Duration
+}
+
+// NewSink creates sink based on provided sink type.
+func NewSink(settings SinkSettings) (Sink, error) {
+	switch settings.Type {
+	case SinkStdout:
+		return NewWriterSink(os.Stdout), nil
+	case SinkFile:
+		file, err := os.OpenFile(settings.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, errors.Wrapf(err, "impossible to open outbox file, path=%s", settings.FilePath)
+		}
+		return NewWriterSink(file), nil
+	case SinkWebhook:
+		if settings.WebhookURL == "" {
+			return nil, errors.New("webhook URL is required by webhook sink")
+		}
+		return NewWebhookSink(&http.Client{Timeout: settings.WebhookTimeout}, settings.WebhookURL), nil
+	case SinkNATS:
+		if settings.NATSSubject == "" {
+			return nil, errors.New("subject is required by NATS sink")
+		}
+		publisher, err := NewNATSConnPublisher(settings.NATSURL, settings.NATSTimeout)
+		if err != nil {
+			return nil, err
+		}
+		return NewNATSSink(publisher, settings.NATSSubject), nil
+	case SinkKafka:
+		if settings.KafkaURL == "" || settings.KafkaTopic == "" {
+			return nil, errors.New("REST Proxy URL and topic are required by Kafka sink")
+		}
+		producer := NewKafkaRESTProducer(&http.Client{Timeout: settings.KafkaTimeout}, settings.KafkaURL)
+		return NewKafkaSink(producer, settings.KafkaTopic, settings.KafkaTimeout), nil
+	default:
+		return nil, errors.Errorf("unsupported sink type, sinkType=%s", settings.Type)
+	}
+}
+
+// MultiSink publishes messages to all sinks.
+// Message is published again to all sinks when any of them fails.
+type MultiSink []Sink
+
+// Publish publishes message to all sinks and stops on first error
+func (s MultiSink) Publish(message *Message) error {
+	for _, sink := range s {
+		if err := sink.Publish(message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriterSink writes messages as JSON lines to io.Writer
+type WriterSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewWriterSink creates new instance of WriterSink.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Publish writes message as single JSON line
+func (s *WriterSink) Publish(message *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return errors.Wrapf(s.encoder.Encode(message), "impossible to write message, messageID=%d", message.ID)
+}
+
+// WebhookSink sends messages with HTTP POST request
+type WebhookSink struct {
+	httpClient *http.Client
+	url        string
+}
+
+// NewWebhookSink creates new instance of WebhookSink.
+func NewWebhookSink(httpClient *http.Client, url string) *WebhookSink {
+	return &WebhookSink{
+		httpClient: httpClient,
+		url:        url,
+	}
+}
+
+// Publish sends message to webhook URL, any response status other than 2xx is treated as error
+func (s WebhookSink) Publish(message *Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return errors.Wrapf(err, "impossible to marshal message, messageID=%d", message.ID)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "error creating new http request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(message.ID, 10))
+	req.Header.Set("X-Event-Type", message.Type)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "error sending message to webhook, messageID=%d", message.ID)
+	}
+	//nolint
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("webhook responded with status %d, messageID=%d", resp.StatusCode, message.ID)
+	}
+
+	return nil
+}
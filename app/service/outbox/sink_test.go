@@ -0,0 +1,163 @@
+// +build unit
+
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterSinkPublish(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+
+	require.Nil(t, sink.Publish(&Message{ID: 1, Type: "user.created", UserID: 10, Data: []byte(`{"id":10}`)}))
+	require.Nil(t, sink.Publish(&Message{ID: 2, Type: "user.deleted", UserID: 10, Data: []byte(`{"id":10}`)}))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var message Message
+	require.Nil(t, json.Unmarshal(lines[1], &message))
+	assert.Equal(t, int64(2), message.ID)
+	assert.Equal(t, "user.deleted", message.Type)
+}
+
+func TestWebhookSinkPublish(t *testing.T) {
+	var received Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "user.created", r.Header.Get("X-Event-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.Nil(t, err)
+		require.Nil(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(&http.Client{Timeout: time.Second}, server.URL)
+	require.Nil(t, sink.Publish(&Message{ID: 1, Type: "user.created", UserID: 10, Data: []byte(`{"id":10}`)}))
+	assert.Equal(t, 10, received.UserID)
+}
+
+func TestWebhookSinkPublishError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(&http.Client{Timeout: time.Second}, server.URL)
+	assert.NotNil(t, sink.Publish(&Message{ID: 1, Type: "user.created", UserID: 10}))
+}
+
+// natsPublisherFunc allows to use function as a NATSPublisher
+type natsPublisherFunc func(subject string, data []byte) error
+
+func (f natsPublisherFunc) Publish(subject string, data []byte) error {
+	return f(subject, data)
+}
+
+func TestNATSSinkPublish(t *testing.T) {
+	var subject string
+	var received Message
+	sink := NewNATSSink(natsPublisherFunc(func(s string, data []byte) error {
+		subject = s
+		return json.Unmarshal(data, &received)
+	}), "user-service")
+
+	require.Nil(t, sink.Publish(&Message{ID: 1, Type: "user.created", UserID: 10, Data: []byte(`{"id":10}`)}))
+	assert.Equal(t, "user-service.user.created", subject)
+	assert.Equal(t, int64(1), received.ID)
+	assert.Equal(t, 10, received.UserID)
+}
+
+func TestNATSSinkPublishError(t *testing.T) {
+	sink := NewNATSSink(natsPublisherFunc(func(string, []byte) error {
+		return errors.New("nats: timeout")
+	}), "user-service")
+
+	err := sink.Publish(&Message{ID: 1, Type: "user.created", UserID: 10})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "nats: timeout")
+}
+
+// kafkaProducerFunc allows to use function as a KafkaProducer
+type kafkaProducerFunc func(ctx context.Context, topic string, key string, value []byte) error
+
+func (f kafkaProducerFunc) Produce(ctx context.Context, topic string, key string, value []byte) error {
+	return f(ctx, topic, key, value)
+}
+
+func TestKafkaSinkPublish(t *testing.T) {
+	var topic, key string
+	var received Message
+	sink := NewKafkaSink(kafkaProducerFunc(func(ctx context.Context, t string, k string, value []byte) error {
+		if _, ok := ctx.Deadline(); !ok {
+			return errors.New("context without deadline")
+		}
+		topic, key = t, k
+		return json.Unmarshal(value, &received)
+	}), "user-events", time.Second)
+
+	require.Nil(t, sink.Publish(&Message{ID: 1, Type: "user.created", UserID: 10, Data: []byte(`{"id":10}`)}))
+	assert.Equal(t, "user-events", topic)
+	assert.Equal(t, "10", key)
+	assert.Equal(t, "user.created", received.Type)
+}
+
+func TestKafkaSinkPublishError(t *testing.T) {
+	sink := NewKafkaSink(kafkaProducerFunc(func(context.Context, string, string, []byte) error {
+		return errors.New("broker not available")
+	}), "user-events", time.Second)
+
+	assert.NotNil(t, sink.Publish(&Message{ID: 1, Type: "user.created", UserID: 10}))
+}
+
+func TestKafkaRESTProducerProduce(t *testing.T) {
+	var request kafkaProduceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/topics/user-events", r.URL.Path)
+		assert.Equal(t, kafkaRESTContentType, r.Header.Get("Content-Type"))
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+		_, _ = w.Write([]byte(`{"offsets":[{"partition":0,"offset":7,"error_code":null,"error":null}]}`))
+	}))
+	defer server.Close()
+
+	producer := NewKafkaRESTProducer(&http.Client{Timeout: time.Second}, server.URL+"/")
+	require.Nil(t, producer.Produce(context.Background(), "user-events", "10", []byte(`{"id":1}`)))
+	require.Len(t, request.Records, 1)
+	assert.Equal(t, "10", request.Records[0].Key)
+	assert.JSONEq(t, `{"id":1}`, string(request.Records[0].Value))
+}
+
+func TestKafkaRESTProducerProduceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":50003,"error":"timeout"}]}`))
+	}))
+	defer server.Close()
+
+	producer := NewKafkaRESTProducer(&http.Client{Timeout: time.Second}, server.URL)
+	err := producer.Produce(context.Background(), "user-events", "10", []byte(`{"id":1}`))
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "errorCode=50003")
+}
+
+func TestNewSinkValidatesSettings(t *testing.T) {
+	_, err := NewSink(SinkSettings{Type: SinkNATS})
+	assert.NotNil(t, err)
+	_, err = NewSink(SinkSettings{Type: SinkKafka, KafkaTopic: "user-events"})
+	assert.NotNil(t, err)
+
+	sink, err := NewSink(SinkSettings{Type: SinkKafka, KafkaURL: "http://localhost:8082", KafkaTopic: "user-events",
+		KafkaTimeout: time.Second})
+	require.Nil(t, err)
+	assert.IsType(t, &KafkaSink{}, sink)
+}
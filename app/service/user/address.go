@@ -0,0 +1,260 @@
+package user
+
+import (
+	"strings"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/user/validator"
+)
+
+// AddressProvider provides an interface to work with addresses of users
+type AddressProvider interface {
+	// GetAddresses returns all addresses of the user
+	GetAddresses(userID int) ([]model.Address, error)
+	// GetAddress returns address of the user
+	GetAddress(userID, addressID int) (*model.Address, error)
+	// CreateAddress creates new address of the user
+	CreateAddress(userID int, request *request.CreateAddress) (*model.Address, error)
+	// UpdateAddress updates existing address of the user
+	UpdateAddress(userID, addressID int, request *request.UpdateAddress) error
+	// DeleteAddress deletes address of the user
+	DeleteAddress(userID, addressID int) error
+}
+
+// AddressService represents service which manages addresses of users.
+// Primary address of the user is also stored as legacy address of the user.
+type AddressService struct {
+	userRepository      dao.UserRepositoryProvider
+	addressRepository   dao.AddressRepositoryProvider
+	transactionProvider dao.TransactionProvider
+}
+
+// NewAddressService creates new instance of AddressService.
+func NewAddressService(
+	userRepository dao.UserRepositoryProvider,
+	addressRepository dao.AddressRepositoryProvider,
+	transactionProvider dao.TransactionProvider,
+) *AddressService {
+	return &AddressService{
+		userRepository:      userRepository,
+		addressRepository:   addressRepository,
+		transactionProvider: transactionProvider,
+	}
+}
+
+// GetAddresses returns all addresses of the user
+func (s AddressService) GetAddresses(userID int) ([]model.Address, error) {
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if user == nil {
+		return nil, httperrors.EntityNotFoundError("user")
+	}
+
+	addresses, err := s.addressRepository.FindByUserID(userID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return addresses, nil
+}
+
+// GetAddress returns address of the user
+func (s AddressService) GetAddress(userID, addressID int) (*model.Address, error) {
+	return getAddress(s.addressRepository, userID, addressID)
+}
+
+// CreateAddress creates new address of the user.
+// The first address of the user is always primary.
+func (s AddressService) CreateAddress(userID int, request *request.CreateAddress) (*model.Address, error) {
+
+	if err := validator.ValidateCreateAddressRequest(request); err != nil {
+		return nil, err
+	}
+
+	address := &model.Address{
+		UserID:     userID,
+		Type:       strings.ToLower(request.Type),
+		Street:     request.Street,
+		City:       request.City,
+		PostalCode: request.PostalCode,
+		Region:     request.Region,
+		Country:    strings.ToUpper(request.Country),
+		Primary:    request.Primary,
+	}
+
+	err := runInTransaction(s.transactionProvider, func(repositories dao.Repositories) error {
+		user, err := repositories.Users.GetByID(userID)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		if user == nil {
+			return httperrors.EntityNotFoundError("user")
+		}
+
+		addresses, err := repositories.Addresses.FindByUserID(userID)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		address.Primary = address.Primary || len(addresses) == 0
+		if address.Primary {
+			if err := repositories.Addresses.ClearPrimary(userID); err != nil {
+				return httperrors.InternalServerError.WithCause(err)
+			}
+		}
+
+		if _, err := repositories.Addresses.Create(address); err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		if !address.Primary {
+			return nil
+		}
+
+		return setLegacyAddress(repositories, user, address)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// UpdateAddress updates existing address of the user.
+// Primary address stays primary until another address is marked as primary.
+func (s AddressService) UpdateAddress(userID, addressID int, request *request.UpdateAddress) error {
+
+	if err := validator.ValidateUpdateAddressRequest(request); err != nil {
+		return err
+	}
+
+	return runInTransaction(s.transactionProvider, func(repositories dao.Repositories) error {
+		address, err := getAddress(repositories.Addresses, userID, addressID)
+		if err != nil {
+			return err
+		}
+
+		if request.Primary && !address.Primary {
+			if err := repositories.Addresses.ClearPrimary(userID); err != nil {
+				return httperrors.InternalServerError.WithCause(err)
+			}
+			address.Primary = true
+		}
+
+		address.Type = strings.ToLower(request.Type)
+		address.Street = request.Street
+		address.City = request.City
+		address.PostalCode = request.PostalCode
+		address.Region = request.Region
+		address.Country = strings.ToUpper(request.Country)
+
+		updated, err := repositories.Addresses.Update(address)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		if !updated {
+			return httperrors.EntityNotFoundError("address")
+		}
+
+		if !address.Primary {
+			return nil
+		}
+
+		user, err := repositories.Users.GetByID(userID)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		return setLegacyAddress(repositories, user, address)
+	})
+}
+
+// DeleteAddress deletes address of the user.
+// When primary address is deleted the oldest remaining address becomes primary.
+func (s AddressService) DeleteAddress(userID, addressID int) error {
+	return runInTransaction(s.transactionProvider, func(repositories dao.Repositories) error {
+		address, err := getAddress(repositories.Addresses, userID, addressID)
+		if err != nil {
+			return err
+		}
+
+		deleted, err := repositories.Addresses.Delete(addressID)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		if !deleted {
+			return httperrors.EntityNotFoundError("address")
+		}
+
+		if !address.Primary {
+			return nil
+		}
+
+		addresses, err := repositories.Addresses.FindByUserID(userID)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		// legacy address is kept when the user has no addresses anymore
+		if len(addresses) == 0 {
+			return nil
+		}
+
+		primary := &addresses[0]
+		primary.Primary = true
+		if _, err := repositories.Addresses.Update(primary); err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		user, err := repositories.Users.GetByID(userID)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		return setLegacyAddress(repositories, user, primary)
+	})
+}
+
+// getAddress returns address when it belongs to the user.
+func getAddress(addressRepository dao.AddressRepositoryProvider, userID, addressID int) (*model.Address, error) {
+	address, err := addressRepository.GetByID(addressID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if address == nil || address.UserID != userID {
+		return nil, httperrors.EntityNotFoundError("address")
+	}
+
+	return address, nil
+}
+
+// setLegacyAddress stores primary address as legacy address of the user and publishes updated user.
+// It has to be called inside of transaction which modifies the primary address.
+func setLegacyAddress(repositories dao.Repositories, user *model.User, primary *model.Address) error {
+	if user == nil {
+		return httperrors.EntityNotFoundError("user")
+	}
+
+	legacyAddress := primary.String()
+	if user.Address == legacyAddress {
+		return nil
+	}
+
+	user.Address = legacyAddress
+	if _, err := repositories.Users.Update(user); err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	return emitUserEvent(repositories.Outbox, model.EventUserUpdated, user)
+}
@@ -0,0 +1,162 @@
+// +build unit
+
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// newTestAddressService returns service working on memory user repository with one user.
+func newTestAddressService(
+	t *testing.T,
+	addressRepository dao.AddressRepositoryProvider,
+) (*AddressService, *dao.MemoryUserRepository, *dao.MemoryOutboxRepository, int) {
+	userRepository := dao.NewMemoryUserRepository()
+	outboxRepository := dao.NewMemoryOutboxRepository()
+	userID, err := userRepository.Create(&model.User{Name: "name", Surname: "surname", Address: "legacy address"})
+	require.Nil(t, err)
+
+	service := NewAddressService(
+		userRepository,
+		addressRepository,
+		newMockTransactionProvider(dao.Repositories{
+			Users:     userRepository,
+			Outbox:    outboxRepository,
+			Addresses: addressRepository,
+		}),
+	)
+
+	return service, userRepository, outboxRepository, userID
+}
+
+func TestCreateAddressFirstIsPrimary(t *testing.T) {
+	mockAddressRepository := dao.MockAddressRepositoryProvider{}
+	mockAddressRepository.On("FindByUserID", mock.Anything).Return([]model.Address{}, nil)
+	mockAddressRepository.On("ClearPrimary", mock.Anything).Return(nil)
+	mockAddressRepository.On("Create", mock.Anything).Return(7, nil)
+	service, userRepository, outboxRepository, userID := newTestAddressService(t, &mockAddressRepository)
+
+	address, err := service.CreateAddress(userID, &request.CreateAddress{
+		Type:       "Home",
+		Street:     "Marszałkowska 1",
+		City:       "Warszawa",
+		PostalCode: "00-001",
+		Country:    "pl",
+	})
+	require.Nil(t, err)
+	assert.True(t, address.Primary)
+	assert.Equal(t, model.AddressHome, address.Type)
+	assert.Equal(t, "PL", address.Country)
+	mockAddressRepository.AssertCalled(t, "ClearPrimary", userID)
+
+	user, err := userRepository.GetByID(userID)
+	require.Nil(t, err)
+	assert.Equal(t, "Marszałkowska 1, 00-001 Warszawa, PL", user.Address)
+
+	events, err := outboxRepository.FindPending(time.Now().Add(time.Minute), 10)
+	require.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, model.EventUserUpdated, events[0].Type)
+}
+
+func TestCreateAddressSecondIsNotPrimary(t *testing.T) {
+	mockAddressRepository := dao.MockAddressRepositoryProvider{}
+	mockAddressRepository.On("FindByUserID", mock.Anything).Return([]model.Address{{ID: 1, Primary: true}}, nil)
+	mockAddressRepository.On("Create", mock.Anything).Return(7, nil)
+	service, userRepository, outboxRepository, userID := newTestAddressService(t, &mockAddressRepository)
+
+	address, err := service.CreateAddress(userID, &request.CreateAddress{
+		Type:    model.AddressShipping,
+		Street:  "Unter den Linden 1",
+		City:    "Berlin",
+		Country: "DE",
+	})
+	require.Nil(t, err)
+	assert.False(t, address.Primary)
+	mockAddressRepository.AssertNotCalled(t, "ClearPrimary", mock.Anything)
+
+	user, err := userRepository.GetByID(userID)
+	require.Nil(t, err)
+	assert.Equal(t, "legacy address", user.Address)
+	events, err := outboxRepository.FindPending(time.Now().Add(time.Minute), 10)
+	require.Nil(t, err)
+	assert.Len(t, events, 0)
+}
+
+func TestCreateAddressErrors(t *testing.T) {
+	service, _, _, userID := newTestAddressService(t, &dao.MockAddressRepositoryProvider{})
+
+	_, err := service.CreateAddress(userID, &request.CreateAddress{Type: "work", Country: "POL"})
+	var details httperrors.Details
+	details.Add("/type", httperrors.AddressTypeNotSupported("work"))
+	details.Add("/street", httperrors.AddressStreetEmpty)
+	details.Add("/city", httperrors.AddressCityEmpty)
+	details.Add("/country", httperrors.AddressCountryIncorrect)
+	assert.Equal(t, httperrors.RequestValidationFailed.WithDetails(details...), err)
+
+	_, err = service.CreateAddress(userID+1, &request.CreateAddress{
+		Type:    model.AddressHome,
+		Street:  "street",
+		City:    "city",
+		Country: "PL",
+	})
+	assert.EqualError(t, httperrors.EntityNotFoundError("user"), err.Error())
+}
+
+func TestGetAddressOfAnotherUser(t *testing.T) {
+	mockAddressRepository := dao.MockAddressRepositoryProvider{}
+	mockAddressRepository.On("GetByID", 7).Return(&model.Address{ID: 7, UserID: 2}, nil)
+	mockAddressRepository.On("GetByID", 8).Return(nil, nil)
+	service, _, _, userID := newTestAddressService(t, &mockAddressRepository)
+
+	_, err := service.GetAddress(userID, 7)
+	assert.EqualError(t, httperrors.EntityNotFoundError("address"), err.Error())
+	_, err = service.GetAddress(userID, 8)
+	assert.EqualError(t, httperrors.EntityNotFoundError("address"), err.Error())
+}
+
+func TestDeletePrimaryAddressPromotesOldest(t *testing.T) {
+	mockAddressRepository := dao.MockAddressRepositoryProvider{}
+	service, userRepository, _, userID := newTestAddressService(t, &mockAddressRepository)
+	mockAddressRepository.On("GetByID", 7).Return(&model.Address{ID: 7, UserID: userID, Primary: true}, nil)
+	mockAddressRepository.On("Delete", 7).Return(true, nil)
+	mockAddressRepository.On("FindByUserID", userID).Return([]model.Address{
+		{ID: 3, UserID: userID, Street: "Oak Street 1", City: "Austin", Country: "US"},
+		{ID: 9, UserID: userID, Street: "Elm Street 5", City: "Dayton", Country: "US"},
+	}, nil)
+	mockAddressRepository.On("Update", mock.Anything).Return(true, nil)
+
+	require.Nil(t, service.DeleteAddress(userID, 7))
+	mockAddressRepository.AssertCalled(t, "Update", mock.MatchedBy(func(address *model.Address) bool {
+		return address.ID == 3 && address.Primary
+	}))
+
+	user, err := userRepository.GetByID(userID)
+	require.Nil(t, err)
+	assert.Equal(t, "Oak Street 1, Austin, US", user.Address)
+}
+
+func TestDeleteLastAddressKeepsLegacyAddress(t *testing.T) {
+	mockAddressRepository := dao.MockAddressRepositoryProvider{}
+	service, userRepository, _, userID := newTestAddressService(t, &mockAddressRepository)
+	mockAddressRepository.On("GetByID", 7).Return(&model.Address{ID: 7, UserID: userID, Primary: true}, nil)
+	mockAddressRepository.On("Delete", 7).Return(true, nil)
+	mockAddressRepository.On("FindByUserID", userID).Return([]model.Address{}, nil)
+
+	require.Nil(t, service.DeleteAddress(userID, 7))
+	mockAddressRepository.AssertNotCalled(t, "Update", mock.Anything)
+
+	user, err := userRepository.GetByID(userID)
+	require.Nil(t, err)
+	assert.Equal(t, "legacy address", user.Address)
+}
@@ -0,0 +1,172 @@
+package user
+
+import (
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx/types"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/user/validator"
+)
+
+// AttributeDefinitionProvider provides an interface to work with schema of custom user attributes
+type AttributeDefinitionProvider interface {
+	// GetAttributeDefinitions returns all attribute definitions
+	GetAttributeDefinitions() ([]model.AttributeDefinition, error)
+	// GetAttributeDefinition returns attribute definition based on ID
+	GetAttributeDefinition(definitionID int) (*model.AttributeDefinition, error)
+	// CreateAttributeDefinition creates new attribute definition
+	CreateAttributeDefinition(request *request.CreateAttributeDefinition) (int, error)
+	// UpdateAttributeDefinition updates existing attribute definition
+	UpdateAttributeDefinition(definitionID int, request *request.UpdateAttributeDefinition) error
+	// DeleteAttributeDefinition deletes attribute definition which is not used by any user
+	DeleteAttributeDefinition(definitionID int) error
+}
+
+// AttributeDefinitionService represents service which manages schema of custom user attributes.
+// Existing values of users are not revalidated when the schema changes, they are checked on the next update.
+type AttributeDefinitionService struct {
+	attributeDefinitionRepository dao.AttributeDefinitionRepositoryProvider
+}
+
+// NewAttributeDefinitionService creates new instance of AttributeDefinitionService.
+func NewAttributeDefinitionService(
+	attributeDefinitionRepository dao.AttributeDefinitionRepositoryProvider,
+) *AttributeDefinitionService {
+	return &AttributeDefinitionService{
+		attributeDefinitionRepository: attributeDefinitionRepository,
+	}
+}
+
+// GetAttributeDefinitions returns all attribute definitions
+func (s AttributeDefinitionService) GetAttributeDefinitions() ([]model.AttributeDefinition, error) {
+	definitions, err := s.attributeDefinitionRepository.FindAll()
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return definitions, nil
+}
+
+// GetAttributeDefinition returns attribute definition based on ID
+func (s AttributeDefinitionService) GetAttributeDefinition(definitionID int) (*model.AttributeDefinition, error) {
+	definition, err := s.attributeDefinitionRepository.GetByID(definitionID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if definition == nil {
+		return nil, httperrors.EntityNotFoundError("attribute definition")
+	}
+
+	return definition, nil
+}
+
+// CreateAttributeDefinition creates new attribute definition
+func (s AttributeDefinitionService) CreateAttributeDefinition(request *request.CreateAttributeDefinition) (int, error) {
+
+	if err := validator.ValidateCreateAttributeDefinitionRequest(request); err != nil {
+		return 0, err
+	}
+
+	existing, err := s.attributeDefinitionRepository.GetByName(request.Name)
+	if err != nil {
+		return 0, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if existing != nil {
+		return 0, httperrors.AttributeDefinitionAlreadyExists
+	}
+
+	enum, err := enumJSON(request.Enum)
+	if err != nil {
+		return 0, httperrors.InternalServerError.WithCause(err)
+	}
+
+	definitionID, err := s.attributeDefinitionRepository.Create(&model.AttributeDefinition{
+		Name:     request.Name,
+		Type:     request.Type,
+		Required: request.Required,
+		Enum:     enum,
+		Pattern:  request.Pattern,
+	})
+	if err != nil {
+		return 0, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return definitionID, nil
+}
+
+// UpdateAttributeDefinition updates existing attribute definition
+func (s AttributeDefinitionService) UpdateAttributeDefinition(
+	definitionID int,
+	request *request.UpdateAttributeDefinition,
+) error {
+	definition, err := s.GetAttributeDefinition(definitionID)
+	if err != nil {
+		return err
+	}
+
+	if err := validator.ValidateUpdateAttributeDefinitionRequest(definition.Type, request); err != nil {
+		return err
+	}
+
+	definition.Enum, err = enumJSON(request.Enum)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	definition.Required = request.Required
+	definition.Pattern = request.Pattern
+
+	updated, err := s.attributeDefinitionRepository.Update(definition)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !updated {
+		return httperrors.EntityNotFoundError("attribute definition")
+	}
+
+	return nil
+}
+
+// DeleteAttributeDefinition deletes attribute definition which is not used by any user
+func (s AttributeDefinitionService) DeleteAttributeDefinition(definitionID int) error {
+	definition, err := s.GetAttributeDefinition(definitionID)
+	if err != nil {
+		return err
+	}
+
+	used, err := s.attributeDefinitionRepository.IsUsed(definition.Name)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if used {
+		return httperrors.AttributeDefinitionInUse
+	}
+
+	deleted, err := s.attributeDefinitionRepository.Delete(definitionID)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !deleted {
+		return httperrors.EntityNotFoundError("attribute definition")
+	}
+
+	return nil
+}
+
+// enumJSON returns allowed values of the attribute as JSON array.
+func enumJSON(enum []string) (types.JSONText, error) {
+	if enum == nil {
+		enum = []string{}
+	}
+
+	return json.Marshal(enum)
+}
@@ -0,0 +1,143 @@
+// +build unit
+
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+func TestCreateAttributeDefinition(t *testing.T) {
+	mockRepository := dao.MockAttributeDefinitionRepositoryProvider{}
+	mockRepository.On("GetByName", "department").Return(nil, nil)
+	mockRepository.On("GetByName", "team").Return(&model.AttributeDefinition{ID: 1, Name: "team"}, nil)
+	mockRepository.On("Create", mock.Anything).Return(7, nil)
+	service := NewAttributeDefinitionService(&mockRepository)
+
+	definitionID, err := service.CreateAttributeDefinition(&request.CreateAttributeDefinition{
+		Name: "department",
+		Type: model.AttributeString,
+		Enum: []string{"sales", "support"},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 7, definitionID)
+	mockRepository.AssertCalled(t, "Create", mock.MatchedBy(func(definition *model.AttributeDefinition) bool {
+		return definition.Name == "department" && string(definition.Enum) == `["sales","support"]`
+	}))
+
+	_, err = service.CreateAttributeDefinition(&request.CreateAttributeDefinition{
+		Name: "team",
+		Type: model.AttributeString,
+	})
+	assert.Equal(t, httperrors.AttributeDefinitionAlreadyExists, err)
+}
+
+func TestUpdateAttributeDefinitionKeepsType(t *testing.T) {
+	mockRepository := dao.MockAttributeDefinitionRepositoryProvider{}
+	mockRepository.On("GetByID", 7).Return(&model.AttributeDefinition{
+		ID:   7,
+		Name: "headcount",
+		Type: model.AttributeNumber,
+	}, nil)
+	mockRepository.On("GetByID", 8).Return(nil, nil)
+	mockRepository.On("Update", mock.Anything).Return(true, nil)
+	service := NewAttributeDefinitionService(&mockRepository)
+
+	require.Nil(t, service.UpdateAttributeDefinition(7, &request.UpdateAttributeDefinition{Required: true}))
+	mockRepository.AssertCalled(t, "Update", mock.MatchedBy(func(definition *model.AttributeDefinition) bool {
+		return definition.Required && definition.Type == model.AttributeNumber && string(definition.Enum) == `[]`
+	}))
+
+	err := service.UpdateAttributeDefinition(7, &request.UpdateAttributeDefinition{Pattern: "^[0-9]+$"})
+	var details httperrors.Details
+	details.Add("/pattern", httperrors.AttributePatternNotAllowed)
+	assert.Equal(t, httperrors.RequestValidationFailed.WithDetails(details...), err)
+
+	err = service.UpdateAttributeDefinition(8, &request.UpdateAttributeDefinition{})
+	assert.EqualError(t, httperrors.EntityNotFoundError("attribute definition"), err.Error())
+}
+
+func TestDeleteAttributeDefinitionInUse(t *testing.T) {
+	mockRepository := dao.MockAttributeDefinitionRepositoryProvider{}
+	mockRepository.On("GetByID", 7).Return(&model.AttributeDefinition{ID: 7, Name: "department"}, nil)
+	mockRepository.On("GetByID", 8).Return(&model.AttributeDefinition{ID: 8, Name: "team"}, nil)
+	mockRepository.On("IsUsed", "department").Return(true, nil)
+	mockRepository.On("IsUsed", "team").Return(false, nil)
+	mockRepository.On("Delete", 8).Return(true, nil)
+	service := NewAttributeDefinitionService(&mockRepository)
+
+	assert.Equal(t, httperrors.AttributeDefinitionInUse, service.DeleteAttributeDefinition(7))
+	mockRepository.AssertNotCalled(t, "Delete", 7)
+	require.Nil(t, service.DeleteAttributeDefinition(8))
+}
+
+func TestServiceUserAttributes(t *testing.T) {
+	mockRepository := dao.MockAttributeDefinitionRepositoryProvider{}
+	mockRepository.On("FindAll").Return([]model.AttributeDefinition{
+		{Name: "department", Type: model.AttributeString, Required: true},
+		{Name: "headcount", Type: model.AttributeNumber},
+	}, nil)
+	userRepository := dao.NewMemoryUserRepository()
+	service := NewService(
+		userRepository,
+		&mockRepository,
+		&dao.MockGroupRepositoryProvider{},
+		&dao.MockUserStatusHistoryRepositoryProvider{},
+		dao.NewMemoryTransactor(
+			userRepository,
+			dao.NewMemoryOutboxRepository(),
+			dao.NewMemoryUserStatusHistoryRepository(),
+			dao.NewMemorySessionRepository(),
+		),
+	)
+
+	createRequest := &request.CreateUser{
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+	}
+	_, err := service.CreateUser(createRequest)
+	var details httperrors.Details
+	details.Add("/attributes/department", httperrors.AttributeRequired("department"))
+	assert.Equal(t, httperrors.RequestValidationFailed.WithDetails(details...), err)
+
+	createRequest.Attributes = map[string]interface{}{"department": "sales", "headcount": nil}
+	userID, err := service.CreateUser(createRequest)
+	require.Nil(t, err)
+	user, err := service.GetUser(userID)
+	require.Nil(t, err)
+	assert.Equal(t, types.JSONText(`{"department":"sales"}`), user.Attributes)
+
+	_, _, _, err = service.FindUsers(&request.FindUsers{Attributes: map[string]string{"headcount": "many"}})
+	details = nil
+	details.Add("/attr.headcount", httperrors.AttributeTypeMismatch("headcount", model.AttributeNumber))
+	assert.Equal(t, httperrors.RequestValidationFailed.WithDetails(details...), err)
+}
+
+func TestFindUsersAttributeDefinitionsError(t *testing.T) {
+	mockRepository := dao.MockAttributeDefinitionRepositoryProvider{}
+	mockRepository.On("FindAll").Return(nil, errors.New("connection refused"))
+	service := NewService(
+		&dao.MockUserRepositoryProvider{},
+		&mockRepository,
+		&dao.MockGroupRepositoryProvider{},
+		&dao.MockUserStatusHistoryRepositoryProvider{},
+		&dao.MockTransactionProvider{},
+	)
+
+	_, _, _, err := service.FindUsers(&request.FindUsers{})
+	require.NotNil(t, err)
+	assert.EqualError(t, httperrors.InternalServerError, err.Error())
+}
@@ -0,0 +1,447 @@
+package user
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/auth"
+	"github.com/mmgopher/user-service/app/service/notifier"
+	"github.com/mmgopher/user-service/app/service/user/validator"
+)
+
+// failedAttemptsReason is recorded in status history when user is locked after too many failed login attempts.
+const failedAttemptsReason = "too many failed login attempts"
+
+// LoginResult represents result of login with correct password, either Tokens or MFAChallenge is set.
+type LoginResult struct {
+	Tokens       *Tokens
+	MFAChallenge *MFAChallenge
+}
+
+// MFAChallenge represents single use token which is exchanged for access token together with MFA code.
+type MFAChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// AuthProvider provides an interface to authenticate users with password
+type AuthProvider interface {
+	// Login starts new session of active user when email and password are correct.
+	// MFA challenge is returned instead when MFA of the user is enabled.
+	Login(request *request.Login, client Client) (*LoginResult, error)
+	// LoginMFA starts new session of the user when MFA challenge and code are correct.
+	LoginMFA(request *request.LoginMFA, client Client) (*Tokens, error)
+	// ChangePassword changes password of the user, current password is required.
+	ChangePassword(userID int, request *request.ChangePassword) error
+	// RequestPasswordReset delivers password reset token to the user with the email.
+	// Result doesn't reveal whether the user exists.
+	RequestPasswordReset(request *request.RequestPasswordReset) error
+	// ConfirmPasswordReset sets new password of the user when the reset token is valid.
+	ConfirmPasswordReset(request *request.ConfirmPasswordReset) error
+}
+
+// AuthService represents service which authenticates users with password.
+// User is locked after maxFailedAttempts failed attempts in a row.
+type AuthService struct {
+	userRepository       dao.UserRepositoryProvider
+	credentialRepository dao.CredentialRepositoryProvider
+	tokenRepository      dao.OneTimeTokenRepositoryProvider
+	mfaRepository        dao.MFARepositoryProvider
+	transactionProvider  dao.TransactionProvider
+	notifier             notifier.Notifier
+	hasher               *auth.PasswordHasher
+	tokenIssuer          *auth.TokenIssuer
+	totp                 *auth.TOTP
+	maxFailedAttempts    int
+	resetTokenTTL        time.Duration
+	mfaChallengeTTL      time.Duration
+	sessionTTL           time.Duration
+	now                  func() time.Time
+}
+
+// NewAuthService creates new instance of AuthService.
+func NewAuthService(
+	userRepository dao.UserRepositoryProvider,
+	credentialRepository dao.CredentialRepositoryProvider,
+	tokenRepository dao.OneTimeTokenRepositoryProvider,
+	mfaRepository dao.MFARepositoryProvider,
+	transactionProvider dao.TransactionProvider,
+	notifier notifier.Notifier,
+	hasher *auth.PasswordHasher,
+	tokenIssuer *auth.TokenIssuer,
+	totp *auth.TOTP,
+	maxFailedAttempts int,
+	resetTokenTTL time.Duration,
+	mfaChallengeTTL time.Duration,
+	sessionTTL time.Duration,
+) *AuthService {
+	return &AuthService{
+		userRepository:       userRepository,
+		credentialRepository: credentialRepository,
+		tokenRepository:      tokenRepository,
+		mfaRepository:        mfaRepository,
+		transactionProvider:  transactionProvider,
+		notifier:             notifier,
+		hasher:               hasher,
+		tokenIssuer:          tokenIssuer,
+		totp:                 totp,
+		maxFailedAttempts:    maxFailedAttempts,
+		resetTokenTTL:        resetTokenTTL,
+		mfaChallengeTTL:      mfaChallengeTTL,
+		sessionTTL:           sessionTTL,
+		now:                  time.Now,
+	}
+}
+
+// Login starts new session of active user when email and password are correct.
+// Unknown email, user without password and wrong password are reported with the same error.
+// Password hash is transparently upgraded when hashing parameters were changed.
+// Failed attempts are reset only after MFA challenge is confirmed when MFA of the user is enabled.
+func (s AuthService) Login(request *request.Login, client Client) (*LoginResult, error) {
+
+	if err := validator.ValidateLoginRequest(request); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepository.GetByEmail(request.Email)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	var credential *model.Credential
+	if user != nil {
+		if credential, err = s.credentialRepository.GetByUserID(user.ID); err != nil {
+			return nil, httperrors.InternalServerError.WithCause(err)
+		}
+	}
+
+	if credential == nil {
+		// hashing takes as long as verification, so response time doesn't reveal if the account exists
+		if _, err := s.hasher.Hash(request.Password); err != nil {
+			return nil, httperrors.InternalServerError.WithCause(err)
+		}
+		return nil, httperrors.AuthInvalidCredentials
+	}
+
+	match, rehash, err := s.hasher.Verify(request.Password, credential.PasswordHash)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !match {
+		if err := s.addFailedAttempt(user); err != nil {
+			return nil, err
+		}
+		return nil, httperrors.AuthInvalidCredentials
+	}
+
+	if user.Status != model.UserStatusActive {
+		return nil, httperrors.AuthUserNotActive(user.Status)
+	}
+
+	if rehash {
+		if err := s.rehash(user.ID, request.Password, credential.PasswordHash); err != nil {
+			return nil, err
+		}
+	}
+
+	mfa, err := s.mfaRepository.GetByUserID(user.ID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if mfa != nil && mfa.Enabled() {
+		token, challenge, err := issueOneTimeToken(
+			s.tokenRepository,
+			model.TokenPurposeMFAChallenge,
+			user.ID,
+			"",
+			s.now().UTC().Add(s.mfaChallengeTTL),
+		)
+		if err != nil {
+			return nil, httperrors.InternalServerError.WithCause(err)
+		}
+
+		return &LoginResult{MFAChallenge: &MFAChallenge{Token: token, ExpiresAt: challenge.ExpiresAt}}, nil
+	}
+
+	if credential.FailedAttempts > 0 {
+		if err := s.credentialRepository.ResetFailedAttempts(user.ID); err != nil {
+			return nil, httperrors.InternalServerError.WithCause(err)
+		}
+	}
+
+	tokens, err := startSession(s.transactionProvider, s.tokenIssuer, user.ID, client, s.now().UTC(), s.sessionTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{Tokens: tokens}, nil
+}
+
+// LoginMFA starts new session of the user when MFA challenge and code are correct.
+// Challenge can be used once, incorrect codes count as failed login attempts and keep the challenge valid.
+func (s AuthService) LoginMFA(request *request.LoginMFA, client Client) (*Tokens, error) {
+
+	if err := validator.ValidateLoginMFARequest(request); err != nil {
+		return nil, err
+	}
+
+	challenge, err := findOneTimeToken(s.tokenRepository, model.TokenPurposeMFAChallenge, request.MFAToken, s.now().UTC())
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if challenge == nil {
+		return nil, httperrors.MFAChallengeIncorrect
+	}
+
+	user, err := s.userRepository.GetByID(challenge.UserID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if user == nil {
+		return nil, httperrors.MFAChallengeIncorrect
+	}
+
+	// the user could be locked or suspended after the challenge was issued
+	if user.Status != model.UserStatusActive {
+		return nil, httperrors.AuthUserNotActive(user.Status)
+	}
+
+	mfa, err := s.mfaRepository.GetByUserID(user.ID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if mfa == nil || !mfa.Enabled() {
+		return nil, httperrors.MFAChallengeIncorrect
+	}
+
+	ok, err := verifyMFACode(s.mfaRepository, s.totp, mfa, request.Code)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !ok {
+		if err := s.addFailedAttempt(user); err != nil {
+			return nil, err
+		}
+		return nil, httperrors.MFALoginCodeIncorrect
+	}
+
+	used, err := s.tokenRepository.Use(challenge.TokenHash)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !used {
+		return nil, httperrors.MFAChallengeIncorrect
+	}
+
+	if err := s.credentialRepository.ResetFailedAttempts(user.ID); err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return startSession(s.transactionProvider, s.tokenIssuer, user.ID, client, s.now().UTC(), s.sessionTTL)
+}
+
+// ChangePassword changes password of the user, current password is required.
+// User without password has to set it with password reset, which proves ownership of the email.
+// Wrong current password counts as failed login attempt.
+func (s AuthService) ChangePassword(userID int, request *request.ChangePassword) error {
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if user == nil {
+		return httperrors.EntityNotFoundError("user")
+	}
+
+	if err := validator.ValidateChangePasswordRequest(request); err != nil {
+		return err
+	}
+
+	credential, err := s.credentialRepository.GetByUserID(userID)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if credential == nil {
+		return httperrors.PasswordNotSet
+	}
+
+	match, _, err := s.hasher.Verify(request.CurrentPassword, credential.PasswordHash)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !match {
+		if err := s.addFailedAttempt(user); err != nil {
+			return err
+		}
+		return httperrors.PasswordCurrentIncorrect
+	}
+
+	passwordHash, err := s.hasher.Hash(request.NewPassword)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if err := s.credentialRepository.Save(userID, passwordHash); err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	return nil
+}
+
+// RequestPasswordReset delivers password reset token to the user with the email.
+// Unknown email and closed user are silently ignored and delivery errors are only logged,
+// so the result doesn't reveal whether the user exists.
+func (s AuthService) RequestPasswordReset(request *request.RequestPasswordReset) error {
+
+	if err := validator.ValidateRequestPasswordResetRequest(request); err != nil {
+		return err
+	}
+
+	user, err := s.userRepository.GetByEmail(request.Email)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if user == nil || user.Status == model.UserStatusClosed {
+		return nil
+	}
+
+	if err := s.sendPasswordReset(user); err != nil {
+		log.Errorf("%+v", err)
+	}
+
+	return nil
+}
+
+// ConfirmPasswordReset sets new password of the user when the reset token is valid.
+// Token is rejected when it expired, it was already used or email of the user was changed after it was issued.
+// Failed login attempts are reset, but locked user has to be activated separately.
+func (s AuthService) ConfirmPasswordReset(request *request.ConfirmPasswordReset) error {
+
+	if err := validator.ValidateConfirmPasswordResetRequest(request); err != nil {
+		return err
+	}
+
+	token, err := findOneTimeToken(s.tokenRepository, model.TokenPurposePasswordReset, request.Token, s.now().UTC())
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if token == nil {
+		return httperrors.PasswordResetTokenIncorrect
+	}
+
+	user, err := s.userRepository.GetByID(token.UserID)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if user == nil || user.Status == model.UserStatusClosed || !strings.EqualFold(user.Email, token.Subject) {
+		return httperrors.PasswordResetTokenIncorrect
+	}
+
+	// tokens are single use, only one of concurrent requests with the same token succeeds
+	used, err := s.tokenRepository.Use(token.TokenHash)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !used {
+		return httperrors.PasswordResetTokenIncorrect
+	}
+
+	passwordHash, err := s.hasher.Hash(request.NewPassword)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if err := s.credentialRepository.Save(user.ID, passwordHash); err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	return nil
+}
+
+// sendPasswordReset issues password reset token and delivers it to the user.
+// Tokens issued earlier can not be used anymore.
+func (s AuthService) sendPasswordReset(user *model.User) error {
+	token, stored, err := issueOneTimeToken(
+		s.tokenRepository,
+		model.TokenPurposePasswordReset,
+		user.ID,
+		user.Email,
+		s.now().UTC().Add(s.resetTokenTTL),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "impossible to issue password reset token, userID=%d", user.ID)
+	}
+
+	return s.notifier.Notify(&notifier.Notification{
+		Type:      notifier.NotificationPasswordReset,
+		UserID:    user.ID,
+		Recipient: user.Email,
+		Token:     token,
+		ExpiresAt: stored.ExpiresAt,
+	})
+}
+
+// addFailedAttempt records failed attempt of active user and locks the user when there were too many of them.
+func (s AuthService) addFailedAttempt(user *model.User) error {
+	return addFailedAttempt(s.transactionProvider, user, s.maxFailedAttempts, s.now().UTC())
+}
+
+// rehash stores hash of the password created with current hashing parameters.
+// Hash is kept when the password was changed concurrently.
+func (s AuthService) rehash(userID int, password, passwordHash string) error {
+	newHash, err := s.hasher.Hash(password)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if _, err := s.credentialRepository.Rehash(userID, passwordHash, newHash); err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	return nil
+}
+
+// addFailedAttempt records failed attempt of active user and locks the user after maxFailedAttempts of them.
+// Attempts are reset when the user is locked, so the user can try again after being activated.
+func addFailedAttempt(provider dao.TransactionProvider, user *model.User, maxFailedAttempts int, now time.Time) error {
+	if user.Status != model.UserStatusActive {
+		return nil
+	}
+
+	return runInTransaction(provider, func(repositories dao.Repositories) error {
+		attempts, err := repositories.Credentials.AddFailedAttempt(user.ID)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		if attempts < maxFailedAttempts {
+			return nil
+		}
+
+		if err := repositories.Credentials.ResetFailedAttempts(user.ID); err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		return changeUserStatus(repositories, user, model.UserStatusLocked, failedAttemptsReason, now)
+	})
+}
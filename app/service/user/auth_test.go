@@ -0,0 +1,516 @@
+// +build unit
+
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/auth"
+	"github.com/mmgopher/user-service/app/service/notifier"
+)
+
+var testArgon2Params = auth.Argon2Params{Time: 1, Memory: 64, Threads: 1, SaltLength: 16, KeyLength: 32}
+
+var testClient = Client{IP: "192.0.2.10", UserAgent: "test-agent/1.0"}
+
+// newTestAuthService returns service which locks users after 3 failed attempts, users don't have MFA.
+// Sessions are stored in memory unless repositories provide them.
+func newTestAuthService(
+	mockUserRepository *dao.MockUserRepositoryProvider,
+	mockCredentialRepository *dao.MockCredentialRepositoryProvider,
+	repositories dao.Repositories,
+) (*AuthService, *auth.TokenIssuer) {
+	mockMFARepository := dao.MockMFARepositoryProvider{}
+	mockMFARepository.On("GetByUserID", mock.Anything).Return(nil, nil)
+	tokenIssuer := auth.NewTokenIssuer([]byte("secret"), "user-service", time.Hour)
+	if repositories.Sessions == nil {
+		repositories.Sessions = dao.NewMemorySessionRepository()
+	}
+	return NewAuthService(
+		mockUserRepository,
+		mockCredentialRepository,
+		&dao.MockOneTimeTokenRepositoryProvider{},
+		&mockMFARepository,
+		newMockTransactionProvider(repositories),
+		notifier.NewWriterNotifier(ioutil.Discard),
+		auth.NewPasswordHasher(testArgon2Params),
+		tokenIssuer,
+		auth.NewTOTP("user-service", 1),
+		3,
+		time.Hour,
+		5*time.Minute,
+		24*time.Hour,
+	), tokenIssuer
+}
+
+// newTestPasswordResetService returns service which writes notifications to the buffer.
+func newTestPasswordResetService(
+	mockUserRepository *dao.MockUserRepositoryProvider,
+	mockCredentialRepository *dao.MockCredentialRepositoryProvider,
+	mockTokenRepository *dao.MockOneTimeTokenRepositoryProvider,
+	notifications *bytes.Buffer,
+) *AuthService {
+	service, _ := newTestAuthService(mockUserRepository, mockCredentialRepository, dao.Repositories{})
+	service.tokenRepository = mockTokenRepository
+	service.notifier = notifier.NewWriterNotifier(notifications)
+	service.now = func() time.Time {
+		return time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+	}
+	return service
+}
+
+func hashTestPassword(t *testing.T, params auth.Argon2Params, password string) string {
+	hash, err := auth.NewPasswordHasher(params).Hash(password)
+	require.Nil(t, err)
+	return hash
+}
+
+func TestLoginOK(t *testing.T) {
+	user := &model.User{ID: 5001, Email: "john@example.com", Status: model.UserStatusActive}
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByEmail", "John@example.com").Return(user, nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("GetByUserID", 5001).Return(&model.Credential{
+		UserID:       5001,
+		PasswordHash: hashTestPassword(t, testArgon2Params, "correct horse 1"),
+	}, nil)
+
+	service, tokenIssuer := newTestAuthService(&mockUserRepository, &mockCredentialRepository, dao.Repositories{})
+	result, err := service.Login(&request.Login{Email: "John@example.com", Password: "correct horse 1"}, testClient)
+	require.Nil(t, err)
+	require.Nil(t, result.MFAChallenge)
+
+	claims, err := tokenIssuer.Parse(result.Tokens.Access.Value)
+	require.Nil(t, err)
+	assert.Equal(t, strconv.Itoa(user.ID), claims.Subject)
+	assert.NotZero(t, claims.SessionID)
+	assert.NotEmpty(t, result.Tokens.Refresh)
+	mockCredentialRepository.AssertNotCalled(t, "Rehash", mock.Anything, mock.Anything, mock.Anything)
+	mockCredentialRepository.AssertNotCalled(t, "ResetFailedAttempts", mock.Anything)
+}
+
+func TestLoginRehashesPassword(t *testing.T) {
+	weaker := testArgon2Params
+	weaker.Memory = 32
+	oldHash := hashTestPassword(t, weaker, "correct horse 1")
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByEmail", "john@example.com").Return(
+		&model.User{ID: 5001, Status: model.UserStatusActive}, nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("GetByUserID", 5001).Return(&model.Credential{
+		UserID:         5001,
+		PasswordHash:   oldHash,
+		FailedAttempts: 2,
+	}, nil)
+	mockCredentialRepository.On("ResetFailedAttempts", 5001).Return(nil)
+	mockCredentialRepository.On("Rehash", 5001, oldHash, mock.MatchedBy(func(hash string) bool {
+		match, rehash, err := auth.NewPasswordHasher(testArgon2Params).Verify("correct horse 1", hash)
+		return err == nil && match && !rehash
+	})).Return(true, nil)
+
+	service, _ := newTestAuthService(&mockUserRepository, &mockCredentialRepository, dao.Repositories{})
+	_, err := service.Login(&request.Login{Email: "john@example.com", Password: "correct horse 1"}, testClient)
+	require.Nil(t, err)
+	mockCredentialRepository.AssertExpectations(t)
+}
+
+func TestLoginInvalidCredentials(t *testing.T) {
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByEmail", "unknown@example.com").Return(nil, nil)
+	mockUserRepository.On("GetByEmail", "jane@example.com").Return(
+		&model.User{ID: 5002, Status: model.UserStatusActive}, nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("GetByUserID", 5002).Return(nil, nil)
+
+	service, _ := newTestAuthService(&mockUserRepository, &mockCredentialRepository, dao.Repositories{})
+	for _, email := range []string{"unknown@example.com", "jane@example.com"} {
+		_, err := service.Login(&request.Login{Email: email, Password: "correct horse 1"}, testClient)
+		assert.Equal(t, httperrors.AuthInvalidCredentials, err, email)
+	}
+
+	var expected httperrors.Details
+	expected.Add("/password", httperrors.AuthPasswordEmpty)
+	_, err := service.Login(&request.Login{Email: "jane@example.com"}, testClient)
+	assert.Equal(t, expected.Err(), err)
+}
+
+func TestLoginLocksUserAfterFailedAttempts(t *testing.T) {
+	user := &model.User{ID: 5001, Status: model.UserStatusActive}
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByEmail", "john@example.com").Return(user, nil)
+	mockUserRepository.On("UpdateStatus", 5001, model.UserStatusActive, model.UserStatusLocked).Return(true, nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("GetByUserID", 5001).Return(&model.Credential{
+		UserID:       5001,
+		PasswordHash: hashTestPassword(t, testArgon2Params, "correct horse 1"),
+	}, nil)
+	mockCredentialRepository.On("AddFailedAttempt", 5001).Return(2, nil).Once()
+	mockCredentialRepository.On("AddFailedAttempt", 5001).Return(3, nil).Once()
+	mockCredentialRepository.On("ResetFailedAttempts", 5001).Return(nil)
+	mockStatusHistoryRepository := dao.MockUserStatusHistoryRepositoryProvider{}
+	mockStatusHistoryRepository.On("Add", &model.UserStatusChange{
+		UserID:     5001,
+		FromStatus: model.UserStatusActive,
+		ToStatus:   model.UserStatusLocked,
+		Reason:     failedAttemptsReason,
+	}).Return(nil)
+	mockOutboxRepository := dao.MockOutboxRepositoryProvider{}
+	mockOutboxRepository.On("Add", mock.Anything).Return(nil)
+
+	service, _ := newTestAuthService(&mockUserRepository, &mockCredentialRepository, dao.Repositories{
+		Users:         &mockUserRepository,
+		Outbox:        &mockOutboxRepository,
+		StatusHistory: &mockStatusHistoryRepository,
+		Credentials:   &mockCredentialRepository,
+	})
+
+	_, err := service.Login(&request.Login{Email: "john@example.com", Password: "wrong password"}, testClient)
+	assert.Equal(t, httperrors.AuthInvalidCredentials, err)
+	mockUserRepository.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+
+	_, err = service.Login(&request.Login{Email: "john@example.com", Password: "wrong password"}, testClient)
+	assert.Equal(t, httperrors.AuthInvalidCredentials, err)
+	assert.Equal(t, model.UserStatusLocked, user.Status)
+	mockCredentialRepository.AssertExpectations(t)
+	mockStatusHistoryRepository.AssertExpectations(t)
+
+	// locked user can't log in even with correct password and further attempts aren't counted
+	_, err = service.Login(&request.Login{Email: "john@example.com", Password: "correct horse 1"}, testClient)
+	assert.Equal(t, httperrors.AuthUserNotActive(model.UserStatusLocked), err)
+	_, err = service.Login(&request.Login{Email: "john@example.com", Password: "wrong password"}, testClient)
+	assert.Equal(t, httperrors.AuthInvalidCredentials, err)
+	mockCredentialRepository.AssertNumberOfCalls(t, "AddFailedAttempt", 2)
+}
+
+func TestChangePasswordOK(t *testing.T) {
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByID", 5001).Return(&model.User{ID: 5001, Status: model.UserStatusActive}, nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("GetByUserID", 5001).Return(&model.Credential{
+		UserID:       5001,
+		PasswordHash: hashTestPassword(t, testArgon2Params, "correct horse 1"),
+	}, nil)
+	mockCredentialRepository.On("Save", 5001, mock.MatchedBy(func(hash string) bool {
+		match, _, err := auth.NewPasswordHasher(testArgon2Params).Verify("battery staple 2", hash)
+		return err == nil && match
+	})).Return(nil)
+
+	service, _ := newTestAuthService(&mockUserRepository, &mockCredentialRepository, dao.Repositories{})
+	err := service.ChangePassword(5001, &request.ChangePassword{
+		CurrentPassword: "correct horse 1",
+		NewPassword:     "battery staple 2",
+	})
+	require.Nil(t, err)
+	mockCredentialRepository.AssertExpectations(t)
+}
+
+// TestChangePasswordWithoutCredential checks that the first password can't be set without proof of email ownership.
+func TestChangePasswordWithoutCredential(t *testing.T) {
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByID", 5001).Return(&model.User{ID: 5001, Status: model.UserStatusActive}, nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("GetByUserID", 5001).Return(nil, nil)
+
+	service, _ := newTestAuthService(&mockUserRepository, &mockCredentialRepository, dao.Repositories{})
+	for _, currentPassword := range []string{"", "correct horse 1"} {
+		err := service.ChangePassword(5001, &request.ChangePassword{
+			CurrentPassword: currentPassword,
+			NewPassword:     "battery staple 2",
+		})
+		assert.Equal(t, httperrors.PasswordNotSet, err)
+	}
+	mockCredentialRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	mockCredentialRepository.AssertNotCalled(t, "AddFailedAttempt", mock.Anything)
+}
+
+func TestChangePasswordErrors(t *testing.T) {
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByID", 5001).Return(&model.User{ID: 5001, Status: model.UserStatusActive}, nil)
+	mockUserRepository.On("GetByID", 5002).Return(nil, nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("GetByUserID", 5001).Return(&model.Credential{
+		UserID:       5001,
+		PasswordHash: hashTestPassword(t, testArgon2Params, "correct horse 1"),
+	}, nil)
+	mockCredentialRepository.On("AddFailedAttempt", 5001).Return(1, nil)
+
+	service, _ := newTestAuthService(&mockUserRepository, &mockCredentialRepository, dao.Repositories{
+		Credentials: &mockCredentialRepository,
+	})
+
+	err := service.ChangePassword(5002, &request.ChangePassword{NewPassword: "battery staple 2"})
+	assert.Equal(t, httperrors.EntityNotFoundError("user"), err)
+
+	err = service.ChangePassword(5001, &request.ChangePassword{NewPassword: "battery staple 2"})
+	assert.Equal(t, httperrors.PasswordCurrentIncorrect, err)
+	mockCredentialRepository.AssertCalled(t, "AddFailedAttempt", 5001)
+	mockCredentialRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+
+	var expected httperrors.Details
+	expected.Add("/new_password", httperrors.PasswordTooWeak)
+	err = service.ChangePassword(5001, &request.ChangePassword{NewPassword: "battery staple"})
+	assert.Equal(t, expected.Err(), err)
+}
+
+func TestRequestPasswordResetOK(t *testing.T) {
+	var created *model.OneTimeToken
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByEmail", "John@example.com").Return(
+		&model.User{ID: 5001, Email: "john@example.com", Status: model.UserStatusLocked}, nil)
+	mockTokenRepository := dao.MockOneTimeTokenRepositoryProvider{}
+	mockTokenRepository.On("DeleteByUserID", model.TokenPurposePasswordReset, 5001).Return(nil)
+	mockTokenRepository.On("Create", mock.Anything).Return(func(token *model.OneTimeToken) error {
+		created = token
+		return nil
+	})
+	var notifications bytes.Buffer
+
+	service := newTestPasswordResetService(&mockUserRepository, &dao.MockCredentialRepositoryProvider{},
+		&mockTokenRepository, &notifications)
+	require.Nil(t, service.RequestPasswordReset(&request.RequestPasswordReset{Email: "John@example.com"}))
+
+	var notification notifier.Notification
+	require.Nil(t, json.Unmarshal(notifications.Bytes(), &notification))
+	assert.Equal(t, notifier.NotificationPasswordReset, notification.Type)
+	assert.Equal(t, "john@example.com", notification.Recipient)
+	require.NotNil(t, created)
+	assert.Equal(t, model.TokenPurposePasswordReset, created.Purpose)
+	assert.Equal(t, "john@example.com", created.Subject)
+	assert.Equal(t, hashOneTimeToken(notification.Token), created.TokenHash)
+	assert.Equal(t, time.Date(2020, 10, 19, 13, 0, 0, 0, time.UTC), created.ExpiresAt)
+}
+
+func TestRequestPasswordResetDoesNotRevealUser(t *testing.T) {
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByEmail", "unknown@example.com").Return(nil, nil)
+	mockUserRepository.On("GetByEmail", "closed@example.com").Return(
+		&model.User{ID: 5001, Email: "closed@example.com", Status: model.UserStatusClosed}, nil)
+	mockUserRepository.On("GetByEmail", "john@example.com").Return(
+		&model.User{ID: 5002, Email: "john@example.com", Status: model.UserStatusActive}, nil)
+	mockTokenRepository := dao.MockOneTimeTokenRepositoryProvider{}
+	mockTokenRepository.On("DeleteByUserID", model.TokenPurposePasswordReset, 5002).Return(errors.New("db error"))
+	var notifications bytes.Buffer
+
+	service := newTestPasswordResetService(&mockUserRepository, &dao.MockCredentialRepositoryProvider{},
+		&mockTokenRepository, &notifications)
+	for _, email := range []string{"unknown@example.com", "closed@example.com", "john@example.com"} {
+		assert.Nil(t, service.RequestPasswordReset(&request.RequestPasswordReset{Email: email}), email)
+	}
+	assert.Zero(t, notifications.Len())
+	mockTokenRepository.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestConfirmPasswordResetOK(t *testing.T) {
+	token := "token"
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByID", 5001).Return(
+		&model.User{ID: 5001, Email: "John@example.com", Status: model.UserStatusActive}, nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("Save", 5001, mock.MatchedBy(func(hash string) bool {
+		match, _, err := auth.NewPasswordHasher(testArgon2Params).Verify("battery staple 2", hash)
+		return err == nil && match
+	})).Return(nil)
+	mockTokenRepository := dao.MockOneTimeTokenRepositoryProvider{}
+	service := newTestPasswordResetService(&mockUserRepository, &mockCredentialRepository,
+		&mockTokenRepository, &bytes.Buffer{})
+	mockTokenRepository.On("Get", model.TokenPurposePasswordReset, hashOneTimeToken(token)).Return(&model.OneTimeToken{
+		TokenHash: hashOneTimeToken(token),
+		Purpose:   model.TokenPurposePasswordReset,
+		UserID:    5001,
+		Subject:   "john@example.com",
+		ExpiresAt: service.now().Add(time.Minute),
+	}, nil)
+	mockTokenRepository.On("Use", hashOneTimeToken(token)).Return(true, nil)
+
+	require.Nil(t, service.ConfirmPasswordReset(&request.ConfirmPasswordReset{
+		Token:       token,
+		NewPassword: "battery staple 2",
+	}))
+	mockCredentialRepository.AssertExpectations(t)
+	mockTokenRepository.AssertExpectations(t)
+}
+
+func TestConfirmPasswordResetTokenIncorrect(t *testing.T) {
+	var testData = []struct {
+		name  string
+		token func(now time.Time) *model.OneTimeToken
+		used  bool
+	}{
+		{"Unknown", func(time.Time) *model.OneTimeToken {
+			return nil
+		}, true},
+		{"Expired", func(now time.Time) *model.OneTimeToken {
+			return &model.OneTimeToken{UserID: 5001, Subject: "john@example.com", ExpiresAt: now}
+		}, true},
+		{"UserDeleted", func(now time.Time) *model.OneTimeToken {
+			return &model.OneTimeToken{UserID: 5002, Subject: "john@example.com", ExpiresAt: now.Add(time.Hour)}
+		}, true},
+		{"EmailChanged", func(now time.Time) *model.OneTimeToken {
+			return &model.OneTimeToken{UserID: 5001, Subject: "old@example.com", ExpiresAt: now.Add(time.Hour)}
+		}, true},
+		{"AlreadyUsed", func(now time.Time) *model.OneTimeToken {
+			return &model.OneTimeToken{UserID: 5001, Subject: "john@example.com", ExpiresAt: now.Add(time.Hour)}
+		}, false},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepository := dao.MockUserRepositoryProvider{}
+			mockUserRepository.On("GetByID", 5001).Return(
+				&model.User{ID: 5001, Email: "john@example.com", Status: model.UserStatusActive}, nil)
+			mockUserRepository.On("GetByID", 5002).Return(nil, nil)
+			mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+			mockTokenRepository := dao.MockOneTimeTokenRepositoryProvider{}
+			service := newTestPasswordResetService(&mockUserRepository, &mockCredentialRepository,
+				&mockTokenRepository, &bytes.Buffer{})
+			mockTokenRepository.On("Get", model.TokenPurposePasswordReset, mock.Anything).Return(
+				tt.token(service.now()), nil)
+			mockTokenRepository.On("Use", mock.Anything).Return(tt.used, nil)
+
+			err := service.ConfirmPasswordReset(&request.ConfirmPasswordReset{
+				Token:       "token",
+				NewPassword: "battery staple 2",
+			})
+			assert.Equal(t, httperrors.PasswordResetTokenIncorrect, err)
+			mockCredentialRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// newTestMFALoginService returns service whose users have MFA stored in the repository.
+func newTestMFALoginService(
+	mockUserRepository *dao.MockUserRepositoryProvider,
+	mockCredentialRepository *dao.MockCredentialRepositoryProvider,
+	mockTokenRepository *dao.MockOneTimeTokenRepositoryProvider,
+	mockMFARepository *dao.MockMFARepositoryProvider,
+	repositories dao.Repositories,
+) (*AuthService, *auth.TokenIssuer) {
+	service, tokenIssuer := newTestAuthService(mockUserRepository, mockCredentialRepository, repositories)
+	service.tokenRepository = mockTokenRepository
+	service.mfaRepository = mockMFARepository
+	return service, tokenIssuer
+}
+
+func TestLoginReturnsMFAChallenge(t *testing.T) {
+	var created *model.OneTimeToken
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByEmail", "john@example.com").Return(
+		&model.User{ID: 5001, Status: model.UserStatusActive}, nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("GetByUserID", 5001).Return(&model.Credential{
+		UserID:         5001,
+		PasswordHash:   hashTestPassword(t, testArgon2Params, "correct horse 1"),
+		FailedAttempts: 2,
+	}, nil)
+	mockTokenRepository := dao.MockOneTimeTokenRepositoryProvider{}
+	mockTokenRepository.On("DeleteByUserID", model.TokenPurposeMFAChallenge, 5001).Return(nil)
+	mockTokenRepository.On("Create", mock.Anything).Return(func(token *model.OneTimeToken) error {
+		created = token
+		return nil
+	})
+	enabledAt := time.Now()
+	mockMFARepository := dao.MockMFARepositoryProvider{}
+	mockMFARepository.On("GetByUserID", 5001).Return(&model.MFA{UserID: 5001, EnabledAt: &enabledAt}, nil)
+
+	service, _ := newTestMFALoginService(&mockUserRepository, &mockCredentialRepository,
+		&mockTokenRepository, &mockMFARepository, dao.Repositories{})
+	result, err := service.Login(&request.Login{Email: "john@example.com", Password: "correct horse 1"}, testClient)
+	require.Nil(t, err)
+	assert.Nil(t, result.Tokens)
+	require.NotNil(t, result.MFAChallenge)
+	require.NotNil(t, created)
+	assert.Equal(t, hashOneTimeToken(result.MFAChallenge.Token), created.TokenHash)
+	assert.Equal(t, created.ExpiresAt, result.MFAChallenge.ExpiresAt)
+	mockCredentialRepository.AssertNotCalled(t, "ResetFailedAttempts", mock.Anything)
+}
+
+func TestLoginMFAOK(t *testing.T) {
+	challenge := "challenge"
+	secret, err := auth.NewTOTP("user-service", 0).GenerateSecret()
+	require.Nil(t, err)
+	code, err := auth.NewTOTP("user-service", 0).Code(secret)
+	require.Nil(t, err)
+
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByID", 5001).Return(&model.User{ID: 5001, Status: model.UserStatusActive}, nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("ResetFailedAttempts", 5001).Return(nil)
+	mockTokenRepository := dao.MockOneTimeTokenRepositoryProvider{}
+	mockTokenRepository.On("Get", model.TokenPurposeMFAChallenge, hashOneTimeToken(challenge)).Return(
+		&model.OneTimeToken{
+			TokenHash: hashOneTimeToken(challenge),
+			UserID:    5001,
+			ExpiresAt: time.Now().Add(time.Minute),
+		}, nil)
+	mockTokenRepository.On("Use", hashOneTimeToken(challenge)).Return(true, nil)
+	enabledAt := time.Now()
+	mockMFARepository := dao.MockMFARepositoryProvider{}
+	mockMFARepository.On("GetByUserID", 5001).Return(
+		&model.MFA{UserID: 5001, Secret: secret, EnabledAt: &enabledAt}, nil)
+	mockMFARepository.On("UseStep", 5001, mock.Anything).Return(true, nil)
+
+	service, tokenIssuer := newTestMFALoginService(&mockUserRepository, &mockCredentialRepository,
+		&mockTokenRepository, &mockMFARepository, dao.Repositories{})
+	tokens, err := service.LoginMFA(&request.LoginMFA{MFAToken: challenge, Code: code}, testClient)
+	require.Nil(t, err)
+
+	claims, err := tokenIssuer.Parse(tokens.Access.Value)
+	require.Nil(t, err)
+	assert.Equal(t, "5001", claims.Subject)
+	mockCredentialRepository.AssertExpectations(t)
+	mockTokenRepository.AssertExpectations(t)
+	mockMFARepository.AssertExpectations(t)
+}
+
+func TestLoginMFAErrors(t *testing.T) {
+	secret, err := auth.NewTOTP("user-service", 0).GenerateSecret()
+	require.Nil(t, err)
+	user := &model.User{ID: 5001, Status: model.UserStatusActive}
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByID", 5001).Return(user, nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("AddFailedAttempt", 5001).Return(1, nil)
+	mockTokenRepository := dao.MockOneTimeTokenRepositoryProvider{}
+	mockTokenRepository.On("Get", model.TokenPurposeMFAChallenge, hashOneTimeToken("unknown")).Return(nil, nil)
+	mockTokenRepository.On("Get", model.TokenPurposeMFAChallenge, hashOneTimeToken("challenge")).Return(
+		&model.OneTimeToken{UserID: 5001, ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	enabledAt := time.Now()
+	mockMFARepository := dao.MockMFARepositoryProvider{}
+	mockMFARepository.On("GetByUserID", 5001).Return(
+		&model.MFA{UserID: 5001, Secret: secret, EnabledAt: &enabledAt}, nil)
+	mockMFARepository.On("UseRecoveryCode", 5001, mock.Anything).Return(false, nil)
+
+	service, _ := newTestMFALoginService(&mockUserRepository, &mockCredentialRepository,
+		&mockTokenRepository, &mockMFARepository, dao.Repositories{Credentials: &mockCredentialRepository})
+
+	_, err = service.LoginMFA(&request.LoginMFA{MFAToken: "unknown", Code: "123456"}, testClient)
+	assert.Equal(t, httperrors.MFAChallengeIncorrect, err)
+
+	_, err = service.LoginMFA(&request.LoginMFA{MFAToken: "challenge", Code: "wrong-code"}, testClient)
+	assert.Equal(t, httperrors.MFALoginCodeIncorrect, err)
+	mockCredentialRepository.AssertCalled(t, "AddFailedAttempt", 5001)
+	mockTokenRepository.AssertNotCalled(t, "Use", mock.Anything)
+
+	user.Status = model.UserStatusLocked
+	_, err = service.LoginMFA(&request.LoginMFA{MFAToken: "challenge", Code: "123456"}, testClient)
+	assert.Equal(t, httperrors.AuthUserNotActive(model.UserStatusLocked), err)
+
+	var expected httperrors.Details
+	expected.Add("/code", httperrors.MFACodeEmpty)
+	_, err = service.LoginMFA(&request.LoginMFA{MFAToken: "challenge"}, testClient)
+	assert.Equal(t, expected.Err(), err)
+}
@@ -0,0 +1,149 @@
+package user
+
+import (
+	"time"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/notifier"
+	"github.com/mmgopher/user-service/app/service/user/validator"
+)
+
+// EmailVerificationProvider provides an interface to verify emails of users
+type EmailVerificationProvider interface {
+	// RequestEmailVerification issues new verification token and delivers it to the email of the user.
+	// Tokens issued earlier can not be used anymore.
+	RequestEmailVerification(userID int) (*model.OneTimeToken, error)
+	// ConfirmEmailVerification marks email of the user as verified when the token is valid.
+	ConfirmEmailVerification(userID int, request *request.ConfirmEmailVerification) error
+}
+
+// EmailVerificationService represents service which verifies emails of users
+type EmailVerificationService struct {
+	userRepository      dao.UserRepositoryProvider
+	transactionProvider dao.TransactionProvider
+	tokenRepository     dao.OneTimeTokenRepositoryProvider
+	notifier            notifier.Notifier
+	tokenTTL            time.Duration
+	now                 func() time.Time
+}
+
+// NewEmailVerificationService creates new instance of EmailVerificationService.
+func NewEmailVerificationService(
+	userRepository dao.UserRepositoryProvider,
+	transactionProvider dao.TransactionProvider,
+	tokenRepository dao.OneTimeTokenRepositoryProvider,
+	notifier notifier.Notifier,
+	tokenTTL time.Duration,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		userRepository:      userRepository,
+		transactionProvider: transactionProvider,
+		tokenRepository:     tokenRepository,
+		notifier:            notifier,
+		tokenTTL:            tokenTTL,
+		now:                 time.Now,
+	}
+}
+
+// RequestEmailVerification issues new verification token and delivers it to the email of the user.
+// Tokens issued earlier can not be used anymore.
+func (s EmailVerificationService) RequestEmailVerification(userID int) (*model.OneTimeToken, error) {
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if user == nil {
+		return nil, httperrors.EntityNotFoundError("user")
+	}
+
+	if user.Email == "" {
+		return nil, httperrors.EmailVerificationEmailMissing
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil, httperrors.EmailVerificationAlreadyVerified
+	}
+
+	token, verification, err := issueOneTimeToken(
+		s.tokenRepository,
+		model.TokenPurposeEmailVerification,
+		userID,
+		user.Email,
+		s.now().UTC().Add(s.tokenTTL),
+	)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if err := s.notifier.Notify(&notifier.Notification{
+		Type:      notifier.NotificationEmailVerification,
+		UserID:    userID,
+		Recipient: user.Email,
+		Token:     token,
+		ExpiresAt: verification.ExpiresAt,
+	}); err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return verification, nil
+}
+
+// ConfirmEmailVerification marks email of the user as verified when the token is valid.
+// Token is rejected when it expired, belongs to another user or the email was changed after it was issued.
+func (s EmailVerificationService) ConfirmEmailVerification(
+	userID int,
+	request *request.ConfirmEmailVerification,
+) error {
+
+	if err := validator.ValidateConfirmEmailVerificationRequest(request); err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	verification, err := findOneTimeToken(s.tokenRepository, model.TokenPurposeEmailVerification, request.Token, now)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if verification == nil || verification.UserID != userID {
+		return httperrors.EmailVerificationTokenIncorrect
+	}
+
+	// tokens are single use, only one of concurrent requests with the same token succeeds
+	used, err := s.tokenRepository.Use(verification.TokenHash)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !used {
+		return httperrors.EmailVerificationTokenIncorrect
+	}
+
+	return runInTransaction(s.transactionProvider, func(repositories dao.Repositories) error {
+		verified, err := repositories.Users.MarkEmailVerified(userID, verification.Subject, now)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		if !verified {
+			return httperrors.EmailVerificationTokenIncorrect
+		}
+
+		// Read user back to publish its complete state.
+		user, err := repositories.Users.GetByID(userID)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		// verified email proves the account is genuine, status change emits the event itself
+		if user.Status == model.UserStatusPending {
+			return changeUserStatus(repositories, user, model.UserStatusActive, emailVerifiedReason, now)
+		}
+
+		return emitUserEvent(repositories.Outbox, model.EventUserUpdated, user)
+	})
+}
@@ -0,0 +1,193 @@
+// +build unit
+
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/notifier"
+)
+
+// newTestEmailVerificationService returns service working on memory user repository with one user.
+func newTestEmailVerificationService(
+	t *testing.T,
+	tokenRepository dao.OneTimeTokenRepositoryProvider,
+	notifications *bytes.Buffer,
+) (*EmailVerificationService, *dao.MemoryUserRepository, *dao.MemoryOutboxRepository, int) {
+	userRepository := dao.NewMemoryUserRepository()
+	outboxRepository := dao.NewMemoryOutboxRepository()
+	userID, err := userRepository.Create(&model.User{Name: "name", Surname: "surname", Email: "John@example.com"})
+	require.Nil(t, err)
+
+	service := NewEmailVerificationService(
+		userRepository,
+		dao.NewMemoryTransactor(
+			userRepository,
+			outboxRepository,
+			dao.NewMemoryUserStatusHistoryRepository(),
+			dao.NewMemorySessionRepository(),
+		),
+		tokenRepository,
+		notifier.NewWriterNotifier(notifications),
+		time.Hour,
+	)
+	service.now = func() time.Time {
+		return time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+	}
+
+	return service, userRepository, outboxRepository, userID
+}
+
+func TestRequestEmailVerificationOK(t *testing.T) {
+	var created *model.OneTimeToken
+	mockTokenRepository := dao.MockOneTimeTokenRepositoryProvider{}
+	mockTokenRepository.On("DeleteByUserID", mock.Anything, mock.Anything).Return(nil)
+	mockTokenRepository.On("Create", mock.Anything).Return(func(token *model.OneTimeToken) error {
+		created = token
+		return nil
+	})
+	var notifications bytes.Buffer
+	service, _, _, userID := newTestEmailVerificationService(t, &mockTokenRepository, &notifications)
+
+	verification, err := service.RequestEmailVerification(userID)
+	require.Nil(t, err)
+	assert.Equal(t, time.Date(2020, 10, 19, 13, 0, 0, 0, time.UTC), verification.ExpiresAt)
+
+	var notification notifier.Notification
+	require.Nil(t, json.Unmarshal(notifications.Bytes(), &notification))
+	assert.Equal(t, notifier.NotificationEmailVerification, notification.Type)
+	assert.Equal(t, "John@example.com", notification.Recipient)
+	require.NotNil(t, created)
+	assert.Equal(t, userID, created.UserID)
+	assert.Equal(t, model.TokenPurposeEmailVerification, created.Purpose)
+	assert.Equal(t, "John@example.com", created.Subject)
+	assert.Equal(t, hashOneTimeToken(notification.Token), created.TokenHash)
+	assert.NotEqual(t, notification.Token, created.TokenHash)
+	mockTokenRepository.AssertCalled(t, "DeleteByUserID", model.TokenPurposeEmailVerification, userID)
+}
+
+func TestRequestEmailVerificationErrors(t *testing.T) {
+	service, userRepository, _, userID := newTestEmailVerificationService(t,
+		&dao.MockOneTimeTokenRepositoryProvider{}, &bytes.Buffer{})
+
+	_, err := service.RequestEmailVerification(userID + 1)
+	assert.EqualError(t, httperrors.EntityNotFoundError("user"), err.Error())
+
+	verified, err := userRepository.MarkEmailVerified(userID, "john@example.com", time.Now())
+	require.Nil(t, err)
+	require.True(t, verified)
+	_, err = service.RequestEmailVerification(userID)
+	assert.EqualError(t, httperrors.EmailVerificationAlreadyVerified, err.Error())
+
+	withoutEmail, err := userRepository.Create(&model.User{Name: "other", Surname: "surname"})
+	require.Nil(t, err)
+	_, err = service.RequestEmailVerification(withoutEmail)
+	assert.EqualError(t, httperrors.EmailVerificationEmailMissing, err.Error())
+}
+
+func TestConfirmEmailVerificationOK(t *testing.T) {
+	token := "token"
+	mockTokenRepository := dao.MockOneTimeTokenRepositoryProvider{}
+	service, userRepository, outboxRepository, userID := newTestEmailVerificationService(t,
+		&mockTokenRepository, &bytes.Buffer{})
+	mockTokenRepository.On("Get", model.TokenPurposeEmailVerification, hashOneTimeToken(token)).Return(
+		&model.OneTimeToken{
+			TokenHash: hashOneTimeToken(token),
+			UserID:    userID,
+			Subject:   "john@EXAMPLE.com",
+			ExpiresAt: service.now().Add(time.Minute),
+		}, nil)
+	mockTokenRepository.On("Use", hashOneTimeToken(token)).Return(true, nil)
+
+	require.Nil(t, service.ConfirmEmailVerification(userID, &request.ConfirmEmailVerification{Token: token}))
+
+	user, err := userRepository.GetByID(userID)
+	require.Nil(t, err)
+	require.NotNil(t, user.EmailVerifiedAt)
+	assert.Equal(t, service.now(), *user.EmailVerifiedAt)
+	events, err := outboxRepository.FindPending(time.Now().Add(time.Minute), 10)
+	require.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, model.EventUserUpdated, events[0].Type)
+	assert.Contains(t, string(events[0].Payload), `"email_verified":true`)
+	mockTokenRepository.AssertExpectations(t)
+}
+
+func TestConfirmEmailVerificationActivatesPendingUser(t *testing.T) {
+	token := "token"
+	mockTokenRepository := dao.MockOneTimeTokenRepositoryProvider{}
+	service, userRepository, _, userID := newTestEmailVerificationService(t,
+		&mockTokenRepository, &bytes.Buffer{})
+	pendingID, err := userRepository.Create(&model.User{Name: "pending", Surname: "surname",
+		Email: "jane@example.com", Status: model.UserStatusPending})
+	require.Nil(t, err)
+	require.NotEqual(t, userID, pendingID)
+	mockTokenRepository.On("Get", model.TokenPurposeEmailVerification, hashOneTimeToken(token)).Return(
+		&model.OneTimeToken{
+			TokenHash: hashOneTimeToken(token),
+			UserID:    pendingID,
+			Subject:   "jane@example.com",
+			ExpiresAt: service.now().Add(time.Minute),
+		}, nil)
+	mockTokenRepository.On("Use", hashOneTimeToken(token)).Return(true, nil)
+
+	require.Nil(t, service.ConfirmEmailVerification(pendingID, &request.ConfirmEmailVerification{Token: token}))
+
+	user, err := userRepository.GetByID(pendingID)
+	require.Nil(t, err)
+	assert.Equal(t, model.UserStatusActive, user.Status)
+}
+
+func TestConfirmEmailVerificationTokenIncorrect(t *testing.T) {
+	var testData = []struct {
+		name         string
+		verification func(userID int, now time.Time) *model.OneTimeToken
+		used         bool
+	}{
+		{"Unknown", func(int, time.Time) *model.OneTimeToken {
+			return nil
+		}, true},
+		{"Expired", func(userID int, now time.Time) *model.OneTimeToken {
+			return &model.OneTimeToken{UserID: userID, Subject: "john@example.com", ExpiresAt: now}
+		}, true},
+		{"AnotherUser", func(userID int, now time.Time) *model.OneTimeToken {
+			return &model.OneTimeToken{UserID: userID + 1, Subject: "john@example.com", ExpiresAt: now.Add(time.Hour)}
+		}, true},
+		{"EmailChanged", func(userID int, now time.Time) *model.OneTimeToken {
+			return &model.OneTimeToken{UserID: userID, Subject: "old@example.com", ExpiresAt: now.Add(time.Hour)}
+		}, true},
+		{"AlreadyUsed", func(userID int, now time.Time) *model.OneTimeToken {
+			return &model.OneTimeToken{UserID: userID, Subject: "john@example.com", ExpiresAt: now.Add(time.Hour)}
+		}, false},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTokenRepository := dao.MockOneTimeTokenRepositoryProvider{}
+			service, userRepository, _, userID := newTestEmailVerificationService(t,
+				&mockTokenRepository, &bytes.Buffer{})
+			mockTokenRepository.On("Get", model.TokenPurposeEmailVerification, mock.Anything).Return(
+				tt.verification(userID, service.now()), nil)
+			mockTokenRepository.On("Use", mock.Anything).Return(tt.used, nil)
+
+			err := service.ConfirmEmailVerification(userID, &request.ConfirmEmailVerification{Token: "token"})
+			require.NotNil(t, err)
+			assert.EqualError(t, httperrors.EmailVerificationTokenIncorrect, err.Error())
+
+			user, err := userRepository.GetByID(userID)
+			require.Nil(t, err)
+			assert.Nil(t, user.EmailVerifiedAt)
+		})
+	}
+}
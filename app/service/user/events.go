@@ -0,0 +1,81 @@
+package user
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// userEventPayload represents user data sent with user.created and user.updated events
+type userEventPayload struct {
+	ID            int                    `json:"id"`
+	Name          string                 `json:"name"`
+	Surname       string                 `json:"surname"`
+	Gender        string                 `json:"gender"`
+	DateOfBirth   string                 `json:"date_of_birth"`
+	Age           int                    `json:"age"`
+	Address       string                 `json:"address"`
+	Email         string                 `json:"email"`
+	Phone         string                 `json:"phone"`
+	EmailVerified bool                   `json:"email_verified"`
+	Attributes    map[string]interface{} `json:"attributes"`
+	Status        string                 `json:"status"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// userDeletedEventPayload represents user data sent with user.deleted event
+type userDeletedEventPayload struct {
+	ID int `json:"id"`
+}
+
+// newUserEvent creates outbox event of given type for the user.
+func newUserEvent(eventType string, user *model.User) (*model.Event, error) {
+	var payload interface{}
+	if eventType == model.EventUserDeleted {
+		payload = userDeletedEventPayload{
+			ID: user.ID,
+		}
+	} else {
+		payload = userEventPayload{
+			ID:            user.ID,
+			Name:          user.Name,
+			Surname:       user.Surname,
+			Gender:        user.Gender,
+			DateOfBirth:   user.DateOfBirth.Format(model.DateLayout),
+			Age:           user.AgeAt(time.Now()),
+			Address:       user.Address,
+			Email:         user.Email,
+			Phone:         user.Phone,
+			EmailVerified: user.EmailVerifiedAt != nil,
+			Attributes:    user.AttributeValues(),
+			Status:        user.Status,
+			CreatedAt:     user.CreatedAt,
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrapf(err, "impossible to marshal %s event payload", eventType)
+	}
+
+	return &model.Event{
+		AggregateID: user.ID,
+		Type:        eventType,
+		Payload:     data,
+	}, nil
+}
+
+// emitUserEvent stores user event in the outbox.
+// It has to be called inside of transaction which modifies the user.
+func emitUserEvent(outbox dao.OutboxRepositoryProvider, eventType string, user *model.User) error {
+	event, err := newUserEvent(eventType, user)
+	if err != nil {
+		return err
+	}
+
+	return outbox.Add(event)
+}
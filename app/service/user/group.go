@@ -0,0 +1,286 @@
+package user
+
+import (
+	"strconv"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/user/validator"
+)
+
+// GroupProvider provides an interface to work with groups of users
+type GroupProvider interface {
+	// GetGroups returns all groups
+	GetGroups() ([]model.Group, error)
+	// GetGroup returns group based on ID
+	GetGroup(groupID int) (*model.Group, error)
+	// CreateGroup creates new group
+	CreateGroup(request *request.CreateGroup) (int, error)
+	// UpdateGroup updates existing group
+	UpdateGroup(groupID int, request *request.UpdateGroup) error
+	// DeleteGroup deletes group with its memberships
+	DeleteGroup(groupID int) error
+	// AddGroupMembers adds users to the group and returns number of new members
+	AddGroupMembers(groupID int, request *request.GroupMembers) (int, error)
+	// RemoveGroupMembers removes users from the group and returns number of removed members
+	RemoveGroupMembers(groupID int, request *request.GroupMembers) (int, error)
+	// GetGroupMembers returns page of members of the group
+	GetGroupMembers(groupID int, request *request.FindGroupMembers) ([]model.User, int, int, error)
+}
+
+// GroupService represents service which manages groups of users.
+// Name of the group is used as a tag of its members.
+type GroupService struct {
+	groupRepository dao.GroupRepositoryProvider
+	userRepository  dao.UserRepositoryProvider
+}
+
+// NewGroupService creates new instance of GroupService.
+func NewGroupService(
+	groupRepository dao.GroupRepositoryProvider,
+	userRepository dao.UserRepositoryProvider,
+) *GroupService {
+	return &GroupService{
+		groupRepository: groupRepository,
+		userRepository:  userRepository,
+	}
+}
+
+// GetGroups returns all groups
+func (s GroupService) GetGroups() ([]model.Group, error) {
+	groups, err := s.groupRepository.FindAll()
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return groups, nil
+}
+
+// GetGroup returns group based on ID
+func (s GroupService) GetGroup(groupID int) (*model.Group, error) {
+	group, err := s.groupRepository.GetByID(groupID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if group == nil {
+		return nil, httperrors.EntityNotFoundError("group")
+	}
+
+	return group, nil
+}
+
+// CreateGroup creates new group
+func (s GroupService) CreateGroup(request *request.CreateGroup) (int, error) {
+
+	if err := validator.ValidateCreateGroupRequest(request); err != nil {
+		return 0, err
+	}
+
+	if err := s.checkNameAvailable(request.Name, 0); err != nil {
+		return 0, err
+	}
+
+	groupID, err := s.groupRepository.Create(&model.Group{
+		Name:        request.Name,
+		Description: request.Description,
+	})
+	if err != nil {
+		return 0, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return groupID, nil
+}
+
+// UpdateGroup updates existing group
+func (s GroupService) UpdateGroup(groupID int, request *request.UpdateGroup) error {
+	group, err := s.GetGroup(groupID)
+	if err != nil {
+		return err
+	}
+
+	if err := validator.ValidateUpdateGroupRequest(request); err != nil {
+		return err
+	}
+
+	if err := s.checkNameAvailable(request.Name, groupID); err != nil {
+		return err
+	}
+
+	group.Name = request.Name
+	group.Description = request.Description
+
+	updated, err := s.groupRepository.Update(group)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !updated {
+		return httperrors.EntityNotFoundError("group")
+	}
+
+	return nil
+}
+
+// DeleteGroup deletes group with its memberships
+func (s GroupService) DeleteGroup(groupID int) error {
+	deleted, err := s.groupRepository.Delete(groupID)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !deleted {
+		return httperrors.EntityNotFoundError("group")
+	}
+
+	return nil
+}
+
+// AddGroupMembers adds users to the group and returns number of new members.
+// Users which don't exist or are already members are skipped.
+func (s GroupService) AddGroupMembers(groupID int, request *request.GroupMembers) (int, error) {
+	if _, err := s.GetGroup(groupID); err != nil {
+		return 0, err
+	}
+
+	if err := validator.ValidateGroupMembersRequest(request); err != nil {
+		return 0, err
+	}
+
+	count, err := s.groupRepository.AddMembers(groupID, request.UserIDs)
+	if err != nil {
+		return 0, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return count, nil
+}
+
+// RemoveGroupMembers removes users from the group and returns number of removed members.
+// Users which aren't members are skipped.
+func (s GroupService) RemoveGroupMembers(groupID int, request *request.GroupMembers) (int, error) {
+	if _, err := s.GetGroup(groupID); err != nil {
+		return 0, err
+	}
+
+	if err := validator.ValidateGroupMembersRequest(request); err != nil {
+		return 0, err
+	}
+
+	count, err := s.groupRepository.RemoveMembers(groupID, request.UserIDs)
+	if err != nil {
+		return 0, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return count, nil
+}
+
+// GetGroupMembers returns page of members of the group, paging works the same way as for GET /v1/users.
+func (s GroupService) GetGroupMembers(groupID int, request *request.FindGroupMembers) (
+	[]model.User, int, int, error) {
+
+	if _, err := s.GetGroup(groupID); err != nil {
+		return nil, 0, 0, err
+	}
+
+	findRequest := newFindGroupMembersRequest(request)
+	// members can't be sorted by custom attributes, so definitions aren't needed
+	if err := validator.ValidateFindUsersRequest(findRequest, nil); err != nil {
+		return nil, 0, 0, err
+	}
+
+	result, beforeID, afterID, err := s.userRepository.FindUsers(
+		dao.NewUserSearchBuilder(findRequest).WithGroups([]int{groupID}, false),
+	)
+	if err != nil {
+		return nil, 0, 0, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return result, beforeID, afterID, nil
+}
+
+// checkNameAvailable returns GroupAlreadyExists when another group has the name.
+func (s GroupService) checkNameAvailable(name string, groupID int) error {
+	existing, err := s.groupRepository.GetByName(name)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if existing != nil && existing.ID != groupID {
+		return httperrors.GroupAlreadyExists
+	}
+
+	return nil
+}
+
+// newFindGroupMembersRequest returns FindUsers request with paging params of the members request.
+func newFindGroupMembersRequest(members *request.FindGroupMembers) *request.FindUsers {
+	return &request.FindUsers{
+		Limit:    members.Limit,
+		BeforeID: members.BeforeID,
+		AfterID:  members.AfterID,
+		Sort:     members.Sort,
+	}
+}
+
+// resolveGroups returns distinct IDs of groups and tags used to filter users.
+// Groups and tags which don't exist are reported as details of RequestValidationFailed.
+func resolveGroups(groupRepository dao.GroupRepositoryProvider, request *request.FindUsers) ([]int, error) {
+	var details httperrors.Details
+	var groupIDs []int
+	seen := make(map[int]struct{})
+	add := func(groupID int) {
+		if _, ok := seen[groupID]; !ok {
+			seen[groupID] = struct{}{}
+			groupIDs = append(groupIDs, groupID)
+		}
+	}
+
+	if len(request.Groups) > 0 {
+		groups, err := groupRepository.FindByIDs(request.Groups)
+		if err != nil {
+			return nil, httperrors.InternalServerError.WithCause(err)
+		}
+
+		existing := make(map[int]struct{}, len(groups))
+		for _, group := range groups {
+			existing[group.ID] = struct{}{}
+		}
+
+		for i, groupID := range request.Groups {
+			if _, ok := existing[groupID]; !ok {
+				details.Add("/group/"+strconv.Itoa(i), httperrors.GroupUnknown(groupID))
+				continue
+			}
+			add(groupID)
+		}
+	}
+
+	if len(request.Tags) > 0 {
+		groups, err := groupRepository.FindByNames(request.Tags)
+		if err != nil {
+			return nil, httperrors.InternalServerError.WithCause(err)
+		}
+
+		existing := make(map[string]int, len(groups))
+		for _, group := range groups {
+			existing[group.Name] = group.ID
+		}
+
+		for i, tag := range request.Tags {
+			groupID, ok := existing[tag]
+			if !ok {
+				details.Add("/tag/"+strconv.Itoa(i), httperrors.GroupTagUnknown(tag))
+				continue
+			}
+			add(groupID)
+		}
+	}
+
+	if err := details.Err(); err != nil {
+		return nil, err
+	}
+
+	return groupIDs, nil
+}
@@ -0,0 +1,123 @@
+// +build unit
+
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+func TestCreateGroup(t *testing.T) {
+	mockRepository := dao.MockGroupRepositoryProvider{}
+	mockRepository.On("GetByName", "beta").Return(nil, nil)
+	mockRepository.On("GetByName", "staff").Return(&model.Group{ID: 1, Name: "staff"}, nil)
+	mockRepository.On("Create", mock.Anything).Return(7, nil)
+	service := NewGroupService(&mockRepository, &dao.MockUserRepositoryProvider{})
+
+	groupID, err := service.CreateGroup(&request.CreateGroup{Name: "beta", Description: "beta testers"})
+	require.Nil(t, err)
+	assert.Equal(t, 7, groupID)
+	mockRepository.AssertCalled(t, "Create", &model.Group{Name: "beta", Description: "beta testers"})
+
+	_, err = service.CreateGroup(&request.CreateGroup{Name: "staff"})
+	assert.Equal(t, httperrors.GroupAlreadyExists, err)
+}
+
+func TestUpdateGroupKeepsOwnName(t *testing.T) {
+	mockRepository := dao.MockGroupRepositoryProvider{}
+	mockRepository.On("GetByID", 7).Return(&model.Group{ID: 7, Name: "beta"}, nil)
+	mockRepository.On("GetByID", 8).Return(nil, nil)
+	mockRepository.On("GetByName", "beta").Return(&model.Group{ID: 7, Name: "beta"}, nil)
+	mockRepository.On("GetByName", "staff").Return(&model.Group{ID: 1, Name: "staff"}, nil)
+	mockRepository.On("Update", mock.Anything).Return(true, nil)
+	service := NewGroupService(&mockRepository, &dao.MockUserRepositoryProvider{})
+
+	require.Nil(t, service.UpdateGroup(7, &request.UpdateGroup{Name: "beta", Description: "testers"}))
+	mockRepository.AssertCalled(t, "Update", &model.Group{ID: 7, Name: "beta", Description: "testers"})
+
+	assert.Equal(t, httperrors.GroupAlreadyExists, service.UpdateGroup(7, &request.UpdateGroup{Name: "staff"}))
+
+	err := service.UpdateGroup(8, &request.UpdateGroup{Name: "beta"})
+	assert.EqualError(t, httperrors.EntityNotFoundError("group"), err.Error())
+}
+
+func TestAddGroupMembers(t *testing.T) {
+	mockRepository := dao.MockGroupRepositoryProvider{}
+	mockRepository.On("GetByID", 7).Return(&model.Group{ID: 7, Name: "beta"}, nil)
+	mockRepository.On("GetByID", 8).Return(nil, nil)
+	mockRepository.On("AddMembers", 7, []int{1, 2, 3}).Return(2, nil)
+	service := NewGroupService(&mockRepository, &dao.MockUserRepositoryProvider{})
+
+	count, err := service.AddGroupMembers(7, &request.GroupMembers{UserIDs: []int{1, 2, 3}})
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = service.AddGroupMembers(7, &request.GroupMembers{})
+	var details httperrors.Details
+	details.Add("/user_ids", httperrors.GroupMembersEmpty)
+	assert.Equal(t, details.Err(), err)
+
+	_, err = service.AddGroupMembers(8, &request.GroupMembers{UserIDs: []int{1}})
+	assert.EqualError(t, httperrors.EntityNotFoundError("group"), err.Error())
+}
+
+func TestGetGroupMembers(t *testing.T) {
+	mockRepository := dao.MockGroupRepositoryProvider{}
+	mockRepository.On("GetByID", 7).Return(&model.Group{ID: 7, Name: "beta"}, nil)
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("FindUsers", mock.Anything).Return([]model.User{{ID: 3}}, 3, 0, nil)
+	service := NewGroupService(&mockRepository, &mockUserRepository)
+
+	users, beforeID, afterID, err := service.GetGroupMembers(7, &request.FindGroupMembers{AfterID: 2})
+	require.Nil(t, err)
+	assert.Equal(t, []model.User{{ID: 3}}, users)
+	assert.Equal(t, 3, beforeID)
+	assert.Equal(t, 0, afterID)
+	mockUserRepository.AssertCalled(t, "FindUsers",
+		dao.NewUserSearchBuilder(&request.FindUsers{AfterID: 2}).WithGroups([]int{7}, false))
+
+	_, _, _, err = service.GetGroupMembers(7, &request.FindGroupMembers{Sort: "attr.department:asc"})
+	var details httperrors.Details
+	details.Add("/sort", httperrors.AttributeUnknown("department"))
+	assert.Equal(t, details.Err(), err)
+}
+
+func TestFindUsersByGroupsAndTags(t *testing.T) {
+	mockGroupRepository := dao.MockGroupRepositoryProvider{}
+	mockGroupRepository.On("FindByIDs", []int{3, 4}).Return([]model.Group{{ID: 3, Name: "staff"}}, nil)
+	mockGroupRepository.On("FindByIDs", []int{3}).Return([]model.Group{{ID: 3, Name: "staff"}}, nil)
+	mockGroupRepository.On("FindByNames", []string{"beta", "staff"}).Return([]model.Group{
+		{ID: 3, Name: "staff"},
+		{ID: 5, Name: "beta"},
+	}, nil)
+	mockGroupRepository.On("FindByNames", []string{"alpha"}).Return([]model.Group{}, nil)
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("FindUsers", mock.Anything).Return([]model.User{}, 0, 0, nil)
+	service := NewService(
+		&mockUserRepository,
+		noAttributeDefinitions(),
+		&mockGroupRepository,
+		noStatusHistory(),
+		&dao.MockTransactionProvider{},
+	)
+
+	findRequest := &request.FindUsers{Groups: []int{3}, Tags: []string{"beta", "staff"}, GroupMatch: "all"}
+	_, _, _, err := service.FindUsers(findRequest)
+	require.Nil(t, err)
+	mockUserRepository.AssertCalled(t, "FindUsers",
+		dao.NewUserSearchBuilder(findRequest).WithAttributes(map[string]interface{}{}).WithGroups([]int{3, 5}, true))
+
+	_, _, _, err = service.FindUsers(&request.FindUsers{Groups: []int{3, 4}, Tags: []string{"alpha"}})
+	var details httperrors.Details
+	details.Add("/group/1", httperrors.GroupUnknown(4))
+	details.Add("/tag/0", httperrors.GroupTagUnknown("alpha"))
+	assert.Equal(t, details.Err(), err)
+}
@@ -0,0 +1,304 @@
+package user
+
+import (
+	"crypto/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/auth"
+	"github.com/mmgopher/user-service/app/service/user/validator"
+)
+
+// Recovery codes are formatted as two groups of characters, e.g. `k7d2m-q4xz9`.
+const (
+	recoveryCodeAlphabet  = "abcdefghijklmnopqrstuvwxyz234567"
+	recoveryCodeLength    = 10
+	recoveryCodeSeparator = "-"
+)
+
+// MFAEnrolment represents pending TOTP second factor of the user.
+type MFAEnrolment struct {
+	Secret string
+	KeyURI string
+	// QRCode is PNG image with QR code of KeyURI.
+	QRCode []byte
+}
+
+// MFAProvider provides an interface to manage TOTP second factor of users
+type MFAProvider interface {
+	// Enroll generates new TOTP secret of the user, MFA is enabled after the first code is confirmed.
+	Enroll(userID int) (*MFAEnrolment, error)
+	// Activate enables MFA of the user when the code is correct and returns new recovery codes.
+	Activate(userID int, request *request.MFACode) ([]string, error)
+	// Disable disables MFA of the user when the code or recovery code is correct.
+	Disable(userID int, request *request.MFACode) error
+	// RegenerateRecoveryCodes replaces recovery codes of the user when the code or recovery code is correct.
+	RegenerateRecoveryCodes(userID int, request *request.MFACode) ([]string, error)
+}
+
+// MFAService represents service which manages TOTP second factor of users.
+// Incorrect codes count as failed login attempts.
+type MFAService struct {
+	userRepository      dao.UserRepositoryProvider
+	mfaRepository       dao.MFARepositoryProvider
+	transactionProvider dao.TransactionProvider
+	totp                *auth.TOTP
+	recoveryCodes       int
+	maxFailedAttempts   int
+	now                 func() time.Time
+}
+
+// NewMFAService creates new instance of MFAService.
+func NewMFAService(
+	userRepository dao.UserRepositoryProvider,
+	mfaRepository dao.MFARepositoryProvider,
+	transactionProvider dao.TransactionProvider,
+	totp *auth.TOTP,
+	recoveryCodes int,
+	maxFailedAttempts int,
+) *MFAService {
+	return &MFAService{
+		userRepository:      userRepository,
+		mfaRepository:       mfaRepository,
+		transactionProvider: transactionProvider,
+		totp:                totp,
+		recoveryCodes:       recoveryCodes,
+		maxFailedAttempts:   maxFailedAttempts,
+		now:                 time.Now,
+	}
+}
+
+// Enroll generates new TOTP secret of the user, MFA is enabled after the first code is confirmed.
+// Pending enrolment is replaced, enabled MFA has to be disabled first.
+func (s MFAService) Enroll(userID int) (*MFAEnrolment, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := s.totp.GenerateSecret()
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	enrolled, err := s.mfaRepository.Enroll(userID, secret)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !enrolled {
+		return nil, httperrors.MFAAlreadyEnabled
+	}
+
+	account := user.Email
+	if account == "" {
+		account = strconv.Itoa(user.ID)
+	}
+
+	qrCode, err := s.totp.QRCode(account, secret)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return &MFAEnrolment{
+		Secret: secret,
+		KeyURI: s.totp.KeyURI(account, secret),
+		QRCode: qrCode,
+	}, nil
+}
+
+// Activate enables MFA of the user when the code is correct and returns new recovery codes.
+func (s MFAService) Activate(userID int, request *request.MFACode) ([]string, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validator.ValidateMFACodeRequest(request); err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.mfaRepository.GetByUserID(userID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if mfa == nil {
+		return nil, httperrors.MFANotEnrolled
+	}
+
+	if mfa.Enabled() {
+		return nil, httperrors.MFAAlreadyEnabled
+	}
+
+	step, ok, err := s.totp.Validate(mfa.Secret, request.Code, mfa.LastUsedStep)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !ok {
+		return nil, s.codeIncorrect(user)
+	}
+
+	codes, codeHashes, err := generateRecoveryCodes(s.recoveryCodes)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	err = runInTransaction(s.transactionProvider, func(repositories dao.Repositories) error {
+		enabled, err := repositories.MFA.Enable(userID, step, s.now().UTC())
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		if !enabled {
+			return httperrors.MFAAlreadyEnabled
+		}
+
+		if err := repositories.MFA.ReplaceRecoveryCodes(userID, codeHashes); err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable disables MFA of the user when the code or recovery code is correct.
+func (s MFAService) Disable(userID int, request *request.MFACode) error {
+	if _, err := s.verify(userID, request); err != nil {
+		return err
+	}
+
+	if err := s.mfaRepository.Delete(userID); err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces recovery codes of the user when the code or recovery code is correct.
+func (s MFAService) RegenerateRecoveryCodes(userID int, request *request.MFACode) ([]string, error) {
+	if _, err := s.verify(userID, request); err != nil {
+		return nil, err
+	}
+
+	codes, codeHashes, err := generateRecoveryCodes(s.recoveryCodes)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if err := s.mfaRepository.ReplaceRecoveryCodes(userID, codeHashes); err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return codes, nil
+}
+
+// verify checks the code of the user with enabled MFA.
+func (s MFAService) verify(userID int, request *request.MFACode) (*model.MFA, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validator.ValidateMFACodeRequest(request); err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.mfaRepository.GetByUserID(userID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if mfa == nil || !mfa.Enabled() {
+		return nil, httperrors.MFANotEnabled
+	}
+
+	ok, err := verifyMFACode(s.mfaRepository, s.totp, mfa, request.Code)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !ok {
+		return nil, s.codeIncorrect(user)
+	}
+
+	return mfa, nil
+}
+
+// codeIncorrect records failed attempt of the user and returns error of incorrect code.
+func (s MFAService) codeIncorrect(user *model.User) error {
+	if err := addFailedAttempt(s.transactionProvider, user, s.maxFailedAttempts, s.now().UTC()); err != nil {
+		return err
+	}
+
+	return httperrors.MFACodeIncorrect
+}
+
+// getUser returns the user or not found error.
+func (s MFAService) getUser(userID int) (*model.User, error) {
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if user == nil {
+		return nil, httperrors.EntityNotFoundError("user")
+	}
+
+	return user, nil
+}
+
+// verifyMFACode checks TOTP code or recovery code of the user with enabled MFA, both can be used only once.
+func verifyMFACode(repository dao.MFARepositoryProvider, totp *auth.TOTP, mfa *model.MFA, code string) (bool, error) {
+	step, ok, err := totp.Validate(mfa.Secret, code, mfa.LastUsedStep)
+	if err != nil {
+		return false, err
+	}
+
+	if ok {
+		return repository.UseStep(mfa.UserID, step)
+	}
+
+	return repository.UseRecoveryCode(mfa.UserID, hashRecoveryCode(code))
+}
+
+// generateRecoveryCodes returns random recovery codes delivered to the user and their hashes which are stored.
+func generateRecoveryCodes(count int) ([]string, []string, error) {
+	codes := make([]string, count)
+	codeHashes := make([]string, count)
+	for i := range codes {
+		b := make([]byte, recoveryCodeLength)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, errors.Wrap(err, "impossible to generate recovery code")
+		}
+
+		for j := range b {
+			b[j] = recoveryCodeAlphabet[int(b[j])%len(recoveryCodeAlphabet)]
+		}
+
+		codes[i] = string(b[:recoveryCodeLength/2]) + recoveryCodeSeparator + string(b[recoveryCodeLength/2:])
+		codeHashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	return codes, codeHashes, nil
+}
+
+// hashRecoveryCode returns hash of the code which is stored instead of the code.
+// Letter case, separator and surrounding spaces are ignored.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return hashOneTimeToken(strings.Replace(code, recoveryCodeSeparator, "", -1))
+}
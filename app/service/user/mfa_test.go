@@ -0,0 +1,177 @@
+// +build unit
+
+package user
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/auth"
+)
+
+// newTestMFAService returns service with user 5001 which issues 4 recovery codes and locks users after 3 failed attempts.
+func newTestMFAService(
+	mockMFARepository *dao.MockMFARepositoryProvider,
+	repositories dao.Repositories,
+) *MFAService {
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByID", 5001).Return(
+		&model.User{ID: 5001, Email: "john@example.com", Status: model.UserStatusActive}, nil)
+	mockUserRepository.On("GetByID", 5002).Return(nil, nil)
+	return NewMFAService(
+		&mockUserRepository,
+		mockMFARepository,
+		newMockTransactionProvider(repositories),
+		auth.NewTOTP("user-service", 1),
+		4,
+		3,
+	)
+}
+
+func newTestTOTPSecret(t *testing.T) (string, string) {
+	secret, err := auth.NewTOTP("user-service", 0).GenerateSecret()
+	require.Nil(t, err)
+	code, err := auth.NewTOTP("user-service", 0).Code(secret)
+	require.Nil(t, err)
+	return secret, code
+}
+
+func TestEnrollMFA(t *testing.T) {
+	mockMFARepository := dao.MockMFARepositoryProvider{}
+	mockMFARepository.On("Enroll", 5001, mock.Anything).Return(true, nil)
+
+	enrolment, err := newTestMFAService(&mockMFARepository, dao.Repositories{}).Enroll(5001)
+	require.Nil(t, err)
+	mockMFARepository.AssertCalled(t, "Enroll", 5001, enrolment.Secret)
+	assert.Contains(t, enrolment.KeyURI, "otpauth://totp/user-service:john@example.com?")
+	assert.Contains(t, enrolment.KeyURI, "secret="+enrolment.Secret)
+	assert.Equal(t, "\x89PNG", string(enrolment.QRCode[:4]))
+}
+
+func TestEnrollMFAErrors(t *testing.T) {
+	mockMFARepository := dao.MockMFARepositoryProvider{}
+	mockMFARepository.On("Enroll", 5001, mock.Anything).Return(false, nil)
+	service := newTestMFAService(&mockMFARepository, dao.Repositories{})
+
+	_, err := service.Enroll(5002)
+	assert.Equal(t, httperrors.EntityNotFoundError("user"), err)
+
+	_, err = service.Enroll(5001)
+	assert.Equal(t, httperrors.MFAAlreadyEnabled, err)
+}
+
+func TestActivateMFA(t *testing.T) {
+	secret, code := newTestTOTPSecret(t)
+	var codeHashes []string
+	mockMFARepository := dao.MockMFARepositoryProvider{}
+	mockMFARepository.On("GetByUserID", 5001).Return(&model.MFA{UserID: 5001, Secret: secret}, nil)
+	mockMFARepository.On("Enable", 5001, mock.Anything, mock.Anything).Return(true, nil)
+	mockMFARepository.On("ReplaceRecoveryCodes", 5001, mock.Anything).Return(
+		func(_ int, hashes []string) error {
+			codeHashes = hashes
+			return nil
+		})
+
+	codes, err := newTestMFAService(&mockMFARepository, dao.Repositories{MFA: &mockMFARepository}).
+		Activate(5001, &request.MFACode{Code: code})
+	require.Nil(t, err)
+	require.Len(t, codes, 4)
+	require.Len(t, codeHashes, 4)
+	for i, recoveryCode := range codes {
+		assert.Regexp(t, regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`), recoveryCode)
+		assert.Equal(t, hashRecoveryCode(recoveryCode), codeHashes[i])
+	}
+	assert.NotEqual(t, codes[0], codes[1])
+	mockMFARepository.AssertExpectations(t)
+}
+
+func TestActivateMFAErrors(t *testing.T) {
+	secret, _ := newTestTOTPSecret(t)
+	enabledAt := time.Now()
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("AddFailedAttempt", 5001).Return(1, nil)
+
+	var testData = []struct {
+		name     string
+		mfa      *model.MFA
+		code     string
+		expected error
+	}{
+		{"NotEnrolled", nil, "123456", httperrors.MFANotEnrolled},
+		{"AlreadyEnabled", &model.MFA{UserID: 5001, Secret: secret, EnabledAt: &enabledAt}, "123456",
+			httperrors.MFAAlreadyEnabled},
+		{"CodeIncorrect", &model.MFA{UserID: 5001, Secret: secret}, "000000x", httperrors.MFACodeIncorrect},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMFARepository := dao.MockMFARepositoryProvider{}
+			mockMFARepository.On("GetByUserID", 5001).Return(tt.mfa, nil)
+			service := newTestMFAService(&mockMFARepository, dao.Repositories{Credentials: &mockCredentialRepository})
+
+			_, err := service.Activate(5001, &request.MFACode{Code: tt.code})
+			assert.Equal(t, tt.expected, err)
+			mockMFARepository.AssertNotCalled(t, "Enable", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+	mockCredentialRepository.AssertNumberOfCalls(t, "AddFailedAttempt", 1)
+}
+
+func TestDisableMFAWithRecoveryCode(t *testing.T) {
+	secret, _ := newTestTOTPSecret(t)
+	enabledAt := time.Now()
+	mockMFARepository := dao.MockMFARepositoryProvider{}
+	mockMFARepository.On("GetByUserID", 5001).Return(
+		&model.MFA{UserID: 5001, Secret: secret, EnabledAt: &enabledAt}, nil)
+	mockMFARepository.On("UseRecoveryCode", 5001, hashRecoveryCode("abcde-fgh23")).Return(true, nil)
+	mockMFARepository.On("Delete", 5001).Return(nil)
+
+	service := newTestMFAService(&mockMFARepository, dao.Repositories{})
+	require.Nil(t, service.Disable(5001, &request.MFACode{Code: " ABCDEfgh23 "}))
+	mockMFARepository.AssertExpectations(t)
+}
+
+func TestRegenerateMFARecoveryCodes(t *testing.T) {
+	secret, code := newTestTOTPSecret(t)
+	enabledAt := time.Now()
+	mockMFARepository := dao.MockMFARepositoryProvider{}
+	mockMFARepository.On("GetByUserID", 5001).Return(
+		&model.MFA{UserID: 5001, Secret: secret, EnabledAt: &enabledAt}, nil)
+	mockMFARepository.On("UseStep", 5001, mock.Anything).Return(true, nil).Once()
+	mockMFARepository.On("UseStep", 5001, mock.Anything).Return(false, nil)
+	mockMFARepository.On("ReplaceRecoveryCodes", 5001, mock.Anything).Return(nil)
+	mockCredentialRepository := dao.MockCredentialRepositoryProvider{}
+	mockCredentialRepository.On("AddFailedAttempt", 5001).Return(1, nil)
+
+	service := newTestMFAService(&mockMFARepository, dao.Repositories{Credentials: &mockCredentialRepository})
+	codes, err := service.RegenerateRecoveryCodes(5001, &request.MFACode{Code: code})
+	require.Nil(t, err)
+	assert.Len(t, codes, 4)
+
+	// the same code can't be used twice
+	_, err = service.RegenerateRecoveryCodes(5001, &request.MFACode{Code: code})
+	assert.Equal(t, httperrors.MFACodeIncorrect, err)
+	mockMFARepository.AssertNumberOfCalls(t, "ReplaceRecoveryCodes", 1)
+	mockMFARepository.AssertNotCalled(t, "UseRecoveryCode", mock.Anything, mock.Anything)
+}
+
+func TestMFANotEnabled(t *testing.T) {
+	secret, code := newTestTOTPSecret(t)
+	mockMFARepository := dao.MockMFARepositoryProvider{}
+	mockMFARepository.On("GetByUserID", 5001).Return(&model.MFA{UserID: 5001, Secret: secret}, nil)
+	service := newTestMFAService(&mockMFARepository, dao.Repositories{})
+
+	assert.Equal(t, httperrors.MFANotEnabled, service.Disable(5001, &request.MFACode{Code: code}))
+	_, err := service.RegenerateRecoveryCodes(5001, &request.MFACode{Code: code})
+	assert.Equal(t, httperrors.MFANotEnabled, err)
+	assert.Equal(t, httperrors.EntityNotFoundError("user"), service.Disable(5002, &request.MFACode{Code: code}))
+}
@@ -1,6 +1,12 @@
 package user
 
 import (
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/pkg/errors"
+
 	"github.com/mmgopher/user-service/app/api/request"
 	"github.com/mmgopher/user-service/app/dao"
 	"github.com/mmgopher/user-service/app/httperrors"
@@ -20,19 +26,37 @@ type Provider interface {
 	UpdateUser(userID int, request *request.UpdateUser) error
 	// FindUsers  searches users in DB using FindUsers criteria.
 	FindUsers(request *request.FindUsers) ([]model.User, int, int, error)
+	// ChangeUserStatus changes status of the user when the transition is allowed
+	ChangeUserStatus(userID int, status string, request *request.ChangeUserStatus) error
+	// GetUserStatusHistory returns all status transitions of the user
+	GetUserStatusHistory(userID int) ([]model.UserStatusChange, error)
 }
 
 // Service represents User service
 type Service struct {
-	userRepository dao.UserRepositoryProvider
+	userRepository                dao.UserRepositoryProvider
+	attributeDefinitionRepository dao.AttributeDefinitionRepositoryProvider
+	groupRepository               dao.GroupRepositoryProvider
+	statusHistoryRepository       dao.UserStatusHistoryRepositoryProvider
+	transactionProvider           dao.TransactionProvider
+	now                           func() time.Time
 }
 
 // NewService creates new instance of Payment service.
 func NewService(
 	userRepository dao.UserRepositoryProvider,
+	attributeDefinitionRepository dao.AttributeDefinitionRepositoryProvider,
+	groupRepository dao.GroupRepositoryProvider,
+	statusHistoryRepository dao.UserStatusHistoryRepositoryProvider,
+	transactionProvider dao.TransactionProvider,
 ) *Service {
 	return &Service{
-		userRepository: userRepository,
+		userRepository:                userRepository,
+		attributeDefinitionRepository: attributeDefinitionRepository,
+		groupRepository:               groupRepository,
+		statusHistoryRepository:       statusHistoryRepository,
+		transactionProvider:           transactionProvider,
+		now:                           time.Now,
 	}
 }
 
@@ -50,47 +74,81 @@ func (s Service) GetUser(userID int) (*model.User, error) {
 	return user, nil
 }
 
-// DeleteUser deletes user from databse
+// DeleteUser deletes user from databse, sessions of the user are revoked
 func (s Service) DeleteUser(userID int) error {
-	deleted, err := s.userRepository.Delete(userID)
-	if err != nil {
-		return httperrors.InternalServerError.WithCause(err)
-	}
+	return s.runInTransaction(func(repositories dao.Repositories) error {
+		deleted, err := repositories.Users.Delete(userID)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
 
-	if !deleted {
-		return httperrors.EntityNotFoundError("user")
-	}
+		if !deleted {
+			return httperrors.EntityNotFoundError("user")
+		}
 
-	return nil
+		if err := revokeUserSessions(repositories, userID, s.now().UTC()); err != nil {
+			return err
+		}
+
+		return emitUserEvent(repositories.Outbox, model.EventUserDeleted, &model.User{ID: userID})
+	})
 }
 
 // CreateUser creates new user
 func (s Service) CreateUser(request *request.CreateUser) (int, error) {
 
-	if err := validator.ValidateCreateUserRequest(request); err != nil {
-		return 0, err
+	definitions, err := s.attributeDefinitionRepository.FindAll()
+	if err != nil {
+		return 0, httperrors.InternalServerError.WithCause(err)
 	}
 
-	exist, err := s.userRepository.CheckIfExistWithNameAndSurname(request.Name, request.Surname)
+	if err := validator.ValidateCreateUserRequest(request, definitions); err != nil {
+		return 0, err
+	}
 
+	attributes, err := attributesJSON(request.Attributes)
 	if err != nil {
 		return 0, httperrors.InternalServerError.WithCause(err)
 	}
 
-	if exist {
-		return 0, httperrors.UserAlreadyRegistered
-	}
+	var userID int
+	err = s.runInTransaction(func(repositories dao.Repositories) error {
+		exist, err := repositories.Users.CheckIfExistWithNameAndSurname(request.Name, request.Surname)
+
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		if exist {
+			return httperrors.UserAlreadyRegistered
+		}
+
+		if err := checkEmailAvailable(repositories.Users, request.Email, 0); err != nil {
+			return err
+		}
 
-	userID, err := s.userRepository.Create(&model.User{
-		Name:    request.Name,
-		Surname: request.Surname,
-		Gender:  request.Gender,
-		Age:     request.Age,
-		Address: request.Address,
+		user := &model.User{
+			Name:        request.Name,
+			Surname:     request.Surname,
+			Gender:      request.Gender,
+			DateOfBirth: validator.DateOfBirth(request.DateOfBirth, request.Age),
+			Address:     request.Address,
+			Email:       request.Email,
+			Phone:       validator.NormalizePhone(request.Phone),
+			Attributes:  attributes,
+			Status:      model.UserStatusPending,
+		}
+
+		userID, err = repositories.Users.Create(user)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		return emitUserEvent(repositories.Outbox, model.EventUserCreated, user)
 	})
 
 	if err != nil {
-		return 0, httperrors.InternalServerError.WithCause(err)
+		return 0, err
 	}
 
 	return userID, nil
@@ -99,52 +157,148 @@ func (s Service) CreateUser(request *request.CreateUser) (int, error) {
 // UpdateUser updates existing user
 func (s Service) UpdateUser(userID int, request *request.UpdateUser) error {
 
-	if err := validator.ValidateUpdateUserRequest(request); err != nil {
-		return err
-	}
-
-	user, err := s.userRepository.GetByNameAndSurname(request.Name, request.Surname)
-
+	definitions, err := s.attributeDefinitionRepository.FindAll()
 	if err != nil {
 		return httperrors.InternalServerError.WithCause(err)
 	}
 
-	if user != nil && userID != user.ID {
-		return httperrors.UserAlreadyRegistered
+	if err := validator.ValidateUpdateUserRequest(request, definitions); err != nil {
+		return err
 	}
 
-	updated, err := s.userRepository.Update(&model.User{
-		ID:      userID,
-		Name:    request.Name,
-		Surname: request.Surname,
-		Gender:  request.Gender,
-		Age:     request.Age,
-		Address: request.Address,
-	})
-
+	attributes, err := attributesJSON(request.Attributes)
 	if err != nil {
 		return httperrors.InternalServerError.WithCause(err)
 	}
 
-	if !updated {
-		return httperrors.EntityNotFoundError("user")
-	}
+	return s.runInTransaction(func(repositories dao.Repositories) error {
+		user, err := repositories.Users.GetByNameAndSurname(request.Name, request.Surname)
 
-	return nil
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		if user != nil && userID != user.ID {
+			return httperrors.UserAlreadyRegistered
+		}
+
+		if err := checkEmailAvailable(repositories.Users, request.Email, userID); err != nil {
+			return err
+		}
+
+		// Verification of the email is reset by repository when the email is changed.
+		updated, err := repositories.Users.Update(&model.User{
+			ID:          userID,
+			Name:        request.Name,
+			Surname:     request.Surname,
+			Gender:      request.Gender,
+			DateOfBirth: validator.DateOfBirth(request.DateOfBirth, request.Age),
+			Address:     request.Address,
+			Email:       request.Email,
+			Phone:       validator.NormalizePhone(request.Phone),
+			Attributes:  attributes,
+		})
+
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		if !updated {
+			return httperrors.EntityNotFoundError("user")
+		}
+
+		// Read user back to publish its complete state.
+		user, err = repositories.Users.GetByID(userID)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		return emitUserEvent(repositories.Outbox, model.EventUserUpdated, user)
+	})
 }
 
 // FindUsers  searches users in DB using FindUsers criteria.
 func (s Service) FindUsers(request *request.FindUsers) (
 	[]model.User, int, int, error) {
 
-	if err := validator.ValidateFindUsersRequest(request); err != nil {
+	definitions, err := s.attributeDefinitionRepository.FindAll()
+	if err != nil {
+		return nil, 0, 0, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if err := validator.ValidateFindUsersRequest(request, definitions); err != nil {
 		return nil, 0, 0, err
 	}
 
-	searchBuilder := dao.NewUserSearchBuilder(request)
+	searchBuilder := dao.NewUserSearchBuilder(request).WithAttributes(validator.AttributeFilters(definitions, request))
+	if len(request.Groups) > 0 || len(request.Tags) > 0 {
+		groupIDs, err := resolveGroups(s.groupRepository, request)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		searchBuilder.WithGroups(groupIDs, request.GroupMatch == validator.GroupMatchAll)
+	}
+
 	result, beforeID, afterID, err := s.userRepository.FindUsers(searchBuilder)
 	if err != nil {
 		return nil, 0, 0, httperrors.InternalServerError.WithCause(err)
 	}
 	return result, beforeID, afterID, nil
 }
+
+// attributesJSON returns custom attributes of the user as JSON object.
+// Attributes with null value are removed, so the user doesn't have them.
+func attributesJSON(attributes map[string]interface{}) (types.JSONText, error) {
+	values := make(map[string]interface{}, len(attributes))
+	for name, value := range attributes {
+		if value != nil {
+			values[name] = value
+		}
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, errors.Wrap(err, "impossible to marshal attributes")
+	}
+
+	return data, nil
+}
+
+// checkEmailAvailable returns UserEmailAlreadyRegistered when the email is used by user other than userID.
+func checkEmailAvailable(users dao.UserRepositoryProvider, email string, userID int) error {
+	if email == "" {
+		return nil
+	}
+
+	user, err := users.GetByEmail(email)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if user != nil && user.ID != userID {
+		return httperrors.UserEmailAlreadyRegistered
+	}
+
+	return nil
+}
+
+// runInTransaction executes fn in database transaction.
+// Errors which are not HTTP errors are reported as internal server error.
+func (s Service) runInTransaction(fn func(repositories dao.Repositories) error) error {
+	return runInTransaction(s.transactionProvider, fn)
+}
+
+// runInTransaction executes fn in transaction of the provider.
+// Errors which are not HTTP errors are reported as internal server error.
+func runInTransaction(transactionProvider dao.TransactionProvider, fn func(repositories dao.Repositories) error) error {
+	err := transactionProvider.RunInTransaction(fn)
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(*httperrors.HTTPError); ok {
+		return err
+	}
+
+	return httperrors.InternalServerError.WithCause(err)
+}
@@ -3,9 +3,13 @@
 package user
 
 import (
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/jmoiron/sqlx/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mmgopher/user-service/app/api/request"
@@ -14,19 +18,51 @@ import (
 	"github.com/mmgopher/user-service/app/model"
 )
 
+// newMockTransactionProvider returns transaction provider which runs function with provided repositories.
+func newMockTransactionProvider(repositories dao.Repositories) *dao.MockTransactionProvider {
+	mockTransactionProvider := dao.MockTransactionProvider{}
+	mockTransactionProvider.On("RunInTransaction", mock.Anything).Return(
+		func(fn func(dao.Repositories) error) error {
+			return fn(repositories)
+		},
+	)
+	return &mockTransactionProvider
+}
+
+// noAttributeDefinitions returns attribute definition repository of deployment without custom attributes.
+func noAttributeDefinitions() *dao.MockAttributeDefinitionRepositoryProvider {
+	mockAttributeDefinitionRepository := dao.MockAttributeDefinitionRepositoryProvider{}
+	mockAttributeDefinitionRepository.On("FindAll").Return([]model.AttributeDefinition{}, nil)
+	return &mockAttributeDefinitionRepository
+}
+
+func noGroups() *dao.MockGroupRepositoryProvider {
+	return &dao.MockGroupRepositoryProvider{}
+}
+
+func noStatusHistory() *dao.MockUserStatusHistoryRepositoryProvider {
+	return &dao.MockUserStatusHistoryRepositoryProvider{}
+}
+
 func TestGetUserOK(t *testing.T) {
 	userID := 5001
 	model := model.User{
-		ID:      userID,
-		Name:    "name",
-		Surname: "surname",
-		Gender:  "male",
-		Age:     10,
-		Address: "address",
+		ID:          userID,
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: time.Date(1990, 5, 14, 0, 0, 0, 0, time.UTC),
+		Address:     "address",
 	}
 	mockUserRepository := dao.MockUserRepositoryProvider{}
 	mockUserRepository.On("GetByID", userID).Return(&model, nil)
-	service := NewService(&mockUserRepository)
+	service := NewService(
+		&mockUserRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		noStatusHistory(),
+		&dao.MockTransactionProvider{},
+	)
 	user, err := service.GetUser(userID)
 	assert.Nil(t, err)
 	assert.Equal(t, model, *user)
@@ -36,7 +72,13 @@ func TestGetUserNotFound(t *testing.T) {
 	userID := 5001
 	mockUserRepository := dao.MockUserRepositoryProvider{}
 	mockUserRepository.On("GetByID", userID).Return(nil, nil)
-	service := NewService(&mockUserRepository)
+	service := NewService(
+		&mockUserRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		noStatusHistory(),
+		&dao.MockTransactionProvider{},
+	)
 	user, err := service.GetUser(userID)
 	require.NotNil(t, err)
 	assert.Nil(t, user)
@@ -47,16 +89,47 @@ func TestDeleteUserOK(t *testing.T) {
 	userID := 5001
 	mockUserRepository := dao.MockUserRepositoryProvider{}
 	mockUserRepository.On("Delete", userID).Return(true, nil)
-	service := NewService(&mockUserRepository)
+	mockOutboxRepository := dao.MockOutboxRepositoryProvider{}
+	mockOutboxRepository.On("Add", mock.MatchedBy(func(event *model.Event) bool {
+		return event.Type == model.EventUserDeleted && event.AggregateID == userID
+	})).Return(nil)
+	now := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+	mockSessionRepository := dao.MockSessionRepositoryProvider{}
+	mockSessionRepository.On("RevokeAll", userID, now).Return(nil)
+	service := NewService(
+		&mockUserRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		noStatusHistory(),
+		newMockTransactionProvider(dao.Repositories{
+			Users:    &mockUserRepository,
+			Outbox:   &mockOutboxRepository,
+			Sessions: &mockSessionRepository,
+		}),
+	)
+	service.now = func() time.Time {
+		return now
+	}
 	err := service.DeleteUser(userID)
 	assert.Nil(t, err)
+	mockOutboxRepository.AssertExpectations(t)
+	mockSessionRepository.AssertExpectations(t)
 }
 
 func TestDeleteUserNotFound(t *testing.T) {
 	userID := 5001
 	mockUserRepository := dao.MockUserRepositoryProvider{}
 	mockUserRepository.On("Delete", userID).Return(false, nil)
-	service := NewService(&mockUserRepository)
+	service := NewService(
+		&mockUserRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		noStatusHistory(),
+		newMockTransactionProvider(dao.Repositories{
+			Users:  &mockUserRepository,
+			Outbox: &dao.MockOutboxRepositoryProvider{},
+		}),
+	)
 	err := service.DeleteUser(userID)
 	require.NotNil(t, err)
 	assert.EqualError(t, httperrors.EntityNotFoundError("user"), err.Error())
@@ -65,43 +138,100 @@ func TestDeleteUserNotFound(t *testing.T) {
 func TestCreateUserOK(t *testing.T) {
 	newUserID := 1
 	request := request.CreateUser{
-		Name:    "name",
-		Surname: "surname",
-		Gender:  "male",
-		Age:     10,
-		Address: "address",
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
 	}
 
-	model := model.User{
+	user := model.User{
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: time.Date(1990, 5, 14, 0, 0, 0, 0, time.UTC),
+		Address:     "address",
+		Attributes:  types.JSONText(`{}`),
+		Status:      model.UserStatusPending,
+	}
+
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("Create", &user).Return(newUserID, nil)
+	mockUserRepository.On("CheckIfExistWithNameAndSurname", request.Name, request.Surname).Return(false, nil)
+	mockOutboxRepository := dao.MockOutboxRepositoryProvider{}
+	mockOutboxRepository.On("Add", mock.MatchedBy(func(event *model.Event) bool {
+		return event.Type == model.EventUserCreated
+	})).Return(nil)
+	service := NewService(
+		&mockUserRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		noStatusHistory(),
+		newMockTransactionProvider(dao.Repositories{
+			Users:  &mockUserRepository,
+			Outbox: &mockOutboxRepository,
+		}),
+	)
+	id, err := service.CreateUser(&request)
+	assert.Equal(t, newUserID, id)
+	assert.Nil(t, err)
+	mockOutboxRepository.AssertExpectations(t)
+}
+
+func TestCreateUserWithDeprecatedAge(t *testing.T) {
+	request := request.CreateUser{
 		Name:    "name",
 		Surname: "surname",
 		Gender:  "male",
-		Age:     10,
+		Age:     30,
 		Address: "address",
 	}
 
 	mockUserRepository := dao.MockUserRepositoryProvider{}
-	mockUserRepository.On("Create", &model).Return(newUserID, nil)
+	mockUserRepository.On("Create", mock.MatchedBy(func(user *model.User) bool {
+		return user.DateOfBirth.Equal(model.Date(time.Now()).AddDate(-30, 0, 0))
+	})).Return(1, nil)
 	mockUserRepository.On("CheckIfExistWithNameAndSurname", request.Name, request.Surname).Return(false, nil)
-	service := NewService(&mockUserRepository)
+	mockOutboxRepository := dao.MockOutboxRepositoryProvider{}
+	mockOutboxRepository.On("Add", mock.Anything).Return(nil)
+	service := NewService(
+		&mockUserRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		noStatusHistory(),
+		newMockTransactionProvider(dao.Repositories{
+			Users:  &mockUserRepository,
+			Outbox: &mockOutboxRepository,
+		}),
+	)
 	id, err := service.CreateUser(&request)
-	assert.Equal(t, newUserID, id)
+	assert.Equal(t, 1, id)
 	assert.Nil(t, err)
+	mockUserRepository.AssertExpectations(t)
 }
 
 func TestCreateUserAlreadyRegistered(t *testing.T) {
 
 	request := request.CreateUser{
-		Name:    "name",
-		Surname: "surname",
-		Gender:  "male",
-		Age:     10,
-		Address: "address",
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
 	}
 
 	mockUserRepository := dao.MockUserRepositoryProvider{}
 	mockUserRepository.On("CheckIfExistWithNameAndSurname", request.Name, request.Surname).Return(true, nil)
-	service := NewService(&mockUserRepository)
+	service := NewService(
+		&mockUserRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		noStatusHistory(),
+		newMockTransactionProvider(dao.Repositories{
+			Users:  &mockUserRepository,
+			Outbox: &dao.MockOutboxRepositoryProvider{},
+		}),
+	)
 	id, err := service.CreateUser(&request)
 	require.NotNil(t, err)
 	assert.Equal(t, 0, id)
@@ -111,15 +241,232 @@ func TestCreateUserAlreadyRegistered(t *testing.T) {
 func TestCreateUserValidationError(t *testing.T) {
 
 	request := request.CreateUser{
-		Name:    "",
-		Surname: "surname",
-		Gender:  "male",
-		Age:     10,
-		Address: "address",
+		Name:        "",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+	}
+	service := NewService(
+		&dao.MockUserRepositoryProvider{},
+		noAttributeDefinitions(),
+		noGroups(),
+		noStatusHistory(),
+		&dao.MockTransactionProvider{},
+	)
+	id, err := service.CreateUser(&request)
+	require.NotNil(t, err)
+	assert.Equal(t, 0, id)
+	assert.EqualError(t, httperrors.RequestValidationFailed.WithDetails(httperrors.Detail{
+		Field: "/name", Code: httperrors.UserNameEmpty.Code, Message: httperrors.UserNameEmpty.Message,
+	}), err.Error())
+}
+
+func TestCreateUserOutboxError(t *testing.T) {
+
+	request := request.CreateUser{
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
 	}
-	service := NewService(&dao.MockUserRepositoryProvider{})
+
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("CheckIfExistWithNameAndSurname", request.Name, request.Surname).Return(false, nil)
+	mockUserRepository.On("Create", mock.Anything).Return(1, nil)
+	mockOutboxRepository := dao.MockOutboxRepositoryProvider{}
+	mockOutboxRepository.On("Add", mock.Anything).Return(errors.New("outbox error"))
+	service := NewService(
+		&mockUserRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		noStatusHistory(),
+		newMockTransactionProvider(dao.Repositories{
+			Users:  &mockUserRepository,
+			Outbox: &mockOutboxRepository,
+		}),
+	)
 	id, err := service.CreateUser(&request)
 	require.NotNil(t, err)
 	assert.Equal(t, 0, id)
-	assert.EqualError(t, httperrors.UserNameEmpty, err.Error())
+	assert.EqualError(t, httperrors.InternalServerError, err.Error())
+}
+
+func TestUpdateUserOK(t *testing.T) {
+	userID := 5001
+	request := request.UpdateUser{
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+	}
+
+	user := model.User{
+		ID:          userID,
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: time.Date(1990, 5, 14, 0, 0, 0, 0, time.UTC),
+		Address:     "address",
+		Attributes:  types.JSONText(`{}`),
+	}
+
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByNameAndSurname", request.Name, request.Surname).Return(nil, nil)
+	mockUserRepository.On("Update", &user).Return(true, nil)
+	mockUserRepository.On("GetByID", userID).Return(&user, nil)
+	mockOutboxRepository := dao.MockOutboxRepositoryProvider{}
+	mockOutboxRepository.On("Add", mock.MatchedBy(func(event *model.Event) bool {
+		return event.Type == model.EventUserUpdated && event.AggregateID == userID
+	})).Return(nil)
+	service := NewService(
+		&mockUserRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		noStatusHistory(),
+		newMockTransactionProvider(dao.Repositories{
+			Users:  &mockUserRepository,
+			Outbox: &mockOutboxRepository,
+		}),
+	)
+	err := service.UpdateUser(userID, &request)
+	assert.Nil(t, err)
+	mockOutboxRepository.AssertExpectations(t)
+}
+
+func TestServiceWithMemoryRepositories(t *testing.T) {
+	userRepository := dao.NewMemoryUserRepository()
+	outboxRepository := dao.NewMemoryOutboxRepository()
+	statusHistoryRepository := dao.NewMemoryUserStatusHistoryRepository()
+	sessionRepository := dao.NewMemorySessionRepository()
+	service := NewService(
+		userRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		statusHistoryRepository,
+		dao.NewMemoryTransactor(userRepository, outboxRepository, statusHistoryRepository, sessionRepository),
+	)
+
+	createRequest := &request.CreateUser{
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+	}
+	userID, err := service.CreateUser(createRequest)
+	require.Nil(t, err)
+
+	_, err = service.CreateUser(createRequest)
+	require.NotNil(t, err)
+	assert.EqualError(t, httperrors.UserAlreadyRegistered, err.Error())
+
+	err = service.UpdateUser(userID, &request.UpdateUser{
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "female",
+		DateOfBirth: "1989-05-14",
+		Address:     "address",
+	})
+	require.Nil(t, err)
+
+	user, err := service.GetUser(userID)
+	require.Nil(t, err)
+	assert.Equal(t, "female", user.Gender)
+	assert.Equal(t, "1989-05-14", user.DateOfBirth.Format(model.DateLayout))
+	assert.Equal(t, model.UserStatusPending, user.Status)
+
+	err = service.ChangeUserStatus(userID, model.UserStatusActive, &request.ChangeUserStatus{Reason: "approved"})
+	require.Nil(t, err)
+	changes, err := service.GetUserStatusHistory(userID)
+	require.Nil(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, model.UserStatusPending, changes[0].FromStatus)
+	assert.Equal(t, "approved", changes[0].Reason)
+
+	require.Nil(t, sessionRepository.Create(&model.Session{
+		UserID:           userID,
+		RefreshTokenHash: "hash",
+		ExpiresAt:        time.Now().Add(time.Hour),
+	}))
+
+	require.Nil(t, service.DeleteUser(userID))
+	_, err = service.GetUser(userID)
+	assert.EqualError(t, httperrors.EntityNotFoundError("user"), err.Error())
+
+	session, err := sessionRepository.GetByRefreshToken("hash")
+	require.Nil(t, err)
+	assert.NotNil(t, session.RevokedAt)
+
+	events, err := outboxRepository.FindPending(time.Now().Add(time.Minute), 10)
+	require.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, model.EventUserCreated, events[0].Type)
+}
+
+func TestServiceUserContact(t *testing.T) {
+	userRepository := dao.NewMemoryUserRepository()
+	service := NewService(
+		userRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		noStatusHistory(),
+		dao.NewMemoryTransactor(
+			userRepository,
+			dao.NewMemoryOutboxRepository(),
+			dao.NewMemoryUserStatusHistoryRepository(),
+			dao.NewMemorySessionRepository(),
+		),
+	)
+
+	userID, err := service.CreateUser(&request.CreateUser{
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Email:       "john@example.com",
+		Phone:       "0048 123-456-789",
+	})
+	require.Nil(t, err)
+
+	user, err := service.GetUser(userID)
+	require.Nil(t, err)
+	assert.Equal(t, "john@example.com", user.Email)
+	assert.Equal(t, "+48123456789", user.Phone)
+
+	_, err = service.CreateUser(&request.CreateUser{
+		Name:        "other",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Email:       "JOHN@example.com",
+	})
+	require.NotNil(t, err)
+	assert.EqualError(t, httperrors.UserEmailAlreadyRegistered, err.Error())
+
+	_, err = userRepository.MarkEmailVerified(userID, "john@example.com", time.Now())
+	require.Nil(t, err)
+	updateRequest := &request.UpdateUser{
+		Name:        "name",
+		Surname:     "surname",
+		Gender:      "male",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Email:       "John@Example.com",
+	}
+	require.Nil(t, service.UpdateUser(userID, updateRequest))
+	user, err = service.GetUser(userID)
+	require.Nil(t, err)
+	assert.NotNil(t, user.EmailVerifiedAt, "verification is kept when only letter case of the email changes")
+	assert.Empty(t, user.Phone)
+
+	updateRequest.Email = "john.smith@example.com"
+	require.Nil(t, service.UpdateUser(userID, updateRequest))
+	user, err = service.GetUser(userID)
+	require.Nil(t, err)
+	assert.Nil(t, user.EmailVerifiedAt)
 }
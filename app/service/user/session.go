@@ -0,0 +1,267 @@
+package user
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/auth"
+	"github.com/mmgopher/user-service/app/service/user/validator"
+)
+
+// Client represents device which the user logs in from.
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// Tokens represents access token and refresh token of the user session.
+type Tokens struct {
+	Access           *auth.Token
+	Refresh          string
+	RefreshExpiresAt time.Time
+}
+
+// SessionProvider provides an interface to manage sessions of users
+type SessionProvider interface {
+	// Refresh rotates refresh token of the session and returns new tokens.
+	Refresh(request *request.RefreshToken, client Client) (*Tokens, error)
+	// GetSessions returns active sessions of the user, the most recently used first.
+	GetSessions(userID int) ([]model.Session, error)
+	// RevokeSession revokes the session of the user.
+	RevokeSession(userID, sessionID int) error
+	// RevokeSessions revokes all sessions of the user.
+	RevokeSessions(userID int) error
+}
+
+// SessionService represents service which manages sessions of users.
+// Session expires when its refresh token isn't used for sessionTTL.
+type SessionService struct {
+	userRepository      dao.UserRepositoryProvider
+	sessionRepository   dao.SessionRepositoryProvider
+	transactionProvider dao.TransactionProvider
+	tokenIssuer         *auth.TokenIssuer
+	sessionTTL          time.Duration
+	now                 func() time.Time
+}
+
+// NewSessionService creates new instance of SessionService.
+func NewSessionService(
+	userRepository dao.UserRepositoryProvider,
+	sessionRepository dao.SessionRepositoryProvider,
+	transactionProvider dao.TransactionProvider,
+	tokenIssuer *auth.TokenIssuer,
+	sessionTTL time.Duration,
+) *SessionService {
+	return &SessionService{
+		userRepository:      userRepository,
+		sessionRepository:   sessionRepository,
+		transactionProvider: transactionProvider,
+		tokenIssuer:         tokenIssuer,
+		sessionTTL:          sessionTTL,
+		now:                 time.Now,
+	}
+}
+
+// Refresh rotates refresh token of the session and returns new tokens.
+// Refresh token can be used once, reuse of rotated token revokes the whole session
+// because either the user or an attacker holds a stolen token.
+func (s SessionService) Refresh(request *request.RefreshToken, client Client) (*Tokens, error) {
+
+	if err := validator.ValidateRefreshTokenRequest(request); err != nil {
+		return nil, err
+	}
+
+	tokenHash := hashOneTimeToken(request.RefreshToken)
+	session, err := s.sessionRepository.GetByRefreshToken(tokenHash)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	now := s.now().UTC()
+	if session == nil || !session.Active(now) {
+		return nil, httperrors.RefreshTokenIncorrect
+	}
+
+	if session.RefreshTokenHash != tokenHash {
+		return nil, s.revokeReused(session)
+	}
+
+	user, err := s.userRepository.GetByID(session.UserID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if user == nil {
+		return nil, httperrors.RefreshTokenIncorrect
+	}
+
+	// locked user keeps the sessions, but they can't be used until the user is activated
+	if user.Status != model.UserStatusActive {
+		return nil, httperrors.AuthUserNotActive(user.Status)
+	}
+
+	refreshToken, err := generateOneTimeToken()
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	session.RefreshTokenHash = hashOneTimeToken(refreshToken)
+	session.UserAgent = client.UserAgent
+	session.IP = client.IP
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(s.sessionTTL)
+
+	var rotated bool
+	err = runInTransaction(s.transactionProvider, func(repositories dao.Repositories) error {
+		if rotated, err = repositories.Sessions.Rotate(session, tokenHash); err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the same token was used concurrently, which is reuse as well
+	if !rotated {
+		return nil, s.revokeReused(session)
+	}
+
+	return issueSessionTokens(s.tokenIssuer, session, refreshToken)
+}
+
+// GetSessions returns active sessions of the user, the most recently used first.
+func (s SessionService) GetSessions(userID int) ([]model.Session, error) {
+	if err := s.checkUser(userID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepository.FindActiveByUserID(userID, s.now().UTC())
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes the session of the user.
+// Access tokens issued for the session stay valid until they expire.
+func (s SessionService) RevokeSession(userID, sessionID int) error {
+	if err := s.checkUser(userID); err != nil {
+		return err
+	}
+
+	revoked, err := s.sessionRepository.Revoke(userID, sessionID, s.now().UTC())
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !revoked {
+		return httperrors.EntityNotFoundError("session")
+	}
+
+	return nil
+}
+
+// RevokeSessions revokes all sessions of the user.
+func (s SessionService) RevokeSessions(userID int) error {
+	if err := s.checkUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepository.RevokeAll(userID, s.now().UTC()); err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	return nil
+}
+
+// revokeReused revokes the session whose refresh token was used more than once
+// and returns error of incorrect refresh token.
+func (s SessionService) revokeReused(session *model.Session) error {
+	log.Warnf("refresh token reuse detected, session is revoked, userID=%d, sessionID=%d", session.UserID, session.ID)
+
+	if _, err := s.sessionRepository.Revoke(session.UserID, session.ID, s.now().UTC()); err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	return httperrors.RefreshTokenIncorrect
+}
+
+// checkUser returns not found error when the user doesn't exist.
+func (s SessionService) checkUser(userID int) error {
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if user == nil {
+		return httperrors.EntityNotFoundError("user")
+	}
+
+	return nil
+}
+
+// startSession creates new session of the user logged in from the client and returns its tokens.
+func startSession(
+	provider dao.TransactionProvider,
+	tokenIssuer *auth.TokenIssuer,
+	userID int,
+	client Client,
+	now time.Time,
+	sessionTTL time.Duration,
+) (*Tokens, error) {
+	refreshToken, err := generateOneTimeToken()
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	session := &model.Session{
+		UserID:           userID,
+		RefreshTokenHash: hashOneTimeToken(refreshToken),
+		UserAgent:        client.UserAgent,
+		IP:               client.IP,
+		LastSeenAt:       now,
+		ExpiresAt:        now.Add(sessionTTL),
+	}
+
+	err = runInTransaction(provider, func(repositories dao.Repositories) error {
+		if err := repositories.Sessions.Create(session); err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return issueSessionTokens(tokenIssuer, session, refreshToken)
+}
+
+// issueSessionTokens returns new access token of the session together with its refresh token.
+func issueSessionTokens(tokenIssuer *auth.TokenIssuer, session *model.Session, refreshToken string) (*Tokens, error) {
+	accessToken, err := tokenIssuer.Issue(session.UserID, session.ID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return &Tokens{
+		Access:           accessToken,
+		Refresh:          refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// revokeUserSessions revokes all sessions of the user in the transaction.
+func revokeUserSessions(repositories dao.Repositories, userID int, revokedAt time.Time) error {
+	if err := repositories.Sessions.RevokeAll(userID, revokedAt); err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	return nil
+}
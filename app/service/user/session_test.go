@@ -0,0 +1,161 @@
+// +build unit
+
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/auth"
+)
+
+// newTestSessionService returns service with user 5001 whose sessions expire after a day of inactivity.
+func newTestSessionService(user *model.User, sessionRepository *dao.MemorySessionRepository) *SessionService {
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("GetByID", 5001).Return(user, nil)
+	mockUserRepository.On("GetByID", 5002).Return(nil, nil)
+	return NewSessionService(
+		&mockUserRepository,
+		sessionRepository,
+		newMockTransactionProvider(dao.Repositories{Sessions: sessionRepository}),
+		auth.NewTokenIssuer([]byte("secret"), "user-service", time.Hour),
+		24*time.Hour,
+	)
+}
+
+// newTestSession starts session of user 5001 and returns its tokens.
+func newTestSession(t *testing.T, service *SessionService, now time.Time) *Tokens {
+	tokens, err := startSession(service.transactionProvider, service.tokenIssuer, 5001, testClient, now, service.sessionTTL)
+	require.Nil(t, err)
+	return tokens
+}
+
+func TestRefreshSession(t *testing.T) {
+	sessionRepository := dao.NewMemorySessionRepository()
+	service := newTestSessionService(&model.User{ID: 5001, Status: model.UserStatusActive}, sessionRepository)
+	now := time.Now().UTC()
+	tokens := newTestSession(t, service, now.Add(-time.Hour))
+
+	client := Client{IP: "198.51.100.7", UserAgent: "other-agent/2.0"}
+	refreshed, err := service.Refresh(&request.RefreshToken{RefreshToken: tokens.Refresh}, client)
+	require.Nil(t, err)
+	assert.NotEqual(t, tokens.Refresh, refreshed.Refresh)
+	assert.True(t, refreshed.RefreshExpiresAt.After(tokens.RefreshExpiresAt))
+
+	claims, err := service.tokenIssuer.Parse(refreshed.Access.Value)
+	require.Nil(t, err)
+	assert.Equal(t, "5001", claims.Subject)
+
+	sessions, err := service.GetSessions(5001)
+	require.Nil(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, claims.SessionID, sessions[0].ID)
+	assert.Equal(t, client.IP, sessions[0].IP)
+	assert.Equal(t, client.UserAgent, sessions[0].UserAgent)
+}
+
+func TestRefreshSessionReuseRevokesSession(t *testing.T) {
+	sessionRepository := dao.NewMemorySessionRepository()
+	service := newTestSessionService(&model.User{ID: 5001, Status: model.UserStatusActive}, sessionRepository)
+	tokens := newTestSession(t, service, time.Now().UTC())
+
+	refreshed, err := service.Refresh(&request.RefreshToken{RefreshToken: tokens.Refresh}, testClient)
+	require.Nil(t, err)
+
+	_, err = service.Refresh(&request.RefreshToken{RefreshToken: tokens.Refresh}, testClient)
+	assert.Equal(t, httperrors.RefreshTokenIncorrect, err)
+
+	// the token rotated by the legitimate client is revoked together with the session
+	_, err = service.Refresh(&request.RefreshToken{RefreshToken: refreshed.Refresh}, testClient)
+	assert.Equal(t, httperrors.RefreshTokenIncorrect, err)
+
+	sessions, err := service.GetSessions(5001)
+	require.Nil(t, err)
+	assert.Len(t, sessions, 0)
+}
+
+func TestRefreshSessionErrors(t *testing.T) {
+	sessionRepository := dao.NewMemorySessionRepository()
+	user := &model.User{ID: 5001, Status: model.UserStatusActive}
+	service := newTestSessionService(user, sessionRepository)
+	expired := newTestSession(t, service, time.Now().UTC().Add(-25*time.Hour))
+	tokens := newTestSession(t, service, time.Now().UTC())
+
+	_, err := service.Refresh(&request.RefreshToken{RefreshToken: expired.Refresh}, testClient)
+	assert.Equal(t, httperrors.RefreshTokenIncorrect, err)
+
+	_, err = service.Refresh(&request.RefreshToken{RefreshToken: "unknown"}, testClient)
+	assert.Equal(t, httperrors.RefreshTokenIncorrect, err)
+
+	_, err = service.Refresh(&request.RefreshToken{}, testClient)
+	var expected httperrors.Details
+	expected.Add("/refresh_token", httperrors.RefreshTokenEmpty)
+	assert.Equal(t, expected.Err(), err)
+
+	// locked user keeps the session, which can be used again after the user is activated
+	user.Status = model.UserStatusLocked
+	_, err = service.Refresh(&request.RefreshToken{RefreshToken: tokens.Refresh}, testClient)
+	assert.Equal(t, httperrors.AuthUserNotActive(model.UserStatusLocked), err)
+
+	user.Status = model.UserStatusActive
+	_, err = service.Refresh(&request.RefreshToken{RefreshToken: tokens.Refresh}, testClient)
+	assert.Nil(t, err)
+}
+
+func TestRevokeSession(t *testing.T) {
+	sessionRepository := dao.NewMemorySessionRepository()
+	service := newTestSessionService(&model.User{ID: 5001, Status: model.UserStatusActive}, sessionRepository)
+	tokens := newTestSession(t, service, time.Now().UTC())
+	other := newTestSession(t, service, time.Now().UTC())
+
+	sessionID := sessionIDOf(t, service, tokens)
+	require.Nil(t, service.RevokeSession(5001, sessionID))
+
+	_, err := service.Refresh(&request.RefreshToken{RefreshToken: tokens.Refresh}, testClient)
+	assert.Equal(t, httperrors.RefreshTokenIncorrect, err)
+
+	sessions, err := service.GetSessions(5001)
+	require.Nil(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, sessionIDOf(t, service, other), sessions[0].ID)
+
+	assert.Equal(t, httperrors.EntityNotFoundError("session"), service.RevokeSession(5001, sessionID))
+	assert.Equal(t, httperrors.EntityNotFoundError("user"), service.RevokeSession(5002, sessionID))
+
+	require.Nil(t, service.RevokeSessions(5001))
+	_, err = service.Refresh(&request.RefreshToken{RefreshToken: other.Refresh}, testClient)
+	assert.Equal(t, httperrors.RefreshTokenIncorrect, err)
+	assert.Equal(t, httperrors.EntityNotFoundError("user"), service.RevokeSessions(5002))
+}
+
+func TestGetSessionsOrder(t *testing.T) {
+	sessionRepository := dao.NewMemorySessionRepository()
+	service := newTestSessionService(&model.User{ID: 5001, Status: model.UserStatusActive}, sessionRepository)
+	now := time.Now().UTC()
+	older := newTestSession(t, service, now.Add(-2*time.Hour))
+	newer := newTestSession(t, service, now.Add(-time.Hour))
+
+	sessions, err := service.GetSessions(5001)
+	require.Nil(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, sessionIDOf(t, service, newer), sessions[0].ID)
+	assert.Equal(t, sessionIDOf(t, service, older), sessions[1].ID)
+
+	_, err = service.GetSessions(5002)
+	assert.Equal(t, httperrors.EntityNotFoundError("user"), err)
+}
+
+// sessionIDOf returns ID of the session which the access token was issued for.
+func sessionIDOf(t *testing.T, service *SessionService, tokens *Tokens) int {
+	claims, err := service.tokenIssuer.Parse(tokens.Access.Value)
+	require.Nil(t, err)
+	require.NotZero(t, claims.SessionID)
+	return claims.SessionID
+}
@@ -0,0 +1,105 @@
+package user
+
+import (
+	"time"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/user/validator"
+)
+
+// emailVerifiedReason is recorded in status history when pending user is activated by email verification.
+const emailVerifiedReason = "email verified"
+
+// userStatusTransitions lists statuses which user with the status can be changed to.
+// Closed is a final status.
+var userStatusTransitions = map[string][]string{
+	model.UserStatusPending:   {model.UserStatusActive, model.UserStatusClosed},
+	model.UserStatusActive:    {model.UserStatusSuspended, model.UserStatusLocked, model.UserStatusClosed},
+	model.UserStatusSuspended: {model.UserStatusActive, model.UserStatusClosed},
+	model.UserStatusLocked:    {model.UserStatusActive, model.UserStatusClosed},
+}
+
+// canChangeUserStatus checks if user with status from can be changed to status to.
+func canChangeUserStatus(from, to string) bool {
+	for _, status := range userStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ChangeUserStatus changes status of the user when the transition is allowed and records it in status history.
+func (s Service) ChangeUserStatus(userID int, status string, request *request.ChangeUserStatus) error {
+
+	if err := validator.ValidateChangeUserStatusRequest(request); err != nil {
+		return err
+	}
+
+	return s.runInTransaction(func(repositories dao.Repositories) error {
+		user, err := repositories.Users.GetByID(userID)
+		if err != nil {
+			return httperrors.InternalServerError.WithCause(err)
+		}
+
+		if user == nil {
+			return httperrors.EntityNotFoundError("user")
+		}
+
+		if !canChangeUserStatus(user.Status, status) {
+			return httperrors.UserStatusTransitionNotAllowed(user.Status, status)
+		}
+
+		return changeUserStatus(repositories, user, status, request.Reason, s.now().UTC())
+	})
+}
+
+// GetUserStatusHistory returns all status transitions of the user from the oldest one
+func (s Service) GetUserStatusHistory(userID int) ([]model.UserStatusChange, error) {
+	if _, err := s.GetUser(userID); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.statusHistoryRepository.FindByUserID(userID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return changes, nil
+}
+
+// changeUserStatus stores new status of the user, records the transition and emits user.updated event.
+// User is changed only when it still has the status it was read with.
+// Sessions of suspended and closed user are revoked at now, locked user keeps them until it is activated.
+func changeUserStatus(repositories dao.Repositories, user *model.User, status, reason string, now time.Time) error {
+	updated, err := repositories.Users.UpdateStatus(user.ID, user.Status, status)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !updated {
+		return httperrors.UserStatusTransitionNotAllowed(user.Status, status)
+	}
+
+	if err := repositories.StatusHistory.Add(&model.UserStatusChange{
+		UserID:     user.ID,
+		FromStatus: user.Status,
+		ToStatus:   status,
+		Reason:     reason,
+	}); err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if status == model.UserStatusSuspended || status == model.UserStatusClosed {
+		if err := revokeUserSessions(repositories, user.ID, now); err != nil {
+			return err
+		}
+	}
+
+	user.Status = status
+	return emitUserEvent(repositories.Outbox, model.EventUserUpdated, user)
+}
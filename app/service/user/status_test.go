@@ -0,0 +1,157 @@
+// +build unit
+
+package user
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+func newStatusTestService(user *model.User, mockUserRepository *dao.MockUserRepositoryProvider,
+	mockStatusHistoryRepository *dao.MockUserStatusHistoryRepositoryProvider,
+	mockOutboxRepository *dao.MockOutboxRepositoryProvider,
+	mockSessionRepository *dao.MockSessionRepositoryProvider) *Service {
+
+	mockUserRepository.On("GetByID", 5001).Return(user, nil)
+	return NewService(
+		mockUserRepository,
+		noAttributeDefinitions(),
+		noGroups(),
+		mockStatusHistoryRepository,
+		newMockTransactionProvider(dao.Repositories{
+			Users:         mockUserRepository,
+			Outbox:        mockOutboxRepository,
+			StatusHistory: mockStatusHistoryRepository,
+			Sessions:      mockSessionRepository,
+		}),
+	)
+}
+
+func TestChangeUserStatusOK(t *testing.T) {
+	user := &model.User{ID: 5001, Name: "name", Status: model.UserStatusActive}
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("UpdateStatus", 5001, model.UserStatusActive, model.UserStatusSuspended).Return(true, nil)
+	mockStatusHistoryRepository := dao.MockUserStatusHistoryRepositoryProvider{}
+	mockStatusHistoryRepository.On("Add", &model.UserStatusChange{
+		UserID:     5001,
+		FromStatus: model.UserStatusActive,
+		ToStatus:   model.UserStatusSuspended,
+		Reason:     "chargeback",
+	}).Return(nil)
+	mockOutboxRepository := dao.MockOutboxRepositoryProvider{}
+	mockOutboxRepository.On("Add", mock.MatchedBy(func(event *model.Event) bool {
+		return event.Type == model.EventUserUpdated && event.AggregateID == 5001
+	})).Return(nil)
+
+	now := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+	mockSessionRepository := dao.MockSessionRepositoryProvider{}
+	mockSessionRepository.On("RevokeAll", 5001, now).Return(nil)
+
+	service := newStatusTestService(user, &mockUserRepository, &mockStatusHistoryRepository, &mockOutboxRepository,
+		&mockSessionRepository)
+	service.now = func() time.Time {
+		return now
+	}
+	err := service.ChangeUserStatus(5001, model.UserStatusSuspended, &request.ChangeUserStatus{Reason: "chargeback"})
+	assert.Nil(t, err)
+	assert.Equal(t, model.UserStatusSuspended, user.Status)
+	mockUserRepository.AssertExpectations(t)
+	mockStatusHistoryRepository.AssertExpectations(t)
+	mockOutboxRepository.AssertExpectations(t)
+	mockSessionRepository.AssertExpectations(t)
+}
+
+func TestChangeUserStatusLockKeepsSessions(t *testing.T) {
+	user := &model.User{ID: 5001, Name: "name", Status: model.UserStatusActive}
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("UpdateStatus", 5001, model.UserStatusActive, model.UserStatusLocked).Return(true, nil)
+	mockStatusHistoryRepository := dao.MockUserStatusHistoryRepositoryProvider{}
+	mockStatusHistoryRepository.On("Add", mock.Anything).Return(nil)
+	mockOutboxRepository := dao.MockOutboxRepositoryProvider{}
+	mockOutboxRepository.On("Add", mock.Anything).Return(nil)
+	mockSessionRepository := dao.MockSessionRepositoryProvider{}
+
+	service := newStatusTestService(user, &mockUserRepository, &mockStatusHistoryRepository, &mockOutboxRepository,
+		&mockSessionRepository)
+	err := service.ChangeUserStatus(5001, model.UserStatusLocked, &request.ChangeUserStatus{Reason: "fraud"})
+	assert.Nil(t, err)
+	mockSessionRepository.AssertNotCalled(t, "RevokeAll", mock.Anything, mock.Anything)
+}
+
+func TestChangeUserStatusNotAllowed(t *testing.T) {
+	var testData = []struct {
+		name string
+		from string
+		to   string
+	}{
+		{"PendingToSuspended", model.UserStatusPending, model.UserStatusSuspended},
+		{"SuspendedToLocked", model.UserStatusSuspended, model.UserStatusLocked},
+		{"ActiveToActive", model.UserStatusActive, model.UserStatusActive},
+		{"ClosedIsFinal", model.UserStatusClosed, model.UserStatusActive},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepository := dao.MockUserRepositoryProvider{}
+			service := newStatusTestService(&model.User{ID: 5001, Status: tt.from}, &mockUserRepository,
+				&dao.MockUserStatusHistoryRepositoryProvider{}, &dao.MockOutboxRepositoryProvider{},
+				&dao.MockSessionRepositoryProvider{})
+
+			err := service.ChangeUserStatus(5001, tt.to, &request.ChangeUserStatus{Reason: "reason"})
+			assert.Equal(t, httperrors.UserStatusTransitionNotAllowed(tt.from, tt.to), err)
+			assert.Equal(t, http.StatusConflict, err.(*httperrors.HTTPError).HTTPCode)
+			mockUserRepository.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestChangeUserStatusConcurrentChange(t *testing.T) {
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockUserRepository.On("UpdateStatus", 5001, model.UserStatusActive, model.UserStatusLocked).Return(false, nil)
+	mockStatusHistoryRepository := dao.MockUserStatusHistoryRepositoryProvider{}
+
+	service := newStatusTestService(&model.User{ID: 5001, Status: model.UserStatusActive}, &mockUserRepository,
+		&mockStatusHistoryRepository, &dao.MockOutboxRepositoryProvider{}, &dao.MockSessionRepositoryProvider{})
+	err := service.ChangeUserStatus(5001, model.UserStatusLocked, &request.ChangeUserStatus{Reason: "fraud"})
+	assert.Equal(t, httperrors.UserStatusTransitionNotAllowed(model.UserStatusActive, model.UserStatusLocked), err)
+	mockStatusHistoryRepository.AssertNotCalled(t, "Add", mock.Anything)
+}
+
+func TestChangeUserStatusErrors(t *testing.T) {
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	service := newStatusTestService(nil, &mockUserRepository,
+		&dao.MockUserStatusHistoryRepositoryProvider{}, &dao.MockOutboxRepositoryProvider{},
+		&dao.MockSessionRepositoryProvider{})
+
+	err := service.ChangeUserStatus(5001, model.UserStatusClosed, &request.ChangeUserStatus{Reason: "gdpr"})
+	assert.Equal(t, httperrors.EntityNotFoundError("user"), err)
+
+	var expected httperrors.Details
+	expected.Add("/reason", httperrors.UserStatusReasonEmpty)
+	err = service.ChangeUserStatus(5001, model.UserStatusClosed, &request.ChangeUserStatus{})
+	assert.Equal(t, expected.Err(), err)
+}
+
+func TestGetUserStatusHistory(t *testing.T) {
+	changes := []model.UserStatusChange{
+		{ID: 1, UserID: 5001, FromStatus: model.UserStatusPending, ToStatus: model.UserStatusActive},
+	}
+	mockUserRepository := dao.MockUserRepositoryProvider{}
+	mockStatusHistoryRepository := dao.MockUserStatusHistoryRepositoryProvider{}
+	mockStatusHistoryRepository.On("FindByUserID", 5001).Return(changes, nil)
+
+	service := newStatusTestService(&model.User{ID: 5001}, &mockUserRepository,
+		&mockStatusHistoryRepository, &dao.MockOutboxRepositoryProvider{}, &dao.MockSessionRepositoryProvider{})
+	result, err := service.GetUserStatusHistory(5001)
+	assert.Nil(t, err)
+	assert.Equal(t, changes, result)
+}
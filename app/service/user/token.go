@@ -0,0 +1,84 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// oneTimeTokenLength is number of random bytes of one-time token.
+const oneTimeTokenLength = 32
+
+// issueOneTimeToken generates token delivered to the user and stores its hash.
+// Tokens of the user with the same purpose issued earlier can not be used anymore.
+func issueOneTimeToken(
+	repository dao.OneTimeTokenRepositoryProvider,
+	purpose string,
+	userID int,
+	subject string,
+	expiresAt time.Time,
+) (string, *model.OneTimeToken, error) {
+	token, err := generateOneTimeToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := repository.DeleteByUserID(purpose, userID); err != nil {
+		return "", nil, err
+	}
+
+	stored := &model.OneTimeToken{
+		TokenHash: hashOneTimeToken(token),
+		Purpose:   purpose,
+		UserID:    userID,
+		Subject:   subject,
+		ExpiresAt: expiresAt,
+	}
+	if err := repository.Create(stored); err != nil {
+		return "", nil, err
+	}
+
+	return token, stored, nil
+}
+
+// findOneTimeToken returns stored token with the purpose, nil is returned when it doesn't exist or it expired.
+func findOneTimeToken(
+	repository dao.OneTimeTokenRepositoryProvider,
+	purpose string,
+	token string,
+	now time.Time,
+) (*model.OneTimeToken, error) {
+	stored, err := repository.Get(purpose, hashOneTimeToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if stored == nil || !now.Before(stored.ExpiresAt) {
+		return nil, nil
+	}
+
+	return stored, nil
+}
+
+// generateOneTimeToken generates random token delivered to the user.
+func generateOneTimeToken() (string, error) {
+	b := make([]byte, oneTimeTokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "impossible to generate one-time token")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashOneTimeToken returns hash of the token which is stored instead of the token.
+func hashOneTimeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
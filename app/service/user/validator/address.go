@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// countryRegex matches ISO 3166-1 alpha-2 country code in any letter case.
+var countryRegex = regexp.MustCompile(`^[A-Za-z]{2}$`)
+
+var supportedAddressTypes = map[string]struct{}{
+	model.AddressHome:     {},
+	model.AddressBilling:  {},
+	model.AddressShipping: {},
+}
+
+// Address validators
+var (
+	// validateAddressType validates `type` request parameter.
+	validateAddressType = func(addressType string) *httperrors.HTTPError {
+		if _, ok := supportedAddressTypes[strings.ToLower(addressType)]; !ok {
+			return httperrors.AddressTypeNotSupported(addressType)
+		}
+
+		return nil
+	}
+
+	// validateAddressStreet validates `street` request parameter.
+	validateAddressStreet = func(street string) *httperrors.HTTPError {
+		if strings.TrimSpace(street) == "" {
+			return httperrors.AddressStreetEmpty
+		}
+
+		return nil
+	}
+
+	// validateAddressCity validates `city` request parameter.
+	validateAddressCity = func(city string) *httperrors.HTTPError {
+		if strings.TrimSpace(city) == "" {
+			return httperrors.AddressCityEmpty
+		}
+
+		return nil
+	}
+
+	// validateAddressCountry validates `country` request parameter.
+	validateAddressCountry = func(country string) *httperrors.HTTPError {
+		if !countryRegex.MatchString(country) {
+			return httperrors.AddressCountryIncorrect
+		}
+
+		return nil
+	}
+)
+
+// ValidateCreateAddressRequest validates POST /v1/users/:user_id/addresses endpoint.
+// All invalid fields are reported at once as details of RequestValidationFailed.
+func ValidateCreateAddressRequest(request *request.CreateAddress) error {
+
+	var details httperrors.Details
+	details.Add("/type", validateAddressType(request.Type))
+	details.Add("/street", validateAddressStreet(request.Street))
+	details.Add("/city", validateAddressCity(request.City))
+	details.Add("/country", validateAddressCountry(request.Country))
+
+	return details.Err()
+}
+
+// ValidateUpdateAddressRequest validates PUT /v1/users/:user_id/addresses/:address_id endpoint.
+// All invalid fields are reported at once as details of RequestValidationFailed.
+func ValidateUpdateAddressRequest(request *request.UpdateAddress) error {
+
+	var details httperrors.Details
+	details.Add("/type", validateAddressType(request.Type))
+	details.Add("/street", validateAddressStreet(request.Street))
+	details.Add("/city", validateAddressCity(request.City))
+	details.Add("/country", validateAddressCountry(request.Country))
+
+	return details.Err()
+}
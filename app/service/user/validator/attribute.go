@@ -0,0 +1,266 @@
+package validator
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// AttributeSortPrefix marks sorting of users by custom attribute, e.g. attr.department:asc.
+const AttributeSortPrefix = "attr."
+
+// attributeNameRegex matches names of custom attributes which can be safely used in JSON paths.
+var attributeNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+var supportedAttributeTypes = map[string]struct{}{
+	model.AttributeString:  {},
+	model.AttributeNumber:  {},
+	model.AttributeBoolean: {},
+}
+
+// Attribute definition validators
+var (
+	// validateAttributeName validates `name` request parameter.
+	validateAttributeName = func(name string) *httperrors.HTTPError {
+		if !attributeNameRegex.MatchString(name) {
+			return httperrors.AttributeNameIncorrect
+		}
+
+		return nil
+	}
+
+	// validateAttributeType validates `type` request parameter.
+	validateAttributeType = func(attributeType string) *httperrors.HTTPError {
+		if _, ok := supportedAttributeTypes[attributeType]; !ok {
+			return httperrors.AttributeTypeNotSupported(attributeType)
+		}
+
+		return nil
+	}
+
+	// validateAttributeEnum validates `enum` request parameter, only string attributes can have it.
+	validateAttributeEnum = func(attributeType string, enum []string) *httperrors.HTTPError {
+		if len(enum) > 0 && attributeType != model.AttributeString {
+			return httperrors.AttributeEnumNotAllowed
+		}
+
+		return nil
+	}
+
+	// validateAttributePattern validates `pattern` request parameter, only string attributes can have it.
+	validateAttributePattern = func(attributeType, pattern string) *httperrors.HTTPError {
+		if pattern == "" {
+			return nil
+		}
+
+		if attributeType != model.AttributeString {
+			return httperrors.AttributePatternNotAllowed
+		}
+
+		if _, err := regexp.Compile(pattern); err != nil {
+			return httperrors.AttributePatternIncorrect
+		}
+
+		return nil
+	}
+)
+
+// ValidateCreateAttributeDefinitionRequest validates POST /v1/admin/attribute-definitions endpoint.
+// All invalid fields are reported at once as details of RequestValidationFailed.
+func ValidateCreateAttributeDefinitionRequest(request *request.CreateAttributeDefinition) error {
+
+	var details httperrors.Details
+	details.Add("/name", validateAttributeName(request.Name))
+	details.Add("/type", validateAttributeType(request.Type))
+	details.Add("/enum", validateAttributeEnum(request.Type, request.Enum))
+	details.Add("/pattern", validateAttributePattern(request.Type, request.Pattern))
+
+	return details.Err()
+}
+
+// ValidateUpdateAttributeDefinitionRequest validates PUT /v1/admin/attribute-definitions/:definition_id endpoint.
+// Type of the attribute is taken from the stored definition because it can't be changed.
+func ValidateUpdateAttributeDefinitionRequest(
+	attributeType string,
+	request *request.UpdateAttributeDefinition,
+) error {
+
+	var details httperrors.Details
+	details.Add("/enum", validateAttributeEnum(attributeType, request.Enum))
+	details.Add("/pattern", validateAttributePattern(attributeType, request.Pattern))
+
+	return details.Err()
+}
+
+// validateAttributes adds problems of custom attributes of the user to details.
+// Definitions are checked in their order and unknown attributes are reported in order of their names.
+func validateAttributes(
+	details *httperrors.Details,
+	definitions []model.AttributeDefinition,
+	attributes map[string]interface{},
+) {
+	defined := make(map[string]struct{}, len(definitions))
+	for _, definition := range definitions {
+		defined[definition.Name] = struct{}{}
+		value, ok := attributes[definition.Name]
+		if !ok || value == nil {
+			if definition.Required {
+				details.Add("/attributes/"+definition.Name, httperrors.AttributeRequired(definition.Name))
+			}
+			continue
+		}
+
+		details.Add("/attributes/"+definition.Name, validateAttributeValue(definition, value))
+	}
+
+	var unknown []string
+	for name := range attributes {
+		if _, ok := defined[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		details.Add("/attributes/"+name, httperrors.AttributeUnknown(name))
+	}
+}
+
+// validateAttributeValue checks that value has type of the attribute and matches its restrictions.
+func validateAttributeValue(definition model.AttributeDefinition, value interface{}) *httperrors.HTTPError {
+	switch definition.Type {
+	case model.AttributeNumber:
+		switch value.(type) {
+		case float64, json.Number:
+			return nil
+		}
+	case model.AttributeBoolean:
+		if _, ok := value.(bool); ok {
+			return nil
+		}
+	case model.AttributeString:
+		if text, ok := value.(string); ok {
+			return validateAttributeText(definition, text)
+		}
+	}
+
+	return httperrors.AttributeTypeMismatch(definition.Name, definition.Type)
+}
+
+// validateAttributeText checks that value of string attribute is allowed by enum and pattern of the attribute.
+func validateAttributeText(definition model.AttributeDefinition, text string) *httperrors.HTTPError {
+	if enum := definition.EnumValues(); len(enum) > 0 {
+		allowed := false
+		for _, value := range enum {
+			allowed = allowed || value == text
+		}
+
+		if !allowed {
+			return httperrors.AttributeValueNotAllowed(definition.Name)
+		}
+	}
+
+	if definition.Pattern != "" {
+		// pattern is validated when the definition is stored
+		pattern, err := regexp.Compile(definition.Pattern)
+		if err == nil && !pattern.MatchString(text) {
+			return httperrors.AttributePatternMismatch(definition.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateAttributeFilters adds problems of attr.<name> filters and sorting by custom attribute to details.
+func validateAttributeFilters(
+	details *httperrors.Details,
+	definitions []model.AttributeDefinition,
+	request *request.FindUsers,
+) {
+	names := make([]string, 0, len(request.Attributes))
+	for name := range request.Attributes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		field := "/" + AttributeSortPrefix + name
+		definition := findAttributeDefinition(definitions, name)
+		if definition == nil {
+			details.Add(field, httperrors.AttributeUnknown(name))
+			continue
+		}
+
+		if _, ok := ParseAttributeFilter(*definition, request.Attributes[name]); !ok {
+			details.Add(field, httperrors.AttributeTypeMismatch(name, definition.Type))
+		}
+	}
+
+	// sort in incorrect format is already reported
+	name := SortAttribute(request.Sort)
+	if name != "" && sortRegex.MatchString(request.Sort) && findAttributeDefinition(definitions, name) == nil {
+		details.Add("/sort", httperrors.AttributeUnknown(name))
+	}
+}
+
+// findAttributeDefinition returns definition of the attribute with the name or nil when there is none.
+func findAttributeDefinition(definitions []model.AttributeDefinition, name string) *model.AttributeDefinition {
+	for i := range definitions {
+		if definitions[i].Name == name {
+			return &definitions[i]
+		}
+	}
+
+	return nil
+}
+
+// SortAttribute returns name of custom attribute used to sort users, e.g. department for attr.department:asc.
+// Empty string is returned when users are sorted by another column.
+func SortAttribute(sort string) string {
+	if !strings.HasPrefix(sort, AttributeSortPrefix) {
+		return ""
+	}
+
+	name := strings.TrimPrefix(sort, AttributeSortPrefix)
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name = name[:i]
+	}
+
+	return name
+}
+
+// ParseAttributeFilter returns value of attr.<name> query parameter converted to type of the attribute.
+// False is returned when the value can't be converted.
+func ParseAttributeFilter(definition model.AttributeDefinition, value string) (interface{}, bool) {
+	switch definition.Type {
+	case model.AttributeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		// NaN and infinities can't be represented in JSON
+		return number, err == nil && !math.IsNaN(number) && !math.IsInf(number, 0)
+	case model.AttributeBoolean:
+		boolean, err := strconv.ParseBool(value)
+		return boolean, err == nil
+	default:
+		return value, true
+	}
+}
+
+// AttributeFilters returns values of attr.<name> filters converted to types of the attributes.
+// It has to be called after the request is validated.
+func AttributeFilters(definitions []model.AttributeDefinition, request *request.FindUsers) map[string]interface{} {
+	filters := make(map[string]interface{}, len(request.Attributes))
+	for name, value := range request.Attributes {
+		if definition := findAttributeDefinition(definitions, name); definition != nil {
+			filters[name], _ = ParseAttributeFilter(*definition, value)
+		}
+	}
+
+	return filters
+}
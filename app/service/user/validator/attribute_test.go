@@ -0,0 +1,137 @@
+// +build unit
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+var testAttributeDefinitions = []model.AttributeDefinition{
+	{Name: "department", Type: model.AttributeString, Required: true, Enum: types.JSONText(`["sales","support"]`)},
+	{Name: "employee_id", Type: model.AttributeString, Pattern: "^E[0-9]+$"},
+	{Name: "headcount", Type: model.AttributeNumber},
+	{Name: "remote", Type: model.AttributeBoolean},
+}
+
+func TestValidateCreateAttributeDefinitionRequest(t *testing.T) {
+	assert.Nil(t, ValidateCreateAttributeDefinitionRequest(&request.CreateAttributeDefinition{
+		Name:    "employee_id",
+		Type:    model.AttributeString,
+		Enum:    []string{"E1", "E2"},
+		Pattern: "^E[0-9]+$",
+	}))
+
+	err := ValidateCreateAttributeDefinitionRequest(&request.CreateAttributeDefinition{
+		Name:    "Employee-ID",
+		Type:    "date",
+		Pattern: "^E",
+	})
+	var expected httperrors.Details
+	expected.Add("/name", httperrors.AttributeNameIncorrect)
+	expected.Add("/type", httperrors.AttributeTypeNotSupported("date"))
+	expected.Add("/pattern", httperrors.AttributePatternNotAllowed)
+	assert.Equal(t, expected.Err(), err)
+
+	err = ValidateCreateAttributeDefinitionRequest(&request.CreateAttributeDefinition{
+		Name:    "level",
+		Type:    model.AttributeNumber,
+		Enum:    []string{"1"},
+		Pattern: "",
+	})
+	assert.Equal(t, validationFailed("/enum", httperrors.AttributeEnumNotAllowed), err)
+
+	err = ValidateUpdateAttributeDefinitionRequest(model.AttributeString, &request.UpdateAttributeDefinition{
+		Pattern: "[",
+	})
+	assert.Equal(t, validationFailed("/pattern", httperrors.AttributePatternIncorrect), err)
+}
+
+func TestValidateUserAttributes(t *testing.T) {
+	err := ValidateCreateUserRequest(&request.CreateUser{
+		Name:        "name",
+		Gender:      "male",
+		Surname:     "surname",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Attributes: map[string]interface{}{
+			"department":  "sales",
+			"employee_id": "E42",
+			"headcount":   float64(12),
+			"remote":      nil,
+		},
+	}, testAttributeDefinitions)
+	assert.Nil(t, err)
+
+	err = ValidateUpdateUserRequest(&request.UpdateUser{
+		Name:        "name",
+		Gender:      "male",
+		Surname:     "surname",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Attributes: map[string]interface{}{
+			"employee_id": "42",
+			"headcount":   "12",
+			"remote":      "yes",
+			"team":        "blue",
+			"floor":       float64(3),
+		},
+	}, testAttributeDefinitions)
+	var expected httperrors.Details
+	expected.Add("/attributes/department", httperrors.AttributeRequired("department"))
+	expected.Add("/attributes/employee_id", httperrors.AttributePatternMismatch("employee_id"))
+	expected.Add("/attributes/headcount", httperrors.AttributeTypeMismatch("headcount", model.AttributeNumber))
+	expected.Add("/attributes/remote", httperrors.AttributeTypeMismatch("remote", model.AttributeBoolean))
+	expected.Add("/attributes/floor", httperrors.AttributeUnknown("floor"))
+	expected.Add("/attributes/team", httperrors.AttributeUnknown("team"))
+	assert.Equal(t, expected.Err(), err)
+
+	err = ValidateCreateUserRequest(&request.CreateUser{
+		Name:        "name",
+		Gender:      "male",
+		Surname:     "surname",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Attributes:  map[string]interface{}{"department": "marketing"},
+	}, testAttributeDefinitions)
+	assert.Equal(t, validationFailed("/attributes/department", httperrors.AttributeValueNotAllowed("department")), err)
+}
+
+func TestValidateFindUsersRequestAttributes(t *testing.T) {
+	findRequest := &request.FindUsers{
+		Sort:       "attr.headcount:desc",
+		Attributes: map[string]string{"department": "sales", "headcount": "12.5", "remote": "true"},
+	}
+	require.Nil(t, ValidateFindUsersRequest(findRequest, testAttributeDefinitions))
+	assert.Equal(t, map[string]interface{}{
+		"department": "sales",
+		"headcount":  12.5,
+		"remote":     true,
+	}, AttributeFilters(testAttributeDefinitions, findRequest))
+
+	err := ValidateFindUsersRequest(&request.FindUsers{
+		Sort:       "attr.floor:asc",
+		Attributes: map[string]string{"headcount": "NaN", "remote": "sometimes", "team": "blue"},
+	}, testAttributeDefinitions)
+	var expected httperrors.Details
+	expected.Add("/attr.headcount", httperrors.AttributeTypeMismatch("headcount", model.AttributeNumber))
+	expected.Add("/attr.remote", httperrors.AttributeTypeMismatch("remote", model.AttributeBoolean))
+	expected.Add("/attr.team", httperrors.AttributeUnknown("team"))
+	expected.Add("/sort", httperrors.AttributeUnknown("floor"))
+	assert.Equal(t, expected.Err(), err)
+
+	err = ValidateFindUsersRequest(&request.FindUsers{Sort: "attr.Floor:asc"}, testAttributeDefinitions)
+	assert.Equal(t, validationFailed("/sort", httperrors.PaginationSortIncorrectFormat), err)
+}
+
+func TestSortAttribute(t *testing.T) {
+	assert.Equal(t, "department", SortAttribute("attr.department:asc"))
+	assert.Equal(t, "", SortAttribute("surname:asc"))
+}
@@ -0,0 +1,90 @@
+package validator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+// groupMembersMaxCount limits number of users added to or removed from the group in one request.
+const groupMembersMaxCount = 1000
+
+// Supported values of `group_match` query parameter
+const (
+	GroupMatchAny = "any"
+	GroupMatchAll = "all"
+)
+
+// Group validators
+var (
+	// validateGroupName validates `name` request parameter.
+	validateGroupName = func(name string) *httperrors.HTTPError {
+		if strings.TrimSpace(name) == "" {
+			return httperrors.GroupNameEmpty
+		}
+
+		return nil
+	}
+
+	// validateGroupMatch validates `group_match` query parameter.
+	validateGroupMatch = func(match string) *httperrors.HTTPError {
+		if match != "" && match != GroupMatchAny && match != GroupMatchAll {
+			return httperrors.GroupMatchNotSupported(match)
+		}
+
+		return nil
+	}
+)
+
+// ValidateCreateGroupRequest validates POST /v1/groups endpoint.
+func ValidateCreateGroupRequest(request *request.CreateGroup) error {
+
+	var details httperrors.Details
+	details.Add("/name", validateGroupName(request.Name))
+
+	return details.Err()
+}
+
+// ValidateUpdateGroupRequest validates PUT /v1/groups/:group_id endpoint.
+func ValidateUpdateGroupRequest(request *request.UpdateGroup) error {
+
+	var details httperrors.Details
+	details.Add("/name", validateGroupName(request.Name))
+
+	return details.Err()
+}
+
+// ValidateGroupMembersRequest validates POST and DELETE /v1/groups/:group_id/members endpoints.
+// All incorrect user IDs are reported at once as details of RequestValidationFailed.
+func ValidateGroupMembersRequest(request *request.GroupMembers) error {
+
+	var details httperrors.Details
+	switch {
+	case len(request.UserIDs) == 0:
+		details.Add("/user_ids", httperrors.GroupMembersEmpty)
+	case len(request.UserIDs) > groupMembersMaxCount:
+		details.Add("/user_ids", httperrors.GroupMembersTooMany(groupMembersMaxCount))
+	default:
+		for i, userID := range request.UserIDs {
+			if userID <= 0 {
+				details.Add("/user_ids/"+strconv.Itoa(i), httperrors.GroupMemberIDIncorrect)
+			}
+		}
+	}
+
+	return details.Err()
+}
+
+// validateGroupFilters adds problems of `group` and `group_match` query parameters to details.
+// Existence of groups and tags is checked when they are resolved.
+func validateGroupFilters(details *httperrors.Details, request *request.FindUsers) {
+	for i, groupID := range request.Groups {
+		if groupID <= 0 {
+			details.Add("/group/"+strconv.Itoa(i), httperrors.GroupUnknown(groupID))
+		}
+	}
+
+	details.Add("/group_match", validateGroupMatch(request.GroupMatch))
+}
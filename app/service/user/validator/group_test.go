@@ -0,0 +1,50 @@
+// +build unit
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+func TestValidateGroupRequests(t *testing.T) {
+	assert.Nil(t, ValidateCreateGroupRequest(&request.CreateGroup{Name: "beta"}))
+	assert.Equal(t, validationFailed("/name", httperrors.GroupNameEmpty),
+		ValidateCreateGroupRequest(&request.CreateGroup{Name: " ", Description: "beta testers"}))
+	assert.Equal(t, validationFailed("/name", httperrors.GroupNameEmpty),
+		ValidateUpdateGroupRequest(&request.UpdateGroup{}))
+}
+
+func TestValidateGroupMembersRequest(t *testing.T) {
+	assert.Nil(t, ValidateGroupMembersRequest(&request.GroupMembers{UserIDs: []int{1, 2}}))
+	assert.Equal(t, validationFailed("/user_ids", httperrors.GroupMembersEmpty),
+		ValidateGroupMembersRequest(&request.GroupMembers{}))
+	assert.Equal(t, validationFailed("/user_ids", httperrors.GroupMembersTooMany(groupMembersMaxCount)),
+		ValidateGroupMembersRequest(&request.GroupMembers{UserIDs: make([]int, groupMembersMaxCount+1)}))
+
+	var expected httperrors.Details
+	expected.Add("/user_ids/0", httperrors.GroupMemberIDIncorrect)
+	expected.Add("/user_ids/2", httperrors.GroupMemberIDIncorrect)
+	assert.Equal(t, expected.Err(), ValidateGroupMembersRequest(&request.GroupMembers{UserIDs: []int{0, 5, -1}}))
+}
+
+func TestValidateFindUsersRequestGroups(t *testing.T) {
+	assert.Nil(t, ValidateFindUsersRequest(&request.FindUsers{
+		Groups:     []int{1, 2},
+		Tags:       []string{"beta"},
+		GroupMatch: GroupMatchAll,
+	}, nil))
+
+	err := ValidateFindUsersRequest(&request.FindUsers{
+		Groups:     []int{1, 0},
+		GroupMatch: "some",
+	}, nil)
+	var expected httperrors.Details
+	expected.Add("/group/1", httperrors.GroupUnknown(0))
+	expected.Add("/group_match", httperrors.GroupMatchNotSupported("some"))
+	assert.Equal(t, expected.Err(), err)
+}
@@ -0,0 +1,35 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+// ValidateMFACodeRequest validates POST /v1/users/:user_id/mfa/* endpoints.
+// Code itself is checked by the service.
+func ValidateMFACodeRequest(request *request.MFACode) error {
+
+	var details httperrors.Details
+	if strings.TrimSpace(request.Code) == "" {
+		details.Add("/code", httperrors.MFACodeEmpty)
+	}
+
+	return details.Err()
+}
+
+// ValidateLoginMFARequest validates POST /v1/auth/login/mfa endpoint.
+func ValidateLoginMFARequest(request *request.LoginMFA) error {
+
+	var details httperrors.Details
+	if request.MFAToken == "" {
+		details.Add("/mfa_token", httperrors.MFATokenEmpty)
+	}
+
+	if strings.TrimSpace(request.Code) == "" {
+		details.Add("/code", httperrors.MFACodeEmpty)
+	}
+
+	return details.Err()
+}
@@ -0,0 +1,27 @@
+// +build unit
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+func TestValidateMFACodeRequest(t *testing.T) {
+	assert.Nil(t, ValidateMFACodeRequest(&request.MFACode{Code: "123456"}))
+	assert.Equal(t, validationFailed("/code", httperrors.MFACodeEmpty),
+		ValidateMFACodeRequest(&request.MFACode{Code: " "}))
+}
+
+func TestValidateLoginMFARequest(t *testing.T) {
+	assert.Nil(t, ValidateLoginMFARequest(&request.LoginMFA{MFAToken: "token", Code: "123456"}))
+
+	var expected httperrors.Details
+	expected.Add("/mfa_token", httperrors.MFATokenEmpty)
+	expected.Add("/code", httperrors.MFACodeEmpty)
+	assert.Equal(t, expected.Err(), ValidateLoginMFARequest(&request.LoginMFA{}))
+}
@@ -0,0 +1,92 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+// Password policy, the maximal length limits cost of hashing.
+const (
+	passwordMinLength = 10
+	passwordMaxLength = 128
+)
+
+// Password validators
+var (
+	// validatePassword checks if `new_password` request parameter meets password policy.
+	validatePassword = func(password string) *httperrors.HTTPError {
+		length := utf8.RuneCountInString(password)
+		if length < passwordMinLength {
+			return httperrors.PasswordTooShort(passwordMinLength)
+		}
+
+		if length > passwordMaxLength {
+			return httperrors.PasswordTooLong(passwordMaxLength)
+		}
+
+		if strings.IndexFunc(password, unicode.IsLetter) < 0 || strings.IndexFunc(password, unicode.IsDigit) < 0 {
+			return httperrors.PasswordTooWeak
+		}
+
+		return nil
+	}
+)
+
+// ValidateLoginRequest validates POST /v1/auth/login endpoint.
+func ValidateLoginRequest(request *request.Login) error {
+
+	var details httperrors.Details
+	if strings.TrimSpace(request.Email) == "" {
+		details.Add("/email", httperrors.AuthEmailEmpty)
+	}
+
+	if request.Password == "" {
+		details.Add("/password", httperrors.AuthPasswordEmpty)
+	}
+
+	return details.Err()
+}
+
+// ValidateRequestPasswordResetRequest validates POST /v1/auth/password-reset endpoint.
+func ValidateRequestPasswordResetRequest(request *request.RequestPasswordReset) error {
+
+	var details httperrors.Details
+	if strings.TrimSpace(request.Email) == "" {
+		details.Add("/email", httperrors.AuthEmailEmpty)
+	}
+
+	return details.Err()
+}
+
+// ValidateConfirmPasswordResetRequest validates POST /v1/auth/password-reset/confirm endpoint.
+func ValidateConfirmPasswordResetRequest(request *request.ConfirmPasswordReset) error {
+
+	var details httperrors.Details
+	if request.Token == "" {
+		details.Add("/token", httperrors.PasswordResetTokenEmpty)
+	}
+
+	if err := validatePassword(request.NewPassword); err != nil {
+		details.Add("/new_password", err)
+	}
+
+	return details.Err()
+}
+
+// ValidateChangePasswordRequest validates POST /v1/users/:user_id/password endpoint.
+// Current password is checked by the service.
+func ValidateChangePasswordRequest(request *request.ChangePassword) error {
+
+	var details httperrors.Details
+	if err := validatePassword(request.NewPassword); err != nil {
+		details.Add("/new_password", err)
+	} else if request.NewPassword == request.CurrentPassword {
+		details.Add("/new_password", httperrors.PasswordNotChanged)
+	}
+
+	return details.Err()
+}
@@ -0,0 +1,67 @@
+// +build unit
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+func TestValidateLoginRequest(t *testing.T) {
+	assert.Nil(t, ValidateLoginRequest(&request.Login{Email: "john@example.com", Password: "secret"}))
+
+	var expected httperrors.Details
+	expected.Add("/email", httperrors.AuthEmailEmpty)
+	expected.Add("/password", httperrors.AuthPasswordEmpty)
+	assert.Equal(t, expected.Err(), ValidateLoginRequest(&request.Login{Email: " "}))
+}
+
+func TestValidatePasswordResetRequests(t *testing.T) {
+	assert.Nil(t, ValidateRequestPasswordResetRequest(&request.RequestPasswordReset{Email: "john@example.com"}))
+	assert.Equal(t, validationFailed("/email", httperrors.AuthEmailEmpty),
+		ValidateRequestPasswordResetRequest(&request.RequestPasswordReset{}))
+
+	assert.Nil(t, ValidateConfirmPasswordResetRequest(&request.ConfirmPasswordReset{
+		Token:       "token",
+		NewPassword: "correct horse 1",
+	}))
+
+	var expected httperrors.Details
+	expected.Add("/token", httperrors.PasswordResetTokenEmpty)
+	expected.Add("/new_password", httperrors.PasswordTooWeak)
+	assert.Equal(t, expected.Err(), ValidateConfirmPasswordResetRequest(&request.ConfirmPasswordReset{
+		NewPassword: "correct horse",
+	}))
+}
+
+func TestValidateChangePasswordRequest(t *testing.T) {
+	var testData = []struct {
+		name     string
+		request  request.ChangePassword
+		expected error
+	}{
+		{"OK", request.ChangePassword{NewPassword: "correct horse 1"}, nil},
+		{"Unicode", request.ChangePassword{CurrentPassword: "old", NewPassword: "zażółć gęślą 1"}, nil},
+		{"TooShort", request.ChangePassword{NewPassword: "horse 1"},
+			validationFailed("/new_password", httperrors.PasswordTooShort(passwordMinLength))},
+		{"TooLong", request.ChangePassword{NewPassword: strings.Repeat("a1", passwordMaxLength/2+1)},
+			validationFailed("/new_password", httperrors.PasswordTooLong(passwordMaxLength))},
+		{"NoDigit", request.ChangePassword{NewPassword: "correct horse"},
+			validationFailed("/new_password", httperrors.PasswordTooWeak)},
+		{"NoLetter", request.ChangePassword{NewPassword: "1234567890"},
+			validationFailed("/new_password", httperrors.PasswordTooWeak)},
+		{"NotChanged", request.ChangePassword{CurrentPassword: "correct horse 1", NewPassword: "correct horse 1"},
+			validationFailed("/new_password", httperrors.PasswordNotChanged)},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ValidateChangePasswordRequest(&tt.request))
+		})
+	}
+}
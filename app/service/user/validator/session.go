@@ -0,0 +1,18 @@
+package validator
+
+import (
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+// ValidateRefreshTokenRequest validates POST /v1/auth/refresh endpoint.
+// Token itself is checked by the service.
+func ValidateRefreshTokenRequest(request *request.RefreshToken) error {
+
+	var details httperrors.Details
+	if request.RefreshToken == "" {
+		details.Add("/refresh_token", httperrors.RefreshTokenEmpty)
+	}
+
+	return details.Err()
+}
@@ -0,0 +1,18 @@
+// +build unit
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+func TestValidateRefreshTokenRequest(t *testing.T) {
+	assert.Nil(t, ValidateRefreshTokenRequest(&request.RefreshToken{RefreshToken: "token"}))
+	assert.Equal(t, validationFailed("/refresh_token", httperrors.RefreshTokenEmpty),
+		ValidateRefreshTokenRequest(&request.RefreshToken{}))
+}
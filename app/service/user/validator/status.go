@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+// userStatusReasonMaxLength limits length of reason recorded in status history.
+const userStatusReasonMaxLength = 500
+
+var supportedUserStatuses = map[string]struct{}{
+	model.UserStatusPending:   {},
+	model.UserStatusActive:    {},
+	model.UserStatusSuspended: {},
+	model.UserStatusLocked:    {},
+	model.UserStatusClosed:    {},
+}
+
+// User status validators
+var (
+	// validateUserStatusReason validates `reason` request parameter.
+	validateUserStatusReason = func(reason string) *httperrors.HTTPError {
+		if strings.TrimSpace(reason) == "" {
+			return httperrors.UserStatusReasonEmpty
+		}
+
+		if utf8.RuneCountInString(reason) > userStatusReasonMaxLength {
+			return httperrors.UserStatusReasonTooLong(userStatusReasonMaxLength)
+		}
+
+		return nil
+	}
+
+	// validateUserStatus validates `status` query parameter, empty status doesn't filter users.
+	validateUserStatus = func(status string) *httperrors.HTTPError {
+		if _, ok := supportedUserStatuses[status]; status != "" && !ok {
+			return httperrors.UserStatusNotSupported(status)
+		}
+
+		return nil
+	}
+)
+
+// ValidateChangeUserStatusRequest validates POST /v1/users/:user_id/activate, suspend, lock and close endpoints.
+func ValidateChangeUserStatusRequest(request *request.ChangeUserStatus) error {
+
+	var details httperrors.Details
+	details.Add("/reason", validateUserStatusReason(request.Reason))
+
+	return details.Err()
+}
@@ -0,0 +1,29 @@
+// +build unit
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+func TestValidateChangeUserStatusRequest(t *testing.T) {
+	assert.Nil(t, ValidateChangeUserStatusRequest(&request.ChangeUserStatus{Reason: "chargeback"}))
+	assert.Equal(t, validationFailed("/reason", httperrors.UserStatusReasonEmpty),
+		ValidateChangeUserStatusRequest(&request.ChangeUserStatus{Reason: " "}))
+	assert.Equal(t, validationFailed("/reason", httperrors.UserStatusReasonTooLong(userStatusReasonMaxLength)),
+		ValidateChangeUserStatusRequest(&request.ChangeUserStatus{
+			Reason: strings.Repeat("ą", userStatusReasonMaxLength+1),
+		}))
+}
+
+func TestValidateFindUsersRequestStatus(t *testing.T) {
+	assert.Nil(t, ValidateFindUsersRequest(&request.FindUsers{Status: "suspended"}, nil))
+	assert.Equal(t, validationFailed("/status", httperrors.UserStatusNotSupported("deleted")),
+		ValidateFindUsersRequest(&request.FindUsers{Status: "deleted"}, nil))
+}
@@ -1,14 +1,32 @@
 package validator
 
 import (
+	"net/mail"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/mmgopher/user-service/app/api/request"
 	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
 )
 
-var sortRegex = regexp.MustCompile("^[a-zA-Z_]*:(asc|desc)$")
+var sortRegex = regexp.MustCompile(`^([a-zA-Z_]*|attr\.[a-z][a-z0-9_]*):(asc|desc)$`)
+
+// e164Regex matches phone number in E.164 format, i.e. + followed by country code and subscriber number.
+var e164Regex = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// phoneSeparators are removed from phone number before it is normalised.
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// emailMaxLength is the longest email address which can be used in SMTP.
+const emailMaxLength = 254
+
+// userMaxAge limits how long ago the user could be born.
+const userMaxAge = 120
+
+// changeFeedMaxWait limits long polling of GET /v1/users/changes endpoint.
+const changeFeedMaxWait = 60 * time.Second
 
 var supportedGenderList = map[string]struct{}{
 	"male":           {},
@@ -22,7 +40,7 @@ var supportedGenderList = map[string]struct{}{
 // User validators
 var (
 	// validateName validates `name` request parameter.
-	validateName = func(name string) error {
+	validateName = func(name string) *httperrors.HTTPError {
 		if name == "" {
 			return httperrors.UserNameEmpty
 		}
@@ -31,7 +49,7 @@ var (
 	}
 
 	// validateSurname validates `surname` request parameter.
-	validateSurname = func(surname string) error {
+	validateSurname = func(surname string) *httperrors.HTTPError {
 		if surname == "" {
 			return httperrors.UserSurnameEmpty
 		}
@@ -39,9 +57,24 @@ var (
 		return nil
 	}
 
-	// validateAge validates `age` request parameter.
-	validateAge = func(age int) error {
-		if age < 1 || age > 120 {
+	// validateDateOfBirth validates `date_of_birth` request parameter.
+	validateDateOfBirth = func(dateOfBirth string) *httperrors.HTTPError {
+		date := ParseDate(dateOfBirth)
+		if date.IsZero() {
+			return httperrors.UserDateOfBirthIncorrect
+		}
+
+		today := model.Date(time.Now())
+		if date.After(today) || date.Before(today.AddDate(-userMaxAge, 0, 0)) {
+			return httperrors.UserDateOfBirthOutOfRange
+		}
+
+		return nil
+	}
+
+	// validateAge validates deprecated `age` request parameter.
+	validateAge = func(age int) *httperrors.HTTPError {
+		if age < 1 || age > userMaxAge {
 			return httperrors.UserAgeIncorrect
 		}
 
@@ -49,7 +82,7 @@ var (
 	}
 
 	// validateGender validates `gender` request parameter.
-	validateGender = func(gender string) error {
+	validateGender = func(gender string) *httperrors.HTTPError {
 		if gender == "" {
 			return httperrors.UserGenderEmpty
 		}
@@ -61,85 +94,186 @@ var (
 		return nil
 	}
 
-	validateAddress = func(address string) error {
+	// validateAddress validates `address` request parameter.
+	validateAddress = func(address string) *httperrors.HTTPError {
 		if address == "" {
 			return httperrors.UserAddressEmpty
 		}
 
 		return nil
 	}
-)
 
-// ValidateCreateUserRequest validates POST /v1/users endpoint.
-func ValidateCreateUserRequest(request *request.CreateUser) error {
+	// validateEmail validates optional `email` request parameter.
+	// Only bare address is accepted, e.g. john@example.com but not John <john@example.com>.
+	validateEmail = func(email string) *httperrors.HTTPError {
+		if email == "" {
+			return nil
+		}
+
+		address, err := mail.ParseAddress(email)
+		if err != nil || address.Address != email || len(email) > emailMaxLength {
+			return httperrors.UserEmailIncorrect
+		}
 
-	if err := validateName(request.Name); err != nil {
-		return err
+		return nil
 	}
 
-	if err := validateSurname(request.Surname); err != nil {
-		return err
+	// validatePhone validates optional `phone` request parameter.
+	validatePhone = func(phone string) *httperrors.HTTPError {
+		if phone != "" && NormalizePhone(phone) == "" {
+			return httperrors.UserPhoneIncorrect
+		}
+
+		return nil
 	}
 
-	if err := validateAge(request.Age); err != nil {
-		return err
+	// validateEmailVerificationToken validates `token` request parameter.
+	validateEmailVerificationToken = func(token string) *httperrors.HTTPError {
+		if token == "" {
+			return httperrors.EmailVerificationTokenEmpty
+		}
+
+		return nil
 	}
+)
 
-	if err := validateGender(request.Gender); err != nil {
-		return err
+// NormalizePhone returns phone number in E.164 format, e.g. +48123456789.
+// Spaces, dashes, dots and parentheses are removed and international prefix 00 is replaced with +.
+// Empty string is returned when the number has no country code or has wrong number of digits.
+func NormalizePhone(phone string) string {
+	normalized := phoneSeparators.Replace(strings.TrimSpace(phone))
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + normalized[2:]
 	}
 
-	if err := validateAddress(request.Address); err != nil {
-		return err
+	if !e164Regex.MatchString(normalized) {
+		return ""
 	}
 
-	return nil
+	return normalized
 }
 
-// ValidateUpdateUserRequest validates PUT /v1/users/:user_id endpoint.
-func ValidateUpdateUserRequest(request *request.UpdateUser) error {
-
-	if err := validateName(request.Name); err != nil {
-		return err
+// ParseDate returns date in format YYYY-MM-DD as midnight UTC.
+// Zero time is returned when the date is invalid.
+func ParseDate(date string) time.Time {
+	parsed, err := time.Parse(model.DateLayout, date)
+	if err != nil {
+		return time.Time{}
 	}
 
-	if err := validateSurname(request.Surname); err != nil {
-		return err
-	}
+	return parsed
+}
 
-	if err := validateAge(request.Age); err != nil {
-		return err
+// DateOfBirth returns date of `date_of_birth` request parameter. When it is empty, date of birth is approximated
+// from deprecated `age` in the same way as migration 20201019160000_add_user_date_of_birth, i.e. age years ago.
+func DateOfBirth(dateOfBirth string, age int) time.Time {
+	if dateOfBirth == "" && age != 0 {
+		return model.Date(time.Now()).AddDate(-age, 0, 0)
 	}
 
-	if err := validateGender(request.Gender); err != nil {
-		return err
-	}
+	return ParseDate(dateOfBirth)
+}
 
-	if err := validateAddress(request.Address); err != nil {
-		return err
+// validateDateOfBirthOrAge validates `date_of_birth` request parameter or deprecated `age` sent instead of it.
+func validateDateOfBirthOrAge(details *httperrors.Details, dateOfBirth string, age int) {
+	if dateOfBirth == "" && age != 0 {
+		details.Add("/age", validateAge(age))
+		return
 	}
 
-	return nil
+	details.Add("/date_of_birth", validateDateOfBirth(dateOfBirth))
+}
+
+// ValidateCreateUserRequest validates POST /v1/users endpoint.
+// All invalid fields are reported at once as details of RequestValidationFailed.
+// Custom attributes are validated against the definitions.
+func ValidateCreateUserRequest(request *request.CreateUser, definitions []model.AttributeDefinition) error {
+
+	var details httperrors.Details
+	details.Add("/name", validateName(request.Name))
+	details.Add("/surname", validateSurname(request.Surname))
+	validateDateOfBirthOrAge(&details, request.DateOfBirth, request.Age)
+	details.Add("/gender", validateGender(request.Gender))
+	details.Add("/address", validateAddress(request.Address))
+	details.Add("/email", validateEmail(request.Email))
+	details.Add("/phone", validatePhone(request.Phone))
+	validateAttributes(&details, definitions, request.Attributes)
+
+	return details.Err()
+}
+
+// ValidateUpdateUserRequest validates PUT /v1/users/:user_id endpoint.
+// All invalid fields are reported at once as details of RequestValidationFailed.
+// Custom attributes are validated against the definitions.
+func ValidateUpdateUserRequest(request *request.UpdateUser, definitions []model.AttributeDefinition) error {
+
+	var details httperrors.Details
+	details.Add("/name", validateName(request.Name))
+	details.Add("/surname", validateSurname(request.Surname))
+	validateDateOfBirthOrAge(&details, request.DateOfBirth, request.Age)
+	details.Add("/gender", validateGender(request.Gender))
+	details.Add("/address", validateAddress(request.Address))
+	details.Add("/email", validateEmail(request.Email))
+	details.Add("/phone", validatePhone(request.Phone))
+	validateAttributes(&details, definitions, request.Attributes)
+
+	return details.Err()
+}
+
+// ValidateConfirmEmailVerificationRequest validates POST /v1/users/:user_id/email/verify/confirm endpoint.
+func ValidateConfirmEmailVerificationRequest(request *request.ConfirmEmailVerification) error {
+
+	var details httperrors.Details
+	details.Add("/token", validateEmailVerificationToken(request.Token))
+
+	return details.Err()
 }
 
 // ValidateFindUsersRequest validates GET /v1/users endpoint.
-func ValidateFindUsersRequest(request *request.FindUsers) error {
+// All invalid parameters are reported at once as details of RequestValidationFailed.
+// Filters and sorting by custom attributes are validated against the definitions.
+func ValidateFindUsersRequest(request *request.FindUsers, definitions []model.AttributeDefinition) error {
+
+	var details httperrors.Details
+	if request.AfterID < 0 {
+		details.Add("/after_id", httperrors.PaginationAfterIDNegative)
+	}
+
+	if request.BeforeID < 0 {
+		details.Add("/before_id", httperrors.PaginationBeforeIDNegative)
+	}
 
 	if request.AfterID > 0 && request.BeforeID > 0 {
-		return httperrors.PaginationAfterIDAndBeforeIDDeclared
-	} else if request.AfterID < 0 {
-		return httperrors.PaginationAfterIDNegative
-	} else if request.BeforeID < 0 {
-		return httperrors.PaginationBeforeIDNegative
+		details.Add("/before_id", httperrors.PaginationAfterIDAndBeforeIDDeclared)
 	}
 
 	if request.Limit < 0 {
-		return httperrors.PaginationLimitNegative
+		details.Add("/limit", httperrors.PaginationLimitNegative)
 	}
 
 	if len(request.Sort) > 0 && !sortRegex.MatchString(request.Sort) {
-		return httperrors.PaginationSortIncorrectFormat
+		details.Add("/sort", httperrors.PaginationSortIncorrectFormat)
+	}
+
+	details.Add("/status", validateUserStatus(request.Status))
+	validateGroupFilters(&details, request)
+	validateAttributeFilters(&details, definitions, request)
+
+	return details.Err()
+}
+
+// ValidateFindUserChangesRequest validates GET /v1/users/changes endpoint.
+// Token is validated when it is decoded.
+func ValidateFindUserChangesRequest(request *request.FindUserChanges) error {
+
+	var details httperrors.Details
+	if request.Limit < 0 {
+		details.Add("/limit", httperrors.ChangeFeedLimitNegative)
+	}
+
+	if request.Wait < 0 || request.Wait > changeFeedMaxWait {
+		details.Add("/wait", httperrors.ChangeFeedWaitIncorrect)
 	}
 
-	return nil
+	return details.Err()
 }
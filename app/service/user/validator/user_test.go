@@ -4,25 +4,54 @@ package validator
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mmgopher/user-service/app/api/request"
 	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
 )
 
+func validationFailed(field string, err *httperrors.HTTPError) error {
+	var details httperrors.Details
+	details.Add(field, err)
+	return details.Err()
+}
+
 func TestValidateCreateUserRequestOK(t *testing.T) {
 
+	err := ValidateCreateUserRequest(&request.CreateUser{
+		Name:        "name",
+		Gender:      "male",
+		Surname:     "surname",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+	}, nil)
+	assert.Nil(t, err)
+}
+
+func TestValidateCreateUserRequestWithDeprecatedAge(t *testing.T) {
+
 	err := ValidateCreateUserRequest(&request.CreateUser{
 		Name:    "name",
 		Gender:  "male",
 		Surname: "surname",
-		Age:     10,
+		Age:     30,
 		Address: "address",
-	})
+	}, nil)
 	assert.Nil(t, err)
 }
+
+func TestDateOfBirth(t *testing.T) {
+	today := model.Date(time.Now())
+
+	assert.Equal(t, time.Date(1990, 5, 14, 0, 0, 0, 0, time.UTC), DateOfBirth("1990-05-14", 30))
+	assert.Equal(t, today.AddDate(-30, 0, 0), DateOfBirth("", 30))
+	assert.True(t, DateOfBirth("", 0).IsZero())
+}
+
 func TestValidateCreateUserRequestError(t *testing.T) {
 
 	var testData = []struct {
@@ -33,86 +62,165 @@ func TestValidateCreateUserRequestError(t *testing.T) {
 		{
 			"EmptyName",
 			&request.CreateUser{
-				Surname: "surname",
-				Gender:  "male",
-				Age:     30,
-				Address: "address",
+				Surname:     "surname",
+				Gender:      "male",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
 			},
-			httperrors.UserNameEmpty,
+			validationFailed("/name", httperrors.UserNameEmpty),
 		},
 		{
 			"EmptySurname",
 			&request.CreateUser{
-				Name:    "name",
-				Gender:  "male",
-				Age:     30,
-				Address: "address",
+				Name:        "name",
+				Gender:      "male",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
 			},
-			httperrors.UserSurnameEmpty,
+			validationFailed("/surname", httperrors.UserSurnameEmpty),
 		},
 		{
 			"EmptyGender",
 			&request.CreateUser{
-				Name:    "name",
-				Surname: "surname",
-				Gender:  "",
-				Age:     30,
-				Address: "address",
+				Name:        "name",
+				Surname:     "surname",
+				Gender:      "",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
 			},
-			httperrors.UserGenderEmpty,
+			validationFailed("/gender", httperrors.UserGenderEmpty),
 		},
 		{
 			"NotSupportedGender",
 			&request.CreateUser{
-				Name:    "name",
-				Surname: "surname",
-				Gender:  "gender",
-				Age:     30,
-				Address: "address",
+				Name:        "name",
+				Surname:     "surname",
+				Gender:      "gender",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
 			},
-			httperrors.UserGenderNotSupported("gender"),
+			validationFailed("/gender", httperrors.UserGenderNotSupported("gender")),
 		},
 		{
-			"IncorrectAge",
+			"IncorrectDateOfBirth",
+			&request.CreateUser{
+				Name:        "name",
+				Gender:      "male",
+				Surname:     "surname",
+				DateOfBirth: "14.05.1990",
+				Address:     "address",
+			},
+			validationFailed("/date_of_birth", httperrors.UserDateOfBirthIncorrect),
+		},
+		{
+			"DateOfBirthInFuture",
+			&request.CreateUser{
+				Name:        "name",
+				Gender:      "male",
+				Surname:     "surname",
+				DateOfBirth: time.Now().AddDate(0, 0, 2).Format(model.DateLayout),
+				Address:     "address",
+			},
+			validationFailed("/date_of_birth", httperrors.UserDateOfBirthOutOfRange),
+		},
+		{
+			"DeprecatedAgeOutOfRange",
 			&request.CreateUser{
 				Name:    "name",
 				Gender:  "male",
 				Surname: "surname",
-				Age:     0,
+				Age:     121,
 				Address: "address",
 			},
-			httperrors.UserAgeIncorrect,
+			validationFailed("/age", httperrors.UserAgeIncorrect),
 		},
 		{
 			"EmptyAddress",
 			&request.CreateUser{
-				Name:    "name",
-				Gender:  "male",
-				Surname: "surname",
-				Age:     10,
+				Name:        "name",
+				Gender:      "male",
+				Surname:     "surname",
+				DateOfBirth: "1990-05-14",
+			},
+			validationFailed("/address", httperrors.UserAddressEmpty),
+		},
+		{
+			"IncorrectEmail",
+			&request.CreateUser{
+				Name:        "name",
+				Gender:      "male",
+				Surname:     "surname",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
+				Email:       "John <john@example.com>",
+			},
+			validationFailed("/email", httperrors.UserEmailIncorrect),
+		},
+		{
+			"IncorrectPhone",
+			&request.CreateUser{
+				Name:        "name",
+				Gender:      "male",
+				Surname:     "surname",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
+				Phone:       "123 456 789",
 			},
-			httperrors.UserAddressEmpty,
+			validationFailed("/phone", httperrors.UserPhoneIncorrect),
 		},
 	}
 
 	for _, tt := range testData {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateCreateUserRequest(tt.request)
+			err := ValidateCreateUserRequest(tt.request, nil)
 			require.NotNil(t, err)
 			assert.EqualError(t, tt.expectedError, err.Error())
 		})
 	}
 }
 
+func TestNormalizePhone(t *testing.T) {
+
+	var testData = []struct {
+		phone    string
+		expected string
+	}{
+		{"+48123456789", "+48123456789"},
+		{"+48 123 456 789", "+48123456789"},
+		{"0048 (12) 345-67-89", "+48123456789"},
+		{"+1.202.555.0143", "+12025550143"},
+		{"123456789", ""},
+		{"+0123456789", ""},
+		{"+12345", ""},
+		{"+1234567890123456", ""},
+		{"+48 123 ABC 789", ""},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.phone, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizePhone(tt.phone))
+		})
+	}
+}
+
+func TestValidateConfirmEmailVerificationRequest(t *testing.T) {
+
+	assert.Nil(t, ValidateConfirmEmailVerificationRequest(&request.ConfirmEmailVerification{Token: "token"}))
+	assert.EqualError(t, validationFailed("/token", httperrors.EmailVerificationTokenEmpty),
+		ValidateConfirmEmailVerificationRequest(&request.ConfirmEmailVerification{}).Error())
+}
+
 func TestValidateUpdateUserRequestOK(t *testing.T) {
 
 	err := ValidateUpdateUserRequest(&request.UpdateUser{
-		Name:    "name",
-		Gender:  "male",
-		Surname: "surname",
-		Age:     10,
-		Address: "address",
-	})
+		Name:        "name",
+		Gender:      "male",
+		Surname:     "surname",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Email:       "John.Smith+news@example.com",
+		Phone:       "+48 123-456-789",
+	}, nil)
 	assert.Nil(t, err)
 }
 
@@ -126,71 +234,95 @@ func TestValidateUpdateUserRequestError(t *testing.T) {
 		{
 			"EmptyName",
 			&request.UpdateUser{
-				Surname: "surname",
-				Gender:  "male",
-				Age:     30,
-				Address: "address",
+				Surname:     "surname",
+				Gender:      "male",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
 			},
-			httperrors.UserNameEmpty,
+			validationFailed("/name", httperrors.UserNameEmpty),
 		},
 		{
 			"EmptySurname",
 			&request.UpdateUser{
-				Name:    "name",
-				Gender:  "male",
-				Age:     30,
-				Address: "address",
+				Name:        "name",
+				Gender:      "male",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
 			},
-			httperrors.UserSurnameEmpty,
+			validationFailed("/surname", httperrors.UserSurnameEmpty),
 		},
 		{
 			"EmptyGender",
 			&request.UpdateUser{
-				Name:    "name",
-				Surname: "surname",
-				Gender:  "",
-				Age:     30,
-				Address: "address",
+				Name:        "name",
+				Surname:     "surname",
+				Gender:      "",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
 			},
-			httperrors.UserGenderEmpty,
+			validationFailed("/gender", httperrors.UserGenderEmpty),
 		},
 		{
 			"NotSupportedGender",
 			&request.UpdateUser{
-				Name:    "name",
-				Surname: "surname",
-				Gender:  "gender",
-				Age:     30,
-				Address: "address",
+				Name:        "name",
+				Surname:     "surname",
+				Gender:      "gender",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
 			},
-			httperrors.UserGenderNotSupported("gender"),
+			validationFailed("/gender", httperrors.UserGenderNotSupported("gender")),
 		},
 		{
-			"IncorrectAge",
+			"DateOfBirthTooLongAgo",
 			&request.UpdateUser{
-				Name:    "name",
-				Gender:  "male",
-				Surname: "surname",
-				Age:     0,
-				Address: "address",
+				Name:        "name",
+				Gender:      "male",
+				Surname:     "surname",
+				DateOfBirth: time.Now().AddDate(-121, 0, 0).Format(model.DateLayout),
+				Address:     "address",
 			},
-			httperrors.UserAgeIncorrect,
+			validationFailed("/date_of_birth", httperrors.UserDateOfBirthOutOfRange),
 		},
 		{
 			"EmptyAddress",
 			&request.UpdateUser{
-				Name:    "name",
-				Gender:  "male",
-				Surname: "surname",
-				Age:     10,
+				Name:        "name",
+				Gender:      "male",
+				Surname:     "surname",
+				DateOfBirth: "1990-05-14",
+			},
+			validationFailed("/address", httperrors.UserAddressEmpty),
+		},
+		{
+			"IncorrectEmail",
+			&request.UpdateUser{
+				Name:        "name",
+				Gender:      "male",
+				Surname:     "surname",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
+				Email:       "John <john@example.com>",
+			},
+			validationFailed("/email", httperrors.UserEmailIncorrect),
+		},
+		{
+			"IncorrectPhone",
+			&request.UpdateUser{
+				Name:        "name",
+				Gender:      "male",
+				Surname:     "surname",
+				DateOfBirth: "1990-05-14",
+				Address:     "address",
+				Phone:       "123 456 789",
 			},
-			httperrors.UserAddressEmpty,
+			validationFailed("/phone", httperrors.UserPhoneIncorrect),
 		},
 	}
 
 	for _, tt := range testData {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateUpdateUserRequest(tt.request)
+			err := ValidateUpdateUserRequest(tt.request, nil)
 			require.NotNil(t, err)
 			assert.EqualError(t, tt.expectedError, err.Error())
 		})
@@ -210,7 +342,7 @@ func TestValidateFindeUserRequestError(t *testing.T) {
 				AfterID:  4,
 				BeforeID: 5,
 			},
-			httperrors.PaginationAfterIDAndBeforeIDDeclared,
+			validationFailed("/before_id", httperrors.PaginationAfterIDAndBeforeIDDeclared),
 		},
 		{
 			"AfterIDNegative",
@@ -218,7 +350,7 @@ func TestValidateFindeUserRequestError(t *testing.T) {
 				AfterID:  -1,
 				BeforeID: 0,
 			},
-			httperrors.PaginationAfterIDNegative,
+			validationFailed("/after_id", httperrors.PaginationAfterIDNegative),
 		},
 		{
 			"BeforeIDNegative",
@@ -226,7 +358,7 @@ func TestValidateFindeUserRequestError(t *testing.T) {
 				AfterID:  0,
 				BeforeID: -5,
 			},
-			httperrors.PaginationBeforeIDNegative,
+			validationFailed("/before_id", httperrors.PaginationBeforeIDNegative),
 		},
 		{
 			"LimitNegative",
@@ -234,7 +366,7 @@ func TestValidateFindeUserRequestError(t *testing.T) {
 				AfterID: 0,
 				Limit:   -1,
 			},
-			httperrors.PaginationLimitNegative,
+			validationFailed("/limit", httperrors.PaginationLimitNegative),
 		},
 		{
 			"SortColumnMissingOrder",
@@ -243,7 +375,7 @@ func TestValidateFindeUserRequestError(t *testing.T) {
 				Limit:   10,
 				Sort:    "id",
 			},
-			httperrors.PaginationSortIncorrectFormat,
+			validationFailed("/sort", httperrors.PaginationSortIncorrectFormat),
 		},
 		{
 			"SortColumnWrongOrder",
@@ -252,15 +384,45 @@ func TestValidateFindeUserRequestError(t *testing.T) {
 				Limit:   10,
 				Sort:    "id:down",
 			},
-			httperrors.PaginationSortIncorrectFormat,
+			validationFailed("/sort", httperrors.PaginationSortIncorrectFormat),
 		},
 	}
 
 	for _, tt := range testData {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateFindUsersRequest(tt.request)
+			err := ValidateFindUsersRequest(tt.request, nil)
 			require.NotNil(t, err)
 			assert.EqualError(t, tt.expectedError, err.Error())
 		})
 	}
 }
+
+func TestValidateCreateUserRequestReportsAllFields(t *testing.T) {
+
+	err := ValidateCreateUserRequest(&request.CreateUser{
+		Gender:      "gender",
+		DateOfBirth: "1890-01-01",
+	}, nil)
+
+	var expected httperrors.Details
+	expected.Add("/name", httperrors.UserNameEmpty)
+	expected.Add("/surname", httperrors.UserSurnameEmpty)
+	expected.Add("/date_of_birth", httperrors.UserDateOfBirthOutOfRange)
+	expected.Add("/gender", httperrors.UserGenderNotSupported("gender"))
+	expected.Add("/address", httperrors.UserAddressEmpty)
+	require.NotNil(t, err)
+	assert.Equal(t, expected.Err(), err)
+}
+
+func TestValidateFindUserChangesRequestError(t *testing.T) {
+
+	err := ValidateFindUserChangesRequest(&request.FindUserChanges{
+		Limit: -1,
+		Wait:  2 * changeFeedMaxWait,
+	})
+
+	var expected httperrors.Details
+	expected.Add("/limit", httperrors.ChangeFeedLimitNegative)
+	expected.Add("/wait", httperrors.ChangeFeedWaitIncorrect)
+	assert.Equal(t, expected.Err(), err)
+}
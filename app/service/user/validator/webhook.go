@@ -0,0 +1,92 @@
+package validator
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+)
+
+const webhookSecretMinLength = 16
+
+var supportedWebhookEventTypes = map[string]struct{}{
+	"user.*":       {},
+	"user.created": {},
+	"user.updated": {},
+	"user.deleted": {},
+}
+
+// Webhook validators
+var (
+	// validateWebhookURL validates `url` request parameter.
+	validateWebhookURL = func(rawURL string) *httperrors.HTTPError {
+		u, err := url.Parse(rawURL)
+		if err != nil || !u.IsAbs() || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return httperrors.WebhookURLIncorrect
+		}
+
+		return nil
+	}
+
+	// validateWebhookEventType validates item of `event_types` request parameter.
+	validateWebhookEventType = func(eventType string) *httperrors.HTTPError {
+		if _, ok := supportedWebhookEventTypes[eventType]; !ok {
+			return httperrors.WebhookEventTypeNotSupported(eventType)
+		}
+
+		return nil
+	}
+
+	// validateWebhookSecret validates `secret` request parameter.
+	validateWebhookSecret = func(secret string) *httperrors.HTTPError {
+		if secret != "" && len(secret) < webhookSecretMinLength {
+			return httperrors.WebhookSecretTooShort
+		}
+
+		return nil
+	}
+)
+
+// ValidateCreateWebhookRequest validates POST /v1/webhooks endpoint.
+// All invalid fields are reported at once as details of RequestValidationFailed.
+func ValidateCreateWebhookRequest(request *request.CreateWebhook) error {
+
+	var details httperrors.Details
+	details.Add("/url", validateWebhookURL(request.URL))
+	for i, eventType := range request.EventTypes {
+		details.Add("/event_types/"+strconv.Itoa(i), validateWebhookEventType(eventType))
+	}
+	details.Add("/secret", validateWebhookSecret(request.Secret))
+
+	return details.Err()
+}
+
+// ValidateUpdateWebhookRequest validates PUT /v1/webhooks/:webhook_id endpoint.
+// All invalid fields are reported at once as details of RequestValidationFailed.
+func ValidateUpdateWebhookRequest(request *request.UpdateWebhook) error {
+
+	var details httperrors.Details
+	details.Add("/url", validateWebhookURL(request.URL))
+	for i, eventType := range request.EventTypes {
+		details.Add("/event_types/"+strconv.Itoa(i), validateWebhookEventType(eventType))
+	}
+	details.Add("/secret", validateWebhookSecret(request.Secret))
+
+	return details.Err()
+}
+
+// ValidateFindWebhookDeliveriesRequest validates GET /v1/webhooks/:webhook_id/deliveries endpoint.
+func ValidateFindWebhookDeliveriesRequest(request *request.FindWebhookDeliveries) error {
+
+	var details httperrors.Details
+	if request.Limit < 0 {
+		details.Add("/limit", httperrors.WebhookDeliveriesLimitNegative)
+	}
+
+	if request.BeforeID < 0 {
+		details.Add("/before_id", httperrors.WebhookDeliveriesBeforeIDNegative)
+	}
+
+	return details.Err()
+}
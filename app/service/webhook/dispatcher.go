@@ -0,0 +1,214 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/outbox"
+)
+
+// maxResponseBodySize limits webhook response body read to keep connection reusable.
+const maxResponseBodySize = 4096
+
+// Dispatcher delivers events to subscribed webhooks.
+// It is an outbox sink which stores delivery of the event for every subscribed webhook,
+// deliveries are sent by Run loop and retried with exponential backoff.
+// Delivery is moved to dead state after max attempts.
+type Dispatcher struct {
+	webhookRepository  dao.WebhookRepositoryProvider
+	deliveryRepository dao.WebhookDeliveryRepositoryProvider
+	httpClient         *http.Client
+	batchSize          int
+	maxAttempts        int
+	pollInterval       time.Duration
+	retryBackoff       time.Duration
+	maxBackoff         time.Duration
+	now                func() time.Time
+}
+
+// NewDispatcher creates new instance of Dispatcher.
+func NewDispatcher(
+	webhookRepository dao.WebhookRepositoryProvider,
+	deliveryRepository dao.WebhookDeliveryRepositoryProvider,
+	httpClient *http.Client,
+	batchSize int,
+	maxAttempts int,
+	pollInterval time.Duration,
+	retryBackoff time.Duration,
+	maxBackoff time.Duration,
+) *Dispatcher {
+	return &Dispatcher{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+		httpClient:         httpClient,
+		batchSize:          batchSize,
+		maxAttempts:        maxAttempts,
+		pollInterval:       pollInterval,
+		retryBackoff:       retryBackoff,
+		maxBackoff:         maxBackoff,
+		now:                time.Now,
+	}
+}
+
+// Publish stores delivery of the message for every active webhook subscribed to message type.
+func (d *Dispatcher) Publish(message *outbox.Message) error {
+	webhooks, err := d.webhookRepository.FindActive()
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	deliveries := make([]model.WebhookDelivery, 0, len(webhooks))
+	for i := range webhooks {
+		if !isSubscribed(&webhooks[i], message.Type) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = json.Marshal(message); err != nil {
+				return errors.Wrapf(err, "impossible to marshal message, messageID=%d", message.ID)
+			}
+		}
+
+		deliveries = append(deliveries, model.WebhookDelivery{
+			WebhookID: webhooks[i].ID,
+			EventID:   message.ID,
+			EventType: message.Type,
+			Payload:   payload,
+		})
+	}
+
+	return d.deliveryRepository.CreateAll(deliveries)
+}
+
+// Run sends pending deliveries every poll interval until context is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			sent, err := d.DeliverPending()
+			if err != nil {
+				log.Errorf("%+v", err)
+				break
+			}
+			if sent == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverPending sends one batch of pending deliveries and returns number of processed deliveries.
+func (d *Dispatcher) DeliverPending() (int, error) {
+	now := d.now()
+	deliveries, err := d.deliveryRepository.FindPending(now, d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks := map[int]*model.Webhook{}
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			if webhook, err = d.webhookRepository.GetByID(delivery.WebhookID); err != nil {
+				return i, err
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+
+		if webhook == nil || !webhook.Active {
+			if err := d.deliveryRepository.MarkFailed(
+				delivery.ID, model.WebhookDeliveryDead, now, 0, "webhook is not active",
+			); err != nil {
+				return i, err
+			}
+			continue
+		}
+
+		responseStatus, err := d.send(webhook, delivery)
+		if err == nil {
+			if err := d.deliveryRepository.MarkDelivered(delivery.ID, responseStatus); err != nil {
+				return i, err
+			}
+			continue
+		}
+
+		status := model.WebhookDeliveryPending
+		if delivery.Attempts+1 >= d.maxAttempts {
+			status = model.WebhookDeliveryDead
+		}
+
+		log.WithFields(log.Fields{
+			"err":        err,
+			"webhookID":  webhook.ID,
+			"deliveryID": delivery.ID,
+			"attempts":   delivery.Attempts + 1,
+			"status":     status,
+		}).Warn("impossible to deliver webhook")
+
+		if err := d.deliveryRepository.MarkFailed(
+			delivery.ID,
+			status,
+			now.Add(outbox.Backoff(d.retryBackoff, d.maxBackoff, delivery.Attempts)),
+			responseStatus,
+			err.Error(),
+		); err != nil {
+			return i, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// send sends signed delivery payload to webhook URL and returns response status.
+// Any response status other than 2xx is treated as error.
+func (d *Dispatcher) send(webhook *model.Webhook, delivery *model.WebhookDelivery) (int, error) {
+	timestamp := d.now().Unix()
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, errors.Wrap(err, "error creating new http request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookID, strconv.Itoa(webhook.ID))
+	req.Header.Set(HeaderDeliveryID, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "error sending webhook request")
+	}
+	//nolint
+	defer resp.Body.Close()
+	//nolint
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseBodySize))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, errors.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,151 @@
+// +build unit
+
+package webhook
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/outbox"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func newTestDispatcher(
+	webhookRepository dao.WebhookRepositoryProvider,
+	deliveryRepository dao.WebhookDeliveryRepositoryProvider,
+	now time.Time,
+) *Dispatcher {
+	dispatcher := NewDispatcher(
+		webhookRepository,
+		deliveryRepository,
+		&http.Client{Timeout: time.Second},
+		100,
+		3,
+		time.Second,
+		time.Second,
+		time.Minute,
+	)
+	dispatcher.now = func() time.Time { return now }
+	return dispatcher
+}
+
+func TestDispatcherPublishFansOutToSubscribedWebhooks(t *testing.T) {
+	webhooks := []model.Webhook{
+		{ID: 1, EventTypes: "", Active: true},
+		{ID: 2, EventTypes: "user.deleted", Active: true},
+		{ID: 3, EventTypes: "user.*", Active: true},
+	}
+
+	mockWebhookRepository := dao.MockWebhookRepositoryProvider{}
+	mockWebhookRepository.On("FindActive").Return(webhooks, nil)
+	mockDeliveryRepository := dao.MockWebhookDeliveryRepositoryProvider{}
+	mockDeliveryRepository.On("CreateAll", mock.MatchedBy(func(deliveries []model.WebhookDelivery) bool {
+		return len(deliveries) == 2 &&
+			deliveries[0].WebhookID == 1 &&
+			deliveries[1].WebhookID == 3 &&
+			deliveries[1].EventID == 10 &&
+			deliveries[1].EventType == model.EventUserCreated
+	})).Return(nil)
+
+	dispatcher := newTestDispatcher(&mockWebhookRepository, &mockDeliveryRepository, time.Now())
+	err := dispatcher.Publish(&outbox.Message{ID: 10, Type: model.EventUserCreated, UserID: 500})
+	require.Nil(t, err)
+	mockDeliveryRepository.AssertExpectations(t)
+}
+
+func TestDispatcherDeliverPendingSignsRequest(t *testing.T) {
+	now := time.Date(2020, 5, 14, 16, 18, 40, 0, time.UTC)
+	payload := []byte(`{"id":10,"type":"user.created","user_id":500}`)
+
+	var receivedBody []byte
+	var receivedSignature, receivedTimestamp string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = ioutil.ReadAll(r.Body)
+		receivedSignature = r.Header.Get(HeaderSignature)
+		receivedTimestamp = r.Header.Get(HeaderTimestamp)
+		assert.Equal(t, model.EventUserCreated, r.Header.Get(HeaderEventType))
+		assert.Equal(t, "7", r.Header.Get(HeaderDeliveryID))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	mockWebhookRepository := dao.MockWebhookRepositoryProvider{}
+	mockWebhookRepository.On("GetByID", 1).Return(&model.Webhook{
+		ID: 1, URL: receiver.URL, Secret: testSecret, Active: true,
+	}, nil)
+	mockDeliveryRepository := dao.MockWebhookDeliveryRepositoryProvider{}
+	mockDeliveryRepository.On("FindPending", now, 100).Return([]model.WebhookDelivery{
+		{ID: 7, WebhookID: 1, EventID: 10, EventType: model.EventUserCreated, Payload: payload},
+	}, nil)
+	mockDeliveryRepository.On("MarkDelivered", int64(7), http.StatusOK).Return(nil)
+
+	dispatcher := newTestDispatcher(&mockWebhookRepository, &mockDeliveryRepository, now)
+	count, err := dispatcher.DeliverPending()
+	require.Nil(t, err)
+	assert.Equal(t, 1, count)
+	mockDeliveryRepository.AssertExpectations(t)
+
+	assert.Equal(t, payload, receivedBody)
+	timestamp, err := strconv.ParseInt(receivedTimestamp, 10, 64)
+	require.Nil(t, err)
+	assert.Equal(t, now.Unix(), timestamp)
+	assert.True(t, VerifySignature(testSecret, timestamp, receivedBody, receivedSignature))
+}
+
+func TestDispatcherDeliverPendingRetriesAndDeadLetters(t *testing.T) {
+	now := time.Date(2020, 5, 14, 16, 18, 40, 0, time.UTC)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	mockWebhookRepository := dao.MockWebhookRepositoryProvider{}
+	mockWebhookRepository.On("GetByID", 1).Return(&model.Webhook{
+		ID: 1, URL: receiver.URL, Secret: testSecret, Active: true,
+	}, nil)
+	mockDeliveryRepository := dao.MockWebhookDeliveryRepositoryProvider{}
+	mockDeliveryRepository.On("FindPending", now, 100).Return([]model.WebhookDelivery{
+		{ID: 7, WebhookID: 1, Attempts: 0, Payload: []byte(`{}`)},
+		{ID: 8, WebhookID: 1, Attempts: 2, Payload: []byte(`{}`)},
+	}, nil)
+	mockDeliveryRepository.On("MarkFailed", int64(7), model.WebhookDeliveryPending, now.Add(time.Second),
+		http.StatusInternalServerError, mock.Anything).Return(nil)
+	mockDeliveryRepository.On("MarkFailed", int64(8), model.WebhookDeliveryDead, now.Add(4*time.Second),
+		http.StatusInternalServerError, mock.Anything).Return(nil)
+
+	dispatcher := newTestDispatcher(&mockWebhookRepository, &mockDeliveryRepository, now)
+	count, err := dispatcher.DeliverPending()
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+	mockDeliveryRepository.AssertExpectations(t)
+	mockWebhookRepository.AssertNumberOfCalls(t, "GetByID", 1)
+}
+
+func TestDispatcherDeliverPendingInactiveWebhook(t *testing.T) {
+	now := time.Date(2020, 5, 14, 16, 18, 40, 0, time.UTC)
+
+	mockWebhookRepository := dao.MockWebhookRepositoryProvider{}
+	mockWebhookRepository.On("GetByID", 1).Return(&model.Webhook{ID: 1, Active: false}, nil)
+	mockDeliveryRepository := dao.MockWebhookDeliveryRepositoryProvider{}
+	mockDeliveryRepository.On("FindPending", now, 100).Return([]model.WebhookDelivery{
+		{ID: 7, WebhookID: 1},
+	}, nil)
+	mockDeliveryRepository.On("MarkFailed", int64(7), model.WebhookDeliveryDead, now, 0, mock.Anything).Return(nil)
+
+	dispatcher := newTestDispatcher(&mockWebhookRepository, &mockDeliveryRepository, now)
+	_, err := dispatcher.DeliverPending()
+	require.Nil(t, err)
+	mockDeliveryRepository.AssertExpectations(t)
+}
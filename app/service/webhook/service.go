@@ -0,0 +1,223 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+	"github.com/mmgopher/user-service/app/service/user/validator"
+)
+
+const (
+	deliveryListDefaultPageSize = 30
+	deliveryListMaxPageSize     = 200
+	secretLength                = 32
+)
+
+// Provider provides an interface to work with Webhook service
+type Provider interface {
+	// GetWebhook returns Webhook based on webhook ID
+	GetWebhook(webhookID int) (*model.Webhook, error)
+	// GetWebhooks returns all webhooks
+	GetWebhooks() ([]model.Webhook, error)
+	// CreateWebhook creates new webhook, secret is generated when it is not provided
+	CreateWebhook(request *request.CreateWebhook) (*model.Webhook, error)
+	// UpdateWebhook updates existing webhook, secret is not changed when it is not provided
+	UpdateWebhook(webhookID int, request *request.UpdateWebhook) error
+	// DeleteWebhook deletes webhook together with its deliveries
+	DeleteWebhook(webhookID int) error
+	// FindDeliveries returns deliveries of the webhook from the newest one and ID of the last returned delivery
+	// if there are more deliveries to return.
+	FindDeliveries(webhookID int, request *request.FindWebhookDeliveries) ([]model.WebhookDelivery, int64, error)
+}
+
+// Service represents Webhook service
+type Service struct {
+	webhookRepository  dao.WebhookRepositoryProvider
+	deliveryRepository dao.WebhookDeliveryRepositoryProvider
+}
+
+// NewService creates new instance of Webhook service.
+func NewService(
+	webhookRepository dao.WebhookRepositoryProvider,
+	deliveryRepository dao.WebhookDeliveryRepositoryProvider,
+) *Service {
+	return &Service{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+	}
+}
+
+// EventTypes returns list of event types the webhook is subscribed to.
+// Empty list means that webhook is subscribed to all events.
+func EventTypes(webhook *model.Webhook) []string {
+	if webhook.EventTypes == "" {
+		return []string{}
+	}
+
+	return strings.Split(webhook.EventTypes, ",")
+}
+
+// isSubscribed checks if webhook is subscribed to the event type.
+func isSubscribed(webhook *model.Webhook, eventType string) bool {
+	eventTypes := EventTypes(webhook)
+	if len(eventTypes) == 0 {
+		return true
+	}
+
+	for _, t := range eventTypes {
+		if t == eventType || (strings.HasSuffix(t, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(t, "*"))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetWebhook returns Webhook based on webhook ID
+func (s Service) GetWebhook(webhookID int) (*model.Webhook, error) {
+	webhook, err := s.webhookRepository.GetByID(webhookID)
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	if webhook == nil {
+		return nil, httperrors.EntityNotFoundError("webhook")
+	}
+
+	return webhook, nil
+}
+
+// GetWebhooks returns all webhooks
+func (s Service) GetWebhooks() ([]model.Webhook, error) {
+	webhooks, err := s.webhookRepository.FindAll()
+	if err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return webhooks, nil
+}
+
+// CreateWebhook creates new webhook, secret is generated when it is not provided
+func (s Service) CreateWebhook(request *request.CreateWebhook) (*model.Webhook, error) {
+
+	if err := validator.ValidateCreateWebhookRequest(request); err != nil {
+		return nil, err
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateSecret(); err != nil {
+			return nil, httperrors.InternalServerError.WithCause(err)
+		}
+	}
+
+	webhook := &model.Webhook{
+		URL:        request.URL,
+		EventTypes: strings.Join(request.EventTypes, ","),
+		Secret:     secret,
+		Active:     request.Active == nil || *request.Active,
+	}
+
+	if _, err := s.webhookRepository.Create(webhook); err != nil {
+		return nil, httperrors.InternalServerError.WithCause(err)
+	}
+
+	return webhook, nil
+}
+
+// UpdateWebhook updates existing webhook, secret is not changed when it is not provided
+func (s Service) UpdateWebhook(webhookID int, request *request.UpdateWebhook) error {
+
+	if err := validator.ValidateUpdateWebhookRequest(request); err != nil {
+		return err
+	}
+
+	webhook, err := s.GetWebhook(webhookID)
+	if err != nil {
+		return err
+	}
+
+	webhook.URL = request.URL
+	webhook.EventTypes = strings.Join(request.EventTypes, ",")
+	if request.Secret != "" {
+		webhook.Secret = request.Secret
+	}
+	if request.Active != nil {
+		webhook.Active = *request.Active
+	}
+
+	updated, err := s.webhookRepository.Update(webhook)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !updated {
+		return httperrors.EntityNotFoundError("webhook")
+	}
+
+	return nil
+}
+
+// DeleteWebhook deletes webhook together with its deliveries
+func (s Service) DeleteWebhook(webhookID int) error {
+	deleted, err := s.webhookRepository.Delete(webhookID)
+	if err != nil {
+		return httperrors.InternalServerError.WithCause(err)
+	}
+
+	if !deleted {
+		return httperrors.EntityNotFoundError("webhook")
+	}
+
+	return nil
+}
+
+// FindDeliveries returns deliveries of the webhook from the newest one and ID of the last returned delivery
+// if there are more deliveries to return.
+func (s Service) FindDeliveries(webhookID int, request *request.FindWebhookDeliveries) (
+	[]model.WebhookDelivery, int64, error) {
+
+	if err := validator.ValidateFindWebhookDeliveriesRequest(request); err != nil {
+		return nil, 0, err
+	}
+
+	if _, err := s.GetWebhook(webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	limit := request.Limit
+	if limit == 0 || limit > deliveryListMaxPageSize {
+		limit = deliveryListDefaultPageSize
+	}
+
+	deliveries, err := s.deliveryRepository.FindByWebhookID(webhookID, request.BeforeID, limit+1)
+	if err != nil {
+		return nil, 0, httperrors.InternalServerError.WithCause(err)
+	}
+
+	var nextBeforeID int64
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+		nextBeforeID = deliveries[limit-1].ID
+	}
+
+	return deliveries, nextBeforeID, nil
+}
+
+// generateSecret generates random secret used to sign webhook requests.
+func generateSecret() (string, error) {
+	b := make([]byte, secretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "impossible to generate webhook secret")
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,99 @@
+// +build unit
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/dao"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/model"
+)
+
+func TestCreateWebhookGeneratesSecret(t *testing.T) {
+	mockWebhookRepository := dao.MockWebhookRepositoryProvider{}
+	mockWebhookRepository.On("Create", mock.MatchedBy(func(webhook *model.Webhook) bool {
+		return webhook.URL == "https://example.com/hook" &&
+			webhook.EventTypes == "user.created,user.deleted" &&
+			len(webhook.Secret) == 2*secretLength &&
+			webhook.Active
+	})).Return(1, nil)
+
+	service := NewService(&mockWebhookRepository, &dao.MockWebhookDeliveryRepositoryProvider{})
+	webhook, err := service.CreateWebhook(&request.CreateWebhook{
+		URL:        "https://example.com/hook",
+		EventTypes: []string{"user.created", "user.deleted"},
+	})
+	require.Nil(t, err)
+	assert.NotEmpty(t, webhook.Secret)
+	mockWebhookRepository.AssertExpectations(t)
+}
+
+func TestCreateWebhookValidationError(t *testing.T) {
+	service := NewService(&dao.MockWebhookRepositoryProvider{}, &dao.MockWebhookDeliveryRepositoryProvider{})
+	webhook, err := service.CreateWebhook(&request.CreateWebhook{
+		URL: "ftp://example.com/hook",
+	})
+	require.NotNil(t, err)
+	assert.Nil(t, webhook)
+	assert.EqualError(t, httperrors.RequestValidationFailed.WithDetails(httperrors.Detail{
+		Field: "/url", Code: httperrors.WebhookURLIncorrect.Code, Message: httperrors.WebhookURLIncorrect.Message,
+	}), err.Error())
+}
+
+func TestUpdateWebhookKeepsSecret(t *testing.T) {
+	webhookID := 1
+	mockWebhookRepository := dao.MockWebhookRepositoryProvider{}
+	mockWebhookRepository.On("GetByID", webhookID).Return(&model.Webhook{
+		ID: webhookID, URL: "https://example.com/old", Secret: testSecret, Active: true,
+	}, nil)
+	mockWebhookRepository.On("Update", &model.Webhook{
+		ID: webhookID, URL: "https://example.com/new", EventTypes: "user.*", Secret: testSecret, Active: true,
+	}).Return(true, nil)
+
+	service := NewService(&mockWebhookRepository, &dao.MockWebhookDeliveryRepositoryProvider{})
+	err := service.UpdateWebhook(webhookID, &request.UpdateWebhook{
+		URL:        "https://example.com/new",
+		EventTypes: []string{"user.*"},
+	})
+	require.Nil(t, err)
+	mockWebhookRepository.AssertExpectations(t)
+}
+
+func TestDeleteWebhookNotFound(t *testing.T) {
+	mockWebhookRepository := dao.MockWebhookRepositoryProvider{}
+	mockWebhookRepository.On("Delete", 1).Return(false, nil)
+
+	service := NewService(&mockWebhookRepository, &dao.MockWebhookDeliveryRepositoryProvider{})
+	err := service.DeleteWebhook(1)
+	require.NotNil(t, err)
+	assert.EqualError(t, httperrors.EntityNotFoundError("webhook"), err.Error())
+}
+
+func TestFindDeliveriesPaging(t *testing.T) {
+	webhookID := 1
+	mockWebhookRepository := dao.MockWebhookRepositoryProvider{}
+	mockWebhookRepository.On("GetByID", webhookID).Return(&model.Webhook{ID: webhookID}, nil)
+	mockDeliveryRepository := dao.MockWebhookDeliveryRepositoryProvider{}
+	mockDeliveryRepository.On("FindByWebhookID", webhookID, int64(0), 3).Return([]model.WebhookDelivery{
+		{ID: 9}, {ID: 8}, {ID: 7},
+	}, nil)
+
+	service := NewService(&mockWebhookRepository, &mockDeliveryRepository)
+	deliveries, nextBeforeID, err := service.FindDeliveries(webhookID, &request.FindWebhookDeliveries{Limit: 2})
+	require.Nil(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, int64(8), nextBeforeID)
+}
+
+func TestIsSubscribed(t *testing.T) {
+	assert.True(t, isSubscribed(&model.Webhook{}, model.EventUserCreated))
+	assert.True(t, isSubscribed(&model.Webhook{EventTypes: "user.*"}, model.EventUserDeleted))
+	assert.True(t, isSubscribed(&model.Webhook{EventTypes: "user.updated,user.deleted"}, model.EventUserDeleted))
+	assert.False(t, isSubscribed(&model.Webhook{EventTypes: "user.updated"}, model.EventUserDeleted))
+}
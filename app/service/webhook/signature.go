@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Headers sent with every webhook request
+const (
+	HeaderWebhookID  = "X-Webhook-ID"
+	HeaderDeliveryID = "X-Webhook-Delivery-ID"
+	HeaderEventType  = "X-Webhook-Event"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+)
+
+const signaturePrefix = "sha256="
+
+// Sign returns signature of the webhook request.
+// It is HMAC-SHA256 of `<timestamp>.<body>` calculated with webhook secret and encoded as hex with `sha256=` prefix.
+// Timestamp is a part of the signature, so receivers can reject replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks if signature matches the webhook request.
+func VerifySignature(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
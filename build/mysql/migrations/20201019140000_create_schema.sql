@@ -0,0 +1,87 @@
+-- Requires MySQL 8.0.13 or newer, because TEXT columns use expression defaults.
+-- +goose Up
+CREATE TABLE `user` (
+     id integer NOT NULL AUTO_INCREMENT PRIMARY KEY,
+     name varchar(255) NOT NULL,
+     surname varchar(255) NOT NULL,
+     gender varchar(32) NOT NULL,
+     age integer NOT NULL,
+     address text NOT NULL,
+     created_at timestamp(6) DEFAULT CURRENT_TIMESTAMP(6)
+) AUTO_INCREMENT = 500;
+
+CREATE TABLE `outbox_event` (
+     id bigint NOT NULL AUTO_INCREMENT PRIMARY KEY,
+     aggregate_id integer NOT NULL,
+     type varchar(64) NOT NULL,
+     payload json NOT NULL,
+     attempts integer NOT NULL DEFAULT 0,
+     last_error text NOT NULL DEFAULT (''),
+     next_attempt_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     published_at timestamp(6) NULL,
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     INDEX outbox_event_pending_idx (published_at, aggregate_id, id)
+);
+
+CREATE TABLE `webhook` (
+     id integer NOT NULL AUTO_INCREMENT PRIMARY KEY,
+     url text NOT NULL,
+     event_types text NOT NULL DEFAULT (''),
+     secret text NOT NULL,
+     active boolean NOT NULL DEFAULT true,
+     created_at timestamp(6) DEFAULT CURRENT_TIMESTAMP(6)
+);
+
+CREATE TABLE `webhook_delivery` (
+     id bigint NOT NULL AUTO_INCREMENT PRIMARY KEY,
+     webhook_id integer NOT NULL,
+     event_id bigint NOT NULL,
+     event_type varchar(64) NOT NULL,
+     payload json NOT NULL,
+     status varchar(32) NOT NULL DEFAULT 'pending',
+     attempts integer NOT NULL DEFAULT 0,
+     response_status integer NOT NULL DEFAULT 0,
+     last_error text NOT NULL DEFAULT (''),
+     next_attempt_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     delivered_at timestamp(6) NULL,
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     INDEX webhook_delivery_webhook_idx (webhook_id, id),
+     INDEX webhook_delivery_pending_idx (status, next_attempt_at),
+     FOREIGN KEY (webhook_id) REFERENCES `webhook` (id) ON DELETE CASCADE
+);
+
+CREATE TABLE `change_sequence` (
+     id integer PRIMARY KEY,
+     value bigint NOT NULL
+);
+INSERT INTO `change_sequence` (id, value) VALUES (1, 0);
+
+CREATE TABLE `user_change` (
+     seq bigint PRIMARY KEY,
+     user_id integer NOT NULL,
+     operation varchar(32) NOT NULL,
+     changed_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+);
+
+CREATE TABLE `idempotency_key` (
+     scope varchar(255) NOT NULL,
+     `key` varchar(255) NOT NULL,
+     fingerprint varchar(64) NOT NULL,
+     status varchar(32) NOT NULL,
+     response_status integer NOT NULL DEFAULT 0,
+     response_content_type varchar(255) NOT NULL DEFAULT '',
+     response_body mediumtext NOT NULL DEFAULT (''),
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     expires_at timestamp(6) NOT NULL,
+     PRIMARY KEY (scope, `key`),
+     INDEX idempotency_key_expires_at_idx (expires_at)
+);
+
+-- +goose Down
+DROP TABLE `idempotency_key`;
+DROP TABLE `user_change`;
+DROP TABLE `change_sequence`;
+DROP TABLE `webhook_delivery`;
+DROP TABLE `webhook`;
+DROP TABLE `outbox_event`;
+DROP TABLE `user`;
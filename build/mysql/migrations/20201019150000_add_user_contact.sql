@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE `user`
+     ADD COLUMN email varchar(254) NOT NULL DEFAULT '',
+     ADD COLUMN phone varchar(16) NOT NULL DEFAULT '',
+     ADD COLUMN email_verified_at timestamp(6) NULL;
+CREATE UNIQUE INDEX user_email_idx ON `user` ((lower(NULLIF(email, ''))));
+
+CREATE TABLE `email_verification` (
+     token_hash varchar(64) NOT NULL PRIMARY KEY,
+     user_id integer NOT NULL,
+     email varchar(254) NOT NULL,
+     expires_at timestamp(6) NOT NULL,
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     INDEX email_verification_user_idx (user_id),
+     FOREIGN KEY (user_id) REFERENCES `user` (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE `email_verification`;
+DROP INDEX user_email_idx ON `user`;
+ALTER TABLE `user`
+     DROP COLUMN email_verified_at,
+     DROP COLUMN phone,
+     DROP COLUMN email;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Date of birth is approximated from the age at the time the user was created.
+ALTER TABLE `user` ADD COLUMN date_of_birth date NULL;
+UPDATE `user`
+   SET date_of_birth = DATE_SUB(DATE(COALESCE(created_at, CURRENT_TIMESTAMP(6))), INTERVAL age YEAR);
+ALTER TABLE `user`
+     MODIFY COLUMN date_of_birth date NOT NULL,
+     DROP COLUMN age;
+CREATE INDEX user_date_of_birth_idx ON `user` (date_of_birth);
+
+-- +goose Down
+ALTER TABLE `user` ADD COLUMN age integer NULL;
+UPDATE `user`
+   SET age = TIMESTAMPDIFF(YEAR, date_of_birth, DATE(COALESCE(created_at, CURRENT_TIMESTAMP(6))));
+ALTER TABLE `user`
+     MODIFY COLUMN age integer NOT NULL,
+     DROP INDEX user_date_of_birth_idx,
+     DROP COLUMN date_of_birth;
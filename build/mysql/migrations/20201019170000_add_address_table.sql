@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE `address` (
+     id integer NOT NULL AUTO_INCREMENT PRIMARY KEY,
+     user_id integer NOT NULL,
+     type varchar(16) NOT NULL,
+     street varchar(255) NOT NULL,
+     city varchar(255) NOT NULL,
+     postal_code varchar(32) NOT NULL DEFAULT '',
+     region varchar(255) NOT NULL DEFAULT '',
+     country char(2) NOT NULL,
+     is_primary boolean NOT NULL DEFAULT false,
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     INDEX address_user_idx (user_id),
+     INDEX address_country_idx (country),
+     FOREIGN KEY (user_id) REFERENCES `user` (id) ON DELETE CASCADE
+);
+-- MySQL has no partial indexes, NULL keys of not primary addresses do not collide.
+CREATE UNIQUE INDEX address_primary_idx ON `address` ((CASE WHEN is_primary THEN user_id END));
+CREATE INDEX address_city_idx ON `address` ((lower(city)));
+
+-- +goose Down
+DROP TABLE `address`;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- MySQL can not index whole JSON documents, attribute filters are evaluated with JSON_CONTAINS.
+ALTER TABLE `user` ADD COLUMN attributes json NOT NULL DEFAULT (JSON_OBJECT());
+CREATE TABLE `attribute_definition` (
+     id integer NOT NULL AUTO_INCREMENT PRIMARY KEY,
+     name varchar(64) NOT NULL,
+     type varchar(16) NOT NULL,
+     required boolean NOT NULL DEFAULT false,
+     enum_values json NOT NULL DEFAULT (JSON_ARRAY()),
+     pattern varchar(255) NOT NULL DEFAULT '',
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     UNIQUE INDEX attribute_definition_name_idx (name)
+);
+
+-- +goose Down
+DROP TABLE `attribute_definition`;
+ALTER TABLE `user` DROP COLUMN attributes;
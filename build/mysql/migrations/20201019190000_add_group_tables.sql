@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE `groups` (
+     id integer NOT NULL AUTO_INCREMENT PRIMARY KEY,
+     name varchar(255) NOT NULL,
+     description varchar(1024) NOT NULL DEFAULT '',
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     UNIQUE INDEX groups_name_idx (name)
+);
+
+CREATE TABLE `user_groups` (
+     group_id integer NOT NULL,
+     user_id integer NOT NULL,
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     PRIMARY KEY (group_id, user_id),
+     INDEX user_groups_user_idx (user_id),
+     FOREIGN KEY (group_id) REFERENCES `groups` (id) ON DELETE CASCADE,
+     FOREIGN KEY (user_id) REFERENCES `user` (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE `user_groups`;
+DROP TABLE `groups`;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Existing users are active, new users are created as pending by the service.
+ALTER TABLE `user` ADD COLUMN status varchar(16) NOT NULL DEFAULT 'active';
+CREATE INDEX user_status_idx ON `user` (status);
+
+-- History is kept after the user is deleted, so it has no foreign key.
+CREATE TABLE `user_status_history` (
+     id integer NOT NULL AUTO_INCREMENT PRIMARY KEY,
+     user_id integer NOT NULL,
+     from_status varchar(16) NOT NULL,
+     to_status varchar(16) NOT NULL,
+     reason varchar(500) NOT NULL,
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     INDEX user_status_history_user_idx (user_id)
+);
+
+-- +goose Down
+DROP TABLE `user_status_history`;
+DROP INDEX user_status_idx ON `user`;
+ALTER TABLE `user` DROP COLUMN status;
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE `user_credential` (
+     user_id integer NOT NULL PRIMARY KEY,
+     password_hash varchar(255) NOT NULL,
+     failed_attempts integer NOT NULL DEFAULT 0,
+     updated_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     FOREIGN KEY (user_id) REFERENCES `user` (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE `user_credential`;
@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE `one_time_token` (
+     token_hash varchar(64) NOT NULL PRIMARY KEY,
+     purpose varchar(32) NOT NULL,
+     user_id integer NOT NULL,
+     subject varchar(254) NOT NULL DEFAULT '',
+     expires_at timestamp(6) NOT NULL,
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     INDEX one_time_token_user_idx (user_id, purpose),
+     FOREIGN KEY (user_id) REFERENCES `user` (id) ON DELETE CASCADE
+);
+INSERT INTO `one_time_token` (token_hash, purpose, user_id, subject, expires_at, created_at)
+SELECT token_hash, 'email_verification', user_id, email, expires_at, created_at
+FROM `email_verification`;
+DROP TABLE `email_verification`;
+
+-- +goose Down
+CREATE TABLE `email_verification` (
+     token_hash varchar(64) NOT NULL PRIMARY KEY,
+     user_id integer NOT NULL,
+     email varchar(254) NOT NULL,
+     expires_at timestamp(6) NOT NULL,
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     INDEX email_verification_user_idx (user_id),
+     FOREIGN KEY (user_id) REFERENCES `user` (id) ON DELETE CASCADE
+);
+INSERT INTO `email_verification` (token_hash, user_id, email, expires_at, created_at)
+SELECT token_hash, user_id, subject, expires_at, created_at
+FROM `one_time_token`
+WHERE purpose = 'email_verification';
+DROP TABLE `one_time_token`;
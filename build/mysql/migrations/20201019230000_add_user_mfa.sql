@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE `user_mfa` (
+     user_id integer NOT NULL PRIMARY KEY,
+     secret varchar(255) NOT NULL,
+     enabled_at timestamp(6) NULL,
+     last_used_step bigint NOT NULL DEFAULT 0,
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     FOREIGN KEY (user_id) REFERENCES `user` (id) ON DELETE CASCADE
+);
+
+CREATE TABLE `user_mfa_recovery_code` (
+     user_id integer NOT NULL,
+     code_hash varchar(64) NOT NULL,
+     PRIMARY KEY (user_id, code_hash),
+     FOREIGN KEY (user_id) REFERENCES `user` (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE `user_mfa_recovery_code`;
+DROP TABLE `user_mfa`;
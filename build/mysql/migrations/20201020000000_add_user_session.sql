@@ -0,0 +1,26 @@
+-- +goose Up
+-- Revoked sessions are kept after the user is deleted, so it has no foreign key.
+CREATE TABLE `user_session` (
+     id integer NOT NULL AUTO_INCREMENT PRIMARY KEY,
+     user_id integer NOT NULL,
+     refresh_token_hash varchar(64) NOT NULL,
+     user_agent varchar(500) NOT NULL DEFAULT '',
+     ip varchar(45) NOT NULL DEFAULT '',
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     last_seen_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     expires_at timestamp(6) NOT NULL,
+     revoked_at timestamp(6) NULL,
+     INDEX user_session_user_idx (user_id)
+);
+
+-- Every refresh token issued for the session, reuse of rotated token revokes the session.
+CREATE TABLE `user_session_refresh_token` (
+     token_hash varchar(64) NOT NULL PRIMARY KEY,
+     session_id integer NOT NULL,
+     created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     FOREIGN KEY (session_id) REFERENCES `user_session` (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE `user_session_refresh_token`;
+DROP TABLE `user_session`;
@@ -1,6 +1,8 @@
 /* ###### Insert TEST DATA #######*/
-INSERT INTO user_sch.user (id, name, surname, gender, age, address, created_at)
-VALUES (1,'Sonny', 'Watts', 'male', 30, '1754  Arron Smith Drive', '2020-04-25T16:47:59.000000'),
+/* Dates of birth are relative to the current date, so users keep the listed age. */
+INSERT INTO user_sch.user (id, name, surname, gender, date_of_birth, address, created_at)
+SELECT id, name, surname, gender, (current_date - make_interval(years => age, days => 1))::date, address, created_at::timestamp
+FROM (VALUES (1,'Sonny', 'Watts', 'male', 30, '1754  Arron Smith Drive', '2020-04-25T16:47:59.000000'),
        (2,'Hollie', 'Gordon', 'female', 55, 'San Pedro 1586 Drive A', '2019-11-25T11:47:59.000000'),
        (3,'Sonny', 'Gordon', 'male', 23, 'California 14 Armbrester Drive', '2019-02-25T11:47:59.000000'),
        (4,'Sonny-sorttest', 'Parker', 'male', 30, 'California 4 Drive B', '2019-03-25T11:47:59.000000'),
@@ -12,4 +14,5 @@ VALUES (1,'Sonny', 'Watts', 'male', 30, '1754  Arron Smith Drive', '2020-04-25T1
        (10,'Troy-sorttest', 'Gordon', 'male', 50, 'California 10 Drive N', '2019-02-15T11:47:59.000000'),
        (11,'Alice-sorttest', 'Gordon', 'female', 21, 'New York 11 Drive Q', '2019-12-25T11:47:59.000000'),
        (12,'Lucia-sorttest', 'Bradley', 'female', 49, 'London 12 Drive Q', '2019-11-25T11:47:59.000000'),
-       (13,'Doug-sorttest', 'Wright', 'male', 31, 'California 13 Drive Q', '2019-09-25T11:47:59.000000') ;
\ No newline at end of file
+       (13,'Doug-sorttest', 'Wright', 'male', 31, 'California 13 Drive Q', '2019-09-25T11:47:59.000000')
+) AS data (id, name, surname, gender, age, address, created_at);
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "user_sch"."outbox_event" (
+     id BIGSERIAL PRIMARY KEY,
+     aggregate_id integer NOT NULL,
+     type text NOT NULL,
+     payload jsonb NOT NULL,
+     attempts integer NOT NULL DEFAULT 0,
+     last_error text NOT NULL DEFAULT '',
+     next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+     published_at timestamp with time zone,
+     created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX outbox_event_pending_idx ON "user_sch"."outbox_event" (aggregate_id, id) WHERE published_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."outbox_event";
+-- +goose StatementEnd
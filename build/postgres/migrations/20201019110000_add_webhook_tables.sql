@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "user_sch"."webhook" (
+     id SERIAL PRIMARY KEY,
+     url text NOT NULL,
+     event_types text NOT NULL DEFAULT '',
+     secret text NOT NULL,
+     active boolean NOT NULL DEFAULT true,
+     created_at timestamp with time zone DEFAULT now()
+);
+CREATE TABLE "user_sch"."webhook_delivery" (
+     id BIGSERIAL PRIMARY KEY,
+     webhook_id integer NOT NULL REFERENCES "user_sch"."webhook" (id) ON DELETE CASCADE,
+     event_id bigint NOT NULL,
+     event_type text NOT NULL,
+     payload jsonb NOT NULL,
+     status text NOT NULL DEFAULT 'pending',
+     attempts integer NOT NULL DEFAULT 0,
+     response_status integer NOT NULL DEFAULT 0,
+     last_error text NOT NULL DEFAULT '',
+     next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+     delivered_at timestamp with time zone,
+     created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX webhook_delivery_webhook_idx ON "user_sch"."webhook_delivery" (webhook_id, id);
+CREATE INDEX webhook_delivery_pending_idx ON "user_sch"."webhook_delivery" (next_attempt_at) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."webhook_delivery";
+DROP TABLE "user_sch"."webhook";
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "user_sch"."change_sequence" (
+     id integer PRIMARY KEY,
+     value bigint NOT NULL
+);
+CREATE TABLE "user_sch"."user_change" (
+     seq bigint PRIMARY KEY,
+     user_id integer NOT NULL,
+     operation text NOT NULL,
+     changed_at timestamp with time zone NOT NULL DEFAULT now()
+);
+INSERT INTO "user_sch"."user_change" (seq, user_id, operation, changed_at)
+SELECT row_number() OVER (ORDER BY id), id, 'created', created_at
+FROM "user_sch"."user";
+INSERT INTO "user_sch"."change_sequence" (id, value)
+SELECT 1, count(*) FROM "user_sch"."user";
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."user_change";
+DROP TABLE "user_sch"."change_sequence";
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "user_sch"."idempotency_key" (
+     scope text NOT NULL,
+     key text NOT NULL,
+     fingerprint text NOT NULL,
+     status text NOT NULL,
+     response_status integer NOT NULL DEFAULT 0,
+     response_content_type text NOT NULL DEFAULT '',
+     response_body text NOT NULL DEFAULT '',
+     created_at timestamp with time zone NOT NULL DEFAULT now(),
+     expires_at timestamp with time zone NOT NULL,
+     PRIMARY KEY (scope, key)
+);
+CREATE INDEX idempotency_key_expires_at_idx ON "user_sch"."idempotency_key" (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."idempotency_key";
+-- +goose StatementEnd
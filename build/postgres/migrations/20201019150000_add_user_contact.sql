@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "user_sch"."user"
+     ADD COLUMN email text NOT NULL DEFAULT '',
+     ADD COLUMN phone text NOT NULL DEFAULT '',
+     ADD COLUMN email_verified_at timestamp with time zone;
+CREATE UNIQUE INDEX user_email_idx ON "user_sch"."user" (lower(NULLIF(email, '')));
+CREATE TABLE "user_sch"."email_verification" (
+     token_hash text PRIMARY KEY,
+     user_id integer NOT NULL REFERENCES "user_sch"."user" (id) ON DELETE CASCADE,
+     email text NOT NULL,
+     expires_at timestamp with time zone NOT NULL,
+     created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX email_verification_user_idx ON "user_sch"."email_verification" (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."email_verification";
+DROP INDEX "user_sch"."user_email_idx";
+ALTER TABLE "user_sch"."user"
+     DROP COLUMN email_verified_at,
+     DROP COLUMN phone,
+     DROP COLUMN email;
+-- +goose StatementEnd
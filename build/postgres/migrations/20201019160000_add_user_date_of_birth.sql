@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "user_sch"."user" ADD COLUMN date_of_birth date;
+-- Date of birth is approximated from the age at the time the user was created.
+UPDATE "user_sch"."user"
+   SET date_of_birth = (COALESCE(created_at, now()) - make_interval(years => age))::date;
+ALTER TABLE "user_sch"."user"
+     ALTER COLUMN date_of_birth SET NOT NULL,
+     DROP COLUMN age;
+CREATE INDEX user_date_of_birth_idx ON "user_sch"."user" (date_of_birth);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "user_sch"."user" ADD COLUMN age integer;
+UPDATE "user_sch"."user"
+   SET age = date_part('year', age(COALESCE(created_at, now())::date, date_of_birth));
+ALTER TABLE "user_sch"."user"
+     ALTER COLUMN age SET NOT NULL,
+     DROP COLUMN date_of_birth;
+-- +goose StatementEnd
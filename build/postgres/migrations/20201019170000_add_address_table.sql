@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "user_sch"."address" (
+     id serial PRIMARY KEY,
+     user_id integer NOT NULL REFERENCES "user_sch"."user" (id) ON DELETE CASCADE,
+     type text NOT NULL,
+     street text NOT NULL,
+     city text NOT NULL,
+     postal_code text NOT NULL DEFAULT '',
+     region text NOT NULL DEFAULT '',
+     country char(2) NOT NULL,
+     is_primary boolean NOT NULL DEFAULT false,
+     created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX address_user_idx ON "user_sch"."address" (user_id);
+CREATE UNIQUE INDEX address_primary_idx ON "user_sch"."address" (user_id) WHERE is_primary;
+CREATE INDEX address_city_idx ON "user_sch"."address" (lower(city));
+CREATE INDEX address_country_idx ON "user_sch"."address" (country);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."address";
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "user_sch"."user" ADD COLUMN attributes jsonb NOT NULL DEFAULT '{}';
+-- jsonb_path_ops index serves attribute filters which are queried with @> operator.
+CREATE INDEX user_attributes_idx ON "user_sch"."user" USING GIN (attributes jsonb_path_ops);
+CREATE TABLE "user_sch"."attribute_definition" (
+     id serial PRIMARY KEY,
+     name text NOT NULL,
+     type text NOT NULL,
+     required boolean NOT NULL DEFAULT false,
+     enum_values jsonb NOT NULL DEFAULT '[]',
+     pattern text NOT NULL DEFAULT '',
+     created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX attribute_definition_name_idx ON "user_sch"."attribute_definition" (name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."attribute_definition";
+ALTER TABLE "user_sch"."user" DROP COLUMN attributes;
+-- +goose StatementEnd
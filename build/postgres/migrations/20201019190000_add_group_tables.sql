@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "user_sch"."groups" (
+     id serial PRIMARY KEY,
+     name text NOT NULL,
+     description text NOT NULL DEFAULT '',
+     created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX groups_name_idx ON "user_sch"."groups" (name);
+
+CREATE TABLE "user_sch"."user_groups" (
+     group_id integer NOT NULL REFERENCES "user_sch"."groups" (id) ON DELETE CASCADE,
+     user_id integer NOT NULL REFERENCES "user_sch"."user" (id) ON DELETE CASCADE,
+     created_at timestamp with time zone NOT NULL DEFAULT now(),
+     PRIMARY KEY (group_id, user_id)
+);
+CREATE INDEX user_groups_user_idx ON "user_sch"."user_groups" (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."user_groups";
+DROP TABLE "user_sch"."groups";
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Existing users are active, new users are created as pending by the service.
+ALTER TABLE "user_sch"."user" ADD COLUMN status text NOT NULL DEFAULT 'active';
+CREATE INDEX user_status_idx ON "user_sch"."user" (status);
+-- History is kept after the user is deleted, so it has no foreign key.
+CREATE TABLE "user_sch"."user_status_history" (
+     id serial PRIMARY KEY,
+     user_id integer NOT NULL,
+     from_status text NOT NULL,
+     to_status text NOT NULL,
+     reason text NOT NULL,
+     created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX user_status_history_user_idx ON "user_sch"."user_status_history" (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."user_status_history";
+DROP INDEX "user_sch"."user_status_idx";
+ALTER TABLE "user_sch"."user" DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "user_sch"."user_credential" (
+     user_id integer PRIMARY KEY REFERENCES "user_sch"."user" (id) ON DELETE CASCADE,
+     password_hash text NOT NULL,
+     failed_attempts integer NOT NULL DEFAULT 0,
+     updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."user_credential";
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "user_sch"."one_time_token" (
+     token_hash text PRIMARY KEY,
+     purpose text NOT NULL,
+     user_id integer NOT NULL REFERENCES "user_sch"."user" (id) ON DELETE CASCADE,
+     subject text NOT NULL DEFAULT '',
+     expires_at timestamp with time zone NOT NULL,
+     created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX one_time_token_user_idx ON "user_sch"."one_time_token" (user_id, purpose);
+INSERT INTO "user_sch"."one_time_token" (token_hash, purpose, user_id, subject, expires_at, created_at)
+SELECT token_hash, 'email_verification', user_id, email, expires_at, created_at
+FROM "user_sch"."email_verification";
+DROP TABLE "user_sch"."email_verification";
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE "user_sch"."email_verification" (
+     token_hash text PRIMARY KEY,
+     user_id integer NOT NULL REFERENCES "user_sch"."user" (id) ON DELETE CASCADE,
+     email text NOT NULL,
+     expires_at timestamp with time zone NOT NULL,
+     created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX email_verification_user_idx ON "user_sch"."email_verification" (user_id);
+INSERT INTO "user_sch"."email_verification" (token_hash, user_id, email, expires_at, created_at)
+SELECT token_hash, user_id, subject, expires_at, created_at
+FROM "user_sch"."one_time_token"
+WHERE purpose = 'email_verification';
+DROP TABLE "user_sch"."one_time_token";
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "user_sch"."user_mfa" (
+     user_id integer PRIMARY KEY REFERENCES "user_sch"."user" (id) ON DELETE CASCADE,
+     secret text NOT NULL,
+     enabled_at timestamp with time zone,
+     last_used_step bigint NOT NULL DEFAULT 0,
+     created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE TABLE "user_sch"."user_mfa_recovery_code" (
+     user_id integer NOT NULL REFERENCES "user_sch"."user" (id) ON DELETE CASCADE,
+     code_hash text NOT NULL,
+     PRIMARY KEY (user_id, code_hash)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."user_mfa_recovery_code";
+DROP TABLE "user_sch"."user_mfa";
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Revoked sessions are kept after the user is deleted, so it has no foreign key.
+CREATE TABLE "user_sch"."user_session" (
+     id serial PRIMARY KEY,
+     user_id integer NOT NULL,
+     refresh_token_hash text NOT NULL,
+     user_agent text NOT NULL DEFAULT '',
+     ip text NOT NULL DEFAULT '',
+     created_at timestamp with time zone NOT NULL DEFAULT now(),
+     last_seen_at timestamp with time zone NOT NULL DEFAULT now(),
+     expires_at timestamp with time zone NOT NULL,
+     revoked_at timestamp with time zone
+);
+CREATE INDEX user_session_user_idx ON "user_sch"."user_session" (user_id);
+
+-- Every refresh token issued for the session, reuse of rotated token revokes the session.
+CREATE TABLE "user_sch"."user_session_refresh_token" (
+     token_hash text PRIMARY KEY,
+     session_id integer NOT NULL REFERENCES "user_sch"."user_session" (id) ON DELETE CASCADE,
+     created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "user_sch"."user_session_refresh_token";
+DROP TABLE "user_sch"."user_session";
+-- +goose StatementEnd
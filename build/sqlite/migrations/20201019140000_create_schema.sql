@@ -0,0 +1,86 @@
+-- +goose Up
+CREATE TABLE "user" (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     name text NOT NULL,
+     surname text NOT NULL,
+     gender text NOT NULL,
+     age integer NOT NULL,
+     address text NOT NULL,
+     created_at timestamp DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO sqlite_sequence (name, seq) VALUES ('user', 499);
+
+CREATE TABLE "outbox_event" (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     aggregate_id integer NOT NULL,
+     type text NOT NULL,
+     payload text NOT NULL,
+     attempts integer NOT NULL DEFAULT 0,
+     last_error text NOT NULL DEFAULT '',
+     next_attempt_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+     published_at timestamp,
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX outbox_event_pending_idx ON "outbox_event" (aggregate_id, id) WHERE published_at IS NULL;
+
+CREATE TABLE "webhook" (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     url text NOT NULL,
+     event_types text NOT NULL DEFAULT '',
+     secret text NOT NULL,
+     active boolean NOT NULL DEFAULT true,
+     created_at timestamp DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "webhook_delivery" (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     webhook_id integer NOT NULL REFERENCES "webhook" (id) ON DELETE CASCADE,
+     event_id bigint NOT NULL,
+     event_type text NOT NULL,
+     payload text NOT NULL,
+     status text NOT NULL DEFAULT 'pending',
+     attempts integer NOT NULL DEFAULT 0,
+     response_status integer NOT NULL DEFAULT 0,
+     last_error text NOT NULL DEFAULT '',
+     next_attempt_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+     delivered_at timestamp,
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX webhook_delivery_webhook_idx ON "webhook_delivery" (webhook_id, id);
+CREATE INDEX webhook_delivery_pending_idx ON "webhook_delivery" (next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE "change_sequence" (
+     id integer PRIMARY KEY,
+     value bigint NOT NULL
+);
+INSERT INTO "change_sequence" (id, value) VALUES (1, 0);
+
+CREATE TABLE "user_change" (
+     seq bigint PRIMARY KEY,
+     user_id integer NOT NULL,
+     operation text NOT NULL,
+     changed_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "idempotency_key" (
+     scope text NOT NULL,
+     "key" text NOT NULL,
+     fingerprint text NOT NULL,
+     status text NOT NULL,
+     response_status integer NOT NULL DEFAULT 0,
+     response_content_type text NOT NULL DEFAULT '',
+     response_body text NOT NULL DEFAULT '',
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+     expires_at timestamp NOT NULL,
+     PRIMARY KEY (scope, "key")
+);
+CREATE INDEX idempotency_key_expires_at_idx ON "idempotency_key" (expires_at);
+
+-- +goose Down
+DROP TABLE "idempotency_key";
+DROP TABLE "user_change";
+DROP TABLE "change_sequence";
+DROP TABLE "webhook_delivery";
+DROP TABLE "webhook";
+DROP TABLE "outbox_event";
+DROP TABLE "user";
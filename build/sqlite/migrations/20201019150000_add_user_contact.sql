@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE "user" ADD COLUMN email text NOT NULL DEFAULT '';
+ALTER TABLE "user" ADD COLUMN phone text NOT NULL DEFAULT '';
+ALTER TABLE "user" ADD COLUMN email_verified_at timestamp;
+CREATE UNIQUE INDEX user_email_idx ON "user" (lower(NULLIF(email, '')));
+
+CREATE TABLE "email_verification" (
+     token_hash text PRIMARY KEY,
+     user_id integer NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+     email text NOT NULL,
+     expires_at timestamp NOT NULL,
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX email_verification_user_idx ON "email_verification" (user_id);
+
+-- +goose Down
+DROP TABLE "email_verification";
+DROP INDEX user_email_idx;
+ALTER TABLE "user" DROP COLUMN email_verified_at;
+ALTER TABLE "user" DROP COLUMN phone;
+ALTER TABLE "user" DROP COLUMN email;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Dates are stored in the same text format in which the driver binds time values, so they compare as strings.
+-- Date of birth is approximated from the age at the time the user was created.
+ALTER TABLE "user" ADD COLUMN date_of_birth date;
+UPDATE "user"
+   SET date_of_birth = date(COALESCE(created_at, CURRENT_TIMESTAMP), '-' || age || ' years') || ' 00:00:00+00:00';
+ALTER TABLE "user" DROP COLUMN age;
+CREATE INDEX user_date_of_birth_idx ON "user" (date_of_birth);
+
+-- +goose Down
+DROP INDEX user_date_of_birth_idx;
+ALTER TABLE "user" ADD COLUMN age integer NOT NULL DEFAULT 0;
+UPDATE "user"
+   SET age = (strftime('%Y', COALESCE(created_at, CURRENT_TIMESTAMP)) - strftime('%Y', date_of_birth))
+           - (strftime('%m-%d', COALESCE(created_at, CURRENT_TIMESTAMP)) < strftime('%m-%d', date_of_birth));
+ALTER TABLE "user" DROP COLUMN date_of_birth;
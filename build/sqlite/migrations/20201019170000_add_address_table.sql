@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE "address" (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     user_id integer NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+     type text NOT NULL,
+     street text NOT NULL,
+     city text NOT NULL,
+     postal_code text NOT NULL DEFAULT '',
+     region text NOT NULL DEFAULT '',
+     country text NOT NULL,
+     is_primary boolean NOT NULL DEFAULT false,
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX address_user_idx ON "address" (user_id);
+CREATE UNIQUE INDEX address_primary_idx ON "address" (user_id) WHERE is_primary;
+CREATE INDEX address_city_idx ON "address" (lower(city));
+CREATE INDEX address_country_idx ON "address" (country);
+
+-- +goose Down
+DROP TABLE "address";
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Attributes are stored as JSON text and queried with JSON1 functions.
+ALTER TABLE "user" ADD COLUMN attributes text NOT NULL DEFAULT '{}';
+CREATE TABLE "attribute_definition" (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     name text NOT NULL,
+     type text NOT NULL,
+     required boolean NOT NULL DEFAULT false,
+     enum_values text NOT NULL DEFAULT '[]',
+     pattern text NOT NULL DEFAULT '',
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX attribute_definition_name_idx ON "attribute_definition" (name);
+
+-- +goose Down
+DROP TABLE "attribute_definition";
+ALTER TABLE "user" DROP COLUMN attributes;
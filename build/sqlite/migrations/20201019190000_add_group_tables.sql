@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE "groups" (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     name text NOT NULL,
+     description text NOT NULL DEFAULT '',
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX groups_name_idx ON "groups" (name);
+
+CREATE TABLE "user_groups" (
+     group_id integer NOT NULL REFERENCES "groups" (id) ON DELETE CASCADE,
+     user_id integer NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+     PRIMARY KEY (group_id, user_id)
+);
+CREATE INDEX user_groups_user_idx ON "user_groups" (user_id);
+
+-- +goose Down
+DROP TABLE "user_groups";
+DROP TABLE "groups";
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Existing users are active, new users are created as pending by the service.
+ALTER TABLE "user" ADD COLUMN status text NOT NULL DEFAULT 'active';
+CREATE INDEX user_status_idx ON "user" (status);
+
+-- History is kept after the user is deleted, so it has no foreign key.
+CREATE TABLE "user_status_history" (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     user_id integer NOT NULL,
+     from_status text NOT NULL,
+     to_status text NOT NULL,
+     reason text NOT NULL,
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX user_status_history_user_idx ON "user_status_history" (user_id);
+
+-- +goose Down
+DROP TABLE "user_status_history";
+DROP INDEX user_status_idx;
+ALTER TABLE "user" DROP COLUMN status;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE "user_credential" (
+     user_id integer PRIMARY KEY REFERENCES "user" (id) ON DELETE CASCADE,
+     password_hash text NOT NULL,
+     failed_attempts integer NOT NULL DEFAULT 0,
+     updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE "user_credential";
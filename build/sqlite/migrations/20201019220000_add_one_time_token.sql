@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE "one_time_token" (
+     token_hash text PRIMARY KEY,
+     purpose text NOT NULL,
+     user_id integer NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+     subject text NOT NULL DEFAULT '',
+     expires_at timestamp NOT NULL,
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX one_time_token_user_idx ON "one_time_token" (user_id, purpose);
+INSERT INTO "one_time_token" (token_hash, purpose, user_id, subject, expires_at, created_at)
+SELECT token_hash, 'email_verification', user_id, email, expires_at, created_at
+FROM "email_verification";
+DROP TABLE "email_verification";
+
+-- +goose Down
+CREATE TABLE "email_verification" (
+     token_hash text PRIMARY KEY,
+     user_id integer NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+     email text NOT NULL,
+     expires_at timestamp NOT NULL,
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX email_verification_user_idx ON "email_verification" (user_id);
+INSERT INTO "email_verification" (token_hash, user_id, email, expires_at, created_at)
+SELECT token_hash, user_id, subject, expires_at, created_at
+FROM "one_time_token"
+WHERE purpose = 'email_verification';
+DROP TABLE "one_time_token";
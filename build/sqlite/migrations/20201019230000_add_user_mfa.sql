@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE "user_mfa" (
+     user_id integer PRIMARY KEY REFERENCES "user" (id) ON DELETE CASCADE,
+     secret text NOT NULL,
+     enabled_at timestamp,
+     last_used_step integer NOT NULL DEFAULT 0,
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "user_mfa_recovery_code" (
+     user_id integer NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+     code_hash text NOT NULL,
+     PRIMARY KEY (user_id, code_hash)
+);
+
+-- +goose Down
+DROP TABLE "user_mfa_recovery_code";
+DROP TABLE "user_mfa";
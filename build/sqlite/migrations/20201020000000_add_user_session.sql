@@ -0,0 +1,25 @@
+-- +goose Up
+-- Revoked sessions are kept after the user is deleted, so it has no foreign key.
+CREATE TABLE "user_session" (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     user_id integer NOT NULL,
+     refresh_token_hash text NOT NULL,
+     user_agent text NOT NULL DEFAULT '',
+     ip text NOT NULL DEFAULT '',
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+     last_seen_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+     expires_at timestamp NOT NULL,
+     revoked_at timestamp
+);
+CREATE INDEX user_session_user_idx ON "user_session" (user_id);
+
+-- Every refresh token issued for the session, reuse of rotated token revokes the session.
+CREATE TABLE "user_session_refresh_token" (
+     token_hash text PRIMARY KEY,
+     session_id integer NOT NULL REFERENCES "user_session" (id) ON DELETE CASCADE,
+     created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE "user_session_refresh_token";
+DROP TABLE "user_session";
@@ -3,11 +3,20 @@ module github.com/mmgopher/user-service
 go 1.14
 
 require (
-	github.com/gin-gonic/gin v1.6.3
+	github.com/BurntSushi/toml v0.3.0
+	github.com/gin-contrib/sse v0.1.0
+	github.com/gin-gonic/gin v1.7.7
+	github.com/go-sql-driver/mysql v1.5.0
 	github.com/jmoiron/sqlx v1.2.0
-	github.com/kelseyhightower/envconfig v1.4.0
 	github.com/lib/pq v1.5.2
+	github.com/mattn/go-sqlite3 v1.14.16
+	github.com/nats-io/nats.go v1.11.0
 	github.com/pkg/errors v0.9.1
 	github.com/sirupsen/logrus v1.6.0
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/stretchr/testify v1.5.1
+	golang.org/x/crypto v0.0.0-20211117183948-ae814b36b871
+	golang.org/x/sync v0.0.0-20220907140024-f12130a52804
+	golang.org/x/sys v0.7.0 // indirect
+	gopkg.in/yaml.v2 v2.2.8
 )
@@ -1,60 +1,215 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"net/http"
 	"os"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/cache"
 	"github.com/mmgopher/user-service/app/config"
 	"github.com/mmgopher/user-service/app/controller"
 	"github.com/mmgopher/user-service/app/dao"
 	"github.com/mmgopher/user-service/app/db"
+	"github.com/mmgopher/user-service/app/service/auth"
+	"github.com/mmgopher/user-service/app/service/changefeed"
+	"github.com/mmgopher/user-service/app/service/idempotency"
+	"github.com/mmgopher/user-service/app/service/notifier"
+	"github.com/mmgopher/user-service/app/service/outbox"
 	"github.com/mmgopher/user-service/app/service/user"
+	"github.com/mmgopher/user-service/app/service/webhook"
 )
 
 func main() {
 
-	cfg, err := config.New()
+	cfg, err := config.New(os.Args[1:])
 	if err != nil {
 		log.WithFields(log.Fields{
 			"err": err,
 		}).Fatal("impossible to initialize config")
 	}
 
-	logLevel, err := log.ParseLevel(strings.ToLower(cfg.LogLevel))
+	log.SetFormatter(&log.JSONFormatter{})
+	log.SetOutput(os.Stdout)
+	// config is logged before the configured level is applied, so the dump is always printed
+	log.WithFields(cfg.Dump()).Info("config loaded")
+	setLogLevel(cfg)
+	go config.Watch(context.Background(), cfg, os.Args[1:], setLogLevel)
+
+	postgresConnection, err := db.GetConnection(cfg.DBSettings())
 	if err != nil {
-		log.WithFields(log.Fields{
-			"err": err,
-		}).Fatal("unsupported log level")
+		log.Fatalf("impossible to establish connection to %s database: %+v", cfg.DBType, err)
 	}
 
-	log.SetFormatter(&log.JSONFormatter{})
-	log.SetOutput(os.Stdout)
-	log.SetLevel(logLevel)
+	replicaConnections, err := db.OpenReplicas(cfg.DBSettings(), cfg.DBReplicaDSNs)
+	if err != nil {
+		log.Fatalf("impossible to open replica connections: %+v", err)
+	}
+	connectionRouter := db.NewConnectionRouter(postgresConnection, replicaConnections, cfg.DBReplicaMaxLag)
+	go connectionRouter.Run(context.Background(), cfg.DBReplicaHealthCheckInterval)
 
-	// Only log the warning severity or above.
-	log.SetLevel(log.WarnLevel)
+	var userRepository dao.UserRepositoryProvider = dao.NewReplicaUserRepository(postgresConnection, connectionRouter)
+	var transactionProvider dao.TransactionProvider = dao.NewTransactor(postgresConnection)
+	var userCache dao.CacheStatsProvider
+	if cfg.UserCacheEnabled {
+		cachedUserRepository := dao.NewCachedUserRepository(
+			userRepository,
+			cache.NewLRU(cfg.UserCacheSize, cfg.UserCacheTTL),
+		)
+		userCache = cachedUserRepository
+		userRepository = cachedUserRepository
+		transactionProvider = cachedUserRepository.Transactional(transactionProvider)
+	}
 
-	postgresConnection, err := db.GetConnection(
-		cfg.DBType,
-		cfg.DBUser,
-		cfg.DBPass,
-		cfg.DBName,
-		cfg.DBHost,
+	attributeDefinitionRepository := dao.NewAttributeDefinitionRepository(postgresConnection)
+	groupRepository := dao.NewGroupRepository(postgresConnection)
+	userService := user.NewService(
+		userRepository,
+		attributeDefinitionRepository,
+		groupRepository,
+		dao.NewUserStatusHistoryRepository(postgresConnection),
+		transactionProvider,
 	)
 
+	userNotifier, err := notifier.NewNotifier(cfg.Notifier, cfg.NotifierFilePath)
 	if err != nil {
-		log.Fatalf("impossible to establish connection to %s database: %+v", cfg.DBType, err)
+		log.Fatalf("impossible to create notifier: %+v", err)
 	}
+	oneTimeTokenRepository := dao.NewOneTimeTokenRepository(postgresConnection)
+	emailVerificationService := user.NewEmailVerificationService(
+		userRepository,
+		transactionProvider,
+		oneTimeTokenRepository,
+		userNotifier,
+		cfg.EmailVerificationTokenTTL,
+	)
 
-	userService := user.NewService(
-		dao.NewUserRepository(postgresConnection),
+	// authentication reads users from primary without cache, so suspended or locked users can't pass stale status checks
+	authUserRepository := dao.NewUserRepository(postgresConnection)
+	mfaRepository := dao.NewMFARepository(postgresConnection)
+	totp := auth.NewTOTP(cfg.MFAIssuer, cfg.MFATOTPSkew)
+	tokenIssuer := auth.NewTokenIssuer(tokenSecret(cfg), cfg.AuthTokenIssuer, cfg.AuthTokenTTL)
+	authService := user.NewAuthService(
+		authUserRepository,
+		dao.NewCredentialRepository(postgresConnection),
+		oneTimeTokenRepository,
+		mfaRepository,
+		transactionProvider,
+		userNotifier,
+		auth.NewPasswordHasher(cfg.PasswordHashParams()),
+		tokenIssuer,
+		totp,
+		cfg.AuthMaxFailedAttempts,
+		cfg.PasswordResetTokenTTL,
+		cfg.MFAChallengeTTL,
+		cfg.SessionTTL,
 	)
 
+	webhookRepository := dao.NewWebhookRepository(postgresConnection)
+	webhookDeliveryRepository := dao.NewWebhookDeliveryRepository(postgresConnection)
+
+	var sinks outbox.MultiSink
+	if cfg.OutboxSink != outbox.SinkNone {
+		sink, err := outbox.NewSink(cfg.OutboxSinkSettings())
+		if err != nil {
+			log.Fatalf("impossible to create outbox sink: %+v", err)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.WebhookEnabled {
+		dispatcher := webhook.NewDispatcher(
+			webhookRepository,
+			webhookDeliveryRepository,
+			&http.Client{Timeout: cfg.WebhookTimeout},
+			cfg.WebhookBatchSize,
+			cfg.WebhookMaxAttempts,
+			cfg.WebhookPollInterval,
+			cfg.WebhookRetryBackoff,
+			cfg.WebhookMaxBackoff,
+		)
+		sinks = append(sinks, dispatcher)
+		go dispatcher.Run(context.Background())
+	}
+
+	if len(sinks) > 0 {
+		relay := outbox.NewRelay(
+			dao.NewOutboxRepository(postgresConnection),
+			sinks,
+			cfg.OutboxBatchSize,
+			cfg.OutboxPollInterval,
+			cfg.OutboxRetryBackoff,
+			cfg.OutboxMaxBackoff,
+		)
+		go relay.Run(context.Background())
+	}
+
+	idempotencyService := idempotency.NewService(
+		dao.NewIdempotencyKeyRepository(postgresConnection),
+		cfg.IdempotencyKeyTTL,
+	)
+	go idempotencyService.RunCleanup(context.Background(), cfg.IdempotencyKeyCleanupInterval)
+
 	router := app.NewRouter(cfg, controller.New(
 		userService,
-	))
+		webhook.NewService(webhookRepository, webhookDeliveryRepository),
+		changefeed.NewService(
+			dao.NewReplicaUserChangeRepository(postgresConnection, connectionRouter),
+			cfg.ChangeFeedPollInterval,
+		),
+		emailVerificationService,
+		user.NewAddressService(userRepository, dao.NewAddressRepository(postgresConnection), transactionProvider),
+		user.NewAttributeDefinitionService(attributeDefinitionRepository),
+		user.NewGroupService(groupRepository, userRepository),
+		authService,
+		user.NewMFAService(
+			authUserRepository,
+			mfaRepository,
+			transactionProvider,
+			totp,
+			cfg.MFARecoveryCodes,
+			cfg.AuthMaxFailedAttempts,
+		),
+		user.NewSessionService(
+			authUserRepository,
+			dao.NewSessionRepository(postgresConnection),
+			transactionProvider,
+			tokenIssuer,
+			cfg.SessionTTL,
+		),
+	), idempotencyService, userCache)
 	router.Run()
 }
+
+// setLogLevel applies log level from config, it is called again when config is reloaded.
+func setLogLevel(cfg *config.Config) {
+	logLevel, err := log.ParseLevel(strings.ToLower(cfg.LogLevel))
+	if err != nil {
+		log.WithFields(log.Fields{
+			"err": err,
+		}).Error("unsupported log level")
+		return
+	}
+
+	log.SetLevel(logLevel)
+}
+
+// tokenSecret returns key which signs access tokens. Random key is generated when it is not configured,
+// so tokens are invalidated on restart and they can't be verified by other instances.
+func tokenSecret(cfg *config.Config) []byte {
+	if cfg.AuthTokenSecret != "" {
+		return []byte(cfg.AuthTokenSecret)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalf("impossible to generate token secret: %+v", err)
+	}
+	log.Warn("AUTH_TOKEN_SECRET is not set, access tokens are signed with random key")
+
+	return secret
+}
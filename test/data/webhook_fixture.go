@@ -0,0 +1,19 @@
+package data
+
+import (
+	"github.com/mmgopher/user-service/app/api/request"
+)
+
+var (
+	// CreateWebhookRequest is a request for POST /v1/webhooks endpoint.
+	CreateWebhookRequest = request.CreateWebhook{
+		URL:        "https://example.com/hooks/users",
+		EventTypes: []string{"user.created", "user.deleted"},
+	}
+
+	// UpdateWebhookRequest is a request for PUT /v1/webhooks/:webhook_id endpoint.
+	UpdateWebhookRequest = request.UpdateWebhook{
+		URL:        "https://example.com/hooks/all-users",
+		EventTypes: []string{"user.*"},
+	}
+)
@@ -0,0 +1,188 @@
+package helpers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/db"
+)
+
+// Environment variables with connection strings of databases used by repository tests.
+// Tests against a database are skipped when its variable is not set, SQLite is always tested.
+const (
+	TestPostgresDSNEnv = "TEST_POSTGRES_DSN"
+	TestMySQLDSNEnv    = "TEST_MYSQL_DSN"
+)
+
+// ForEachDatabase runs test against every supported database with freshly migrated schema.
+func ForEachDatabase(t *testing.T, test func(t *testing.T, connection *sqlx.DB)) {
+	t.Run(db.SQLiteType, func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "user-service")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		connection, err := db.GetConnection(db.Settings{Type: db.SQLiteType, Name: filepath.Join(dir, "test.db")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer connection.Close()
+
+		runOnMigratedDatabase(t, connection, test)
+	})
+
+	t.Run(db.PostgresType, func(t *testing.T) {
+		dsn := os.Getenv(TestPostgresDSNEnv)
+		if dsn == "" {
+			t.Skipf("%s is not set", TestPostgresDSNEnv)
+		}
+
+		connection, err := sqlx.Connect(db.PostgresType, dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer connection.Close()
+
+		if _, err := connection.Exec(`DROP SCHEMA IF EXISTS user_sch CASCADE; CREATE SCHEMA user_sch`); err != nil {
+			t.Fatal(err)
+		}
+
+		runOnMigratedDatabase(t, connection, test)
+	})
+
+	t.Run(db.MySQLType, func(t *testing.T) {
+		dsn := os.Getenv(TestMySQLDSNEnv)
+		if dsn == "" {
+			t.Skipf("%s is not set", TestMySQLDSNEnv)
+		}
+
+		cfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.ParseTime = true
+		cfg.MultiStatements = true
+		cfg.ClientFoundRows = true
+
+		connection, err := sqlx.Connect(db.MySQLType, cfg.FormatDSN())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer connection.Close()
+
+		var tables []string
+		if err := connection.Select(&tables, `SHOW TABLES`); err != nil {
+			t.Fatal(err)
+		}
+		if len(tables) > 0 {
+			if _, err := connection.Exec(
+				"SET FOREIGN_KEY_CHECKS = 0; DROP TABLE `" + strings.Join(tables, "`, `") + "`; SET FOREIGN_KEY_CHECKS = 1",
+			); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		runOnMigratedDatabase(t, connection, test)
+	})
+}
+
+func runOnMigratedDatabase(t *testing.T, connection *sqlx.DB, test func(t *testing.T, connection *sqlx.DB)) {
+	if err := ApplyMigrations(connection, MigrationsDir(connection.DriverName())); err != nil {
+		t.Fatal(err)
+	}
+
+	test(t, connection)
+}
+
+// MigrationsDir returns directory with goose migrations of the database driver.
+func MigrationsDir(driverName string) string {
+	_, file, _, _ := runtime.Caller(0)
+	dir := driverName
+	if driverName == db.SQLiteType {
+		dir = "sqlite"
+	}
+
+	return filepath.Join(filepath.Dir(file), "..", "..", "build", dir, "migrations")
+}
+
+// ApplyMigrations executes Up sections of all goose migrations from the directory.
+// Statements between StatementBegin and StatementEnd annotations are executed at once,
+// other statements are split on semicolons at the end of line like goose does.
+func ApplyMigrations(connection *sqlx.DB, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return errors.Wrap(err, "impossible to list migrations")
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return errors.Wrapf(err, "impossible to read migration %s", file)
+		}
+
+		for _, statement := range upStatements(string(content)) {
+			if _, err := connection.Exec(statement); err != nil {
+				return errors.Wrapf(err, "impossible to apply migration %s", file)
+			}
+		}
+	}
+
+	return nil
+}
+
+func upStatements(migration string) []string {
+	var statements []string
+	var current strings.Builder
+	up := false
+	inBlock := false
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for _, line := range strings.Split(migration, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "-- +goose Up"):
+			up = true
+			continue
+		case strings.HasPrefix(trimmed, "-- +goose Down"):
+			flush()
+			return statements
+		case strings.HasPrefix(trimmed, "-- +goose StatementBegin"):
+			inBlock = true
+			continue
+		case strings.HasPrefix(trimmed, "-- +goose StatementEnd"):
+			inBlock = false
+			flush()
+			continue
+		}
+
+		if !up || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+
+	flush()
+	return statements
+}
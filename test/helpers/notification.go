@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/mmgopher/user-service/app/service/notifier"
+)
+
+// NotifierFilePathEnv is environment variable with path of the file written by `file` notifier of tested service.
+const NotifierFilePathEnv = "NOTIFIER_FILE_PATH"
+
+// LastNotificationToken returns token of the last notification of the type delivered to the recipient.
+func LastNotificationToken(notificationType, recipient string) (string, error) {
+	file, err := os.Open(os.Getenv(NotifierFilePathEnv))
+	if err != nil {
+		return "", errors.Wrap(err, "impossible to open notifications file")
+	}
+	//nolint
+	defer file.Close()
+
+	var token string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var notification notifier.Notification
+		if err := json.Unmarshal(scanner.Bytes(), &notification); err != nil {
+			return "", errors.Wrap(err, "impossible to decode notification")
+		}
+
+		if notification.Type == notificationType && notification.Recipient == recipient {
+			token = notification.Token
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", errors.Wrap(err, "impossible to read notifications file")
+	}
+
+	if token == "" {
+		return "", errors.Errorf("no %s notification delivered to %s", notificationType, recipient)
+	}
+
+	return token, nil
+}
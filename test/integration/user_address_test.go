@@ -0,0 +1,164 @@
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// TestAddresses makes test of /v1/users/:user_id/addresses routes
+// and filtering of GET /v1/users by city and country of addresses.
+func TestAddresses(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	surname := fmt.Sprintf("Address%d", time.Now().UnixNano())
+	createRequest, err := json.Marshal(request.CreateUser{
+		Name:        "Address",
+		Surname:     surname,
+		Gender:      "female",
+		DateOfBirth: "1990-05-14",
+		Address:     "legacy address",
+	})
+	require.Nil(t, err)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.CreateUserRoute,
+		nil,
+		nil,
+		createRequest,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	var created response.CreateUser
+	require.Nil(t, json.Unmarshal(respBody, &created))
+
+	addressesURL := helpers.StrReplace(os.Getenv("APP_BASE_URL")+app.RootPath+app.CreateAddressRoute,
+		":user_id", created.ID)
+	city := fmt.Sprintf("City%d", time.Now().UnixNano())
+
+	invalidRequest, err := json.Marshal(request.CreateAddress{Type: "work", Country: "POL"})
+	require.Nil(t, err)
+	statusCode, respBody, err = httpService.DoRequest(http.MethodPost, addressesURL, nil, nil, invalidRequest)
+	require.Nil(t, err)
+	var details httperrors.Details
+	details.Add("/type", httperrors.AddressTypeNotSupported("work"))
+	details.Add("/street", httperrors.AddressStreetEmpty)
+	details.Add("/city", httperrors.AddressCityEmpty)
+	details.Add("/country", httperrors.AddressCountryIncorrect)
+	expectedErr := httperrors.RequestValidationFailed.WithDetails(details...)
+	assert.Equal(t, expectedErr.HTTPCode, statusCode)
+	assert.JSONEq(t, expectedErr.Error(), string(respBody))
+
+	homeRequest, err := json.Marshal(request.CreateAddress{
+		Type:       "home",
+		Street:     "Marszałkowska 1",
+		City:       city,
+		PostalCode: "00-001",
+		Country:    "pl",
+	})
+	require.Nil(t, err)
+	statusCode, respBody, err = httpService.DoRequest(http.MethodPost, addressesURL, nil, nil, homeRequest)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	var home response.CreateAddress
+	require.Nil(t, json.Unmarshal(respBody, &home))
+
+	shippingRequest, err := json.Marshal(request.CreateAddress{
+		Type:    "shipping",
+		Street:  "Unter den Linden 1",
+		City:    "Berlin",
+		Country: "DE",
+	})
+	require.Nil(t, err)
+	statusCode, respBody, err = httpService.DoRequest(http.MethodPost, addressesURL, nil, nil, shippingRequest)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	var shipping response.CreateAddress
+	require.Nil(t, json.Unmarshal(respBody, &shipping))
+
+	statusCode, respBody, err = httpService.DoRequest(http.MethodGet, addressesURL, nil, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	var addresses response.AddressList
+	require.Nil(t, json.Unmarshal(respBody, &addresses))
+	require.Len(t, addresses.Result, 2)
+	assert.Equal(t, home.ID, addresses.Result[0].ID)
+	assert.Equal(t, "PL", addresses.Result[0].Country)
+	assert.True(t, addresses.Result[0].Primary)
+	assert.False(t, addresses.Result[1].Primary)
+
+	userURL := helpers.StrReplace(os.Getenv("APP_BASE_URL")+app.RootPath+app.GetUserRoute, ":user_id", created.ID)
+	statusCode, respBody, err = httpService.DoRequest(http.MethodGet, userURL, nil, nil, nil)
+	require.Nil(t, err)
+	var user response.User
+	require.Nil(t, json.Unmarshal(respBody, &user))
+	assert.Equal(t, "Marszałkowska 1, 00-001 "+city+", PL", user.Address)
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.GetUserListRoute,
+		map[string]string{"city": city, "country": "pl"},
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	var users response.UserListWithPagination
+	require.Nil(t, json.Unmarshal(respBody, &users))
+	require.Len(t, users.Result, 1)
+	assert.Equal(t, created.ID, users.Result[0].ID)
+
+	addressURL := helpers.StrReplace(os.Getenv("APP_BASE_URL")+app.RootPath+app.DeleteAddressRoute,
+		":user_id", created.ID)
+	statusCode, _, err = httpService.DoRequest(
+		http.MethodDelete,
+		helpers.StrReplace(addressURL, ":address_id", home.ID),
+		nil,
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		helpers.StrReplace(addressURL, ":address_id", shipping.ID),
+		nil,
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	var address response.Address
+	require.Nil(t, json.Unmarshal(respBody, &address))
+	assert.True(t, address.Primary)
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		helpers.StrReplace(addressURL, ":address_id", home.ID),
+		nil,
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, httperrors.EntityNotFoundError("address").HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.EntityNotFoundError("address").Error(), string(respBody))
+
+	statusCode, respBody, err = httpService.DoRequest(http.MethodGet, userURL, nil, nil, nil)
+	require.Nil(t, err)
+	require.Nil(t, json.Unmarshal(respBody, &user))
+	assert.Equal(t, "Unter den Linden 1, Berlin, DE", user.Address)
+}
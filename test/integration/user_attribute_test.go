@@ -0,0 +1,136 @@
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// TestUserAttributes makes test of /v1/admin/attribute-definitions routes
+// and filtering of GET /v1/users by custom attributes.
+func TestUserAttributes(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	definitionsURL := os.Getenv("APP_BASE_URL") + app.RootPath + app.CreateAttributeDefinitionRoute
+	name := fmt.Sprintf("department_%d", time.Now().UnixNano())
+
+	invalidRequest, err := json.Marshal(request.CreateAttributeDefinition{Name: "Department", Type: "date"})
+	require.Nil(t, err)
+	statusCode, respBody, err := httpService.DoRequest(http.MethodPost, definitionsURL, nil, nil, invalidRequest)
+	require.Nil(t, err)
+	var details httperrors.Details
+	details.Add("/name", httperrors.AttributeNameIncorrect)
+	details.Add("/type", httperrors.AttributeTypeNotSupported("date"))
+	expectedErr := httperrors.RequestValidationFailed.WithDetails(details...)
+	assert.Equal(t, expectedErr.HTTPCode, statusCode)
+	assert.JSONEq(t, expectedErr.Error(), string(respBody))
+
+	createRequest, err := json.Marshal(request.CreateAttributeDefinition{
+		Name: name,
+		Type: "string",
+		Enum: []string{"sales", "support"},
+	})
+	require.Nil(t, err)
+	statusCode, respBody, err = httpService.DoRequest(http.MethodPost, definitionsURL, nil, nil, createRequest)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	var definition response.CreateAttributeDefinition
+	require.Nil(t, json.Unmarshal(respBody, &definition))
+
+	statusCode, _, err = httpService.DoRequest(http.MethodPost, definitionsURL, nil, nil, createRequest)
+	require.Nil(t, err)
+	assert.Equal(t, httperrors.AttributeDefinitionAlreadyExists.HTTPCode, statusCode)
+
+	userIDs := make([]int, 0, 2)
+	for i, department := range []string{"sales", "support"} {
+		userRequest, err := json.Marshal(request.CreateUser{
+			Name:        "Attribute",
+			Surname:     fmt.Sprintf("%s%d", name, i),
+			Gender:      "female",
+			DateOfBirth: "1990-05-14",
+			Address:     "address",
+			Attributes:  map[string]interface{}{name: department},
+		})
+		require.Nil(t, err)
+		statusCode, respBody, err = httpService.DoRequest(
+			http.MethodPost,
+			os.Getenv("APP_BASE_URL")+app.RootPath+app.CreateUserRoute,
+			nil,
+			nil,
+			userRequest,
+		)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusCreated, statusCode)
+		var created response.CreateUser
+		require.Nil(t, json.Unmarshal(respBody, &created))
+		userIDs = append(userIDs, created.ID)
+	}
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.GetUserListRoute,
+		map[string]string{"attr." + name: "sales"},
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	var users response.UserListWithPagination
+	require.Nil(t, json.Unmarshal(respBody, &users))
+	require.Len(t, users.Result, 1)
+	assert.Equal(t, userIDs[0], users.Result[0].ID)
+	assert.Equal(t, "sales", users.Result[0].Attributes[name])
+
+	definitionURL := helpers.StrReplace(
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.DeleteAttributeDefinitionRoute,
+		":definition_id",
+		definition.ID,
+	)
+	statusCode, respBody, err = httpService.DoRequest(http.MethodDelete, definitionURL, nil, nil, nil)
+	require.Nil(t, err)
+	assert.Equal(t, httperrors.AttributeDefinitionInUse.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.AttributeDefinitionInUse.Error(), string(respBody))
+
+	for _, userID := range userIDs {
+		statusCode, _, err = httpService.DoRequest(
+			http.MethodDelete,
+			os.Getenv("APP_BASE_URL")+app.RootPath+helpers.StrReplace(app.DeleteUserRoute, ":user_id", userID),
+			nil,
+			nil,
+			nil,
+		)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, statusCode)
+	}
+
+	statusCode, _, err = httpService.DoRequest(http.MethodDelete, definitionURL, nil, nil, nil)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.GetUserListRoute,
+		map[string]string{"attr." + name: "sales"},
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	details = nil
+	details.Add("/attr."+name, httperrors.AttributeUnknown(name))
+	expectedErr = httperrors.RequestValidationFailed.WithDetails(details...)
+	assert.Equal(t, expectedErr.HTTPCode, statusCode)
+	assert.JSONEq(t, expectedErr.Error(), string(respBody))
+}
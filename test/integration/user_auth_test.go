@@ -0,0 +1,211 @@
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/service/notifier"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// TestUserAuth makes test of POST /v1/auth/login and POST /v1/users/:user_id/password routes.
+// It expects default AUTH_MAX_FAILED_ATTEMPTS.
+func TestUserAuth(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	baseURL := os.Getenv("APP_BASE_URL") + app.RootPath
+	suffix := time.Now().UnixNano()
+	email := fmt.Sprintf("auth_%d@example.com", suffix)
+
+	userRequest, err := json.Marshal(request.CreateUser{
+		Name:        "Auth",
+		Surname:     fmt.Sprintf("User%d", suffix),
+		Gender:      "female",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Email:       email,
+	})
+	require.Nil(t, err)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		baseURL+app.CreateUserRoute,
+		nil,
+		nil,
+		userRequest,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	var created response.CreateUser
+	require.Nil(t, json.Unmarshal(respBody, &created))
+
+	post := func(route string, body interface{}) (int, []byte) {
+		requestBody, err := json.Marshal(body)
+		require.Nil(t, err)
+		statusCode, respBody, err := httpService.DoRequest(
+			http.MethodPost,
+			baseURL+helpers.StrReplace(route, ":user_id", created.ID),
+			nil,
+			nil,
+			requestBody,
+		)
+		require.Nil(t, err)
+		return statusCode, respBody
+	}
+	login := func(password string) (int, []byte) {
+		return post(app.LoginRoute, request.Login{Email: email, Password: password})
+	}
+
+	statusCode, respBody = login("correct horse 1")
+	assert.Equal(t, httperrors.AuthInvalidCredentials.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.AuthInvalidCredentials.Error(), string(respBody))
+
+	// the first password can be set only with password reset
+	statusCode, respBody = post(app.ChangePasswordRoute, request.ChangePassword{NewPassword: "correct horse 1"})
+	assert.Equal(t, httperrors.PasswordNotSet.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.PasswordNotSet.Error(), string(respBody))
+	setPassword(t, httpService, baseURL, email, "correct horse 1")
+
+	statusCode, respBody = login("correct horse 1")
+	expectedErr := httperrors.AuthUserNotActive("pending")
+	assert.Equal(t, expectedErr.HTTPCode, statusCode)
+	assert.JSONEq(t, expectedErr.Error(), string(respBody))
+
+	statusCode, _ = post(app.ActivateUserRoute, request.ChangeUserStatus{Reason: "identity checked"})
+	require.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, respBody = login("correct horse 1")
+	require.Equal(t, http.StatusOK, statusCode)
+	var token response.Token
+	require.Nil(t, json.Unmarshal(respBody, &token))
+	assert.NotEmpty(t, token.AccessToken)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.True(t, token.ExpiresAt.After(time.Now()))
+
+	statusCode, respBody = post(app.ChangePasswordRoute, request.ChangePassword{
+		CurrentPassword: "wrong password",
+		NewPassword:     "battery staple 2",
+	})
+	assert.Equal(t, httperrors.PasswordCurrentIncorrect.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.PasswordCurrentIncorrect.Error(), string(respBody))
+
+	// wrong current password counts as failed attempt, so the user is locked after 4 failed logins
+	for i := 0; i < 4; i++ {
+		statusCode, _ = login("wrong password")
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+	}
+
+	statusCode, respBody = login("correct horse 1")
+	expectedErr = httperrors.AuthUserNotActive("locked")
+	assert.Equal(t, expectedErr.HTTPCode, statusCode)
+	assert.JSONEq(t, expectedErr.Error(), string(respBody))
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		baseURL+helpers.StrReplace(app.GetUserStatusHistoryRoute, ":user_id", created.ID),
+		nil,
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	var history response.UserStatusHistory
+	require.Nil(t, json.Unmarshal(respBody, &history))
+	require.Len(t, history.Result, 2)
+	assert.Equal(t, "locked", history.Result[1].ToStatus)
+	assert.Equal(t, "too many failed login attempts", history.Result[1].Reason)
+}
+
+// TestPasswordReset makes test of POST /v1/auth/password-reset and POST /v1/auth/password-reset/confirm routes.
+// Token is delivered by notifier, so only invalid tokens can be confirmed here.
+func TestPasswordReset(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	baseURL := os.Getenv("APP_BASE_URL") + app.RootPath
+	suffix := time.Now().UnixNano()
+	email := fmt.Sprintf("reset_%d@example.com", suffix)
+
+	userRequest, err := json.Marshal(request.CreateUser{
+		Name:        "Reset",
+		Surname:     fmt.Sprintf("User%d", suffix),
+		Gender:      "female",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Email:       email,
+	})
+	require.Nil(t, err)
+	statusCode, _, err := httpService.DoRequest(
+		http.MethodPost,
+		baseURL+app.CreateUserRoute,
+		nil,
+		nil,
+		userRequest,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+
+	post := func(route string, body interface{}) (int, []byte) {
+		requestBody, err := json.Marshal(body)
+		require.Nil(t, err)
+		statusCode, respBody, err := httpService.DoRequest(
+			http.MethodPost,
+			baseURL+route,
+			nil,
+			nil,
+			requestBody,
+		)
+		require.Nil(t, err)
+		return statusCode, respBody
+	}
+
+	// response for existing and unknown email is the same
+	for _, resetEmail := range []string{email, "unknown_" + email} {
+		statusCode, respBody := post(app.PasswordResetRoute, request.RequestPasswordReset{Email: resetEmail})
+		assert.Equal(t, http.StatusAccepted, statusCode)
+		assert.JSONEq(t, `{}`, string(respBody))
+	}
+
+	statusCode, respBody := post(app.ConfirmPasswordResetRoute, request.ConfirmPasswordReset{
+		Token:       "unknown",
+		NewPassword: "battery staple 2",
+	})
+	assert.Equal(t, httperrors.PasswordResetTokenIncorrect.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.PasswordResetTokenIncorrect.Error(), string(respBody))
+
+	var details httperrors.Details
+	details.Add("/token", httperrors.PasswordResetTokenEmpty)
+	details.Add("/new_password", httperrors.PasswordTooShort(10))
+	expectedErr := httperrors.RequestValidationFailed.WithDetails(details...)
+	statusCode, respBody = post(app.ConfirmPasswordResetRoute, request.ConfirmPasswordReset{NewPassword: "short"})
+	assert.Equal(t, expectedErr.HTTPCode, statusCode)
+	assert.JSONEq(t, expectedErr.Error(), string(respBody))
+}
+
+// setPassword sets password of the user with the email through password reset flow.
+func setPassword(t *testing.T, httpService helpers.HTTPProvider, baseURL, email, password string) {
+	post := func(route string, body interface{}) int {
+		requestBody, err := json.Marshal(body)
+		require.Nil(t, err)
+		statusCode, _, err := httpService.DoRequest(http.MethodPost, baseURL+route, nil, nil, requestBody)
+		require.Nil(t, err)
+		return statusCode
+	}
+
+	require.Equal(t, http.StatusAccepted, post(app.PasswordResetRoute, request.RequestPasswordReset{Email: email}))
+	token, err := helpers.LastNotificationToken(notifier.NotificationPasswordReset, email)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, post(app.ConfirmPasswordResetRoute, request.ConfirmPasswordReset{
+		Token:       token,
+		NewPassword: password,
+	}))
+}
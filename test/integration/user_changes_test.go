@@ -0,0 +1,87 @@
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// TestGetUserChangesOK makes test of GET /v1/users/changes
+func TestGetUserChangesOK(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	changesURL := os.Getenv("APP_BASE_URL") + app.RootPath + app.GetUserChanges
+
+	// Read the feed to the end to get the latest token
+	var token string
+	for {
+		statusCode, respBody, err := httpService.DoRequest(
+			http.MethodGet, changesURL, map[string]string{"since": token, "limit": "1000"}, nil, nil,
+		)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, statusCode)
+		var changes response.UserChangeList
+		require.Nil(t, json.Unmarshal(respBody, &changes))
+		token = changes.NextToken
+		if len(changes.Result) == 0 {
+			break
+		}
+	}
+
+	createRequest, err := json.Marshal(request.CreateUser{
+		Name:        "Change",
+		Surname:     "Feed",
+		Gender:      "female",
+		DateOfBirth: "1987-05-14",
+		Address:     "London 1 Feed Street",
+	})
+	require.Nil(t, err)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.CreateUserRoute,
+		nil,
+		nil,
+		createRequest,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	var created response.CreateUser
+	require.Nil(t, json.Unmarshal(respBody, &created))
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet, changesURL, map[string]string{"since": token, "wait": "5s"}, nil, nil,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	var changes response.UserChangeList
+	require.Nil(t, json.Unmarshal(respBody, &changes))
+	require.Len(t, changes.Result, 1)
+	assert.Equal(t, "user.created", changes.Result[0].Type)
+	assert.Equal(t, created.ID, changes.Result[0].UserID)
+	require.NotNil(t, changes.Result[0].User)
+	assert.Equal(t, "Change", changes.Result[0].User.Name)
+	assert.Equal(t, changes.Result[0].Token, changes.NextToken)
+}
+
+func TestGetUserChangesIncorrectToken(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	statusCode, _, err := httpService.DoRequest(
+		http.MethodGet,
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.GetUserChanges,
+		map[string]string{"since": "incorrect"},
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
@@ -56,10 +56,44 @@ func TestCreateUserOK(t *testing.T) {
 	assert.Equal(t, http.StatusOK, statusCode)
 	assert.Equal(t, data.CreateUserRequest.Name, user.Name)
 	assert.Equal(t, data.CreateUserRequest.Surname, user.Surname)
-	assert.Equal(t, data.CreateUserRequest.Age, user.Age)
+	assert.Equal(t, data.CreateUserRequest.DateOfBirth, user.DateOfBirth)
 	assert.Equal(t, data.CreateUserRequest.Gender, user.Gender)
 }
 
+// TestCreateUserWithDeprecatedAge makes test of POST /v1/users sent by clients which still send `age`
+func TestCreateUserWithDeprecatedAge(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.CreateUserRoute,
+		nil,
+		nil,
+		[]byte(`{"name":"Deprecated","surname":"Age","gender":"female","age":30,"address":"address"}`),
+	)
+	require.Nil(t, err)
+
+	var target response.CreateUser
+	assert.Nil(t, json.Unmarshal(respBody, &target))
+	require.Equal(t, http.StatusCreated, statusCode)
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		helpers.StrReplace(
+			os.Getenv("APP_BASE_URL")+app.RootPath+app.GetUserRoute,
+			":user_id",
+			target.ID,
+		),
+		nil,
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	var user response.User
+	assert.Nil(t, json.Unmarshal(respBody, &user))
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 30, user.Age)
+}
+
 func TestCreateUserError(t *testing.T) {
 
 	var testData = []struct {
@@ -70,49 +104,98 @@ func TestCreateUserError(t *testing.T) {
 		{
 			testName: "EmptyName",
 			request: request.CreateUser{
-				Surname: "test",
-				Age:     23,
-				Gender:  "male",
+				Surname:     "test",
+				DateOfBirth: "1997-05-14",
+				Gender:      "male",
+				Address:     "address",
 			},
-			expectedError: httperrors.UserNameEmpty,
+			expectedError: validationFailed(
+				httperrors.Detail{Field: "/name", Code: httperrors.UserNameEmpty.Code, Message: httperrors.UserNameEmpty.Message},
+			),
 		},
 		{
 			testName: "EmptySurname",
 			request: request.CreateUser{
-				Name:   "test",
-				Age:    23,
-				Gender: "male",
+				Name:        "test",
+				DateOfBirth: "1997-05-14",
+				Gender:      "male",
+				Address:     "address",
 			},
-			expectedError: httperrors.UserSurnameEmpty,
+			expectedError: validationFailed(
+				httperrors.Detail{Field: "/surname", Code: httperrors.UserSurnameEmpty.Code, Message: httperrors.UserSurnameEmpty.Message},
+			),
 		},
 		{
-			testName: "IncorrectAge",
+			testName: "IncorrectDateOfBirth",
 			request: request.CreateUser{
-				Name:    "test",
-				Surname: "test",
-				Age:     -1,
-				Gender:  "male",
+				Name:        "test",
+				Surname:     "test",
+				DateOfBirth: "1990-02-30",
+				Gender:      "male",
+				Address:     "address",
 			},
-			expectedError: httperrors.UserAgeIncorrect,
+			expectedError: validationFailed(httperrors.Detail{
+				Field:   "/date_of_birth",
+				Code:    httperrors.UserDateOfBirthIncorrect.Code,
+				Message: httperrors.UserDateOfBirthIncorrect.Message,
+			}),
 		},
 		{
 			testName: "NotSupportedGender",
 			request: request.CreateUser{
-				Name:    "test",
-				Surname: "test",
-				Age:     23,
-				Gender:  "other",
+				Name:        "test",
+				Surname:     "test",
+				DateOfBirth: "1997-05-14",
+				Gender:      "other",
+				Address:     "address",
 			},
-			expectedError: httperrors.UserGenderNotSupported("other"),
+			expectedError: validationFailed(httperrors.Detail{
+				Field:   "/gender",
+				Code:    httperrors.UserGenderNotSupported("other").Code,
+				Message: httperrors.UserGenderNotSupported("other").Message,
+			}),
+		},
+		{
+			testName: "IncorrectEmailAndPhone",
+			request: request.CreateUser{
+				Name:        "test",
+				Surname:     "test",
+				DateOfBirth: "1997-05-14",
+				Gender:      "male",
+				Address:     "address",
+				Email:       "test@",
+				Phone:       "123",
+			},
+			expectedError: validationFailed(
+				httperrors.Detail{Field: "/email", Code: httperrors.UserEmailIncorrect.Code, Message: httperrors.UserEmailIncorrect.Message},
+				httperrors.Detail{Field: "/phone", Code: httperrors.UserPhoneIncorrect.Code, Message: httperrors.UserPhoneIncorrect.Message},
+			),
+		},
+		{
+			testName: "AllFieldsIncorrect",
+			request: request.CreateUser{
+				DateOfBirth: "1890-01-01",
+				Gender:      "male",
+			},
+			expectedError: validationFailed(
+				httperrors.Detail{Field: "/name", Code: httperrors.UserNameEmpty.Code, Message: httperrors.UserNameEmpty.Message},
+				httperrors.Detail{Field: "/surname", Code: httperrors.UserSurnameEmpty.Code, Message: httperrors.UserSurnameEmpty.Message},
+				httperrors.Detail{
+					Field:   "/date_of_birth",
+					Code:    httperrors.UserDateOfBirthOutOfRange.Code,
+					Message: httperrors.UserDateOfBirthOutOfRange.Message,
+				},
+				httperrors.Detail{Field: "/address", Code: httperrors.UserAddressEmpty.Code, Message: httperrors.UserAddressEmpty.Message},
+			),
 		},
 		{
 			testName: "AlreadyRegistered",
 			request: request.CreateUser{
-				Name:    "Sonny",
-				Surname: "Watts",
-				Age:     23,
-				Gender:  "male",
-				Address: "address",
+				Name:        "Sonny",
+				Surname:     "Watts",
+				DateOfBirth: "1997-05-14",
+				Gender:      "male",
+				Address:     "address",
 			},
 			expectedError: httperrors.UserAlreadyRegistered,
 		},
@@ -136,3 +219,7 @@ func TestCreateUserError(t *testing.T) {
 		})
 	}
 }
+
+func validationFailed(details ...httperrors.Detail) *httperrors.HTTPError {
+	return httperrors.RequestValidationFailed.WithDetails(details...)
+}
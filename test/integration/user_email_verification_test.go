@@ -0,0 +1,127 @@
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// TestEmailVerification makes test of POST /v1/users/:user_id/email/verify
+// and POST /v1/users/:user_id/email/verify/confirm.
+// Token is delivered by notifier, so only invalid tokens can be confirmed here.
+func TestEmailVerification(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	email := fmt.Sprintf("verify.%d@example.com", time.Now().UnixNano())
+	createRequest, err := json.Marshal(request.CreateUser{
+		Name:        "Verify",
+		Surname:     email,
+		Gender:      "female",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Email:       email,
+		Phone:       "+48 123 456 789",
+	})
+	require.Nil(t, err)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.CreateUserRoute,
+		nil,
+		nil,
+		createRequest,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	var created response.CreateUser
+	require.Nil(t, json.Unmarshal(respBody, &created))
+
+	duplicateRequest, err := json.Marshal(request.CreateUser{
+		Name:        "Duplicate",
+		Surname:     email,
+		Gender:      "female",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Email:       "VERIFY" + email[len("verify"):],
+	})
+	require.Nil(t, err)
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodPost,
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.CreateUserRoute,
+		nil,
+		nil,
+		duplicateRequest,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, httperrors.UserEmailAlreadyRegistered.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.UserEmailAlreadyRegistered.Error(), string(respBody))
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		helpers.StrReplace(os.Getenv("APP_BASE_URL")+app.RootPath+app.GetUserRoute, ":user_id", created.ID),
+		nil,
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	var user response.User
+	require.Nil(t, json.Unmarshal(respBody, &user))
+	assert.Equal(t, email, user.Email)
+	assert.Equal(t, "+48123456789", user.Phone)
+	assert.False(t, user.EmailVerified)
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodPost,
+		helpers.StrReplace(os.Getenv("APP_BASE_URL")+app.RootPath+app.RequestEmailVerificationRoute,
+			":user_id", created.ID),
+		nil,
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusAccepted, statusCode)
+	var verification response.EmailVerification
+	require.Nil(t, json.Unmarshal(respBody, &verification))
+	assert.True(t, verification.ExpiresAt.After(time.Now()))
+
+	confirmRequest, err := json.Marshal(request.ConfirmEmailVerification{Token: "invalid"})
+	require.Nil(t, err)
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodPost,
+		helpers.StrReplace(os.Getenv("APP_BASE_URL")+app.RootPath+app.ConfirmEmailVerificationRoute,
+			":user_id", created.ID),
+		nil,
+		nil,
+		confirmRequest,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, httperrors.EmailVerificationTokenIncorrect.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.EmailVerificationTokenIncorrect.Error(), string(respBody))
+}
+
+// TestEmailVerificationEmailMissing makes test of POST /v1/users/:user_id/email/verify for user without email.
+func TestEmailVerificationEmailMissing(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		helpers.StrReplace(os.Getenv("APP_BASE_URL")+app.RootPath+app.RequestEmailVerificationRoute, ":user_id", 1),
+		nil,
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, httperrors.EmailVerificationEmailMissing.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.EmailVerificationEmailMissing.Error(), string(respBody))
+}
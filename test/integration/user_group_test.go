@@ -0,0 +1,169 @@
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// TestUserGroups makes test of /v1/groups routes and filtering of GET /v1/users by groups and tags.
+func TestUserGroups(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	baseURL := os.Getenv("APP_BASE_URL") + app.RootPath
+	suffix := time.Now().UnixNano()
+
+	createGroup := func(name string) int {
+		createRequest, err := json.Marshal(request.CreateGroup{Name: name})
+		require.Nil(t, err)
+		statusCode, respBody, err := httpService.DoRequest(
+			http.MethodPost,
+			baseURL+app.CreateGroupRoute,
+			nil,
+			nil,
+			createRequest,
+		)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusCreated, statusCode)
+		var group response.CreateGroup
+		require.Nil(t, json.Unmarshal(respBody, &group))
+		return group.ID
+	}
+	betaName := fmt.Sprintf("beta_%d", suffix)
+	betaID := createGroup(betaName)
+	staffID := createGroup(fmt.Sprintf("staff_%d", suffix))
+
+	duplicateRequest, err := json.Marshal(request.CreateGroup{Name: betaName})
+	require.Nil(t, err)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		baseURL+app.CreateGroupRoute,
+		nil,
+		nil,
+		duplicateRequest,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, httperrors.GroupAlreadyExists.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.GroupAlreadyExists.Error(), string(respBody))
+
+	userIDs := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		userRequest, err := json.Marshal(request.CreateUser{
+			Name:        "Group",
+			Surname:     fmt.Sprintf("Member%d", i),
+			Gender:      "female",
+			DateOfBirth: "1990-05-14",
+			Address:     "address",
+		})
+		require.Nil(t, err)
+		statusCode, respBody, err = httpService.DoRequest(
+			http.MethodPost,
+			baseURL+app.CreateUserRoute,
+			nil,
+			nil,
+			userRequest,
+		)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusCreated, statusCode)
+		var created response.CreateUser
+		require.Nil(t, json.Unmarshal(respBody, &created))
+		userIDs = append(userIDs, created.ID)
+	}
+
+	changeMembers := func(method string, groupID int, memberIDs ...int) int {
+		membersRequest, err := json.Marshal(request.GroupMembers{UserIDs: memberIDs})
+		require.Nil(t, err)
+		statusCode, respBody, err := httpService.DoRequest(
+			method,
+			baseURL+helpers.StrReplace(app.AddGroupMembersRoute, ":group_id", groupID),
+			nil,
+			nil,
+			membersRequest,
+		)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, statusCode)
+		var change response.GroupMembersChange
+		require.Nil(t, json.Unmarshal(respBody, &change))
+		return change.Count
+	}
+	assert.Equal(t, 3, changeMembers(http.MethodPost, betaID, userIDs...))
+	assert.Equal(t, 2, changeMembers(http.MethodPost, staffID, userIDs[1], userIDs[2]))
+	assert.Equal(t, 1, changeMembers(http.MethodDelete, betaID, userIDs[2]))
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		baseURL+helpers.StrReplace(app.GetGroupMemberListRoute, ":group_id", betaID),
+		map[string]string{"limit": "1"},
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	var members response.UserListWithPagination
+	require.Nil(t, json.Unmarshal(respBody, &members))
+	require.Len(t, members.Result, 1)
+	assert.Equal(t, userIDs[0], members.Result[0].ID)
+	assert.Equal(t, userIDs[0], members.Pagination.AfterID)
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		baseURL+app.GetUserListRoute,
+		map[string]string{"tag": betaName, "group": strconv.Itoa(staffID), "group_match": "all"},
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	var users response.UserListWithPagination
+	require.Nil(t, json.Unmarshal(respBody, &users))
+	require.Len(t, users.Result, 1)
+	assert.Equal(t, userIDs[1], users.Result[0].ID)
+
+	statusCode, _, err = httpService.DoRequest(
+		http.MethodDelete,
+		baseURL+helpers.StrReplace(app.DeleteGroupRoute, ":group_id", betaID),
+		nil,
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		baseURL+app.GetUserListRoute,
+		map[string]string{"tag": betaName},
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	var details httperrors.Details
+	details.Add("/tag/0", httperrors.GroupTagUnknown(betaName))
+	expectedErr := httperrors.RequestValidationFailed.WithDetails(details...)
+	assert.Equal(t, expectedErr.HTTPCode, statusCode)
+	assert.JSONEq(t, expectedErr.Error(), string(respBody))
+
+	statusCode, _, err = httpService.DoRequest(
+		http.MethodDelete,
+		baseURL+helpers.StrReplace(app.DeleteGroupRoute, ":group_id", staffID),
+		nil,
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+}
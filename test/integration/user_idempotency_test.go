@@ -0,0 +1,52 @@
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/middleware"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// TestCreateUserIdempotent makes test of POST /v1/users retried with the same Idempotency-Key
+func TestCreateUserIdempotent(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	createRequest := request.CreateUser{
+		Name:        "Idempotent",
+		Surname:     "Retry",
+		Gender:      "female",
+		DateOfBirth: "1979-05-14",
+		Address:     "Ohio 12 Elm Street",
+	}
+	body, err := json.Marshal(createRequest)
+	require.Nil(t, err)
+	headers := map[string]string{middleware.IdempotencyKeyHeader: "integration-create-user"}
+	url := os.Getenv("APP_BASE_URL") + app.RootPath + app.CreateUserRoute
+
+	statusCode, firstBody, err := httpService.DoRequest(http.MethodPost, url, nil, headers, body)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	statusCode, secondBody, err := httpService.DoRequest(http.MethodPost, url, nil, headers, body)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.JSONEq(t, string(firstBody), string(secondBody))
+
+	createRequest.DateOfBirth = "1978-02-28"
+	body, err = json.Marshal(createRequest)
+	require.Nil(t, err)
+	statusCode, respBody, err := httpService.DoRequest(http.MethodPost, url, nil, headers, body)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, statusCode)
+	assert.JSONEq(t, httperrors.IdempotencyKeyReused.Error(), string(respBody))
+}
@@ -184,6 +184,23 @@ var getUserListData = []struct {
 		"",
 		0,
 	},
+	{
+		"SortAscByDateOfBirthPage1FilterNameFilterAge",
+		3,
+		map[string]string{
+			"limit":   "4",
+			"name":    "sorttest",
+			"sort":    "date_of_birth:asc",
+			"min_age": "23",
+			"max_age": "31",
+		},
+		13,
+		8,
+		"",
+		0,
+		"",
+		0,
+	},
 	{
 		"SortAscByAgePage1FilterNameFilterAge",
 		4,
@@ -0,0 +1,137 @@
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/app/service/auth"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// TestUserMFA makes test of POST /v1/users/:user_id/mfa/* and POST /v1/auth/login/mfa routes.
+// TOTP code is used only once, because codes of the same time step are rejected as replayed.
+func TestUserMFA(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	baseURL := os.Getenv("APP_BASE_URL") + app.RootPath
+	suffix := time.Now().UnixNano()
+	email := fmt.Sprintf("mfa_%d@example.com", suffix)
+
+	userRequest, err := json.Marshal(request.CreateUser{
+		Name:        "MFA",
+		Surname:     fmt.Sprintf("User%d", suffix),
+		Gender:      "male",
+		DateOfBirth: "1985-03-01",
+		Address:     "address",
+		Email:       email,
+	})
+	require.Nil(t, err)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		baseURL+app.CreateUserRoute,
+		nil,
+		nil,
+		userRequest,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	var created response.CreateUser
+	require.Nil(t, json.Unmarshal(respBody, &created))
+
+	post := func(route string, body interface{}) (int, []byte) {
+		requestBody, err := json.Marshal(body)
+		require.Nil(t, err)
+		statusCode, respBody, err := httpService.DoRequest(
+			http.MethodPost,
+			baseURL+helpers.StrReplace(route, ":user_id", created.ID),
+			nil,
+			nil,
+			requestBody,
+		)
+		require.Nil(t, err)
+		return statusCode, respBody
+	}
+	login := func() (int, []byte) {
+		return post(app.LoginRoute, request.Login{Email: email, Password: "correct horse 1"})
+	}
+
+	setPassword(t, httpService, baseURL, email, "correct horse 1")
+	statusCode, _ = post(app.ActivateUserRoute, request.ChangeUserStatus{Reason: "back-office account"})
+	require.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, respBody = post(app.DisableMFARoute, request.MFACode{Code: "123456"})
+	assert.Equal(t, httperrors.MFANotEnabled.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.MFANotEnabled.Error(), string(respBody))
+
+	statusCode, respBody = post(app.EnrollMFARoute, struct{}{})
+	require.Equal(t, http.StatusOK, statusCode)
+	var enrolment response.MFAEnrolment
+	require.Nil(t, json.Unmarshal(respBody, &enrolment))
+	assert.NotEmpty(t, enrolment.QRCode)
+	assert.Contains(t, enrolment.OTPAuthURI, "secret="+enrolment.Secret)
+
+	code, err := auth.NewTOTP("", 0).Code(enrolment.Secret)
+	require.Nil(t, err)
+	statusCode, respBody = post(app.ActivateMFARoute, request.MFACode{Code: code})
+	require.Equal(t, http.StatusOK, statusCode)
+	var recovery response.MFARecoveryCodes
+	require.Nil(t, json.Unmarshal(respBody, &recovery))
+	require.Len(t, recovery.RecoveryCodes, 10)
+
+	statusCode, _ = post(app.EnrollMFARoute, struct{}{})
+	assert.Equal(t, httperrors.MFAAlreadyEnabled.HTTPCode, statusCode)
+
+	// login requires the second factor now
+	statusCode, respBody = login()
+	require.Equal(t, http.StatusAccepted, statusCode)
+	var challenge response.MFAChallenge
+	require.Nil(t, json.Unmarshal(respBody, &challenge))
+	assert.NotEmpty(t, challenge.MFAToken)
+
+	statusCode, respBody = post(app.LoginMFARoute, request.LoginMFA{MFAToken: challenge.MFAToken, Code: "wrong"})
+	assert.Equal(t, httperrors.MFALoginCodeIncorrect.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.MFALoginCodeIncorrect.Error(), string(respBody))
+
+	statusCode, respBody = post(app.LoginMFARoute, request.LoginMFA{
+		MFAToken: challenge.MFAToken,
+		Code:     recovery.RecoveryCodes[0],
+	})
+	require.Equal(t, http.StatusOK, statusCode)
+	var token response.Token
+	require.Nil(t, json.Unmarshal(respBody, &token))
+	assert.NotEmpty(t, token.AccessToken)
+
+	statusCode, respBody = post(app.LoginMFARoute, request.LoginMFA{
+		MFAToken: challenge.MFAToken,
+		Code:     recovery.RecoveryCodes[1],
+	})
+	assert.Equal(t, httperrors.MFAChallengeIncorrect.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.MFAChallengeIncorrect.Error(), string(respBody))
+
+	statusCode, respBody = post(app.RegenerateMFARecoveryCodesRoute, request.MFACode{Code: recovery.RecoveryCodes[2]})
+	require.Equal(t, http.StatusOK, statusCode)
+	var regenerated response.MFARecoveryCodes
+	require.Nil(t, json.Unmarshal(respBody, &regenerated))
+
+	statusCode, respBody = post(app.DisableMFARoute, request.MFACode{Code: recovery.RecoveryCodes[3]})
+	assert.Equal(t, httperrors.MFACodeIncorrect.HTTPCode, statusCode)
+	assert.JSONEq(t, httperrors.MFACodeIncorrect.Error(), string(respBody))
+
+	statusCode, _ = post(app.DisableMFARoute, request.MFACode{Code: regenerated.RecoveryCodes[0]})
+	require.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, _ = login()
+	assert.Equal(t, http.StatusOK, statusCode)
+}
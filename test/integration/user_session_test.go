@@ -0,0 +1,149 @@
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// TestUserSessions makes test of POST /v1/auth/refresh and /v1/users/:user_id/sessions routes.
+func TestUserSessions(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	baseURL := os.Getenv("APP_BASE_URL") + app.RootPath
+	suffix := time.Now().UnixNano()
+	email := fmt.Sprintf("session_%d@example.com", suffix)
+
+	userRequest, err := json.Marshal(request.CreateUser{
+		Name:        "Session",
+		Surname:     fmt.Sprintf("User%d", suffix),
+		Gender:      "female",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+		Email:       email,
+	})
+	require.Nil(t, err)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		baseURL+app.CreateUserRoute,
+		nil,
+		nil,
+		userRequest,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	var created response.CreateUser
+	require.Nil(t, json.Unmarshal(respBody, &created))
+
+	do := func(method, route string, body interface{}, userAgent string) (int, []byte) {
+		var requestBody []byte
+		if body != nil {
+			var err error
+			requestBody, err = json.Marshal(body)
+			require.Nil(t, err)
+		}
+		statusCode, respBody, err := httpService.DoRequest(
+			method,
+			baseURL+helpers.StrReplace(route, ":user_id", created.ID),
+			nil,
+			map[string]string{"User-Agent": userAgent},
+			requestBody,
+		)
+		require.Nil(t, err)
+		return statusCode, respBody
+	}
+	login := func(userAgent string) response.Token {
+		statusCode, respBody := do(http.MethodPost, app.LoginRoute,
+			request.Login{Email: email, Password: "correct horse 1"}, userAgent)
+		require.Equal(t, http.StatusOK, statusCode)
+		var token response.Token
+		require.Nil(t, json.Unmarshal(respBody, &token))
+		assert.NotEmpty(t, token.RefreshToken)
+		return token
+	}
+	refresh := func(refreshToken string) (int, []byte) {
+		return do(http.MethodPost, app.RefreshTokenRoute, request.RefreshToken{RefreshToken: refreshToken}, "phone/1.0")
+	}
+	sessions := func() []response.Session {
+		statusCode, respBody := do(http.MethodGet, app.GetSessionListRoute, nil, "admin/1.0")
+		require.Equal(t, http.StatusOK, statusCode)
+		var list response.SessionList
+		require.Nil(t, json.Unmarshal(respBody, &list))
+		return list.Result
+	}
+
+	setPassword(t, httpService, baseURL, email, "correct horse 1")
+	statusCode, _ = do(http.MethodPost, app.ActivateUserRoute,
+		request.ChangeUserStatus{Reason: "identity checked"}, "admin/1.0")
+	require.Equal(t, http.StatusOK, statusCode)
+
+	laptop := login("laptop/1.0")
+	phone := login("phone/1.0")
+	listed := sessions()
+	require.Len(t, listed, 2)
+	assert.Equal(t, "phone/1.0", listed[0].UserAgent)
+	assert.Equal(t, "laptop/1.0", listed[1].UserAgent)
+	assert.NotEmpty(t, listed[0].IP)
+
+	statusCode, respBody = refresh(phone.RefreshToken)
+	require.Equal(t, http.StatusOK, statusCode)
+	var refreshed response.Token
+	require.Nil(t, json.Unmarshal(respBody, &refreshed))
+	assert.NotEqual(t, phone.RefreshToken, refreshed.RefreshToken)
+
+	// reuse of rotated token revokes the whole session including the token issued by the rotation
+	for _, refreshToken := range []string{phone.RefreshToken, refreshed.RefreshToken} {
+		statusCode, respBody = refresh(refreshToken)
+		assert.Equal(t, httperrors.RefreshTokenIncorrect.HTTPCode, statusCode)
+		assert.JSONEq(t, httperrors.RefreshTokenIncorrect.Error(), string(respBody))
+	}
+
+	listed = sessions()
+	require.Len(t, listed, 1)
+	assert.Equal(t, "laptop/1.0", listed[0].UserAgent)
+
+	statusCode, _ = do(http.MethodDelete,
+		helpers.StrReplace(app.RevokeSessionRoute, ":session_id", listed[0].ID), nil, "admin/1.0")
+	require.Equal(t, http.StatusOK, statusCode)
+	statusCode, respBody = do(http.MethodDelete,
+		helpers.StrReplace(app.RevokeSessionRoute, ":session_id", listed[0].ID), nil, "admin/1.0")
+	expectedErr := httperrors.EntityNotFoundError("session")
+	assert.Equal(t, expectedErr.HTTPCode, statusCode)
+	assert.JSONEq(t, expectedErr.Error(), string(respBody))
+	statusCode, _ = refresh(laptop.RefreshToken)
+	assert.Equal(t, httperrors.RefreshTokenIncorrect.HTTPCode, statusCode)
+
+	login("laptop/1.0")
+	login("tablet/1.0")
+	statusCode, _ = do(http.MethodDelete, app.RevokeSessionsRoute, nil, "admin/1.0")
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Len(t, sessions(), 0)
+
+	// suspended user loses all sessions
+	token := login("laptop/1.0")
+	statusCode, _ = do(http.MethodPost, app.SuspendUserRoute, request.ChangeUserStatus{Reason: "chargeback"}, "admin/1.0")
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Len(t, sessions(), 0)
+	statusCode, _ = refresh(token.RefreshToken)
+	assert.Equal(t, httperrors.RefreshTokenIncorrect.HTTPCode, statusCode)
+
+	var details httperrors.Details
+	details.Add("/refresh_token", httperrors.RefreshTokenEmpty)
+	expectedErr = httperrors.RequestValidationFailed.WithDetails(details...)
+	statusCode, respBody = refresh("")
+	assert.Equal(t, expectedErr.HTTPCode, statusCode)
+	assert.JSONEq(t, expectedErr.Error(), string(respBody))
+}
@@ -0,0 +1,132 @@
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/api/request"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// TestUserStatus makes test of status transition routes, status history and filtering of GET /v1/users by status.
+func TestUserStatus(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	baseURL := os.Getenv("APP_BASE_URL") + app.RootPath
+
+	userRequest, err := json.Marshal(request.CreateUser{
+		Name:        "Status",
+		Surname:     fmt.Sprintf("User%d", time.Now().UnixNano()),
+		Gender:      "male",
+		DateOfBirth: "1990-05-14",
+		Address:     "address",
+	})
+	require.Nil(t, err)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		baseURL+app.CreateUserRoute,
+		nil,
+		nil,
+		userRequest,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	var created response.CreateUser
+	require.Nil(t, json.Unmarshal(respBody, &created))
+
+	getStatus := func() string {
+		statusCode, respBody, err := httpService.DoRequest(
+			http.MethodGet,
+			baseURL+helpers.StrReplace(app.GetUserRoute, ":user_id", created.ID),
+			nil,
+			nil,
+			nil,
+		)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, statusCode)
+		var user response.User
+		require.Nil(t, json.Unmarshal(respBody, &user))
+		return user.Status
+	}
+	assert.Equal(t, "pending", getStatus())
+
+	changeStatus := func(route, reason string) (int, []byte) {
+		changeRequest, err := json.Marshal(request.ChangeUserStatus{Reason: reason})
+		require.Nil(t, err)
+		statusCode, respBody, err := httpService.DoRequest(
+			http.MethodPost,
+			baseURL+helpers.StrReplace(route, ":user_id", created.ID),
+			nil,
+			nil,
+			changeRequest,
+		)
+		require.Nil(t, err)
+		return statusCode, respBody
+	}
+
+	statusCode, respBody = changeStatus(app.SuspendUserRoute, "chargeback")
+	expectedErr := httperrors.UserStatusTransitionNotAllowed("pending", "suspended")
+	assert.Equal(t, expectedErr.HTTPCode, statusCode)
+	assert.JSONEq(t, expectedErr.Error(), string(respBody))
+
+	statusCode, respBody = changeStatus(app.ActivateUserRoute, "")
+	var details httperrors.Details
+	details.Add("/reason", httperrors.UserStatusReasonEmpty)
+	expectedErr = httperrors.RequestValidationFailed.WithDetails(details...)
+	assert.Equal(t, expectedErr.HTTPCode, statusCode)
+	assert.JSONEq(t, expectedErr.Error(), string(respBody))
+
+	statusCode, _ = changeStatus(app.ActivateUserRoute, "identity checked")
+	require.Equal(t, http.StatusOK, statusCode)
+	statusCode, _ = changeStatus(app.SuspendUserRoute, "chargeback")
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "suspended", getStatus())
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		baseURL+app.GetUserListRoute,
+		map[string]string{"status": "suspended", "after_id": strconv.Itoa(created.ID - 1)},
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	var users response.UserListWithPagination
+	require.Nil(t, json.Unmarshal(respBody, &users))
+	require.NotEmpty(t, users.Result)
+	assert.Equal(t, created.ID, users.Result[0].ID)
+
+	statusCode, _ = changeStatus(app.CloseUserRoute, "requested by the user")
+	require.Equal(t, http.StatusOK, statusCode)
+	statusCode, _ = changeStatus(app.ActivateUserRoute, "reopen")
+	assert.Equal(t, http.StatusConflict, statusCode)
+
+	statusCode, respBody, err = httpService.DoRequest(
+		http.MethodGet,
+		baseURL+helpers.StrReplace(app.GetUserStatusHistoryRoute, ":user_id", created.ID),
+		nil,
+		nil,
+		nil,
+	)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+	var history response.UserStatusHistory
+	require.Nil(t, json.Unmarshal(respBody, &history))
+	require.Len(t, history.Result, 3)
+	assert.Equal(t, "pending", history.Result[0].FromStatus)
+	assert.Equal(t, "identity checked", history.Result[0].Reason)
+	assert.Equal(t, "suspended", history.Result[2].FromStatus)
+	assert.Equal(t, "closed", history.Result[2].ToStatus)
+}
@@ -55,6 +55,6 @@ func TestUpdateUserOK(t *testing.T) {
 	assert.Equal(t, http.StatusOK, statusCode)
 	assert.Equal(t, data.UpdateUserRequest.Name, user.Name)
 	assert.Equal(t, data.UpdateUserRequest.Surname, user.Surname)
-	assert.Equal(t, data.UpdateUserRequest.Age, user.Age)
+	assert.Equal(t, data.UpdateUserRequest.DateOfBirth, user.DateOfBirth)
 	assert.Equal(t, data.UpdateUserRequest.Gender, user.Gender)
 }
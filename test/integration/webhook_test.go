@@ -0,0 +1,93 @@
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mmgopher/user-service/app"
+	"github.com/mmgopher/user-service/app/api/response"
+	"github.com/mmgopher/user-service/app/httperrors"
+	"github.com/mmgopher/user-service/test/data"
+	"github.com/mmgopher/user-service/test/helpers"
+)
+
+// TestWebhookLifecycle makes test of POST, GET, PUT and DELETE /v1/webhooks endpoints
+func TestWebhookLifecycle(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	request, err := json.Marshal(data.CreateWebhookRequest)
+	require.Nil(t, err)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.CreateWebhookRoute,
+		nil,
+		nil,
+		request,
+	)
+	require.Nil(t, err)
+
+	var created response.CreateWebhook
+	assert.Nil(t, json.Unmarshal(respBody, &created))
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.True(t, created.ID > 0)
+	assert.NotEmpty(t, created.Secret)
+
+	webhookURL := helpers.StrReplace(
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.GetWebhookRoute,
+		":webhook_id",
+		created.ID,
+	)
+
+	statusCode, respBody, err = httpService.DoRequest(http.MethodGet, webhookURL, nil, nil, nil)
+	require.Nil(t, err)
+	var webhook response.Webhook
+	assert.Nil(t, json.Unmarshal(respBody, &webhook))
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, data.CreateWebhookRequest.URL, webhook.URL)
+	assert.Equal(t, data.CreateWebhookRequest.EventTypes, webhook.EventTypes)
+
+	request, err = json.Marshal(data.UpdateWebhookRequest)
+	require.Nil(t, err)
+	statusCode, _, err = httpService.DoRequest(http.MethodPut, webhookURL, nil, nil, request)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, respBody, err = httpService.DoRequest(http.MethodGet, webhookURL+"/deliveries", nil, nil, nil)
+	require.Nil(t, err)
+	var deliveries response.WebhookDeliveryList
+	assert.Nil(t, json.Unmarshal(respBody, &deliveries))
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, _, err = httpService.DoRequest(http.MethodDelete, webhookURL, nil, nil, nil)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, respBody, err = httpService.DoRequest(http.MethodGet, webhookURL, nil, nil, nil)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.JSONEq(t, httperrors.EntityNotFoundError("webhook").Error(), string(respBody))
+}
+
+func TestCreateWebhookError(t *testing.T) {
+	httpService := helpers.NewHTTPService(http.DefaultClient)
+	statusCode, respBody, err := httpService.DoRequest(
+		http.MethodPost,
+		os.Getenv("APP_BASE_URL")+app.RootPath+app.CreateWebhookRoute,
+		nil,
+		nil,
+		[]byte(`{"url":"https://example.com/hook","event_types":["order.created"]}`),
+	)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.JSONEq(t, validationFailed(httperrors.Detail{
+		Field:   "/event_types/0",
+		Code:    httperrors.WebhookEventTypeNotSupported("order.created").Code,
+		Message: httperrors.WebhookEventTypeNotSupported("order.created").Message,
+	}).Error(), string(respBody))
+}